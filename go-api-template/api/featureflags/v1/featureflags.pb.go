@@ -0,0 +1,439 @@
+// API 接口定义：功能开关管理
+// 查看当前生效的功能开关及其对指定租户、用户的求值结果。
+// 所有方法都需要访问令牌，且调用者必须在 admin.users 中。
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: featureflags/v1/featureflags.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FeatureFlag 功能开关的定义与求值结果
+type FeatureFlag struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// 定义的来源：config（features.flags）或 file（features.file）
+	Source string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	// 总开关
+	Enabled bool `protobuf:"varint,4,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// 只在这些环境中开启，为空时不限环境
+	Environments []string `protobuf:"bytes,5,rep,name=environments,proto3" json:"environments,omitempty"`
+	// 只在这些租户中开启，为空时不限租户
+	Tenants []string `protobuf:"bytes,6,rep,name=tenants,proto3" json:"tenants,omitempty"`
+	// 始终开启的用户名
+	Users []string `protobuf:"bytes,7,rep,name=users,proto3" json:"users,omitempty"`
+	// 灰度比例（0-100），未设置时为全部开启
+	Percentage *float64 `protobuf:"fixed64,8,opt,name=percentage,proto3,oneof" json:"percentage,omitempty"`
+	// 对求值对象的结果
+	Evaluation *FeatureFlagEvaluation `protobuf:"bytes,9,opt,name=evaluation,proto3" json:"evaluation,omitempty"`
+	// 自进程启动以来本实例的求值次数
+	EvaluationsOn  int64 `protobuf:"varint,10,opt,name=evaluations_on,json=evaluationsOn,proto3" json:"evaluations_on,omitempty"`
+	EvaluationsOff int64 `protobuf:"varint,11,opt,name=evaluations_off,json=evaluationsOff,proto3" json:"evaluations_off,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FeatureFlag) Reset() {
+	*x = FeatureFlag{}
+	mi := &file_featureflags_v1_featureflags_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeatureFlag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeatureFlag) ProtoMessage() {}
+
+func (x *FeatureFlag) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflags_v1_featureflags_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeatureFlag.ProtoReflect.Descriptor instead.
+func (*FeatureFlag) Descriptor() ([]byte, []int) {
+	return file_featureflags_v1_featureflags_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FeatureFlag) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FeatureFlag) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *FeatureFlag) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *FeatureFlag) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *FeatureFlag) GetEnvironments() []string {
+	if x != nil {
+		return x.Environments
+	}
+	return nil
+}
+
+func (x *FeatureFlag) GetTenants() []string {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+func (x *FeatureFlag) GetUsers() []string {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *FeatureFlag) GetPercentage() float64 {
+	if x != nil && x.Percentage != nil {
+		return *x.Percentage
+	}
+	return 0
+}
+
+func (x *FeatureFlag) GetEvaluation() *FeatureFlagEvaluation {
+	if x != nil {
+		return x.Evaluation
+	}
+	return nil
+}
+
+func (x *FeatureFlag) GetEvaluationsOn() int64 {
+	if x != nil {
+		return x.EvaluationsOn
+	}
+	return 0
+}
+
+func (x *FeatureFlag) GetEvaluationsOff() int64 {
+	if x != nil {
+		return x.EvaluationsOff
+	}
+	return 0
+}
+
+// FeatureFlagEvaluation 开关的求值结果
+type FeatureFlagEvaluation struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Enabled bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// 决定结果的规则：disabled | environment | user | tenant | enabled | rollout
+	Reason        string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeatureFlagEvaluation) Reset() {
+	*x = FeatureFlagEvaluation{}
+	mi := &file_featureflags_v1_featureflags_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeatureFlagEvaluation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeatureFlagEvaluation) ProtoMessage() {}
+
+func (x *FeatureFlagEvaluation) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflags_v1_featureflags_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeatureFlagEvaluation.ProtoReflect.Descriptor instead.
+func (*FeatureFlagEvaluation) Descriptor() ([]byte, []int) {
+	return file_featureflags_v1_featureflags_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FeatureFlagEvaluation) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *FeatureFlagEvaluation) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// ListFeatureFlagsRequest ListFeatureFlags 方法的请求参数
+// 求值对象默认为调用者自己（当前租户、当前用户与当前环境），非空的字段替换对应的部分
+type ListFeatureFlagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenant        string                 `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Environment   string                 `protobuf:"bytes,3,opt,name=environment,proto3" json:"environment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFeatureFlagsRequest) Reset() {
+	*x = ListFeatureFlagsRequest{}
+	mi := &file_featureflags_v1_featureflags_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFeatureFlagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeatureFlagsRequest) ProtoMessage() {}
+
+func (x *ListFeatureFlagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflags_v1_featureflags_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFeatureFlagsRequest.ProtoReflect.Descriptor instead.
+func (*ListFeatureFlagsRequest) Descriptor() ([]byte, []int) {
+	return file_featureflags_v1_featureflags_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListFeatureFlagsRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *ListFeatureFlagsRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ListFeatureFlagsRequest) GetEnvironment() string {
+	if x != nil {
+		return x.Environment
+	}
+	return ""
+}
+
+// ListFeatureFlagsResponse ListFeatureFlags 方法的响应结果
+type ListFeatureFlagsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Flags []*FeatureFlag         `protobuf:"bytes,1,rep,name=flags,proto3" json:"flags,omitempty"`
+	// 实际使用的求值对象
+	Tenant        string `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	User          string `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Environment   string `protobuf:"bytes,4,opt,name=environment,proto3" json:"environment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFeatureFlagsResponse) Reset() {
+	*x = ListFeatureFlagsResponse{}
+	mi := &file_featureflags_v1_featureflags_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFeatureFlagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeatureFlagsResponse) ProtoMessage() {}
+
+func (x *ListFeatureFlagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflags_v1_featureflags_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFeatureFlagsResponse.ProtoReflect.Descriptor instead.
+func (*ListFeatureFlagsResponse) Descriptor() ([]byte, []int) {
+	return file_featureflags_v1_featureflags_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListFeatureFlagsResponse) GetFlags() []*FeatureFlag {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+func (x *ListFeatureFlagsResponse) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *ListFeatureFlagsResponse) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ListFeatureFlagsResponse) GetEnvironment() string {
+	if x != nil {
+		return x.Environment
+	}
+	return ""
+}
+
+var File_featureflags_v1_featureflags_proto protoreflect.FileDescriptor
+
+const file_featureflags_v1_featureflags_proto_rawDesc = "" +
+	"\n" +
+	"\"featureflags/v1/featureflags.proto\x12\x0ffeatureflags.v1\"\x95\x03\n" +
+	"\vFeatureFlag\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\x18\n" +
+	"\aenabled\x18\x04 \x01(\bR\aenabled\x12\"\n" +
+	"\fenvironments\x18\x05 \x03(\tR\fenvironments\x12\x18\n" +
+	"\atenants\x18\x06 \x03(\tR\atenants\x12\x14\n" +
+	"\x05users\x18\a \x03(\tR\x05users\x12#\n" +
+	"\n" +
+	"percentage\x18\b \x01(\x01H\x00R\n" +
+	"percentage\x88\x01\x01\x12F\n" +
+	"\n" +
+	"evaluation\x18\t \x01(\v2&.featureflags.v1.FeatureFlagEvaluationR\n" +
+	"evaluation\x12%\n" +
+	"\x0eevaluations_on\x18\n" +
+	" \x01(\x03R\revaluationsOn\x12'\n" +
+	"\x0fevaluations_off\x18\v \x01(\x03R\x0eevaluationsOffB\r\n" +
+	"\v_percentage\"I\n" +
+	"\x15FeatureFlagEvaluation\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"g\n" +
+	"\x17ListFeatureFlagsRequest\x12\x16\n" +
+	"\x06tenant\x18\x01 \x01(\tR\x06tenant\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12 \n" +
+	"\venvironment\x18\x03 \x01(\tR\venvironment\"\x9c\x01\n" +
+	"\x18ListFeatureFlagsResponse\x122\n" +
+	"\x05flags\x18\x01 \x03(\v2\x1c.featureflags.v1.FeatureFlagR\x05flags\x12\x16\n" +
+	"\x06tenant\x18\x02 \x01(\tR\x06tenant\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\x12 \n" +
+	"\venvironment\x18\x04 \x01(\tR\venvironment2}\n" +
+	"\x12FeatureFlagService\x12g\n" +
+	"\x10ListFeatureFlags\x12(.featureflags.v1.ListFeatureFlagsRequest\x1a).featureflags.v1.ListFeatureFlagsResponseB(Z&go-api-template/api/featureflags/v1;v1b\x06proto3"
+
+var (
+	file_featureflags_v1_featureflags_proto_rawDescOnce sync.Once
+	file_featureflags_v1_featureflags_proto_rawDescData []byte
+)
+
+func file_featureflags_v1_featureflags_proto_rawDescGZIP() []byte {
+	file_featureflags_v1_featureflags_proto_rawDescOnce.Do(func() {
+		file_featureflags_v1_featureflags_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_featureflags_v1_featureflags_proto_rawDesc), len(file_featureflags_v1_featureflags_proto_rawDesc)))
+	})
+	return file_featureflags_v1_featureflags_proto_rawDescData
+}
+
+var file_featureflags_v1_featureflags_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_featureflags_v1_featureflags_proto_goTypes = []any{
+	(*FeatureFlag)(nil),              // 0: featureflags.v1.FeatureFlag
+	(*FeatureFlagEvaluation)(nil),    // 1: featureflags.v1.FeatureFlagEvaluation
+	(*ListFeatureFlagsRequest)(nil),  // 2: featureflags.v1.ListFeatureFlagsRequest
+	(*ListFeatureFlagsResponse)(nil), // 3: featureflags.v1.ListFeatureFlagsResponse
+}
+var file_featureflags_v1_featureflags_proto_depIdxs = []int32{
+	1, // 0: featureflags.v1.FeatureFlag.evaluation:type_name -> featureflags.v1.FeatureFlagEvaluation
+	0, // 1: featureflags.v1.ListFeatureFlagsResponse.flags:type_name -> featureflags.v1.FeatureFlag
+	2, // 2: featureflags.v1.FeatureFlagService.ListFeatureFlags:input_type -> featureflags.v1.ListFeatureFlagsRequest
+	3, // 3: featureflags.v1.FeatureFlagService.ListFeatureFlags:output_type -> featureflags.v1.ListFeatureFlagsResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_featureflags_v1_featureflags_proto_init() }
+func file_featureflags_v1_featureflags_proto_init() {
+	if File_featureflags_v1_featureflags_proto != nil {
+		return
+	}
+	file_featureflags_v1_featureflags_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_featureflags_v1_featureflags_proto_rawDesc), len(file_featureflags_v1_featureflags_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_featureflags_v1_featureflags_proto_goTypes,
+		DependencyIndexes: file_featureflags_v1_featureflags_proto_depIdxs,
+		MessageInfos:      file_featureflags_v1_featureflags_proto_msgTypes,
+	}.Build()
+	File_featureflags_v1_featureflags_proto = out.File
+	file_featureflags_v1_featureflags_proto_goTypes = nil
+	file_featureflags_v1_featureflags_proto_depIdxs = nil
+}
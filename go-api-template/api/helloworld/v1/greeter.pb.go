@@ -24,6 +24,58 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// PresenceAction 在线状态变化的类型
+type PresenceAction int32
+
+const (
+	PresenceAction_PRESENCE_ACTION_UNSPECIFIED PresenceAction = 0
+	// 用户打开了第一个会话
+	PresenceAction_PRESENCE_ACTION_JOINED PresenceAction = 1
+	// 用户关闭了最后一个会话
+	PresenceAction_PRESENCE_ACTION_LEFT PresenceAction = 2
+)
+
+// Enum value maps for PresenceAction.
+var (
+	PresenceAction_name = map[int32]string{
+		0: "PRESENCE_ACTION_UNSPECIFIED",
+		1: "PRESENCE_ACTION_JOINED",
+		2: "PRESENCE_ACTION_LEFT",
+	}
+	PresenceAction_value = map[string]int32{
+		"PRESENCE_ACTION_UNSPECIFIED": 0,
+		"PRESENCE_ACTION_JOINED":      1,
+		"PRESENCE_ACTION_LEFT":        2,
+	}
+)
+
+func (x PresenceAction) Enum() *PresenceAction {
+	p := new(PresenceAction)
+	*p = x
+	return p
+}
+
+func (x PresenceAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PresenceAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_helloworld_v1_greeter_proto_enumTypes[0].Descriptor()
+}
+
+func (PresenceAction) Type() protoreflect.EnumType {
+	return &file_helloworld_v1_greeter_proto_enumTypes[0]
+}
+
+func (x PresenceAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PresenceAction.Descriptor instead.
+func (PresenceAction) EnumDescriptor() ([]byte, []int) {
+	return file_helloworld_v1_greeter_proto_rawDescGZIP(), []int{0}
+}
+
 // SayHelloRequest SayHello 方法的请求参数
 type SayHelloRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -74,7 +126,9 @@ func (x *SayHelloRequest) GetName() string {
 type SayHelloResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// 问候消息
-	Message       string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// 保存的问候记录
+	Greeting      *Greeting `protobuf:"bytes,2,opt,name=greeting,proto3" json:"greeting,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -116,17 +170,760 @@ func (x *SayHelloResponse) GetMessage() string {
 	return ""
 }
 
+func (x *SayHelloResponse) GetGreeting() *Greeting {
+	if x != nil {
+		return x.Greeting
+	}
+	return nil
+}
+
+// GetGreetingRequest GetGreeting 方法的请求参数
+type GetGreetingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGreetingRequest) Reset() {
+	*x = GetGreetingRequest{}
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGreetingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGreetingRequest) ProtoMessage() {}
+
+func (x *GetGreetingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGreetingRequest.ProtoReflect.Descriptor instead.
+func (*GetGreetingRequest) Descriptor() ([]byte, []int) {
+	return file_helloworld_v1_greeter_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetGreetingRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// GetGreetingResponse GetGreeting 方法的响应结果
+type GetGreetingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Greeting      *Greeting              `protobuf:"bytes,1,opt,name=greeting,proto3" json:"greeting,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGreetingResponse) Reset() {
+	*x = GetGreetingResponse{}
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGreetingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGreetingResponse) ProtoMessage() {}
+
+func (x *GetGreetingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGreetingResponse.ProtoReflect.Descriptor instead.
+func (*GetGreetingResponse) Descriptor() ([]byte, []int) {
+	return file_helloworld_v1_greeter_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetGreetingResponse) GetGreeting() *Greeting {
+	if x != nil {
+		return x.Greeting
+	}
+	return nil
+}
+
+// ListGreetingsRequest ListGreetings 方法的请求参数
+// 排序字段：id、created_at，如 -created_at
+// 过滤字段：created_at、name，如 name=World,created_at>=2024-01-01T00:00:00Z
+type ListGreetingsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 每页条数，默认 20，最大 100
+	Limit int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	// 偏移量，大于 0 时使用偏移分页，不能与 cursor 同时使用
+	Offset int32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// 上一页响应中的 next_cursor，为空表示第一页
+	Cursor string `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// 排序字段，逗号分隔，- 前缀表示倒序，默认 -id
+	Sort string `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+	// 过滤条件，逗号分隔，之间为 AND 关系
+	Filter        string `protobuf:"bytes,5,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGreetingsRequest) Reset() {
+	*x = ListGreetingsRequest{}
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGreetingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGreetingsRequest) ProtoMessage() {}
+
+func (x *ListGreetingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGreetingsRequest.ProtoReflect.Descriptor instead.
+func (*ListGreetingsRequest) Descriptor() ([]byte, []int) {
+	return file_helloworld_v1_greeter_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListGreetingsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListGreetingsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListGreetingsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListGreetingsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListGreetingsRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+// ListGreetingsResponse ListGreetings 方法的响应结果
+type ListGreetingsResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Greetings []*Greeting            `protobuf:"bytes,1,rep,name=greetings,proto3" json:"greetings,omitempty"`
+	// 下一页的游标，为空表示没有下一页
+	NextCursor string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// 满足过滤条件的问候总数
+	Total         int64 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGreetingsResponse) Reset() {
+	*x = ListGreetingsResponse{}
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGreetingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGreetingsResponse) ProtoMessage() {}
+
+func (x *ListGreetingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGreetingsResponse.ProtoReflect.Descriptor instead.
+func (*ListGreetingsResponse) Descriptor() ([]byte, []int) {
+	return file_helloworld_v1_greeter_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListGreetingsResponse) GetGreetings() []*Greeting {
+	if x != nil {
+		return x.Greetings
+	}
+	return nil
+}
+
+func (x *ListGreetingsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *ListGreetingsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// WatchGreetingsRequest WatchGreetings 方法的请求参数
+type WatchGreetingsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 只接收该名称的问候，为空时接收全部
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// 从该 ID 之后开始推送（不含），为 0 时只推送订阅之后的新问候
+	AfterId       int64 `protobuf:"varint,2,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchGreetingsRequest) Reset() {
+	*x = WatchGreetingsRequest{}
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchGreetingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchGreetingsRequest) ProtoMessage() {}
+
+func (x *WatchGreetingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchGreetingsRequest.ProtoReflect.Descriptor instead.
+func (*WatchGreetingsRequest) Descriptor() ([]byte, []int) {
+	return file_helloworld_v1_greeter_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WatchGreetingsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WatchGreetingsRequest) GetAfterId() int64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+// WatchGreetingsResponse WatchGreetings 推送的一条消息
+type WatchGreetingsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 新保存的问候
+	Greeting      *Greeting `protobuf:"bytes,1,opt,name=greeting,proto3" json:"greeting,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchGreetingsResponse) Reset() {
+	*x = WatchGreetingsResponse{}
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchGreetingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchGreetingsResponse) ProtoMessage() {}
+
+func (x *WatchGreetingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchGreetingsResponse.ProtoReflect.Descriptor instead.
+func (*WatchGreetingsResponse) Descriptor() ([]byte, []int) {
+	return file_helloworld_v1_greeter_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WatchGreetingsResponse) GetGreeting() *Greeting {
+	if x != nil {
+		return x.Greeting
+	}
+	return nil
+}
+
+// Greeting 一条问候记录
+type Greeting struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 问候记录 ID，按保存顺序递增
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 被问候者名称
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// 问候消息
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// 创建时间（RFC 3339）
+	CreatedAt string `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// 版本号，HTTP 响应头 ETag 的取值；问候记录保存后不再修改，版本固定为 1
+	Version       int64 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Greeting) Reset() {
+	*x = Greeting{}
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Greeting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Greeting) ProtoMessage() {}
+
+func (x *Greeting) ProtoReflect() protoreflect.Message {
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Greeting.ProtoReflect.Descriptor instead.
+func (*Greeting) Descriptor() ([]byte, []int) {
+	return file_helloworld_v1_greeter_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Greeting) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Greeting) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Greeting) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Greeting) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Greeting) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// GreetSessionRequest 会话中客户端发送的消息
+type GreetSessionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 要问候的用户名称
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GreetSessionRequest) Reset() {
+	*x = GreetSessionRequest{}
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GreetSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GreetSessionRequest) ProtoMessage() {}
+
+func (x *GreetSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GreetSessionRequest.ProtoReflect.Descriptor instead.
+func (*GreetSessionRequest) Descriptor() ([]byte, []int) {
+	return file_helloworld_v1_greeter_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GreetSessionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// GreetSessionResponse 会话中服务端推送的消息，每条消息只包含其中一种
+type GreetSessionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*GreetSessionResponse_Greeting
+	//	*GreetSessionResponse_Presence
+	//	*GreetSessionResponse_Error
+	Payload       isGreetSessionResponse_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GreetSessionResponse) Reset() {
+	*x = GreetSessionResponse{}
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GreetSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GreetSessionResponse) ProtoMessage() {}
+
+func (x *GreetSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GreetSessionResponse.ProtoReflect.Descriptor instead.
+func (*GreetSessionResponse) Descriptor() ([]byte, []int) {
+	return file_helloworld_v1_greeter_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GreetSessionResponse) GetPayload() isGreetSessionResponse_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *GreetSessionResponse) GetGreeting() *Greeting {
+	if x != nil {
+		if x, ok := x.Payload.(*GreetSessionResponse_Greeting); ok {
+			return x.Greeting
+		}
+	}
+	return nil
+}
+
+func (x *GreetSessionResponse) GetPresence() *Presence {
+	if x != nil {
+		if x, ok := x.Payload.(*GreetSessionResponse_Presence); ok {
+			return x.Presence
+		}
+	}
+	return nil
+}
+
+func (x *GreetSessionResponse) GetError() *SessionError {
+	if x != nil {
+		if x, ok := x.Payload.(*GreetSessionResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isGreetSessionResponse_Payload interface {
+	isGreetSessionResponse_Payload()
+}
+
+type GreetSessionResponse_Greeting struct {
+	// 对客户端所发名称的问候
+	Greeting *Greeting `protobuf:"bytes,1,opt,name=greeting,proto3,oneof"`
+}
+
+type GreetSessionResponse_Presence struct {
+	// 在线用户变化
+	Presence *Presence `protobuf:"bytes,2,opt,name=presence,proto3,oneof"`
+}
+
+type GreetSessionResponse_Error struct {
+	// 单条消息处理失败（如参数错误、发送过快），会话继续
+	Error *SessionError `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*GreetSessionResponse_Greeting) isGreetSessionResponse_Payload() {}
+
+func (*GreetSessionResponse_Presence) isGreetSessionResponse_Payload() {}
+
+func (*GreetSessionResponse_Error) isGreetSessionResponse_Payload() {}
+
+// Presence 在线状态变化通知
+type Presence struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Action PresenceAction         `protobuf:"varint,1,opt,name=action,proto3,enum=helloworld.v1.PresenceAction" json:"action,omitempty"`
+	// 状态变化的用户名
+	Username string `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	// 变化后的在线用户数
+	Online        int32 `protobuf:"varint,3,opt,name=online,proto3" json:"online,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Presence) Reset() {
+	*x = Presence{}
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Presence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Presence) ProtoMessage() {}
+
+func (x *Presence) ProtoReflect() protoreflect.Message {
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Presence.ProtoReflect.Descriptor instead.
+func (*Presence) Descriptor() ([]byte, []int) {
+	return file_helloworld_v1_greeter_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Presence) GetAction() PresenceAction {
+	if x != nil {
+		return x.Action
+	}
+	return PresenceAction_PRESENCE_ACTION_UNSPECIFIED
+}
+
+func (x *Presence) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Presence) GetOnline() int32 {
+	if x != nil {
+		return x.Online
+	}
+	return 0
+}
+
+// SessionError 会话中单条消息的处理错误
+type SessionError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 与 HTTP 响应相同的业务错误码，如 INVALID_PARAMS、TOO_MANY_REQUESTS
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// 错误描述
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionError) Reset() {
+	*x = SessionError{}
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionError) ProtoMessage() {}
+
+func (x *SessionError) ProtoReflect() protoreflect.Message {
+	mi := &file_helloworld_v1_greeter_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionError.ProtoReflect.Descriptor instead.
+func (*SessionError) Descriptor() ([]byte, []int) {
+	return file_helloworld_v1_greeter_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SessionError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *SessionError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 var File_helloworld_v1_greeter_proto protoreflect.FileDescriptor
 
 const file_helloworld_v1_greeter_proto_rawDesc = "" +
 	"\n" +
 	"\x1bhelloworld/v1/greeter.proto\x12\rhelloworld.v1\"%\n" +
 	"\x0fSayHelloRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\",\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"a\n" +
 	"\x10SayHelloResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage2]\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x123\n" +
+	"\bgreeting\x18\x02 \x01(\v2\x17.helloworld.v1.GreetingR\bgreeting\"$\n" +
+	"\x12GetGreetingRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"J\n" +
+	"\x13GetGreetingResponse\x123\n" +
+	"\bgreeting\x18\x01 \x01(\v2\x17.helloworld.v1.GreetingR\bgreeting\"\x88\x01\n" +
+	"\x14ListGreetingsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\x12\x12\n" +
+	"\x04sort\x18\x04 \x01(\tR\x04sort\x12\x16\n" +
+	"\x06filter\x18\x05 \x01(\tR\x06filter\"\x85\x01\n" +
+	"\x15ListGreetingsResponse\x125\n" +
+	"\tgreetings\x18\x01 \x03(\v2\x17.helloworld.v1.GreetingR\tgreetings\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\"F\n" +
+	"\x15WatchGreetingsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x19\n" +
+	"\bafter_id\x18\x02 \x01(\x03R\aafterId\"M\n" +
+	"\x16WatchGreetingsResponse\x123\n" +
+	"\bgreeting\x18\x01 \x01(\v2\x17.helloworld.v1.GreetingR\bgreeting\"\x81\x01\n" +
+	"\bGreeting\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x03R\aversion\")\n" +
+	"\x13GreetSessionRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\xc4\x01\n" +
+	"\x14GreetSessionResponse\x125\n" +
+	"\bgreeting\x18\x01 \x01(\v2\x17.helloworld.v1.GreetingH\x00R\bgreeting\x125\n" +
+	"\bpresence\x18\x02 \x01(\v2\x17.helloworld.v1.PresenceH\x00R\bpresence\x123\n" +
+	"\x05error\x18\x03 \x01(\v2\x1b.helloworld.v1.SessionErrorH\x00R\x05errorB\t\n" +
+	"\apayload\"u\n" +
+	"\bPresence\x125\n" +
+	"\x06action\x18\x01 \x01(\x0e2\x1d.helloworld.v1.PresenceActionR\x06action\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x16\n" +
+	"\x06online\x18\x03 \x01(\x05R\x06online\"<\n" +
+	"\fSessionError\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage*g\n" +
+	"\x0ePresenceAction\x12\x1f\n" +
+	"\x1bPRESENCE_ACTION_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16PRESENCE_ACTION_JOINED\x10\x01\x12\x18\n" +
+	"\x14PRESENCE_ACTION_LEFT\x10\x022\xcd\x03\n" +
 	"\x0eGreeterService\x12K\n" +
-	"\bSayHello\x12\x1e.helloworld.v1.SayHelloRequest\x1a\x1f.helloworld.v1.SayHelloResponseB&Z$go-api-template/api/helloworld/v1;v1b\x06proto3"
+	"\bSayHello\x12\x1e.helloworld.v1.SayHelloRequest\x1a\x1f.helloworld.v1.SayHelloResponse\x12T\n" +
+	"\vGetGreeting\x12!.helloworld.v1.GetGreetingRequest\x1a\".helloworld.v1.GetGreetingResponse\x12Z\n" +
+	"\rListGreetings\x12#.helloworld.v1.ListGreetingsRequest\x1a$.helloworld.v1.ListGreetingsResponse\x12_\n" +
+	"\x0eWatchGreetings\x12$.helloworld.v1.WatchGreetingsRequest\x1a%.helloworld.v1.WatchGreetingsResponse0\x01\x12[\n" +
+	"\fGreetSession\x12\".helloworld.v1.GreetSessionRequest\x1a#.helloworld.v1.GreetSessionResponse(\x010\x01B&Z$go-api-template/api/helloworld/v1;v1b\x06proto3"
 
 var (
 	file_helloworld_v1_greeter_proto_rawDescOnce sync.Once
@@ -140,19 +937,48 @@ func file_helloworld_v1_greeter_proto_rawDescGZIP() []byte {
 	return file_helloworld_v1_greeter_proto_rawDescData
 }
 
-var file_helloworld_v1_greeter_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_helloworld_v1_greeter_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_helloworld_v1_greeter_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_helloworld_v1_greeter_proto_goTypes = []any{
-	(*SayHelloRequest)(nil),  // 0: helloworld.v1.SayHelloRequest
-	(*SayHelloResponse)(nil), // 1: helloworld.v1.SayHelloResponse
+	(PresenceAction)(0),            // 0: helloworld.v1.PresenceAction
+	(*SayHelloRequest)(nil),        // 1: helloworld.v1.SayHelloRequest
+	(*SayHelloResponse)(nil),       // 2: helloworld.v1.SayHelloResponse
+	(*GetGreetingRequest)(nil),     // 3: helloworld.v1.GetGreetingRequest
+	(*GetGreetingResponse)(nil),    // 4: helloworld.v1.GetGreetingResponse
+	(*ListGreetingsRequest)(nil),   // 5: helloworld.v1.ListGreetingsRequest
+	(*ListGreetingsResponse)(nil),  // 6: helloworld.v1.ListGreetingsResponse
+	(*WatchGreetingsRequest)(nil),  // 7: helloworld.v1.WatchGreetingsRequest
+	(*WatchGreetingsResponse)(nil), // 8: helloworld.v1.WatchGreetingsResponse
+	(*Greeting)(nil),               // 9: helloworld.v1.Greeting
+	(*GreetSessionRequest)(nil),    // 10: helloworld.v1.GreetSessionRequest
+	(*GreetSessionResponse)(nil),   // 11: helloworld.v1.GreetSessionResponse
+	(*Presence)(nil),               // 12: helloworld.v1.Presence
+	(*SessionError)(nil),           // 13: helloworld.v1.SessionError
 }
 var file_helloworld_v1_greeter_proto_depIdxs = []int32{
-	0, // 0: helloworld.v1.GreeterService.SayHello:input_type -> helloworld.v1.SayHelloRequest
-	1, // 1: helloworld.v1.GreeterService.SayHello:output_type -> helloworld.v1.SayHelloResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	9,  // 0: helloworld.v1.SayHelloResponse.greeting:type_name -> helloworld.v1.Greeting
+	9,  // 1: helloworld.v1.GetGreetingResponse.greeting:type_name -> helloworld.v1.Greeting
+	9,  // 2: helloworld.v1.ListGreetingsResponse.greetings:type_name -> helloworld.v1.Greeting
+	9,  // 3: helloworld.v1.WatchGreetingsResponse.greeting:type_name -> helloworld.v1.Greeting
+	9,  // 4: helloworld.v1.GreetSessionResponse.greeting:type_name -> helloworld.v1.Greeting
+	12, // 5: helloworld.v1.GreetSessionResponse.presence:type_name -> helloworld.v1.Presence
+	13, // 6: helloworld.v1.GreetSessionResponse.error:type_name -> helloworld.v1.SessionError
+	0,  // 7: helloworld.v1.Presence.action:type_name -> helloworld.v1.PresenceAction
+	1,  // 8: helloworld.v1.GreeterService.SayHello:input_type -> helloworld.v1.SayHelloRequest
+	3,  // 9: helloworld.v1.GreeterService.GetGreeting:input_type -> helloworld.v1.GetGreetingRequest
+	5,  // 10: helloworld.v1.GreeterService.ListGreetings:input_type -> helloworld.v1.ListGreetingsRequest
+	7,  // 11: helloworld.v1.GreeterService.WatchGreetings:input_type -> helloworld.v1.WatchGreetingsRequest
+	10, // 12: helloworld.v1.GreeterService.GreetSession:input_type -> helloworld.v1.GreetSessionRequest
+	2,  // 13: helloworld.v1.GreeterService.SayHello:output_type -> helloworld.v1.SayHelloResponse
+	4,  // 14: helloworld.v1.GreeterService.GetGreeting:output_type -> helloworld.v1.GetGreetingResponse
+	6,  // 15: helloworld.v1.GreeterService.ListGreetings:output_type -> helloworld.v1.ListGreetingsResponse
+	8,  // 16: helloworld.v1.GreeterService.WatchGreetings:output_type -> helloworld.v1.WatchGreetingsResponse
+	11, // 17: helloworld.v1.GreeterService.GreetSession:output_type -> helloworld.v1.GreetSessionResponse
+	13, // [13:18] is the sub-list for method output_type
+	8,  // [8:13] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_helloworld_v1_greeter_proto_init() }
@@ -160,18 +986,24 @@ func file_helloworld_v1_greeter_proto_init() {
 	if File_helloworld_v1_greeter_proto != nil {
 		return
 	}
+	file_helloworld_v1_greeter_proto_msgTypes[10].OneofWrappers = []any{
+		(*GreetSessionResponse_Greeting)(nil),
+		(*GreetSessionResponse_Presence)(nil),
+		(*GreetSessionResponse_Error)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_helloworld_v1_greeter_proto_rawDesc), len(file_helloworld_v1_greeter_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      1,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_helloworld_v1_greeter_proto_goTypes,
 		DependencyIndexes: file_helloworld_v1_greeter_proto_depIdxs,
+		EnumInfos:         file_helloworld_v1_greeter_proto_enumTypes,
 		MessageInfos:      file_helloworld_v1_greeter_proto_msgTypes,
 	}.Build()
 	File_helloworld_v1_greeter_proto = out.File
@@ -22,7 +22,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	GreeterService_SayHello_FullMethodName = "/helloworld.v1.GreeterService/SayHello"
+	GreeterService_SayHello_FullMethodName       = "/helloworld.v1.GreeterService/SayHello"
+	GreeterService_GetGreeting_FullMethodName    = "/helloworld.v1.GreeterService/GetGreeting"
+	GreeterService_ListGreetings_FullMethodName  = "/helloworld.v1.GreeterService/ListGreetings"
+	GreeterService_WatchGreetings_FullMethodName = "/helloworld.v1.GreeterService/WatchGreetings"
+	GreeterService_GreetSession_FullMethodName   = "/helloworld.v1.GreeterService/GreetSession"
 )
 
 // GreeterServiceClient is the client API for GreeterService service.
@@ -33,6 +37,16 @@ const (
 type GreeterServiceClient interface {
 	// SayHello 向指定用户发送问候
 	SayHello(ctx context.Context, in *SayHelloRequest, opts ...grpc.CallOption) (*SayHelloResponse, error)
+	// GetGreeting 按 ID 获取一条问候记录
+	GetGreeting(ctx context.Context, in *GetGreetingRequest, opts ...grpc.CallOption) (*GetGreetingResponse, error)
+	// ListGreetings 分页列出当前租户的问候记录
+	ListGreetings(ctx context.Context, in *ListGreetingsRequest, opts ...grpc.CallOption) (*ListGreetingsResponse, error)
+	// WatchGreetings 订阅新保存的问候，连接保持期间持续推送
+	// 指定 after_id 时先补发该 ID 之后的历史问候，用于断线续传
+	WatchGreetings(ctx context.Context, in *WatchGreetingsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchGreetingsResponse], error)
+	// GreetSession 交互式问候会话，需要登录
+	// 客户端每发送一个名称，服务端回复对应的问候；会话期间同时推送其他用户的上线、下线通知
+	GreetSession(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GreetSessionRequest, GreetSessionResponse], error)
 }
 
 type greeterServiceClient struct {
@@ -53,6 +67,58 @@ func (c *greeterServiceClient) SayHello(ctx context.Context, in *SayHelloRequest
 	return out, nil
 }
 
+func (c *greeterServiceClient) GetGreeting(ctx context.Context, in *GetGreetingRequest, opts ...grpc.CallOption) (*GetGreetingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetGreetingResponse)
+	err := c.cc.Invoke(ctx, GreeterService_GetGreeting_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *greeterServiceClient) ListGreetings(ctx context.Context, in *ListGreetingsRequest, opts ...grpc.CallOption) (*ListGreetingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGreetingsResponse)
+	err := c.cc.Invoke(ctx, GreeterService_ListGreetings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *greeterServiceClient) WatchGreetings(ctx context.Context, in *WatchGreetingsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchGreetingsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GreeterService_ServiceDesc.Streams[0], GreeterService_WatchGreetings_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchGreetingsRequest, WatchGreetingsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GreeterService_WatchGreetingsClient = grpc.ServerStreamingClient[WatchGreetingsResponse]
+
+func (c *greeterServiceClient) GreetSession(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GreetSessionRequest, GreetSessionResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GreeterService_ServiceDesc.Streams[1], GreeterService_GreetSession_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GreetSessionRequest, GreetSessionResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GreeterService_GreetSessionClient = grpc.BidiStreamingClient[GreetSessionRequest, GreetSessionResponse]
+
 // GreeterServiceServer is the server API for GreeterService service.
 // All implementations must embed UnimplementedGreeterServiceServer
 // for forward compatibility.
@@ -61,6 +127,16 @@ func (c *greeterServiceClient) SayHello(ctx context.Context, in *SayHelloRequest
 type GreeterServiceServer interface {
 	// SayHello 向指定用户发送问候
 	SayHello(context.Context, *SayHelloRequest) (*SayHelloResponse, error)
+	// GetGreeting 按 ID 获取一条问候记录
+	GetGreeting(context.Context, *GetGreetingRequest) (*GetGreetingResponse, error)
+	// ListGreetings 分页列出当前租户的问候记录
+	ListGreetings(context.Context, *ListGreetingsRequest) (*ListGreetingsResponse, error)
+	// WatchGreetings 订阅新保存的问候，连接保持期间持续推送
+	// 指定 after_id 时先补发该 ID 之后的历史问候，用于断线续传
+	WatchGreetings(*WatchGreetingsRequest, grpc.ServerStreamingServer[WatchGreetingsResponse]) error
+	// GreetSession 交互式问候会话，需要登录
+	// 客户端每发送一个名称，服务端回复对应的问候；会话期间同时推送其他用户的上线、下线通知
+	GreetSession(grpc.BidiStreamingServer[GreetSessionRequest, GreetSessionResponse]) error
 	mustEmbedUnimplementedGreeterServiceServer()
 }
 
@@ -74,6 +150,18 @@ type UnimplementedGreeterServiceServer struct{}
 func (UnimplementedGreeterServiceServer) SayHello(context.Context, *SayHelloRequest) (*SayHelloResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SayHello not implemented")
 }
+func (UnimplementedGreeterServiceServer) GetGreeting(context.Context, *GetGreetingRequest) (*GetGreetingResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetGreeting not implemented")
+}
+func (UnimplementedGreeterServiceServer) ListGreetings(context.Context, *ListGreetingsRequest) (*ListGreetingsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListGreetings not implemented")
+}
+func (UnimplementedGreeterServiceServer) WatchGreetings(*WatchGreetingsRequest, grpc.ServerStreamingServer[WatchGreetingsResponse]) error {
+	return status.Error(codes.Unimplemented, "method WatchGreetings not implemented")
+}
+func (UnimplementedGreeterServiceServer) GreetSession(grpc.BidiStreamingServer[GreetSessionRequest, GreetSessionResponse]) error {
+	return status.Error(codes.Unimplemented, "method GreetSession not implemented")
+}
 func (UnimplementedGreeterServiceServer) mustEmbedUnimplementedGreeterServiceServer() {}
 func (UnimplementedGreeterServiceServer) testEmbeddedByValue()                        {}
 
@@ -113,6 +201,60 @@ func _GreeterService_SayHello_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GreeterService_GetGreeting_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGreetingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GreeterServiceServer).GetGreeting(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GreeterService_GetGreeting_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GreeterServiceServer).GetGreeting(ctx, req.(*GetGreetingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GreeterService_ListGreetings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGreetingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GreeterServiceServer).ListGreetings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GreeterService_ListGreetings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GreeterServiceServer).ListGreetings(ctx, req.(*ListGreetingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GreeterService_WatchGreetings_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchGreetingsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GreeterServiceServer).WatchGreetings(m, &grpc.GenericServerStream[WatchGreetingsRequest, WatchGreetingsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GreeterService_WatchGreetingsServer = grpc.ServerStreamingServer[WatchGreetingsResponse]
+
+func _GreeterService_GreetSession_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GreeterServiceServer).GreetSession(&grpc.GenericServerStream[GreetSessionRequest, GreetSessionResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GreeterService_GreetSessionServer = grpc.BidiStreamingServer[GreetSessionRequest, GreetSessionResponse]
+
 // GreeterService_ServiceDesc is the grpc.ServiceDesc for GreeterService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -124,7 +266,27 @@ var GreeterService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SayHello",
 			Handler:    _GreeterService_SayHello_Handler,
 		},
+		{
+			MethodName: "GetGreeting",
+			Handler:    _GreeterService_GetGreeting_Handler,
+		},
+		{
+			MethodName: "ListGreetings",
+			Handler:    _GreeterService_ListGreetings_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchGreetings",
+			Handler:       _GreeterService_WatchGreetings_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GreetSession",
+			Handler:       _GreeterService_GreetSession_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "helloworld/v1/greeter.proto",
 }
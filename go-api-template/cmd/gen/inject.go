@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+)
+
+// injection 对已有文件的一处修改
+type injection struct {
+	path  string
+	apply func(src []byte) ([]byte, error)
+}
+
+// injections 返回接入新模块需要修改的位置
+// ProviderSet 沿用各层 biz.go / data.go / service.go 中的聚合列表；
+// server 包通过 "// gen:xxx" 标记定位插入点
+func injections(n names) []injection {
+	return []injection{
+		{filepath.Join("internal", "biz", "biz.go"), addToProviderSet(n)},
+		{filepath.Join("internal", "data", "data.go"), addToProviderSet(n)},
+		{filepath.Join("internal", "service", "service.go"), addToProviderSet(n)},
+		{filepath.Join("internal", "server", "server.go"),
+			insertBeforeMarker("gen:services", fmt.Sprintf("%s *service.%sService", n.Pascal, n.Pascal))},
+		{filepath.Join("internal", "server", "http.go"),
+			insertBeforeMarker("gen:routes", fmt.Sprintf("register%sRoutes(v1Group, svcs.%s)", n.Pascal, n.Pascal))},
+		{filepath.Join("internal", "server", "grpc.go"),
+			insertBeforeMarker("gen:grpc", fmt.Sprintf("register%sGRPC(srv, svcs.%s)", n.Pascal, n.Pascal))},
+	}
+}
+
+// providerSetBlock 匹配 "var ProviderSet = wire.NewSet(...)" 的参数列表
+var providerSetBlock = regexp.MustCompile(`(?s)var ProviderSet = wire\.NewSet\(\n(.*?)\n\)`)
+
+// addToProviderSet 将 XxxProviderSet 加入聚合列表
+// 列表中已有 "// XxxProviderSet, // 未来：..." 形式的占位注释时原位替换，否则追加到末尾
+func addToProviderSet(n names) func([]byte) ([]byte, error) {
+	entry := fmt.Sprintf("\t%sProviderSet, // %s 模块", n.Pascal, n.Pascal)
+	placeholder := regexp.MustCompile(`(?m)^\s*//\s*` + n.Pascal + `ProviderSet,.*$`)
+	active := regexp.MustCompile(`(?m)^\s*` + n.Pascal + `ProviderSet,`)
+
+	return func(src []byte) ([]byte, error) {
+		loc := providerSetBlock.FindSubmatchIndex(src)
+		if loc == nil {
+			return nil, errors.New("var ProviderSet = wire.NewSet(...) not found")
+		}
+		body := src[loc[2]:loc[3]]
+		if active.Match(body) {
+			return nil, fmt.Errorf("%sProviderSet is already registered", n.Pascal)
+		}
+
+		var newBody []byte
+		if placeholder.Match(body) {
+			newBody = placeholder.ReplaceAll(body, []byte(entry))
+		} else {
+			newBody = append(append(append([]byte{}, body...), '\n'), entry...)
+		}
+
+		var out bytes.Buffer
+		out.Write(src[:loc[2]])
+		out.Write(newBody)
+		out.Write(src[loc[3]:])
+		return out.Bytes(), nil
+	}
+}
+
+// insertBeforeMarker 在 "// <marker>" 注释所在行之前插入一行，缩进与标记行一致
+func insertBeforeMarker(marker, line string) func([]byte) ([]byte, error) {
+	pattern := regexp.MustCompile(`(?m)^([ \t]*)// ` + regexp.QuoteMeta(marker) + `\b`)
+	return func(src []byte) ([]byte, error) {
+		loc := pattern.FindSubmatchIndex(src)
+		if loc == nil {
+			return nil, fmt.Errorf("marker // %s not found", marker)
+		}
+		if bytes.Contains(src, []byte(line)) {
+			return nil, fmt.Errorf("%q is already present", line)
+		}
+		indent := src[loc[2]:loc[3]]
+
+		var out bytes.Buffer
+		out.Write(src[:loc[0]])
+		out.Write(indent)
+		out.WriteString(line)
+		out.WriteByte('\n')
+		out.Write(src[loc[0]:])
+		return out.Bytes(), nil
+	}
+}
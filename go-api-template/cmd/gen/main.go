@@ -0,0 +1,60 @@
+// Package main 是代码生成工具的入口
+//
+// 用法：
+//
+//	go run ./cmd/gen module <name>
+//
+// 按模板生成一个完整的领域模块（proto、biz、data、service、dto、路由、迁移与测试），
+// 并把它接入各层 ProviderSet 与路由注册，随后执行 buf generate 和 wire。
+//
+//	go run ./cmd/gen swagger
+//
+// 在 swag init 之后按 proto 描述重写响应消息的 schema，使文档与 protojson 编码的响应体一致。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd := flag.Arg(0); cmd {
+	case "module":
+		err = runModule(flag.Args()[1:])
+	case "swagger":
+		err = runSwagger(flag.Args()[1:])
+	case "help", "-h", "--help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", cmd)
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: go run ./cmd/gen <command> [flags]
+
+Commands:
+  module <name>   scaffold a domain module across proto/biz/data/service/server
+  swagger         rewrite proto message schemas in the swag output to follow protojson
+
+Run 'go run ./cmd/gen module -h' for module flags.
+`)
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// moduleFile 一个由模板生成的文件
+type moduleFile struct {
+	template string
+	path     string
+}
+
+// moduleFiles 返回模块需要生成的全部文件（相对项目根目录）
+// migration 为本次迁移文件的序号前缀，如 0002
+func moduleFiles(n names, migration string) []moduleFile {
+	return []moduleFile{
+		{"proto.tmpl", filepath.Join("api", n.Snake, "v1", n.Snake+".proto")},
+		{"biz.go.tmpl", filepath.Join("internal", "biz", n.Snake+".go")},
+		{"biz_test.go.tmpl", filepath.Join("internal", "biz", n.Snake+"_test.go")},
+		{"data.go.tmpl", filepath.Join("internal", "data", n.Snake+".go")},
+		{"data_sql.go.tmpl", filepath.Join("internal", "data", n.Snake+"_sql.go")},
+		{"data_test.go.tmpl", filepath.Join("internal", "data", n.Snake+"_test.go")},
+		{"migration.up.sql.tmpl", filepath.Join("internal", "data", "migrations", migration+"_create_"+n.PluralSnake+".up.sql")},
+		{"migration.down.sql.tmpl", filepath.Join("internal", "data", "migrations", migration+"_create_"+n.PluralSnake+".down.sql")},
+		{"service.go.tmpl", filepath.Join("internal", "service", n.Snake+".go")},
+		{"service_test.go.tmpl", filepath.Join("internal", "service", n.Snake+"_test.go")},
+		{"dto.go.tmpl", filepath.Join("internal", "server", "dto", n.Snake+".go")},
+		{"server.go.tmpl", filepath.Join("internal", "server", n.Snake+".go")},
+	}
+}
+
+// runModule 实现 "module" 子命令
+func runModule(args []string) error {
+	fs := flag.NewFlagSet("module", flag.ExitOnError)
+	root := fs.String("root", ".", "project root (directory containing go.mod)")
+	skipTools := fs.Bool("skip-tools", false, "do not run buf generate and wire after scaffolding")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: go run ./cmd/gen module [flags] <name>\n\n"+
+			"Scaffold a CRUD module named <name> (e.g. product, order_item).\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("exactly one module name is required")
+	}
+
+	modulePath, err := readModulePath(filepath.Join(*root, "go.mod"))
+	if err != nil {
+		return err
+	}
+	n, err := newNames(fs.Arg(0), modulePath)
+	if err != nil {
+		return err
+	}
+	migration, err := nextMigrationVersion(filepath.Join(*root, "internal", "data", "migrations"))
+	if err != nil {
+		return err
+	}
+
+	// 先在内存中完成全部渲染和注入，任何一步失败都不会留下半个模块
+	writes := map[string][]byte{}
+	for _, f := range moduleFiles(n, migration) {
+		path := filepath.Join(*root, f.path)
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists, module %q may have been generated before", f.path, n.Snake)
+		}
+		content, err := render(f.template, n)
+		if err != nil {
+			return fmt.Errorf("render %s: %w", f.path, err)
+		}
+		writes[path] = content
+	}
+	for _, inj := range injections(n) {
+		path := filepath.Join(*root, inj.path)
+		src, ok := writes[path]
+		if !ok {
+			if src, err = os.ReadFile(path); err != nil {
+				return err
+			}
+		}
+		out, err := inj.apply(src)
+		if err != nil {
+			return fmt.Errorf("%s: %w", inj.path, err)
+		}
+		if writes[path], err = format.Source(out); err != nil {
+			return fmt.Errorf("%s: format after injection: %w", inj.path, err)
+		}
+	}
+
+	paths := make([]string, 0, len(writes))
+	for path := range writes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		content := writes[path]
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(*root, path)
+		fmt.Println("  wrote", rel)
+	}
+
+	if *skipTools {
+		fmt.Println("\nNext steps:\n  buf generate api/\n  wire ./cmd/server/\n  make swagger")
+		return nil
+	}
+	return runTools(*root)
+}
+
+// render 用模块名渲染模板，Go 文件会经过 gofmt
+func render(name string, n names) ([]byte, error) {
+	tmpl, err := template.ParseFS(templateFS, "templates/"+name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, n); err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(name, ".go.tmpl") {
+		return format.Source(buf.Bytes())
+	}
+	return buf.Bytes(), nil
+}
+
+// readModulePath 读取 go.mod 中的 module 路径
+func readModulePath(goMod string) (string, error) {
+	content, err := os.ReadFile(goMod)
+	if err != nil {
+		return "", fmt.Errorf("read go.mod (use -root to point at the project root): %w", err)
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`), nil
+		}
+	}
+	return "", errors.New("module directive not found in go.mod")
+}
+
+// migrationVersionPattern 与 data 包中迁移文件的命名约定保持一致
+var migrationVersionPattern = regexp.MustCompile(`^(\d+)_.+\.(up|down)\.sql$`)
+
+// nextMigrationVersion 返回下一个迁移文件序号（4 位补零）
+func nextMigrationVersion(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	latest := 0
+	for _, e := range entries {
+		m := migrationVersionPattern.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		if v, _ := strconv.Atoi(m[1]); v > latest {
+			latest = v
+		}
+	}
+	return fmt.Sprintf("%04d", latest+1), nil
+}
+
+// runTools 执行 buf generate 与 wire，工具未安装时提示手动执行
+func runTools(root string) error {
+	steps := [][]string{
+		{"buf", "generate", "api/"},
+		{"wire", "./cmd/server/"},
+	}
+	for _, step := range steps {
+		if _, err := exec.LookPath(step[0]); err != nil {
+			fmt.Printf("\n%s not found in PATH, run manually:\n  %s\n", step[0], strings.Join(step, " "))
+			continue
+		}
+		fmt.Println("\n$", strings.Join(step, " "))
+		cmd := exec.Command(step[0], step[1:]...)
+		cmd.Dir = root
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed: %w", step[0], err)
+		}
+	}
+	fmt.Println("\nDone. Run 'make swagger' to refresh the API docs.")
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewNames(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    names
+		wantErr bool
+	}{
+		{in: "product", want: names{Pascal: "Product", Camel: "product", Snake: "product", Kebab: "product",
+			PluralPascal: "Products", PluralSnake: "products", PluralKebab: "products"}},
+		{in: "order_item", want: names{Pascal: "OrderItem", Camel: "orderItem", Snake: "order_item", Kebab: "order-item",
+			PluralPascal: "OrderItems", PluralSnake: "order_items", PluralKebab: "order-items"}},
+		{in: "Product", wantErr: true},
+		{in: "order__item", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := newNames(tt.in, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newNames error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("newNames = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextMigrationVersion(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"0001_init.up.sql", "0001_init.down.sql", "0012_add.up.sql", "README.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := nextMigrationVersion(dir)
+	if err != nil || got != "0013" {
+		t.Errorf("nextMigrationVersion = %q, %v; want 0013", got, err)
+	}
+}
+
+// TestGenerateModule 在项目副本中生成模块，并确认生成结果能通过编译、vet 与生成的测试
+// 需要 buf 与 wire 生成 proto 代码和依赖注入代码，未安装时跳过
+func TestGenerateModule(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generates and builds a copy of the project")
+	}
+	for _, tool := range []string{"buf", "wire"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not found in PATH", tool)
+		}
+	}
+
+	root := t.TempDir()
+	if err := os.CopyFS(root, os.DirFS(filepath.Join("..", ".."))); err != nil {
+		t.Fatalf("copy project: %v", err)
+	}
+	if err := runModule([]string{"-root", root, "order_item"}); err != nil {
+		t.Fatalf("gen module: %v", err)
+	}
+
+	for _, path := range []string{
+		"internal/biz/order_item_test.go",
+		"internal/data/order_item_test.go",
+		"internal/service/order_item_test.go",
+	} {
+		if _, err := os.Stat(filepath.Join(root, path)); err != nil {
+			t.Errorf("expected generated file: %v", err)
+		}
+	}
+
+	steps := [][]string{
+		{"go", "build", "./..."},
+		{"go", "vet", "./..."},
+		{"go", "test", "-run", "OrderItem", "./internal/biz/", "./internal/data/", "./internal/service/"},
+	}
+	for _, step := range steps {
+		cmd := exec.Command(step[0], step[1:]...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%s: %v\n%s", strings.Join(step, " "), err, out)
+		}
+	}
+}
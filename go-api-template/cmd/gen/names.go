@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// moduleNamePattern 模块名：小写字母开头，单词之间用 _ 或 - 分隔，如 order、order_item
+var moduleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*([_-][a-z0-9]+)*$`)
+
+// names 模块名的各种书写形式，供模板与代码注入使用
+type names struct {
+	// Module 项目 Go module 路径，如 go-api-template
+	Module string
+
+	Pascal       string // OrderItem
+	Camel        string // orderItem
+	Snake        string // order_item
+	Kebab        string // order-item
+	PluralPascal string // OrderItems
+	PluralSnake  string // order_items
+	PluralKebab  string // order-items
+}
+
+// newNames 解析模块名，module 为项目 Go module 路径
+func newNames(name, module string) (names, error) {
+	if !moduleNamePattern.MatchString(name) {
+		return names{}, fmt.Errorf("invalid module name %q: use lower case words separated by _ or -, e.g. order_item", name)
+	}
+	words := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' })
+
+	plural := append([]string{}, words...)
+	plural[len(plural)-1] = pluralize(plural[len(plural)-1])
+
+	n := names{
+		Module:       module,
+		Pascal:       pascal(words),
+		Snake:        strings.Join(words, "_"),
+		Kebab:        strings.Join(words, "-"),
+		PluralPascal: pascal(plural),
+		PluralSnake:  strings.Join(plural, "_"),
+		PluralKebab:  strings.Join(plural, "-"),
+	}
+	n.Camel = words[0] + n.Pascal[len(words[0]):]
+	return n, nil
+}
+
+func pascal(words []string) string {
+	var b strings.Builder
+	for _, w := range words {
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+// pluralize 英文名词复数的简化规则，覆盖常见的资源命名
+// 不规则名词（如 person）生成后可手动调整路由和表名
+func pluralize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"),
+		strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+		return word + "es"
+	case strings.HasSuffix(word, "y") && len(word) > 1 && !strings.ContainsRune("aeiou", rune(word[len(word)-2])):
+		return word[:len(word)-1] + "ies"
+	default:
+		return word + "s"
+	}
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"sigs.k8s.io/yaml"
+)
+
+// runSwagger 实现 "swagger" 子命令
+// swag 按 Go 结构体生成 proto 消息的 schema：int64 是 integer、枚举是带 x-enum-varnames 的整数、
+// oneof 是一个没有类型的接口字段，而响应实际按 protojson 编码。
+// 该命令在 swag init 之后执行，按 proto 描述重写这些 definitions，使文档与响应体一致
+func runSwagger(args []string) error {
+	fs := flag.NewFlagSet("swagger", flag.ExitOnError)
+	root := fs.String("root", ".", "project root (directory containing go.mod)")
+	dir := fs.String("dir", filepath.Join("internal", "swagger"), "swag output directory, relative to root")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: go run ./cmd/gen swagger [flags]\n\n"+
+			"Rewrite proto message schemas in the swag output to follow protojson.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	files, err := buildDescriptors(*root)
+	if err != nil {
+		return err
+	}
+	outDir := filepath.Join(*root, *dir)
+	data, err := os.ReadFile(filepath.Join(outDir, "swagger.json"))
+	if err != nil {
+		return err
+	}
+	var doc spec.Swagger
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parse swagger.json: %w", err)
+	}
+
+	n := newProtoSchemas(files, doc.Definitions).rewrite()
+	if n == 0 {
+		fmt.Println("no proto message schemas found")
+		return nil
+	}
+
+	// 与 swag 相同的编码方式，未改动的部分与 swag init 的输出逐字节一致
+	indented, err := json.MarshalIndent(&doc, "", "    ")
+	if err != nil {
+		return err
+	}
+	compact, err := json.Marshal(&doc)
+	if err != nil {
+		return err
+	}
+	yamlData, err := yaml.JSONToYAML(compact)
+	if err != nil {
+		return err
+	}
+	docsPath := filepath.Join(outDir, "docs.go")
+	docsSrc, err := os.ReadFile(docsPath)
+	if err != nil {
+		return err
+	}
+	docsOut, err := spliceDefinitions(docsSrc, indented)
+	if err != nil {
+		return fmt.Errorf("%s: %w", docsPath, err)
+	}
+
+	writes := map[string][]byte{
+		filepath.Join(outDir, "swagger.json"): indented,
+		filepath.Join(outDir, "swagger.yaml"): yamlData,
+		docsPath:                              docsOut,
+	}
+	for path, content := range writes {
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("rewrote %d proto schemas in %s\n", n, *dir)
+	return nil
+}
+
+// buildDescriptors 用 buf 编译 api 目录下的 proto 文件，返回包含源码注释的文件描述
+func buildDescriptors(root string) (*protoregistry.Files, error) {
+	if _, err := exec.LookPath("buf"); err != nil {
+		return nil, errors.New("buf not found in PATH")
+	}
+	cmd := exec.Command("buf", "build", "api/", "-o", "-")
+	cmd.Dir = root
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("buf build failed: %w", err)
+	}
+	// buf 输出的 Image 与 FileDescriptorSet 在线格式上兼容
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(out, &set); err != nil {
+		return nil, fmt.Errorf("parse buf image: %w", err)
+	}
+	return protodesc.NewFiles(&set)
+}
+
+// spliceDefinitions 用 swagger.json 中的 definitions 替换 docs.go 模板中的同一段
+// docs.go 中的规范与 swagger.json 只在 info 的模板占位符上不同，definitions 部分完全相同，
+// 只是模板是 Go 原始字符串，其中的反引号被写成 ` + "`" + `
+func spliceDefinitions(docs, swaggerJSON []byte) ([]byte, error) {
+	const key = "\n    \"definitions\": "
+	newDefs, err := jsonValueAt(swaggerJSON, key)
+	if err != nil {
+		return nil, fmt.Errorf("swagger.json: %w", err)
+	}
+
+	const backtick = "` + \"`\" + `"
+	const escaped = `\u0060`
+	// 先把模板中的反引号换成 JSON 转义，才能按 JSON 定位 definitions 的结尾
+	src := bytes.ReplaceAll(docs, []byte(backtick), []byte(escaped))
+	oldDefs, err := jsonValueAt(src, key)
+	if err != nil {
+		return nil, err
+	}
+	start := bytes.Index(src, []byte(key)) + len(key)
+	var out bytes.Buffer
+	out.Write(src[:start])
+	out.Write(bytes.ReplaceAll(newDefs, []byte("`"), []byte(escaped)))
+	out.Write(src[start+len(oldDefs):])
+	return bytes.ReplaceAll(out.Bytes(), []byte(escaped), []byte(backtick)), nil
+}
+
+// jsonValueAt 返回 key 之后紧跟的一个 JSON 值的原始文本
+func jsonValueAt(data []byte, key string) ([]byte, error) {
+	i := bytes.Index(data, []byte(key))
+	if i < 0 {
+		return nil, errors.New("definitions not found")
+	}
+	rest := data[i+len(key):]
+	dec := json.NewDecoder(bytes.NewReader(rest))
+	var v json.RawMessage
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return rest[:dec.InputOffset()], nil
+}
+
+// protoSchemas 按 protojson 的编码规则生成 proto 消息与枚举的 schema
+type protoSchemas struct {
+	files *protoregistry.Files
+	defs  spec.Definitions
+	// keys proto 全名到 definitions 键的映射，只包含 swag 已为其生成 schema 的类型
+	keys map[protoreflect.FullName]string
+	// queue 被引用但尚未生成 schema 的类型（如只出现在 oneof 中的消息）
+	queue []protoreflect.Descriptor
+	done  map[protoreflect.FullName]bool
+}
+
+// newProtoSchemas 创建 protoSchemas
+func newProtoSchemas(files *protoregistry.Files, defs spec.Definitions) *protoSchemas {
+	p := &protoSchemas{
+		files: files,
+		defs:  defs,
+		keys:  make(map[protoreflect.FullName]string),
+		done:  make(map[protoreflect.FullName]bool),
+	}
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		p.collect(fd.Messages(), fd.Enums())
+		return true
+	})
+	return p
+}
+
+// collect 找出 swag 已生成 schema 的消息与枚举，包括嵌套类型
+func (p *protoSchemas) collect(msgs protoreflect.MessageDescriptors, enums protoreflect.EnumDescriptors) {
+	for i := range enums.Len() {
+		if key := definitionKey(enums.Get(i)); hasKey(p.defs, key) {
+			p.keys[enums.Get(i).FullName()] = key
+			p.queue = append(p.queue, enums.Get(i))
+		}
+	}
+	for i := range msgs.Len() {
+		md := msgs.Get(i)
+		if md.IsMapEntry() {
+			continue
+		}
+		if hasKey(p.defs, definitionKey(md)) {
+			p.keys[md.FullName()] = definitionKey(md)
+			p.queue = append(p.queue, md)
+		}
+		p.collect(md.Messages(), md.Enums())
+	}
+}
+
+// hasKey 判断 definitions 中是否已有 key
+func hasKey(defs spec.Definitions, key string) bool {
+	_, ok := defs[key]
+	return ok
+}
+
+// rewrite 重写全部 schema，返回重写的数量
+func (p *protoSchemas) rewrite() int {
+	n := 0
+	for len(p.queue) > 0 {
+		d := p.queue[0]
+		p.queue = p.queue[1:]
+		if p.done[d.FullName()] {
+			continue
+		}
+		p.done[d.FullName()] = true
+		key := definitionKey(d)
+		switch d := d.(type) {
+		case protoreflect.MessageDescriptor:
+			p.defs[key] = p.message(d, p.defs[key])
+		case protoreflect.EnumDescriptor:
+			p.defs[key] = enumSchema(d)
+		}
+		n++
+	}
+	return n
+}
+
+// message 生成消息的 schema；字段说明优先沿用 swag 从 Go 注释中提取的内容
+func (p *protoSchemas) message(md protoreflect.MessageDescriptor, old spec.Schema) spec.Schema {
+	s := typed("object", "")
+	s.Description = old.Description
+	fields := md.Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		// protojson 的字段名由 response.field_naming 决定，文档以默认的 proto 名称为准
+		name := fd.TextName()
+		prop := p.field(fd)
+		if desc := old.Properties[name].Description; desc != "" {
+			prop.Description = desc
+		} else {
+			prop.Description = comment(md.ParentFile(), fd)
+		}
+		if prop.Description != "" && prop.Ref.String() != "" {
+			// 与 swag 相同，带说明的引用写成 allOf，否则说明会被 $ref 忽略
+			prop = *spec.ComposedSchema(spec.Schema{SchemaProps: spec.SchemaProps{Ref: prop.Ref}}).WithDescription(prop.Description)
+		}
+		s.SetProperty(name, prop)
+	}
+	return s
+}
+
+// field 生成字段的 schema，repeated 为数组，map 为 additionalProperties
+func (p *protoSchemas) field(fd protoreflect.FieldDescriptor) spec.Schema {
+	switch {
+	case fd.IsMap():
+		value := p.scalar(fd.MapValue())
+		return *spec.MapProperty(&value)
+	case fd.IsList():
+		item := p.scalar(fd)
+		return *spec.ArrayProperty(&item)
+	default:
+		return p.scalar(fd)
+	}
+}
+
+// scalar 生成单个值的 schema，规则与 protojson 相同
+func (p *protoSchemas) scalar(fd protoreflect.FieldDescriptor) spec.Schema {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return typed("boolean", "")
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return typed("integer", "")
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// 64 位整数超出 JavaScript 的安全整数范围，protojson 写成字符串
+		return typed("string", "int64")
+	case protoreflect.FloatKind:
+		return typed("number", "")
+	case protoreflect.DoubleKind:
+		return typed("number", "")
+	case protoreflect.StringKind:
+		return typed("string", "")
+	case protoreflect.BytesKind:
+		return typed("string", "byte")
+	case protoreflect.EnumKind:
+		return p.ref(fd.Enum())
+	default:
+		if s, ok := wellKnownSchema(fd.Message()); ok {
+			return s
+		}
+		return p.ref(fd.Message())
+	}
+}
+
+// ref 引用类型的 schema，尚未生成的类型加入队列
+func (p *protoSchemas) ref(d protoreflect.Descriptor) spec.Schema {
+	key, ok := p.keys[d.FullName()]
+	if !ok {
+		key = definitionKey(d)
+		p.keys[d.FullName()] = key
+		p.queue = append(p.queue, d)
+	}
+	return *spec.RefSchema("#/definitions/" + key)
+}
+
+// wellKnownSchema 返回 protojson 对 well-known 类型的特殊编码
+func wellKnownSchema(md protoreflect.MessageDescriptor) (spec.Schema, bool) {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return typed("string", "date-time"), true
+	case "google.protobuf.Duration":
+		// 如 "1.5s"
+		return typed("string", ""), true
+	case "google.protobuf.FieldMask":
+		return typed("string", ""), true
+	case "google.protobuf.Struct", "google.protobuf.Any", "google.protobuf.Empty":
+		return typed("object", ""), true
+	case "google.protobuf.Value":
+		return spec.Schema{}, true
+	case "google.protobuf.ListValue":
+		return *spec.ArrayProperty(&spec.Schema{}), true
+	case "google.protobuf.BoolValue":
+		return typed("boolean", ""), true
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value":
+		return typed("integer", ""), true
+	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return typed("string", "int64"), true
+	case "google.protobuf.FloatValue":
+		return typed("number", ""), true
+	case "google.protobuf.DoubleValue":
+		return typed("number", ""), true
+	case "google.protobuf.StringValue":
+		return typed("string", ""), true
+	case "google.protobuf.BytesValue":
+		return typed("string", "byte"), true
+	}
+	return spec.Schema{}, false
+}
+
+// typed 返回指定类型的 schema；整数与浮点数不带 format，与 swag 的输出一致
+func typed(typ, format string) spec.Schema {
+	s := spec.Schema{}
+	s.Typed(typ, format)
+	return s
+}
+
+// enumSchema 生成枚举的 schema，protojson 默认以名称编码枚举
+func enumSchema(ed protoreflect.EnumDescriptor) spec.Schema {
+	s := typed("string", "")
+	values := ed.Values()
+	for i := range values.Len() {
+		s.Enum = append(s.Enum, string(values.Get(i).Name()))
+	}
+	s.Description = comment(ed.ParentFile(), ed)
+	return s
+}
+
+// definitionKey 返回 swag 为 Go 类型生成的 definitions 键：包路径中的 / 换成 _，再接类型名
+// 嵌套类型的 Go 类型名以 _ 连接外层消息名，与 protoc-gen-go 一致
+func definitionKey(d protoreflect.Descriptor) string {
+	name := string(d.Name())
+	for parent := d.Parent(); parent != nil; parent = parent.Parent() {
+		if _, ok := parent.(protoreflect.MessageDescriptor); !ok {
+			break
+		}
+		name = string(parent.Name()) + "_" + name
+	}
+	pkg := string(d.ParentFile().Package())
+	if opts, ok := d.ParentFile().Options().(*descriptorpb.FileOptions); ok && opts.GetGoPackage() != "" {
+		importPath, _, _ := strings.Cut(opts.GetGoPackage(), ";")
+		pkg = importPath
+	}
+	return strings.ReplaceAll(pkg, "/", "_") + "." + name
+}
+
+// comment 返回 proto 定义前的注释，多行注释合并为一行
+func comment(fd protoreflect.FileDescriptor, d protoreflect.Descriptor) string {
+	loc := fd.SourceLocations().ByDescriptor(d)
+	var lines []string
+	for line := range strings.SplitSeq(loc.LeadingComments, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "")
+}
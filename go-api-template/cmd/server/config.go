@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"go.yaml.in/yaml/v3"
+
+	"go-api-template/internal/conf"
+)
+
+// runConfig 检查配置：config validate | print [-sources] | seal
+// 不初始化任何依赖，部署流水线可以在发布前单独校验配置
+func runConfig(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing action: validate | print | seal")
+	}
+	action, args := args[0], args[1:]
+	if action == "seal" {
+		return runConfigSeal(args)
+	}
+
+	fs := newFlagSet("config " + action)
+	showSources := false
+	if action == "print" {
+		fs.BoolVar(&showSources, "sources", false, "print which layer supplied each value")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// loadConfig 已包含校验，加载成功即校验通过
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "validate":
+		fmt.Printf("config %s is valid (env=%s)\n", cfg.Path(), cfg.App.Env)
+		return nil
+	case "print":
+		if showSources {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KEY\tSOURCE")
+			for _, ks := range cfg.Sources() {
+				fmt.Fprintf(w, "%s\t%s\n", ks.Key, ks.Source)
+			}
+			return w.Flush()
+		}
+		// Dump 会对秘钥做脱敏处理，输出可以安全地出现在 CI 日志中
+		out, err := yaml.Marshal(cfg.Dump())
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(out)
+		return err
+	default:
+		return fmt.Errorf("unknown action %q: expected validate | print | seal", action)
+	}
+}
+
+// runConfigSeal 生成 encfile:// 引用使用的加密秘钥文件：config seal -out FILE [-in FILE] [-key-env NAME]
+// 输入为 名称: 明文 形式的 YAML（JSON 同样可以解析），默认从标准输入读取，明文不落盘；
+// 密钥从环境变量读取（base64 编码的 32 字节，可用 openssl rand -base64 32 生成）。
+// 不加载配置：配置中的 encfile:// 引用在加密文件生成之前无法解析
+func runConfigSeal(args []string) error {
+	fs := newFlagSet("config seal")
+	in := fs.String("in", "-", "plaintext secrets file (name: value YAML), - for stdin")
+	out := fs.String("out", "", "encrypted file to write, referenced by secrets.encrypted_file")
+	keyEnv := fs.String("key-env", (&conf.SecretsConfig{}).GetKeyEnv(), "environment variable holding the base64 key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+
+	encoded := os.Getenv(*keyEnv)
+	if encoded == "" {
+		return fmt.Errorf("environment variable %s is not set", *keyEnv)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("invalid key in %s: %w", *keyEnv, err)
+	}
+
+	var content []byte
+	if *in == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(*in)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read secrets: %w", err)
+	}
+	var secrets map[string]string
+	if err := yaml.Unmarshal(content, &secrets); err != nil {
+		return fmt.Errorf("failed to parse secrets: %w", err)
+	}
+	if len(secrets) == 0 {
+		return errors.New("no secrets to seal")
+	}
+
+	sealed, err := conf.SealSecrets(key, secrets)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, append(sealed, '\n'), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("sealed %d secrets into %s\n", len(secrets), *out)
+	return nil
+}
@@ -4,6 +4,8 @@
 // @title           Go API Template
 // @version         1.0
 // @description     一个基于整洁架构的 Go API 服务模板，用于学习和实践 Go Web 开发
+// @description     请求体与响应体支持 application/json、application/x-protobuf、application/msgpack，按 Content-Type 与 Accept 协商；
+// @description     以下响应结构为 JSON 格式，protobuf 与 MessagePack 的成功响应只包含 data 部分
 
 // @contact.name   开发者
 // @contact.email  dev@example.com
@@ -22,100 +24,105 @@
 package main
 
 import (
-	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
-	"os/signal"
-	"syscall"
+	"strings"
 
 	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/logger"
 )
 
-// 命令行参数
-var configPath string
+// 全局命令行参数，对所有子命令生效
+var (
+	configPath string
+	overrides  stringList
+)
 
 func init() {
 	// 支持通过命令行参数指定配置文件路径
 	// 默认值为 configs/config.yaml（相对于项目根目录）
 	flag.StringVar(&configPath, "config", "configs/config.yaml", "config file path")
+	// 支持多次传入：-set app.port=9090 -set log.level=debug
+	flag.Var(&overrides, "set", "override a config value (key=value), repeatable")
+	flag.Usage = usage
+}
+
+// stringList 可重复传入的字符串命令行参数
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// command 子命令定义
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+// commands 所有子命令
+// 每个子命令只构建自己需要的依赖：config 只加载配置，migrate 只初始化数据层
+var commands = []command{
+	{"serve", "Start the HTTP and gRPC servers (default)", runServe},
+	{"migrate", "Run database migrations: up | down [-steps N] | status", runMigrate},
+	{"config", "Inspect configuration: validate | print [-sources] | seal", runConfig},
+	{"routes", "List registered HTTP routes and gRPC methods", runRoutes},
+	{"version", "Print version information", runVersion},
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [global flags] <command> [args]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-9s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(out, "\nGlobal flags:")
+	flag.PrintDefaults()
 }
 
 func main() {
 	flag.Parse()
 
-	// ========================================
-	// 加载配置
-	// ========================================
-	cfg, err := conf.LoadConfig(configPath)
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+	// 未指定子命令时启动服务，兼容 `server -config xxx` 的用法
+	args := flag.Args()
+	name := "serve"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
 	}
 
-	log.Printf("Loaded config: env=%s, port=%d", cfg.App.Env, cfg.App.Port)
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(args); err != nil {
+				log.Fatalf("%s: %v", name, err)
+			}
+			return
+		}
+	}
 
-	// ========================================
-	// 初始化应用
-	// ========================================
-	// 使用 Wire 生成的 wireApp 函数初始化所有依赖
-	// wireApp 定义在 wire.go，实现代码由 Wire 自动生成在 wire_gen.go
-	// wireApp 可以直接调用，因为它在同一个 package main 中
-	httpServer, err := wireApp(cfg)
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+// loadConfig 按全局参数加载并校验配置，同时初始化日志
+func loadConfig() (*conf.Config, error) {
+	cfg, err := conf.LoadConfig(configPath, conf.WithOverrides(overrides...))
 	if err != nil {
-		log.Fatalf("Failed to initialize application: %v", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
-
-	// ========================================
-	// 启动服务
-	// ========================================
-	log.Printf("Starting server on %s", httpServer.Addr())
-	log.Println("API endpoints:")
-	log.Println("  GET  /health                         - Health check")
-	log.Println("  GET  /                               - Service info")
-	log.Println("  POST /api/v1/greeter/say-hello       - Say hello (JSON body)")
-	log.Println("  GET  /api/v1/greeter/say-hello/:name - Say hello (URL param)")
-	log.Println("  GET  /swagger/*                      - Swagger UI")
-
-	// 启动 HTTP 服务器（非阻塞）
-	errChan := httpServer.Start()
-
-	// ========================================
-	// 等待关闭信号
-	// ========================================
-	// 创建关闭服务信号通道
-	quit := make(chan os.Signal, 1)
-	// 告诉操作系统：当收到 SIGINT 或 SIGTERM 时，发送到 quit 管道
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-
-	// 阻塞等待：服务器错误 或 关闭信号
-	select {
-	case err := <-errChan:
-		// 服务器启动失败或意外停止
-		log.Fatalf("Server error: %v", err)
-	case sig := <-quit:
-		// 收到关闭信号
-		log.Printf("Received signal: %v", sig)
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
 	}
-
-	// ========================================
-	// 优雅关闭
-	// ========================================
-	log.Println("Shutting down server...")
-
-	// 创建带超时的 context 用于优雅关闭
-	// 超时时间从配置读取，确保不会无限等待
-	shutdownTimeout := cfg.Server.GetShutdownTimeout()
-	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
-	defer cancel()
-
-	// 执行优雅关闭
-	// Shutdown 会：
-	// 1. 停止接受新连接
-	// 2. 等待正在处理的请求完成（或直到 context 超时）
-	// 3. 关闭所有空闲连接
-	if err := httpServer.Stop(ctx); err != nil {
-		log.Printf("Server forced to shutdown: %v", err)
-	} else {
-		log.Println("Server gracefully stopped")
+	if err := logger.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		return nil, fmt.Errorf("failed to setup logger: %w", err)
 	}
+	return cfg, nil
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/server"
+)
+
+// routeTable 是 wireRoutes 构建的服务器，只用于读取注册结果，不会启动
+type routeTable struct {
+	HTTP *server.HTTPServer
+	GRPC *server.GRPCServer
+}
+
+// runRoutes 列出所有 HTTP 路由和 gRPC 方法，按路径排序
+// 以与 serve 相同的方式注册路由，但依赖替换为不访问外部资源的替身（见 wireRoutes），
+// 没有数据库、Redis 的环境中同样可以执行
+func runRoutes(args []string) error {
+	fs := newFlagSet("routes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	// release 模式下 gin 注册路由时不逐条打印调试日志，输出只包含路由表本身
+	// 服务器按 app.env 设置 gin 模式，显式设置的 GIN_MODE 优先，这里也不覆盖它
+	if os.Getenv(gin.EnvGinMode) == "" {
+		if err := os.Setenv(gin.EnvGinMode, gin.ReleaseMode); err != nil {
+			return err
+		}
+	}
+
+	t, cleanup, err := wireRoutes(cfg, conf.NewWatcher(cfg))
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	routes := t.HTTP.Routes()
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "HTTP (%s)\n", t.HTTP.Addr())
+	for _, r := range routes {
+		fmt.Fprintf(w, "  %s\t%s\n", r.Method, r.Path)
+	}
+	fmt.Fprintf(w, "\ngRPC (%s)\n", t.GRPC.Addr())
+	for _, m := range t.GRPC.Methods() {
+		fmt.Fprintf(w, "  %s\n", m)
+	}
+	return w.Flush()
+}
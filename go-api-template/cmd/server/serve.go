@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/event"
+	"go-api-template/internal/pkg/jobs"
+	"go-api-template/internal/pkg/logger"
+	"go-api-template/internal/pkg/webhook"
+	"go-api-template/internal/server"
+)
+
+// app 聚合需要随进程启动和关闭的服务器与后台任务
+type app struct {
+	http     *server.HTTPServer
+	grpc     *server.GRPCServer
+	relay    *event.Relay
+	webhooks *webhook.Dispatcher
+	jobs     *jobs.Manager
+}
+
+// newApp 创建 app，由 Wire 注入各服务器
+func newApp(httpServer *server.HTTPServer, grpcServer *server.GRPCServer, relay *event.Relay, webhooks *webhook.Dispatcher, jobManager *jobs.Manager) *app {
+	return &app{http: httpServer, grpc: grpcServer, relay: relay, webhooks: webhooks, jobs: jobManager}
+}
+
+// runServe 启动 HTTP 与 gRPC 服务器，收到 SIGINT/SIGTERM 后优雅关闭
+func runServe(args []string) error {
+	fs := newFlagSet("serve")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// ========================================
+	// 加载配置
+	// ========================================
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Loaded config: env=%s, port=%d", cfg.App.Env, cfg.App.Port)
+	for _, ks := range cfg.Sources() {
+		if ks.Source.Kind != conf.SourceBase {
+			log.Printf("  config %s <- %s", ks.Key, ks.Source)
+		}
+	}
+
+	// ========================================
+	// 配置热加载
+	// ========================================
+	// 修改配置文件或发送 SIGHUP 会重新加载配置，只有可热加载的配置项会生效
+	watcher := conf.NewWatcher(cfg)
+	watcher.Subscribe(func(e conf.ChangeEvent) {
+		if e.Changed("log.level") {
+			if err := logger.SetLevel(e.New.Log.Level); err != nil {
+				log.Printf("Failed to apply log level: %v", err)
+			}
+		}
+	})
+	if err := watcher.Start(); err != nil {
+		return fmt.Errorf("failed to start config watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// ========================================
+	// 初始化应用
+	// ========================================
+	// 使用 Wire 生成的 wireApp 函数初始化所有依赖
+	// wireApp 定义在 wire.go，实现代码由 Wire 自动生成在 wire_gen.go
+	// cleanup 按依赖的逆序释放资源（如关闭数据库连接）
+	a, cleanup, err := wireApp(cfg, watcher)
+	if err != nil {
+		return fmt.Errorf("failed to initialize application: %w", err)
+	}
+	defer cleanup()
+
+	// ========================================
+	// 启动服务
+	// ========================================
+	log.Printf("Starting HTTP server on %s, gRPC server on %s", a.http.Addr(), a.grpc.Addr())
+	log.Println("Run `server routes` to list all HTTP routes and gRPC methods")
+
+	// 启动服务器（非阻塞）
+	httpErr := a.http.Start()
+	grpcErr := a.grpc.Start()
+	// 投递 outbox 中的领域事件（含上次退出时未投递完的）
+	a.relay.Start()
+	// 向外部订阅者投递 webhook
+	a.webhooks.Start()
+	// 执行后台任务与定时任务
+	a.jobs.Start()
+
+	// ========================================
+	// 等待关闭信号
+	// ========================================
+	// 创建关闭服务信号通道
+	quit := make(chan os.Signal, 1)
+	// 告诉操作系统：当收到 SIGINT 或 SIGTERM 时，发送到 quit 管道
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	// 阻塞等待：服务器错误 或 关闭信号
+	// 服务器出错时不直接退出进程，仍然走优雅关闭流程，保证 cleanup 被执行
+	var serveErr error
+	select {
+	case err := <-httpErr:
+		serveErr = fmt.Errorf("http server error: %w", err)
+	case err := <-grpcErr:
+		serveErr = fmt.Errorf("grpc server error: %w", err)
+	case sig := <-quit:
+		// 收到关闭信号
+		log.Printf("Received signal: %v", sig)
+	}
+
+	// ========================================
+	// 优雅关闭
+	// ========================================
+	log.Println("Shutting down server...")
+
+	// 创建带超时的 context 用于优雅关闭
+	// 超时时间从配置读取，确保不会无限等待
+	shutdownTimeout := cfg.Server.GetShutdownTimeout()
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// 执行优雅关闭
+	// Shutdown 会：
+	// 1. 停止接受新连接
+	// 2. 等待正在处理的请求完成（或直到 context 超时）
+	// 3. 关闭所有空闲连接
+	if err := a.http.Stop(ctx); err != nil {
+		log.Printf("HTTP server forced to shutdown: %v", err)
+	}
+	if err := a.grpc.Stop(ctx); err != nil {
+		log.Printf("gRPC server forced to shutdown: %v", err)
+	}
+	// 服务器停止后不再产生新事件，最后停止 relay；未投递的事件留在 outbox 中
+	if err := a.relay.Stop(ctx); err != nil {
+		log.Printf("Event relay forced to stop: %v", err)
+	}
+	// relay 停止后不再产生新的投递记录；未完成的投递留在数据库中，下次启动后继续
+	if err := a.webhooks.Stop(ctx); err != nil {
+		log.Printf("Webhook dispatcher forced to stop: %v", err)
+	}
+	// 等待执行中的后台任务完成；超时未完成的任务重新排队，下次启动后继续
+	if err := a.jobs.Stop(ctx); err != nil {
+		log.Printf("Job manager forced to stop: %v", err)
+	}
+	if serveErr == nil {
+		log.Println("Server gracefully stopped")
+	}
+	return serveErr
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// 版本信息，构建时通过 -ldflags 注入：
+// go build -ldflags "-X main.version=v1.2.0 -X main.commit=abc123 -X main.buildTime=..."
+var (
+	version   = "dev"
+	commit    = ""
+	buildTime = ""
+)
+
+// runVersion 打印版本信息
+// 未通过 ldflags 注入时，从 Go 工具链嵌入的 VCS 信息中读取
+func runVersion(args []string) error {
+	fs := newFlagSet("version")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rev, at := commit, buildTime
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			switch {
+			case s.Key == "vcs.revision" && rev == "":
+				rev = s.Value
+			case s.Key == "vcs.time" && at == "":
+				at = s.Value
+			}
+		}
+	}
+
+	fmt.Printf("version:    %s\n", version)
+	fmt.Printf("commit:     %s\n", orUnknown(rev))
+	fmt.Printf("build time: %s\n", orUnknown(at))
+	fmt.Printf("go:         %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	return nil
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
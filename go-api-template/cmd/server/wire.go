@@ -12,6 +12,15 @@ import (
 	"go-api-template/internal/biz"
 	"go-api-template/internal/conf"
 	"go-api-template/internal/data"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/pkg/crash"
+	"go-api-template/internal/pkg/event"
+	"go-api-template/internal/pkg/featureflags"
+	"go-api-template/internal/pkg/idempotency"
+	"go-api-template/internal/pkg/jobs"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/tenant"
+	"go-api-template/internal/pkg/webhook"
 	"go-api-template/internal/server"
 	"go-api-template/internal/service"
 )
@@ -20,19 +29,78 @@ import (
 // Wire 会分析这个函数，根据 ProviderSet 中的构造函数自动生成依赖组装代码
 //
 // 函数签名说明：
-// - 参数：*conf.Config 由 main 加载后传入
-// - 返回值：*server.HTTPServer 封装了 Gin 引擎和配置
+// - 参数：*conf.Config 由 main 加载后传入；*conf.Watcher 提供可热加载的配置
+// - 返回值：*app 聚合 HTTP 与 gRPC 服务器、事件 relay 与 webhook 投递、后台任务；cleanup 按逆序释放资源
 // - 函数体：调用 wire.Build 并传入所有 ProviderSet
-func wireApp(c *conf.Config) (*server.HTTPServer, error) {
+func wireApp(c *conf.Config, w *conf.Watcher) (*app, func(), error) {
 	// wire.Build 声明所有需要的 Provider
 	// Wire 会分析依赖关系，按正确顺序调用构造函数
 	wire.Build(
-		data.ProviderSet,    // Data -> GreeterRepo
-		biz.ProviderSet,     // GreeterUsecase
-		service.ProviderSet, // GreeterService
-		server.ProviderSet,  // HTTPServer
+		data.NewData,             // Data：数据库连接
+		data.ProviderSet,         // Data -> GreeterRepo
+		biz.ProviderSet,          // GreeterUsecase
+		service.ProviderSet,      // GreeterService
+		server.ProviderSet,       // HTTPServer
+		auth.ProviderSet,         // TokenManager
+		idempotency.ProviderSet,  // Idempotency Store
+		event.ProviderSet,        // 领域事件 Relay 与 Publisher
+		webhook.ProviderSet,      // Webhook 投递 Dispatcher
+		jobs.ProviderSet,         // 后台任务 Manager 与 Store
+		crash.ProviderSet,        // panic 上报
+		pagination.ProviderSet,   // 列表分页游标签名
+		tenant.ProviderSet,       // 请求租户解析
+		featureflags.ProviderSet, // 功能开关
+		// 问候语模板来自热加载的配置，biz 层只依赖 GreetingTemplateSource 接口
+		wire.Bind(new(biz.GreetingTemplateSource), new(*conf.Watcher)),
+		// 登录令牌由 auth 包以 JWT 实现，biz 层只依赖 TokenIssuer 接口
+		wire.Bind(new(biz.TokenIssuer), new(*auth.TokenManager)),
+		// 租户解析从访问令牌中读取租户，复用同一个 TokenManager
+		wire.Bind(new(tenant.TokenParser), new(*auth.TokenManager)),
+		// 订阅地址的网络校验与投递时的连接校验使用同一个 Guard
+		wire.Bind(new(biz.WebhookTargetGuard), new(*webhook.Guard)),
+		newApp,
 	)
 
 	// 占位返回，Wire 会替换整个函数体
-	return nil, nil
+	return nil, nil, nil
+}
+
+// wireMigrator 只构建数据层，供 migrate 子命令使用
+// 不依赖 biz/service/server，迁移时不会初始化任何业务组件
+func wireMigrator(c *conf.Config) (*data.Migrator, func(), error) {
+	wire.Build(
+		data.NewData,
+		data.NewMigrator,
+	)
+	return nil, nil, nil
+}
+
+// wireRoutes 只构建 HTTP 与 gRPC 服务器，供 routes 子命令使用
+// 路由注册与真实服务一致，但不连接数据库、Redis，不打开事件与 panic 上报的输出：
+// 数据层固定使用内存存储，幂等键存储、任务队列与 panic 上报均以进程内的替身代替
+func wireRoutes(c *conf.Config, w *conf.Watcher) (*routeTable, func(), error) {
+	wire.Build(
+		data.NewMemoryData,
+		data.ProviderSet,
+		biz.ProviderSet,
+		service.ProviderSet,
+		server.ProviderSet,
+		auth.ProviderSet,
+		pagination.ProviderSet,
+		tenant.ProviderSet,
+		featureflags.ProviderSet,
+		event.NewBus,
+		jobs.NewManager,
+		idempotency.NewMemoryStore,
+		wire.Bind(new(idempotency.Store), new(*idempotency.MemoryStore)),
+		wire.Bind(new(jobs.Store), new(jobs.DBStore)),
+		wire.InterfaceValue(new(crash.PanicReporter), crash.Nop{}),
+		webhook.NewGuard,
+		wire.Bind(new(biz.WebhookTargetGuard), new(*webhook.Guard)),
+		wire.Bind(new(biz.GreetingTemplateSource), new(*conf.Watcher)),
+		wire.Bind(new(biz.TokenIssuer), new(*auth.TokenManager)),
+		wire.Bind(new(tenant.TokenParser), new(*auth.TokenManager)),
+		wire.Struct(new(routeTable), "*"),
+	)
+	return nil, nil, nil
 }
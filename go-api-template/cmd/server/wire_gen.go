@@ -10,6 +10,15 @@ import (
 	"go-api-template/internal/biz"
 	"go-api-template/internal/conf"
 	"go-api-template/internal/data"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/pkg/crash"
+	"go-api-template/internal/pkg/event"
+	"go-api-template/internal/pkg/featureflags"
+	"go-api-template/internal/pkg/idempotency"
+	"go-api-template/internal/pkg/jobs"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/tenant"
+	"go-api-template/internal/pkg/webhook"
 	"go-api-template/internal/server"
 	"go-api-template/internal/service"
 )
@@ -20,17 +29,205 @@ import (
 // Wire 会分析这个函数，根据 ProviderSet 中的构造函数自动生成依赖组装代码
 //
 // 函数签名说明：
-// - 参数：*conf.Config 由 main 加载后传入
-// - 返回值：*server.HTTPServer 封装了 Gin 引擎和配置
+// - 参数：*conf.Config 由 main 加载后传入；*conf.Watcher 提供可热加载的配置
+// - 返回值：*app 聚合 HTTP 与 gRPC 服务器、事件 relay 与 webhook 投递、后台任务；cleanup 按逆序释放资源
 // - 函数体：调用 wire.Build 并传入所有 ProviderSet
-func wireApp(c *conf.Config) (*server.HTTPServer, error) {
-	dataData, err := data.NewData(c)
+func wireApp(c *conf.Config, w *conf.Watcher) (*app, func(), error) {
+	tokenManager, err := auth.NewTokenManager(c)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	resolver := tenant.NewResolver(w, tokenManager)
+	manager, cleanup, err := featureflags.NewManager(w)
+	if err != nil {
+		return nil, nil, err
+	}
+	store, cleanup2, err := idempotency.NewStore(c)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	panicReporter, cleanup3, err := crash.NewReporter(c)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	dataData, cleanup4, err := data.NewData(c)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	greeterRepo := data.NewGreeterRepo(dataData)
+	transaction := data.NewTransaction(dataData)
+	outbox := data.NewOutbox(dataData)
+	greeterUsecase := biz.NewGreeterUsecase(greeterRepo, transaction, w, outbox)
+	codec, err := pagination.NewCodec(c)
+	if err != nil {
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	greeterService := service.NewGreeterService(greeterUsecase, c, codec)
+	userRepo := data.NewUserRepo(dataData)
+	userUsecase, err := biz.NewUserUsecase(userRepo, tokenManager)
+	if err != nil {
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	userService := service.NewUserService(userUsecase)
+	orderRepo := data.NewOrderRepo(dataData)
+	orderUsecase := biz.NewOrderUsecase(orderRepo)
+	orderService := service.NewOrderService(orderUsecase, codec)
+	webhookStore := data.NewWebhookStore(dataData)
+	guard := webhook.NewGuard(c)
+	webhookUsecase := biz.NewWebhookUsecase(webhookStore, transaction, guard)
+	bus := event.NewBus()
+	dbStore := data.NewJobStore(dataData)
+	jobsStore, cleanup5, err := jobs.NewStore(c, dbStore)
+	if err != nil {
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	jobsManager, err := jobs.NewManager(c, jobsStore)
+	if err != nil {
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	webhookService := service.NewWebhookService(webhookUsecase, bus, jobsManager, c, codec)
+	jobService := service.NewJobService(jobsManager, c)
+	featureFlagService := service.NewFeatureFlagService(manager, c)
+	services := &server.Services{
+		Greeter:     greeterService,
+		User:        userService,
+		Order:       orderService,
+		Webhook:     webhookService,
+		Job:         jobService,
+		FeatureFlag: featureFlagService,
+	}
+	httpServer := server.NewHTTPServer(c, w, tokenManager, resolver, manager, store, panicReporter, services)
+	grpcServer := server.NewGRPCServer(c, tokenManager, resolver, manager, panicReporter, services)
+	publisher, cleanup6, err := event.NewPublisher(c, bus)
+	if err != nil {
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	relay := event.NewRelay(c, outbox, publisher)
+	dispatcher := webhook.NewDispatcher(c, webhookStore, guard)
+	mainApp := newApp(httpServer, grpcServer, relay, dispatcher, jobsManager)
+	return mainApp, func() {
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+	}, nil
+}
+
+// wireMigrator 只构建数据层，供 migrate 子命令使用
+// 不依赖 biz/service/server，迁移时不会初始化任何业务组件
+func wireMigrator(c *conf.Config) (*data.Migrator, func(), error) {
+	dataData, cleanup, err := data.NewData(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	migrator, err := data.NewMigrator(dataData)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return migrator, func() {
+		cleanup()
+	}, nil
+}
+
+// wireRoutes 只构建 HTTP 与 gRPC 服务器，供 routes 子命令使用
+// 路由注册与真实服务一致，但不连接数据库、Redis，不打开事件与 panic 上报的输出：
+// 数据层固定使用内存存储，幂等键存储、任务队列与 panic 上报均以进程内的替身代替
+func wireRoutes(c *conf.Config, w *conf.Watcher) (*routeTable, func(), error) {
+	tokenManager, err := auth.NewTokenManager(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolver := tenant.NewResolver(w, tokenManager)
+	manager, cleanup, err := featureflags.NewManager(w)
+	if err != nil {
+		return nil, nil, err
+	}
+	memoryStore := idempotency.NewMemoryStore()
+	panicReporter := _wireNopValue
+	dataData := data.NewMemoryData(c)
 	greeterRepo := data.NewGreeterRepo(dataData)
-	greeterUsecase := biz.NewGreeterUsecase(greeterRepo)
-	greeterService := service.NewGreeterService(greeterUsecase)
-	httpServer := server.NewHTTPServer(c, greeterService)
-	return httpServer, nil
+	transaction := data.NewTransaction(dataData)
+	outbox := data.NewOutbox(dataData)
+	greeterUsecase := biz.NewGreeterUsecase(greeterRepo, transaction, w, outbox)
+	codec, err := pagination.NewCodec(c)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	greeterService := service.NewGreeterService(greeterUsecase, c, codec)
+	userRepo := data.NewUserRepo(dataData)
+	userUsecase, err := biz.NewUserUsecase(userRepo, tokenManager)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	userService := service.NewUserService(userUsecase)
+	orderRepo := data.NewOrderRepo(dataData)
+	orderUsecase := biz.NewOrderUsecase(orderRepo)
+	orderService := service.NewOrderService(orderUsecase, codec)
+	webhookStore := data.NewWebhookStore(dataData)
+	guard := webhook.NewGuard(c)
+	webhookUsecase := biz.NewWebhookUsecase(webhookStore, transaction, guard)
+	bus := event.NewBus()
+	dbStore := data.NewJobStore(dataData)
+	jobsManager, err := jobs.NewManager(c, dbStore)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	webhookService := service.NewWebhookService(webhookUsecase, bus, jobsManager, c, codec)
+	jobService := service.NewJobService(jobsManager, c)
+	featureFlagService := service.NewFeatureFlagService(manager, c)
+	services := &server.Services{
+		Greeter:     greeterService,
+		User:        userService,
+		Order:       orderService,
+		Webhook:     webhookService,
+		Job:         jobService,
+		FeatureFlag: featureFlagService,
+	}
+	httpServer := server.NewHTTPServer(c, w, tokenManager, resolver, manager, memoryStore, panicReporter, services)
+	grpcServer := server.NewGRPCServer(c, tokenManager, resolver, manager, panicReporter, services)
+	mainRouteTable := &routeTable{
+		HTTP: httpServer,
+		GRPC: grpcServer,
+	}
+	return mainRouteTable, func() {
+		cleanup()
+	}, nil
 }
+
+var (
+	_wireNopValue = crash.Nop{}
+)
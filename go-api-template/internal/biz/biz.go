@@ -1,5 +1,8 @@
 // Package biz 是领域层，包含核心业务逻辑和领域模型。
 // 这是整洁架构的核心，不依赖任何外部层。
+//
+// 所有仓储接口的读写都限定在 ctx 携带的租户（见 pkg/tenant）之内：
+// 实现只能读到、改到当前租户的记录，ctx 中没有租户时返回错误而不是访问全部数据。
 package biz
 
 import "github.com/google/wire"
@@ -8,7 +11,8 @@ import "github.com/google/wire"
 // 新增模块时，只需在对应文件定义 XxxProviderSet，然后添加到这里
 var ProviderSet = wire.NewSet(
 	GreeterProviderSet,
-	// UserProviderSet,    // 未来：用户模块
-	// OrderProviderSet,   // 未来：订单模块
+	UserProviderSet,    // User 模块
+	OrderProviderSet,   // Order 模块
+	WebhookProviderSet, // Webhook 模块
 	// ProductProviderSet, // 未来：商品模块
 )
@@ -0,0 +1,24 @@
+package biz
+
+import "errors"
+
+// 领域层通用错误
+// 仓储和用例通过 fmt.Errorf("...: %w", ErrXxx) 包装后返回，
+// 上层（server）使用 errors.Is 识别并映射为对应的 HTTP 状态码 / gRPC 状态码，
+// 领域层本身不依赖任何传输协议。
+var (
+	// ErrNotFound 资源不存在
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists 唯一性约束冲突（如用户名已被占用）
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrConflict 操作与资源当前状态冲突（如订单状态不允许该流转）
+	ErrConflict = errors.New("conflict")
+	// ErrVersionMismatch 调用方持有的版本已过期，资源在读取之后被其他请求修改过
+	ErrVersionMismatch = errors.New("version mismatch")
+	// ErrInvalidArgument 输入不满足业务规则（如密码强度不足）
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrUnauthorized 身份无法确认（未登录、凭证错误）
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden 身份已确认，但无权执行该操作（如非管理员调用管理接口）
+	ErrForbidden = errors.New("forbidden")
+)
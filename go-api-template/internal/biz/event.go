@@ -0,0 +1,31 @@
+package biz
+
+import (
+	"context"
+	"time"
+)
+
+// Event 领域事件，描述领域中已经发生的事实
+// 事件以 JSON 序列化后写入 outbox，字段需要带 json tag，并且只增不改以保持下游兼容
+type Event interface {
+	// EventType 事件类型，格式为 <模块>.<过去式动作>，下游据此订阅
+	EventType() string
+}
+
+// EventOutbox 领域事件的发件箱
+// 在 Transaction.InTx 中调用时，事件与实体在同一事务中写入：事务回滚则事件不会发出，
+// 事务提交则事件最终一定会被投递（至少一次）
+type EventOutbox interface {
+	Add(ctx context.Context, events ...Event) error
+}
+
+// GreetingCreated 一条问候记录已创建
+type GreetingCreated struct {
+	GreeterID int64     `json:"greeter_id"`
+	Name      string    `json:"name"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EventType 实现 Event
+func (GreetingCreated) EventType() string { return "greeter.greeting_created" }
@@ -3,19 +3,33 @@ package biz
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/wire"
+
+	"go-api-template/internal/pkg/featureflags"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/tenant"
 )
 
 // GreeterProviderSet 是 Greeter 模块的依赖提供者集合
 var GreeterProviderSet = wire.NewSet(NewGreeterUsecase)
 
+// FlagGreetingNormalizeName 功能开关：问候语中的名称去掉多余空白并将每个单词首字母大写
+// （"  ada   lovelace " -> "Ada Lovelace"）。通过 features.flags 逐步放量，
+// 问候记录中保存的仍是原始名称，按名称查询与推送过滤不受影响
+const FlagGreetingNormalizeName = "greeting_normalize_name"
+
 // Greeter 是领域实体，表示一条问候记录
 type Greeter struct {
 	ID        int64     // 唯一标识
 	Name      string    // 被问候者名称
 	Message   string    // 问候消息
+	Version   int64     // 版本号，问候记录保存后不再修改，始终为初始版本
 	CreatedAt time.Time // 创建时间
 }
 
@@ -24,47 +38,156 @@ type Greeter struct {
 type GreeterRepo interface {
 	// Save 保存一条问候记录
 	Save(ctx context.Context, g *Greeter) (*Greeter, error)
+	// Get 按 ID 获取问候记录，不存在时返回包装了 ErrNotFound 的错误
+	Get(ctx context.Context, id int64) (*Greeter, error)
 	// GetByName 根据名称获取最近的问候记录
 	GetByName(ctx context.Context, name string) (*Greeter, error)
-	// Count 获取问候总数
+	// Count 获取当前租户的问候总数，访问序号按租户分别计数
+	// 在事务中调用时，实现需保证其他事务在本事务结束前无法插入新记录
 	Count(ctx context.Context) (int64, error)
+	// ListSince 按 ID 升序返回 ID 大于 afterID 的问候记录，最多 limit 条
+	// name 非空时只返回该名称的记录
+	ListSince(ctx context.Context, afterID int64, name string, limit int) ([]*Greeter, error)
+	// LatestID 返回当前租户最新一条问候记录的 ID，没有记录时返回 0
+	LatestID(ctx context.Context) (int64, error)
+	// List 按 req 的排序、过滤条件列出当前租户问候记录中的一页，可用字段见 GreeterListSpec
+	List(ctx context.Context, req *pagination.Request) (*pagination.Page[*Greeter], error)
+}
+
+// GreeterListSpec 问候记录列表允许的排序与过滤字段
+var GreeterListSpec = &pagination.Spec{
+	Fields: []pagination.Field{
+		{Name: "id", Type: pagination.Int, Sortable: true},
+		{Name: "created_at", Type: pagination.Time, Sortable: true, Filterable: true},
+		{Name: "name", Type: pagination.String, Filterable: true},
+	},
+	Key:         "id",
+	DefaultSort: "-id",
+}
+
+// GreeterListValue 返回问候记录在 GreeterListSpec 中字段的值，供内存分页与构造下一页游标使用
+func GreeterListValue(g *Greeter, field string) any {
+	switch field {
+	case "id":
+		return g.ID
+	case "created_at":
+		return g.CreatedAt.UTC()
+	case "name":
+		return g.Name
+	}
+	return nil
+}
+
+// GreetingTemplateSource 提供当前生效的问候语模板
+// 模板可能在运行期被修改（配置热加载），因此每次问候时都重新读取；租户可以覆盖全局模板
+// 占位符：{name} 被问候者名称，{visitor} 访问序号
+type GreetingTemplateSource interface {
+	GreetingTemplate(tenantID string) string
 }
 
 // GreeterUsecase 是问候业务用例，包含核心业务逻辑
 type GreeterUsecase struct {
-	repo GreeterRepo
+	repo      GreeterRepo
+	tx        Transaction
+	templates GreetingTemplateSource
+	outbox    EventOutbox
 }
 
 // NewGreeterUsecase 创建 GreeterUsecase 实例
 // repo 参数通过依赖注入传入，Usecase 不知道也不关心具体实现
-func NewGreeterUsecase(repo GreeterRepo) *GreeterUsecase {
-	return &GreeterUsecase{repo: repo}
+func NewGreeterUsecase(repo GreeterRepo, tx Transaction, templates GreetingTemplateSource, outbox EventOutbox) *GreeterUsecase {
+	return &GreeterUsecase{repo: repo, tx: tx, templates: templates, outbox: outbox}
 }
 
 // SayHello 执行问候业务逻辑
 // 核心逻辑：创建问候记录并返回个性化消息
+// 计数与保存在同一事务中完成，并发请求不会拿到相同的访问序号；
+// GreetingCreated 事件也在该事务中写入 outbox，保存失败时不会发出事件
 func (uc *GreeterUsecase) SayHello(ctx context.Context, name string) (*Greeter, error) {
-	// 获取当前问候总数，用于生成个性化消息
-	count, err := uc.repo.Count(ctx)
+	tenantID, err := tenant.Require(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get count: %w", err)
+		return nil, err
+	}
+	displayName := name
+	if featureflags.Enabled(ctx, FlagGreetingNormalizeName) {
+		displayName = normalizeName(name)
 	}
+	var saved *Greeter
+	err = uc.tx.InTx(ctx, func(ctx context.Context) error {
+		// 获取当前问候总数，用于生成个性化消息
+		count, err := uc.repo.Count(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get count: %w", err)
+		}
 
-	// 构建问候消息,sprintf用于格式化字符串，不输出到控制台，而是返回一个字符串
-	message := fmt.Sprintf("Hello, %s! You are visitor #%d.", name, count+1)
+		// 构建问候消息，模板由配置提供，访问序号按租户计数
+		message := renderGreeting(uc.templates.GreetingTemplate(tenantID), displayName, count+1)
 
-	// 创建问候记录,greeter 是问候记录的结构体，且没有 ID
-	greeter := &Greeter{
-		Name:      name,
-		Message:   message,
-		CreatedAt: time.Now(),
-	}
+		// 创建问候记录,greeter 是问候记录的结构体，且没有 ID
+		greeter := &Greeter{
+			Name:      name,
+			Message:   message,
+			Version:   initialVersion,
+			CreatedAt: time.Now(),
+		}
+
+		// 保存到存储,saved 是保存后的问候记录，就是 greeter 的副本，但是有 ID
+		saved, err = uc.repo.Save(ctx, greeter)
+		if err != nil {
+			return fmt.Errorf("failed to save greeter: %w", err)
+		}
 
-	// 保存到存储,saved 是保存后的问候记录，就是 greeter 的副本，但是有 ID
-	saved, err := uc.repo.Save(ctx, greeter)
+		err = uc.outbox.Add(ctx, GreetingCreated{
+			GreeterID: saved.ID,
+			Name:      saved.Name,
+			Message:   saved.Message,
+			CreatedAt: saved.CreatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record greeting event: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to save greeter: %w", err)
+		return nil, err
 	}
-
 	return saved, nil
 }
+
+// GetGreeting 按 ID 获取问候记录
+func (uc *GreeterUsecase) GetGreeting(ctx context.Context, id int64) (*Greeter, error) {
+	return uc.repo.Get(ctx, id)
+}
+
+// ListGreetings 分页列出当前租户的问候记录，req 由 GreeterListSpec 解析得到
+func (uc *GreeterUsecase) ListGreetings(ctx context.Context, req *pagination.Request) (*pagination.Page[*Greeter], error) {
+	return uc.repo.List(ctx, req)
+}
+
+// GreetingsSince 按保存顺序返回 afterID 之后的问候记录，用于推送新问候与断线续传
+func (uc *GreeterUsecase) GreetingsSince(ctx context.Context, afterID int64, name string, limit int) ([]*Greeter, error) {
+	return uc.repo.ListSince(ctx, afterID, name, limit)
+}
+
+// LatestGreetingID 返回最新一条问候记录的 ID，订阅者从这里开始接收新问候
+func (uc *GreeterUsecase) LatestGreetingID(ctx context.Context) (int64, error) {
+	return uc.repo.LatestID(ctx)
+}
+
+// normalizeName 合并名称中的空白，并将每个单词的首字母大写，其余字符保持原样
+func normalizeName(name string) string {
+	words := strings.Fields(name)
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToUpper(r)) + w[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+// renderGreeting 用名称和访问序号填充问候语模板
+func renderGreeting(template, name string, visitor int64) string {
+	return strings.NewReplacer(
+		"{name}", name,
+		"{visitor}", strconv.FormatInt(visitor, 10),
+	).Replace(template)
+}
@@ -0,0 +1,232 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/wire"
+
+	"go-api-template/internal/pkg/pagination"
+)
+
+// OrderProviderSet 是 Order 模块的依赖提供者集合
+var OrderProviderSet = wire.NewSet(NewOrderUsecase)
+
+// Order 是领域实体，表示一个用户订单
+// 金额以最小货币单位（分）的整数保存，避免浮点数的舍入误差
+type Order struct {
+	ID        int64
+	UserID    int64  // 下单用户
+	Product   string // 商品名称
+	Quantity  int32  // 数量
+	Amount    int64  // 订单总金额（分）
+	Status    OrderStatus
+	Version   int64 // 乐观锁版本号，每次流转加一
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// OrderRepo 定义了订单数据的存储接口
+type OrderRepo interface {
+	// Create 保存新订单与创建记录，回填两者的 ID
+	Create(ctx context.Context, o *Order, t *OrderTransition) (*Order, error)
+	// Get 按 ID 获取订单
+	Get(ctx context.Context, id int64) (*Order, error)
+	// ListByUser 按 req 的排序、过滤条件列出用户订单中的一页，可用字段见 OrderListSpec
+	ListByUser(ctx context.Context, userID int64, req *pagination.Request) (*pagination.Page[*Order], error)
+	// Transition 以 o.Version 为条件将订单从 t.From 流转到 t.To 并写入审计记录，两者原子完成，成功后 o.Version 加一
+	// 订单已被并发请求抢先修改时返回包装了 ErrVersionMismatch 的错误
+	Transition(ctx context.Context, o *Order, t *OrderTransition) error
+	// ListTransitions 按时间顺序列出订单的流转记录
+	ListTransitions(ctx context.Context, orderID int64) ([]*OrderTransition, error)
+}
+
+// OrderListSpec 订单列表允许的排序与过滤字段
+// 字段名与 API 中的字段名一致，Repo 实现负责映射到存储中的列
+var OrderListSpec = &pagination.Spec{
+	Fields: []pagination.Field{
+		{Name: "id", Type: pagination.Int, Sortable: true},
+		{Name: "created_at", Type: pagination.Time, Sortable: true, Filterable: true},
+		{Name: "updated_at", Type: pagination.Time, Sortable: true},
+		{Name: "amount", Type: pagination.Int, Sortable: true, Filterable: true},
+		{Name: "quantity", Type: pagination.Int, Filterable: true},
+		{Name: "status", Type: pagination.Enum, Filterable: true, Values: []string{"pending", "paid", "shipped", "completed", "cancelled"}},
+		{Name: "product", Type: pagination.String, Filterable: true},
+	},
+	Key:         "id",
+	DefaultSort: "-id",
+}
+
+// OrderListValue 返回订单在 OrderListSpec 中字段的值，供内存分页与构造下一页游标使用
+func OrderListValue(o *Order, field string) any {
+	switch field {
+	case "id":
+		return o.ID
+	case "created_at":
+		return o.CreatedAt.UTC()
+	case "updated_at":
+		return o.UpdatedAt.UTC()
+	case "amount":
+		return o.Amount
+	case "quantity":
+		return int64(o.Quantity)
+	case "status":
+		return o.Status.String()
+	case "product":
+		return o.Product
+	}
+	return nil
+}
+
+// 订单规则
+const (
+	maxOrderProductLen = 200
+	maxOrderNoteLen    = 500
+)
+
+// OrderUsecase 是订单业务用例
+// 订单只对下单用户可见，其他用户访问时表现为订单不存在
+type OrderUsecase struct {
+	repo OrderRepo
+}
+
+// NewOrderUsecase 创建 OrderUsecase 实例
+func NewOrderUsecase(repo OrderRepo) *OrderUsecase {
+	return &OrderUsecase{repo: repo}
+}
+
+// Create 创建待支付订单
+func (uc *OrderUsecase) Create(ctx context.Context, userID int64, product string, quantity int32, amount int64) (*Order, error) {
+	product = strings.TrimSpace(product)
+	switch {
+	case product == "" || utf8.RuneCountInString(product) > maxOrderProductLen:
+		return nil, fmt.Errorf("%w: product must be 1-%d characters", ErrInvalidArgument, maxOrderProductLen)
+	case quantity <= 0:
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidArgument)
+	case amount <= 0:
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidArgument)
+	}
+
+	now := time.Now()
+	order := &Order{
+		UserID:    userID,
+		Product:   product,
+		Quantity:  quantity,
+		Amount:    amount,
+		Status:    OrderStatusPending,
+		Version:   initialVersion,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	created := &OrderTransition{
+		From:      OrderStatusUnknown,
+		To:        OrderStatusPending,
+		Event:     OrderEventCreate,
+		Actor:     userActor(userID),
+		CreatedAt: now,
+	}
+	order, err := uc.repo.Create(ctx, order, created)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	return order, nil
+}
+
+// Get 获取用户自己的订单及其流转记录
+func (uc *OrderUsecase) Get(ctx context.Context, userID, orderID int64) (*Order, []*OrderTransition, error) {
+	order, err := uc.owned(ctx, userID, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	transitions, err := uc.repo.ListTransitions(ctx, order.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, transitions, nil
+}
+
+// List 分页列出用户的订单，req 由 OrderListSpec 解析得到
+func (uc *OrderUsecase) List(ctx context.Context, userID int64, req *pagination.Request) (*pagination.Page[*Order], error) {
+	return uc.repo.ListByUser(ctx, userID, req)
+}
+
+// Pay 支付订单：pending -> paid
+func (uc *OrderUsecase) Pay(ctx context.Context, userID, orderID, version int64) (*Order, error) {
+	return uc.fire(ctx, userID, orderID, version, OrderEventPay, "")
+}
+
+// Ship 订单发货：paid -> shipped
+// 模板中没有角色体系，暂由下单用户自己触发；接入商家/运营角色后应在这里校验权限
+func (uc *OrderUsecase) Ship(ctx context.Context, userID, orderID, version int64) (*Order, error) {
+	return uc.fire(ctx, userID, orderID, version, OrderEventShip, "")
+}
+
+// Complete 确认收货：shipped -> completed
+func (uc *OrderUsecase) Complete(ctx context.Context, userID, orderID, version int64) (*Order, error) {
+	return uc.fire(ctx, userID, orderID, version, OrderEventComplete, "")
+}
+
+// Cancel 取消订单：pending/paid -> cancelled，reason 记录在审计记录中
+func (uc *OrderUsecase) Cancel(ctx context.Context, userID, orderID, version int64, reason string) (*Order, error) {
+	return uc.fire(ctx, userID, orderID, version, OrderEventCancel, reason)
+}
+
+// fire 对订单触发事件：按状态机计算目标状态，并与审计记录一起原子保存
+// version 为客户端持有的订单版本，非 0 时与当前版本不一致返回 ErrVersionMismatch
+func (uc *OrderUsecase) fire(ctx context.Context, userID, orderID, version int64, event OrderEvent, note string) (*Order, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxOrderNoteLen {
+		return nil, fmt.Errorf("%w: note must be at most %d characters", ErrInvalidArgument, maxOrderNoteLen)
+	}
+
+	order, err := uc.owned(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := expectVersion("order", order.ID, order.Version, version); err != nil {
+		return nil, err
+	}
+	to, err := nextOrderStatus(order.Status, event)
+	if err != nil {
+		return nil, fmt.Errorf("order %d: %w", order.ID, err)
+	}
+
+	now := time.Now()
+	transition := &OrderTransition{
+		OrderID:   order.ID,
+		From:      order.Status,
+		To:        to,
+		Event:     event,
+		Actor:     userActor(userID),
+		Note:      note,
+		CreatedAt: now,
+	}
+	order.Status = to
+	order.UpdatedAt = now
+	if err := uc.repo.Transition(ctx, order, transition); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// owned 获取订单并校验归属
+func (uc *OrderUsecase) owned(ctx context.Context, userID, orderID int64) (*Order, error) {
+	order, err := uc.repo.Get(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		// 不区分"不存在"和"无权访问"，避免泄露其他用户的订单 ID
+		return nil, fmt.Errorf("order %d: %w", orderID, ErrNotFound)
+	}
+	return order, nil
+}
+
+// userActor 审计记录中的操作人标识
+func userActor(userID int64) string {
+	return "user:" + strconv.FormatInt(userID, 10)
+}
@@ -0,0 +1,126 @@
+package biz
+
+import (
+	"fmt"
+	"time"
+)
+
+// OrderStatus 订单状态
+// 与 fundamentals/014-enums 的定义一致，零值保留为"未知"，
+// 避免未赋值的字段被误认为"待支付"
+type OrderStatus int
+
+const (
+	OrderStatusUnknown   OrderStatus = iota // 未知（零值，不应出现在已保存的订单中）
+	OrderStatusPending                      // 待支付
+	OrderStatusPaid                         // 已支付
+	OrderStatusShipped                      // 已发货
+	OrderStatusCompleted                    // 已完成
+	OrderStatusCancelled                    // 已取消
+)
+
+// orderStatusCodes 状态的存储编码
+// 数据库保存字符串而不是整数，调整枚举顺序不会破坏已有数据
+var orderStatusCodes = map[OrderStatus]string{
+	OrderStatusPending:   "pending",
+	OrderStatusPaid:      "paid",
+	OrderStatusShipped:   "shipped",
+	OrderStatusCompleted: "completed",
+	OrderStatusCancelled: "cancelled",
+}
+
+// String 返回状态的存储编码
+func (s OrderStatus) String() string {
+	if code, ok := orderStatusCodes[s]; ok {
+		return code
+	}
+	return "unknown"
+}
+
+// IsValid 判断是否为已定义的状态
+func (s OrderStatus) IsValid() bool {
+	_, ok := orderStatusCodes[s]
+	return ok
+}
+
+// IsFinal 判断是否为终态，终态不再接受任何流转
+func (s OrderStatus) IsFinal() bool {
+	return s == OrderStatusCompleted || s == OrderStatusCancelled
+}
+
+// CanCancel 判断当前状态是否可以取消
+func (s OrderStatus) CanCancel() bool {
+	return s.Can(OrderEventCancel)
+}
+
+// CanShip 判断当前状态是否可以发货
+func (s OrderStatus) CanShip() bool {
+	return s.Can(OrderEventShip)
+}
+
+// Can 判断当前状态是否允许发生指定事件
+func (s OrderStatus) Can(event OrderEvent) bool {
+	_, ok := orderTransitions[orderTransitionKey{from: s, event: event}]
+	return ok
+}
+
+// ParseOrderStatus 解析存储编码
+func ParseOrderStatus(code string) (OrderStatus, error) {
+	for status, c := range orderStatusCodes {
+		if c == code {
+			return status, nil
+		}
+	}
+	return OrderStatusUnknown, fmt.Errorf("unknown order status %q", code)
+}
+
+// OrderEvent 驱动订单状态流转的事件
+type OrderEvent string
+
+const (
+	OrderEventCreate   OrderEvent = "create"
+	OrderEventPay      OrderEvent = "pay"
+	OrderEventShip     OrderEvent = "ship"
+	OrderEventComplete OrderEvent = "complete"
+	OrderEventCancel   OrderEvent = "cancel"
+)
+
+type orderTransitionKey struct {
+	from  OrderStatus
+	event OrderEvent
+}
+
+// orderTransitions 订单状态机：(当前状态, 事件) -> 目标状态
+// 所有合法流转都在这张表里，表中没有的组合一律拒绝：
+//
+//	pending --pay--> paid --ship--> shipped --complete--> completed
+//	   |              |
+//	   +---cancel-----+-----> cancelled
+var orderTransitions = map[orderTransitionKey]OrderStatus{
+	{OrderStatusPending, OrderEventPay}:      OrderStatusPaid,
+	{OrderStatusPending, OrderEventCancel}:   OrderStatusCancelled,
+	{OrderStatusPaid, OrderEventShip}:        OrderStatusShipped,
+	{OrderStatusPaid, OrderEventCancel}:      OrderStatusCancelled,
+	{OrderStatusShipped, OrderEventComplete}: OrderStatusCompleted,
+}
+
+// OrderTransition 一次状态流转的审计记录
+type OrderTransition struct {
+	ID        int64
+	OrderID   int64
+	From      OrderStatus // 创建订单时为 OrderStatusUnknown
+	To        OrderStatus
+	Event     OrderEvent
+	Actor     string // 操作人，如 "user:42"
+	Note      string // 备注（如取消原因）
+	CreatedAt time.Time
+}
+
+// nextOrderStatus 按状态机计算目标状态，不允许的流转返回 ErrConflict
+func nextOrderStatus(from OrderStatus, event OrderEvent) (OrderStatus, error) {
+	to, ok := orderTransitions[orderTransitionKey{from: from, event: event}]
+	if !ok {
+		return OrderStatusUnknown, fmt.Errorf("%w: cannot %s an order in status %s", ErrConflict, event, from)
+	}
+	return to, nil
+}
@@ -0,0 +1,188 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"go-api-template/internal/pkg/pagination"
+)
+
+// fakeOrderRepo 内存中的 OrderRepo，只保存测试需要的订单
+type fakeOrderRepo struct {
+	orders      map[int64]*Order
+	transitions []*OrderTransition
+}
+
+func (r *fakeOrderRepo) Create(_ context.Context, o *Order, t *OrderTransition) (*Order, error) {
+	o.ID = int64(len(r.orders) + 1)
+	t.OrderID = o.ID
+	r.orders[o.ID] = o
+	r.transitions = append(r.transitions, t)
+	return o, nil
+}
+
+func (r *fakeOrderRepo) Get(_ context.Context, id int64) (*Order, error) {
+	o, ok := r.orders[id]
+	if !ok {
+		return nil, fmt.Errorf("order %d: %w", id, ErrNotFound)
+	}
+	copied := *o
+	return &copied, nil
+}
+
+func (r *fakeOrderRepo) ListByUser(context.Context, int64, *pagination.Request) (*pagination.Page[*Order], error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeOrderRepo) Transition(_ context.Context, o *Order, t *OrderTransition) error {
+	if r.orders[o.ID].Version != o.Version {
+		return fmt.Errorf("order %d: %w", o.ID, ErrVersionMismatch)
+	}
+	o.Version++
+	copied := *o
+	r.orders[o.ID] = &copied
+	r.transitions = append(r.transitions, t)
+	return nil
+}
+
+func (r *fakeOrderRepo) ListTransitions(_ context.Context, orderID int64) ([]*OrderTransition, error) {
+	var out []*OrderTransition
+	for _, t := range r.transitions {
+		if t.OrderID == orderID {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+// allOrderEvents 可以对已有订单触发的全部事件
+var allOrderEvents = []OrderEvent{OrderEventPay, OrderEventShip, OrderEventComplete, OrderEventCancel}
+
+func TestNextOrderStatus(t *testing.T) {
+	allowed := map[OrderStatus]map[OrderEvent]OrderStatus{
+		OrderStatusPending:   {OrderEventPay: OrderStatusPaid, OrderEventCancel: OrderStatusCancelled},
+		OrderStatusPaid:      {OrderEventShip: OrderStatusShipped, OrderEventCancel: OrderStatusCancelled},
+		OrderStatusShipped:   {OrderEventComplete: OrderStatusCompleted},
+		OrderStatusUnknown:   {},
+		OrderStatusCompleted: {},
+		OrderStatusCancelled: {},
+	}
+	for from, events := range allowed {
+		for _, event := range slices.Concat(allOrderEvents, []OrderEvent{OrderEventCreate}) {
+			t.Run(fmt.Sprintf("%s %s", event, from), func(t *testing.T) {
+				to, err := nextOrderStatus(from, event)
+				want, ok := events[event]
+				if ok {
+					if err != nil || to != want {
+						t.Errorf("nextOrderStatus = %s, %v; want %s", to, err, want)
+					}
+					if !from.Can(event) {
+						t.Errorf("%s.Can(%s) = false, want true", from, event)
+					}
+					return
+				}
+				if !errors.Is(err, ErrConflict) || to != OrderStatusUnknown {
+					t.Errorf("nextOrderStatus = %s, %v; want ErrConflict", to, err)
+				}
+				if from.Can(event) {
+					t.Errorf("%s.Can(%s) = true, want false", from, event)
+				}
+			})
+		}
+	}
+}
+
+func TestOrderUsecaseFire(t *testing.T) {
+	const owner, stranger = 1, 2
+	tests := []struct {
+		name       string
+		status     OrderStatus
+		userID     int64
+		version    int64
+		event      OrderEvent
+		wantStatus OrderStatus
+		wantErr    error
+	}{
+		{name: "pay pending", status: OrderStatusPending, userID: owner, event: OrderEventPay, wantStatus: OrderStatusPaid},
+		{name: "cancel pending", status: OrderStatusPending, userID: owner, event: OrderEventCancel, wantStatus: OrderStatusCancelled},
+		{name: "ship paid", status: OrderStatusPaid, userID: owner, event: OrderEventShip, wantStatus: OrderStatusShipped},
+		{name: "cancel paid", status: OrderStatusPaid, userID: owner, event: OrderEventCancel, wantStatus: OrderStatusCancelled},
+		{name: "complete shipped", status: OrderStatusShipped, userID: owner, event: OrderEventComplete, wantStatus: OrderStatusCompleted},
+		{name: "matching version", status: OrderStatusPending, userID: owner, version: 1, event: OrderEventPay, wantStatus: OrderStatusPaid},
+		{name: "ship pending", status: OrderStatusPending, userID: owner, event: OrderEventShip, wantErr: ErrConflict},
+		{name: "cancel shipped", status: OrderStatusShipped, userID: owner, event: OrderEventCancel, wantErr: ErrConflict},
+		{name: "pay completed", status: OrderStatusCompleted, userID: owner, event: OrderEventPay, wantErr: ErrConflict},
+		{name: "pay cancelled", status: OrderStatusCancelled, userID: owner, event: OrderEventPay, wantErr: ErrConflict},
+		{name: "stale version", status: OrderStatusPending, userID: owner, version: 5, event: OrderEventPay, wantErr: ErrVersionMismatch},
+		// 其他用户的订单与不存在的订单无法区分
+		{name: "other user's order", status: OrderStatusPending, userID: stranger, event: OrderEventPay, wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeOrderRepo{orders: map[int64]*Order{
+				1: {ID: 1, UserID: owner, Product: "book", Quantity: 1, Amount: 100, Status: tt.status, Version: initialVersion},
+			}}
+			uc := NewOrderUsecase(repo)
+
+			got, err := uc.fire(context.Background(), tt.userID, 1, tt.version, tt.event, " note ")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("fire = %v, want %v", err, tt.wantErr)
+				}
+				if repo.orders[1].Status != tt.status || len(repo.transitions) != 0 {
+					t.Errorf("rejected %s changed the order to %s", tt.event, repo.orders[1].Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fire: %v", err)
+			}
+			if got.Status != tt.wantStatus || got.Version != initialVersion+1 {
+				t.Errorf("order = %s v%d, want %s v%d", got.Status, got.Version, tt.wantStatus, initialVersion+1)
+			}
+			if len(repo.transitions) != 1 {
+				t.Fatalf("transitions = %d, want 1", len(repo.transitions))
+			}
+			want := OrderTransition{OrderID: 1, From: tt.status, To: tt.wantStatus, Event: tt.event, Actor: "user:1", Note: "note"}
+			if tr := *repo.transitions[0]; tr.OrderID != want.OrderID || tr.From != want.From || tr.To != want.To ||
+				tr.Event != want.Event || tr.Actor != want.Actor || tr.Note != want.Note {
+				t.Errorf("transition = %+v, want %+v", tr, want)
+			}
+		})
+	}
+}
+
+func TestOrderUsecaseOwned(t *testing.T) {
+	repo := &fakeOrderRepo{orders: map[int64]*Order{}}
+	uc := NewOrderUsecase(repo)
+	ctx := context.Background()
+	order, err := uc.Create(ctx, 1, "book", 1, 100)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		userID  int64
+		orderID int64
+		wantErr error
+	}{
+		{"owner", 1, order.ID, nil},
+		{"other user", 2, order.ID, ErrNotFound},
+		{"missing order", 1, order.ID + 1, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, transitions, err := uc.Get(ctx, tt.userID, tt.orderID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Get = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (got.ID != order.ID || len(transitions) != 1 || transitions[0].Event != OrderEventCreate) {
+				t.Errorf("Get = %+v with %d transitions, want the order and its create transition", got, len(transitions))
+			}
+		})
+	}
+}
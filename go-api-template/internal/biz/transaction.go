@@ -0,0 +1,12 @@
+package biz
+
+import "context"
+
+// Transaction 在一个事务中执行多个仓储操作
+// 事务通过 context 传递：fn 内使用传入的 ctx 调用的所有仓储方法都会加入同一事务，
+// 仓储接口因此不需要为事务额外增加参数。
+type Transaction interface {
+	// InTx 开启事务执行 fn，fn 返回错误或 panic 时回滚，否则提交
+	// 嵌套调用会加入外层事务，而不是开启新事务
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,240 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/wire"
+
+	"go-api-template/internal/pkg/tenant"
+)
+
+// UserProviderSet 是 User 模块的依赖提供者集合
+var UserProviderSet = wire.NewSet(NewUserUsecase)
+
+// User 是领域实体，表示一个注册用户
+type User struct {
+	ID           int64     // 唯一标识
+	Username     string    // 登录名，全局唯一，注册后不可修改
+	Email        string    // 邮箱
+	Nickname     string    // 昵称
+	PasswordHash string    // argon2id 密码哈希，永远不离开服务端
+	Version      int64     // 乐观锁版本号，每次更新加一
+	CreatedAt    time.Time // 创建时间
+	UpdatedAt    time.Time // 更新时间
+}
+
+// UserRepo 定义了用户数据的存储接口
+// 记录不存在时返回包装了 ErrNotFound 的错误，用户名冲突时返回包装了 ErrAlreadyExists 的错误
+type UserRepo interface {
+	// Create 保存新用户并回填 ID
+	Create(ctx context.Context, u *User) (*User, error)
+	// GetByID 按 ID 获取用户
+	GetByID(ctx context.Context, id int64) (*User, error)
+	// GetByUsername 按登录名获取用户
+	GetByUsername(ctx context.Context, username string) (*User, error)
+	// Update 以 u.Version 为条件更新邮箱、昵称、密码哈希与更新时间，成功后 u.Version 加一
+	// 记录已被其他请求修改（版本不一致）时返回包装了 ErrVersionMismatch 的错误
+	Update(ctx context.Context, u *User) (*User, error)
+}
+
+// TokenIssuer 为登录成功的用户签发访问令牌
+// 具体的令牌格式（JWT）属于基础设施，领域层只关心"签发一个有期限的令牌"
+// 令牌绑定用户所属的租户，不能用来访问其他租户
+type TokenIssuer interface {
+	Issue(tenantID string, userID int64, username string) (token string, expiresAt time.Time, err error)
+}
+
+// Session 登录结果
+type Session struct {
+	User      *User
+	Token     string    // 访问令牌
+	ExpiresAt time.Time // 令牌过期时间
+}
+
+// 账户规则
+const (
+	minPasswordLen = 8
+	// argon2 对任意长度输入都安全，上限只是防止超长输入拖慢哈希计算
+	maxPasswordLen = 128
+	maxNicknameLen = 50
+	maxEmailLen    = 254
+)
+
+// usernamePattern 登录名：3-32 位字母、数字或下划线
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,32}$`)
+
+// UserUsecase 是用户业务用例：注册、登录与资料维护
+type UserUsecase struct {
+	repo   UserRepo
+	tokens TokenIssuer
+
+	// dummyHash 用户不存在时参与一次校验，使登录耗时与"密码错误"一致，
+	// 避免通过响应时间枚举已注册的用户名
+	dummyHash string
+}
+
+// NewUserUsecase 创建 UserUsecase 实例
+func NewUserUsecase(repo UserRepo, tokens TokenIssuer) (*UserUsecase, error) {
+	dummyHash, err := hashPassword("dummy-password")
+	if err != nil {
+		return nil, err
+	}
+	return &UserUsecase{repo: repo, tokens: tokens, dummyHash: dummyHash}, nil
+}
+
+// Register 注册新用户
+func (uc *UserUsecase) Register(ctx context.Context, username, email, nickname, password string) (*User, error) {
+	email = strings.TrimSpace(email)
+	nickname = strings.TrimSpace(nickname)
+	if !usernamePattern.MatchString(username) {
+		return nil, fmt.Errorf("%w: username must be 3-32 letters, digits or underscores", ErrInvalidArgument)
+	}
+	if err := validateProfile(email, nickname); err != nil {
+		return nil, err
+	}
+	if err := validatePassword(password); err != nil {
+		return nil, err
+	}
+	if nickname == "" {
+		nickname = username
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	now := time.Now()
+	user, err := uc.repo.Create(ctx, &User{
+		Username:     username,
+		Email:        email,
+		Nickname:     nickname,
+		PasswordHash: hash,
+		Version:      initialVersion,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	return user, nil
+}
+
+// Login 校验用户名和密码，成功后签发访问令牌
+// 用户不存在与密码错误返回同一个错误，不向调用方透露用户名是否已注册
+func (uc *UserUsecase) Login(ctx context.Context, username, password string) (*Session, error) {
+	invalid := fmt.Errorf("%w: invalid username or password", ErrUnauthorized)
+
+	user, err := uc.repo.GetByUsername(ctx, username)
+	if errors.Is(err, ErrNotFound) {
+		_, _ = verifyPassword(password, uc.dummyHash)
+		return nil, invalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := verifyPassword(password, user.PasswordHash)
+	if err != nil {
+		return nil, fmt.Errorf("user %d: %w", user.ID, err)
+	}
+	if !ok {
+		return nil, invalid
+	}
+
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, expiresAt, err := uc.tokens.Issue(tenantID, user.ID, user.Username)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{User: user, Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// GetProfile 获取用户资料
+func (uc *UserUsecase) GetProfile(ctx context.Context, userID int64) (*User, error) {
+	return uc.repo.GetByID(ctx, userID)
+}
+
+// UpdateProfile 更新邮箱与昵称
+// 两者对空值的处理不同：邮箱是可选资料，空邮箱表示清除；昵称总有值（注册时默认为登录名），空昵称保持原值
+// version 为客户端持有的资料版本，非 0 时与当前版本不一致返回 ErrVersionMismatch
+func (uc *UserUsecase) UpdateProfile(ctx context.Context, userID, version int64, email, nickname string) (*User, error) {
+	email = strings.TrimSpace(email)
+	nickname = strings.TrimSpace(nickname)
+	if err := validateProfile(email, nickname); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := expectVersion("user", user.ID, user.Version, version); err != nil {
+		return nil, err
+	}
+	user.Email = email
+	if nickname != "" {
+		user.Nickname = nickname
+	}
+	user.UpdatedAt = time.Now()
+	return uc.repo.Update(ctx, user)
+}
+
+// ChangePassword 校验旧密码后设置新密码，version 的含义同 UpdateProfile
+// 已签发的令牌在过期前仍然有效，需要立即失效时应引入令牌版本号或黑名单
+func (uc *UserUsecase) ChangePassword(ctx context.Context, userID, version int64, oldPassword, newPassword string) (*User, error) {
+	if err := validatePassword(newPassword); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := expectVersion("user", user.ID, user.Version, version); err != nil {
+		return nil, err
+	}
+	ok, err := verifyPassword(oldPassword, user.PasswordHash)
+	if err != nil {
+		return nil, fmt.Errorf("user %d: %w", user.ID, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: old password is incorrect", ErrUnauthorized)
+	}
+
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.PasswordHash = hash
+	user.UpdatedAt = time.Now()
+	return uc.repo.Update(ctx, user)
+}
+
+// validatePassword 校验密码长度（按字符数计算）
+func validatePassword(password string) error {
+	if n := utf8.RuneCountInString(password); n < minPasswordLen || n > maxPasswordLen {
+		return fmt.Errorf("%w: password must be %d-%d characters", ErrInvalidArgument, minPasswordLen, maxPasswordLen)
+	}
+	return nil
+}
+
+// validateProfile 校验可修改的资料字段，email 为空表示不填写
+func validateProfile(email, nickname string) error {
+	if email != "" && (len(email) > maxEmailLen || !strings.Contains(email, "@")) {
+		return fmt.Errorf("%w: email is invalid", ErrInvalidArgument)
+	}
+	if utf8.RuneCountInString(nickname) > maxNicknameLen {
+		return fmt.Errorf("%w: nickname must be at most %d characters", ErrInvalidArgument, maxNicknameLen)
+	}
+	return nil
+}
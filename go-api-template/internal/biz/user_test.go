@@ -0,0 +1,228 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/internal/pkg/tenant"
+)
+
+// fakeUserRepo 内存中的 UserRepo
+type fakeUserRepo struct {
+	users map[int64]*User
+}
+
+func (r *fakeUserRepo) Create(_ context.Context, u *User) (*User, error) {
+	for _, existing := range r.users {
+		if existing.Username == u.Username {
+			return nil, fmt.Errorf("user %s: %w", u.Username, ErrAlreadyExists)
+		}
+	}
+	u.ID = int64(len(r.users) + 1)
+	copied := *u
+	r.users[u.ID] = &copied
+	return u, nil
+}
+
+func (r *fakeUserRepo) GetByID(_ context.Context, id int64) (*User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, fmt.Errorf("user %d: %w", id, ErrNotFound)
+	}
+	copied := *u
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) GetByUsername(ctx context.Context, username string) (*User, error) {
+	for id, u := range r.users {
+		if u.Username == username {
+			return r.GetByID(ctx, id)
+		}
+	}
+	return nil, fmt.Errorf("user %s: %w", username, ErrNotFound)
+}
+
+func (r *fakeUserRepo) Update(_ context.Context, u *User) (*User, error) {
+	if r.users[u.ID].Version != u.Version {
+		return nil, fmt.Errorf("user %d: %w", u.ID, ErrVersionMismatch)
+	}
+	u.Version++
+	copied := *u
+	r.users[u.ID] = &copied
+	return u, nil
+}
+
+// fakeTokens 签发形如 token-<租户>-<用户 ID> 的令牌
+type fakeTokens struct{}
+
+func (fakeTokens) Issue(tenantID string, userID int64, _ string) (string, time.Time, error) {
+	return fmt.Sprintf("token-%s-%d", tenantID, userID), time.Now().Add(time.Hour), nil
+}
+
+// newTestUserUsecase 创建 UserUsecase 并注册用户 alice（密码 correct-horse）
+func newTestUserUsecase(t *testing.T) (*UserUsecase, *User) {
+	t.Helper()
+	uc, err := NewUserUsecase(&fakeUserRepo{users: map[int64]*User{}}, fakeTokens{})
+	if err != nil {
+		t.Fatalf("NewUserUsecase: %v", err)
+	}
+	alice, err := uc.Register(context.Background(), "alice", "alice@example.com", "", "correct-horse")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	return uc, alice
+}
+
+func TestPasswordHashing(t *testing.T) {
+	hash, err := hashPassword("correct-horse")
+	if err != nil {
+		t.Fatalf("hashPassword: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=65536,t=3,p=2$") || strings.Contains(hash, "correct-horse") {
+		t.Errorf("hash = %q, want a PHC argon2id string without the password", hash)
+	}
+	if again, _ := hashPassword("correct-horse"); again == hash {
+		t.Error("hashing the same password twice gave the same hash, want a random salt")
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		encoded  string
+		want     bool
+		wantErr  error
+	}{
+		{name: "correct password", password: "correct-horse", encoded: hash, want: true},
+		{name: "wrong password", password: "wrong-horse", encoded: hash, want: false},
+		{name: "other algorithm", password: "correct-horse", encoded: "$2a$10$abcdefghijklmnopqrstuv", wantErr: errMalformedHash},
+		{name: "bad parameters", password: "correct-horse", encoded: strings.Replace(hash, "m=65536", "m=x", 1), wantErr: errMalformedHash},
+		{name: "bad salt", password: "correct-horse", encoded: "$argon2id$v=19$m=65536,t=3,p=2$!!$AAAA", wantErr: errMalformedHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifyPassword(tt.password, tt.encoded)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("verifyPassword = %v, %v; want %v, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserLogin(t *testing.T) {
+	uc, alice := newTestUserUsecase(t)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	session, err := uc.Login(ctx, "alice", "correct-horse")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if session.User.ID != alice.ID || session.Token != fmt.Sprintf("token-acme-%d", alice.ID) {
+		t.Errorf("session = %+v, want a token for alice in acme", session)
+	}
+
+	wrongStart := time.Now()
+	_, wrongErr := uc.Login(ctx, "alice", "wrong-horse")
+	wrongTook := time.Since(wrongStart)
+	unknownStart := time.Now()
+	_, unknownErr := uc.Login(ctx, "mallory", "wrong-horse")
+	unknownTook := time.Since(unknownStart)
+
+	for name, err := range map[string]error{"wrong password": wrongErr, "unknown user": unknownErr} {
+		if !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("%s: Login = %v, want ErrUnauthorized", name, err)
+		}
+	}
+	// 用户不存在与密码错误不可区分：错误相同，且同样计算一次 argon2 哈希
+	if wrongErr.Error() != unknownErr.Error() {
+		t.Errorf("unknown user error %q differs from wrong password error %q", unknownErr, wrongErr)
+	}
+	if unknownTook < wrongTook/4 {
+		t.Errorf("unknown user took %v, wrong password %v; want comparable times", unknownTook, wrongTook)
+	}
+}
+
+func TestUserChangePassword(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     int64
+		oldPassword string
+		newPassword string
+		wantErr     error
+	}{
+		{name: "changed", oldPassword: "correct-horse", newPassword: "battery-staple"},
+		{name: "matching version", version: 1, oldPassword: "correct-horse", newPassword: "battery-staple"},
+		{name: "wrong old password", oldPassword: "wrong-horse", newPassword: "battery-staple", wantErr: ErrUnauthorized},
+		{name: "new password too short", oldPassword: "correct-horse", newPassword: "short", wantErr: ErrInvalidArgument},
+		{name: "stale version", version: 2, oldPassword: "correct-horse", newPassword: "battery-staple", wantErr: ErrVersionMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, alice := newTestUserUsecase(t)
+			ctx := tenant.NewContext(context.Background(), "acme")
+
+			updated, err := uc.ChangePassword(ctx, alice.ID, tt.version, tt.oldPassword, tt.newPassword)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ChangePassword = %v, want %v", err, tt.wantErr)
+			}
+			loginWith := "correct-horse"
+			if tt.wantErr == nil {
+				if updated.Version != alice.Version+1 {
+					t.Errorf("version = %d, want %d", updated.Version, alice.Version+1)
+				}
+				loginWith = tt.newPassword
+			}
+			if _, err := uc.Login(ctx, "alice", loginWith); err != nil {
+				t.Errorf("Login with %q: %v", loginWith, err)
+			}
+		})
+	}
+}
+
+func TestUserUpdateProfile(t *testing.T) {
+	tests := []struct {
+		name         string
+		version      int64
+		email        string
+		nickname     string
+		wantEmail    string
+		wantNickname string
+		wantErr      error
+	}{
+		{name: "both updated", email: " alice@example.org ", nickname: " Alice ", wantEmail: "alice@example.org", wantNickname: "Alice"},
+		// 邮箱可选，空值清除；昵称总有值，空值保持原值
+		{name: "empty email clears it", email: "", nickname: "Alice", wantEmail: "", wantNickname: "Alice"},
+		{name: "empty nickname keeps it", email: "alice@example.org", nickname: "", wantEmail: "alice@example.org", wantNickname: "alice"},
+		{name: "invalid email", email: "not-an-email", wantErr: ErrInvalidArgument},
+		{name: "nickname too long", nickname: strings.Repeat("a", maxNicknameLen+1), wantErr: ErrInvalidArgument},
+		{name: "stale version", version: 2, email: "alice@example.org", wantErr: ErrVersionMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, alice := newTestUserUsecase(t)
+			ctx := context.Background()
+
+			_, err := uc.UpdateProfile(ctx, alice.ID, tt.version, tt.email, tt.nickname)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateProfile = %v, want %v", err, tt.wantErr)
+			}
+			got, err := uc.GetProfile(ctx, alice.ID)
+			if err != nil {
+				t.Fatalf("GetProfile: %v", err)
+			}
+			if tt.wantErr != nil {
+				if got.Email != alice.Email || got.Nickname != alice.Nickname || got.Version != alice.Version {
+					t.Errorf("rejected update changed the profile to %+v", got)
+				}
+				return
+			}
+			if got.Email != tt.wantEmail || got.Nickname != tt.wantNickname || got.Version != alice.Version+1 {
+				t.Errorf("profile = {%q, %q, v%d}, want {%q, %q, v%d}",
+					got.Email, got.Nickname, got.Version, tt.wantEmail, tt.wantNickname, alice.Version+1)
+			}
+		})
+	}
+}
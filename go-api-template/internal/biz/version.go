@@ -0,0 +1,22 @@
+package biz
+
+import "fmt"
+
+// 乐观并发控制
+//
+// 可修改的实体带有 Version 字段，新建时为 1，每次更新加一。
+// 仓储以 "WHERE version = 读取时的版本" 条件更新，读取之后被其他请求修改过时返回 ErrVersionMismatch，
+// 因此"读取 -> 修改 -> 保存"之间的并发写入不会互相覆盖。
+// 调用方还可以传入期望版本（HTTP 的 If-Match、gRPC 请求中的 version），
+// 把"读取"提前到客户端上一次获取资源的时刻，0 表示不做该项校验。
+
+// initialVersion 新建实体的版本号
+const initialVersion int64 = 1
+
+// expectVersion 校验实体当前版本与调用方期望的版本一致，expected 为 0 时跳过
+func expectVersion(kind string, id, current, expected int64) error {
+	if expected != 0 && expected != current {
+		return fmt.Errorf("%s %d: %w: expected version %d, current %d", kind, id, ErrVersionMismatch, expected, current)
+	}
+	return nil
+}
@@ -0,0 +1,462 @@
+package biz
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+
+	"go-api-template/internal/pkg/pagination"
+)
+
+// WebhookProviderSet 是 Webhook 模块的依赖提供者集合
+var WebhookProviderSet = wire.NewSet(NewWebhookUsecase)
+
+// WebhookSubscription 是领域实体，表示一个外部系统对若干事件类型的订阅
+type WebhookSubscription struct {
+	ID         int64
+	UserID     int64    // 创建订阅的用户
+	URL        string   // 接收事件的地址
+	EventTypes []string // 订阅的事件类型，WebhookAllEvents 表示全部
+	// Secret 签名密钥，接收方用它校验请求确实来自本服务
+	// 只在创建和轮换时返回给调用方
+	Secret    string
+	Active    bool // 停用的订阅不再产生新的投递
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Matches 判断订阅是否接收该类型的事件
+func (s *WebhookSubscription) Matches(eventType string) bool {
+	return slices.Contains(s.EventTypes, WebhookAllEvents) || slices.Contains(s.EventTypes, eventType)
+}
+
+// WebhookDeliveryStatus 投递状态
+type WebhookDeliveryStatus string
+
+// 投递状态取值
+const (
+	// WebhookDeliveryPending 等待投递或等待重试
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliverySucceeded 接收方返回了 2xx
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryFailed 超过最大投递次数，不再自动重试，可以手动重新投递
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery 一个事件对一个订阅的投递
+// 同一事件会为每个匹配的订阅各生成一条投递，互不影响
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int64
+	EventID        int64  // 领域事件 ID，接收方据此去重
+	EventType      string // 领域事件类型
+	Payload        []byte // 发送的请求体，生成后不再改变，重新投递时原样发送
+	Status         WebhookDeliveryStatus
+	Attempts       int       // 已尝试次数
+	LastStatusCode int       // 最近一次响应的状态码，未收到响应时为 0
+	LastError      string    // 最近一次失败的原因
+	NextAttemptAt  time.Time // 下次投递时间，仅 pending 状态有意义
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeliveredAt    *time.Time // 投递成功的时间
+}
+
+// WebhookAttempt 一次投递尝试的记录，只追加不修改
+type WebhookAttempt struct {
+	ID         int64
+	DeliveryID int64
+	Attempt    int    // 第几次尝试，从 1 开始
+	StatusCode int    // 响应状态码，未收到响应时为 0
+	Error      string // 失败原因，成功时为空
+	Duration   time.Duration
+	CreatedAt  time.Time
+}
+
+// WebhookEvent 需要分发给订阅者的领域事件
+type WebhookEvent struct {
+	ID         int64
+	Type       string
+	Data       json.RawMessage
+	OccurredAt time.Time
+}
+
+// WebhookRepo 定义了 Webhook 数据的存储接口
+type WebhookRepo interface {
+	// CreateSubscription 保存新订阅并回填 ID
+	CreateSubscription(ctx context.Context, s *WebhookSubscription) (*WebhookSubscription, error)
+	// GetSubscription 按 ID 获取订阅
+	GetSubscription(ctx context.Context, id int64) (*WebhookSubscription, error)
+	// ListSubscriptions 按 ID 倒序列出用户的订阅
+	ListSubscriptions(ctx context.Context, userID int64) ([]*WebhookSubscription, error)
+	// ListActiveSubscriptions 列出全部启用中的订阅，用于事件分发
+	ListActiveSubscriptions(ctx context.Context) ([]*WebhookSubscription, error)
+	// UpdateSubscription 更新订阅的地址、事件类型、密钥与启用状态
+	UpdateSubscription(ctx context.Context, s *WebhookSubscription) error
+	// DeleteSubscription 删除订阅及其全部投递记录，订阅不存在或属于其他租户时返回包装了 ErrNotFound 的错误
+	DeleteSubscription(ctx context.Context, id int64) error
+
+	// CreateDeliveries 保存一批待投递记录并回填 ID
+	CreateDeliveries(ctx context.Context, deliveries []*WebhookDelivery) error
+	// GetDelivery 按 ID 获取投递
+	GetDelivery(ctx context.Context, id int64) (*WebhookDelivery, error)
+	// ListDeliveries 按 req 的排序、过滤条件列出订阅投递中的一页，可用字段见 WebhookDeliveryListSpec；
+	// 订阅不存在或属于其他租户时返回包装了 ErrNotFound 的错误
+	ListDeliveries(ctx context.Context, subscriptionID int64, req *pagination.Request) (*pagination.Page[*WebhookDelivery], error)
+	// ListAttempts 按时间顺序列出投递的尝试记录
+	ListAttempts(ctx context.Context, deliveryID int64) ([]*WebhookAttempt, error)
+	// ResetDelivery 把非 pending 状态的投递重新置为 pending 并立即投递，尝试次数从 0 开始
+	// 投递仍处于 pending 时返回包装了 ErrConflict 的错误
+	ResetDelivery(ctx context.Context, d *WebhookDelivery) error
+	// DeleteFinishedDeliveries 删除在 before 之前结束（成功或失败）的投递及其尝试记录，返回删除的投递数
+	DeleteFinishedDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// WebhookDeliveryListSpec 投递日志允许的排序与过滤字段
+// 默认每页 50 条，与投递日志的常用查看范围一致
+var WebhookDeliveryListSpec = &pagination.Spec{
+	Fields: []pagination.Field{
+		{Name: "id", Type: pagination.Int, Sortable: true},
+		{Name: "created_at", Type: pagination.Time, Sortable: true, Filterable: true},
+		{Name: "updated_at", Type: pagination.Time, Sortable: true},
+		{Name: "status", Type: pagination.Enum, Filterable: true,
+			Values: []string{string(WebhookDeliveryPending), string(WebhookDeliverySucceeded), string(WebhookDeliveryFailed)}},
+		{Name: "event_type", Type: pagination.String, Filterable: true},
+		{Name: "last_status_code", Type: pagination.Int, Filterable: true},
+	},
+	Key:          "id",
+	DefaultSort:  "-id",
+	DefaultLimit: 50,
+}
+
+// WebhookDeliveryListValue 返回投递在 WebhookDeliveryListSpec 中字段的值，供内存分页与构造下一页游标使用
+func WebhookDeliveryListValue(d *WebhookDelivery, field string) any {
+	switch field {
+	case "id":
+		return d.ID
+	case "created_at":
+		return d.CreatedAt.UTC()
+	case "updated_at":
+		return d.UpdatedAt.UTC()
+	case "status":
+		return string(d.Status)
+	case "event_type":
+		return d.EventType
+	case "last_status_code":
+		return int64(d.LastStatusCode)
+	}
+	return nil
+}
+
+// WebhookAllEvents 订阅全部事件类型
+const WebhookAllEvents = "*"
+
+// WebhookEventTypes 可以订阅的事件类型
+// 新的领域事件需要开放给外部系统时加入这里
+var WebhookEventTypes = []string{
+	GreetingCreated{}.EventType(),
+}
+
+// Webhook 规则
+const (
+	maxWebhookURLLen     = 2000
+	minWebhookSecretLen  = 16
+	maxWebhookSecretLen  = 200
+	maxWebhookEventTypes = 50
+)
+
+// WebhookTargetGuard 校验接收地址的主机是否允许访问
+// 由 webhook 包实现：解析主机名，拒绝回环、内网、链路本地等地址，防止借订阅访问内网服务
+type WebhookTargetGuard interface {
+	CheckTarget(ctx context.Context, host string) error
+}
+
+// WebhookUsecase 是 Webhook 业务用例
+// 订阅只对创建者可见，其他用户访问时表现为订阅不存在
+type WebhookUsecase struct {
+	repo  WebhookRepo
+	tx    Transaction
+	guard WebhookTargetGuard
+}
+
+// NewWebhookUsecase 创建 WebhookUsecase 实例
+func NewWebhookUsecase(repo WebhookRepo, tx Transaction, guard WebhookTargetGuard) *WebhookUsecase {
+	return &WebhookUsecase{repo: repo, tx: tx, guard: guard}
+}
+
+// CreateSubscription 创建订阅
+// secret 为空时生成随机密钥；返回的订阅中带有密钥，调用方应提示用户妥善保存
+func (uc *WebhookUsecase) CreateSubscription(ctx context.Context, userID int64, rawURL string, eventTypes []string, secret string) (*WebhookSubscription, error) {
+	if err := uc.validateURL(ctx, rawURL); err != nil {
+		return nil, err
+	}
+	eventTypes, err := normalizeWebhookEventTypes(eventTypes)
+	if err != nil {
+		return nil, err
+	}
+	if secret == "" {
+		if secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	} else if err := validateWebhookSecret(secret); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sub := &WebhookSubscription{
+		UserID:     userID,
+		URL:        rawURL,
+		EventTypes: eventTypes,
+		Secret:     secret,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	sub, err = uc.repo.CreateSubscription(ctx, sub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return sub, nil
+}
+
+// GetSubscription 获取用户自己的订阅
+func (uc *WebhookUsecase) GetSubscription(ctx context.Context, userID, id int64) (*WebhookSubscription, error) {
+	return uc.owned(ctx, userID, id)
+}
+
+// ListSubscriptions 列出用户的全部订阅
+func (uc *WebhookUsecase) ListSubscriptions(ctx context.Context, userID int64) ([]*WebhookSubscription, error) {
+	return uc.repo.ListSubscriptions(ctx, userID)
+}
+
+// WebhookSubscriptionUpdate 订阅的可修改字段，nil 表示保持不变
+type WebhookSubscriptionUpdate struct {
+	URL        *string
+	EventTypes []string
+	// Secret 轮换签名密钥；指向空字符串时生成随机密钥
+	Secret *string
+	Active *bool
+}
+
+// UpdateSubscription 修改订阅
+// 已生成的投递保持原来的请求体，但会发送到新地址并使用新密钥签名
+func (uc *WebhookUsecase) UpdateSubscription(ctx context.Context, userID, id int64, update WebhookSubscriptionUpdate) (*WebhookSubscription, error) {
+	sub, err := uc.owned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if update.URL != nil {
+		if err := uc.validateURL(ctx, *update.URL); err != nil {
+			return nil, err
+		}
+		sub.URL = *update.URL
+	}
+	if update.EventTypes != nil {
+		if sub.EventTypes, err = normalizeWebhookEventTypes(update.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	if update.Secret != nil {
+		secret := *update.Secret
+		if secret == "" {
+			if secret, err = newWebhookSecret(); err != nil {
+				return nil, err
+			}
+		} else if err := validateWebhookSecret(secret); err != nil {
+			return nil, err
+		}
+		sub.Secret = secret
+	}
+	if update.Active != nil {
+		sub.Active = *update.Active
+	}
+	sub.UpdatedAt = time.Now()
+	if err := uc.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// DeleteSubscription 删除订阅，尚未完成的投递随之取消
+func (uc *WebhookUsecase) DeleteSubscription(ctx context.Context, userID, id int64) error {
+	if _, err := uc.owned(ctx, userID, id); err != nil {
+		return err
+	}
+	return uc.repo.DeleteSubscription(ctx, id)
+}
+
+// ListDeliveries 分页列出订阅的投递，req 由 WebhookDeliveryListSpec 解析得到
+func (uc *WebhookUsecase) ListDeliveries(ctx context.Context, userID, subscriptionID int64, req *pagination.Request) (*pagination.Page[*WebhookDelivery], error) {
+	if _, err := uc.owned(ctx, userID, subscriptionID); err != nil {
+		return nil, err
+	}
+	return uc.repo.ListDeliveries(ctx, subscriptionID, req)
+}
+
+// GetDelivery 获取投递及其每次尝试的记录
+func (uc *WebhookUsecase) GetDelivery(ctx context.Context, userID, subscriptionID, deliveryID int64) (*WebhookDelivery, []*WebhookAttempt, error) {
+	delivery, err := uc.ownedDelivery(ctx, userID, subscriptionID, deliveryID)
+	if err != nil {
+		return nil, nil, err
+	}
+	attempts, err := uc.repo.ListAttempts(ctx, delivery.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return delivery, attempts, nil
+}
+
+// Redeliver 手动重新投递：请求体与首次投递相同，尝试次数重新计算
+// 接收方按事件 ID 去重时，重新投递不会被重复处理
+func (uc *WebhookUsecase) Redeliver(ctx context.Context, userID, subscriptionID, deliveryID int64) (*WebhookDelivery, error) {
+	delivery, err := uc.ownedDelivery(ctx, userID, subscriptionID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status == WebhookDeliveryPending {
+		return nil, fmt.Errorf("webhook delivery %d: %w: delivery is still in progress", delivery.ID, ErrConflict)
+	}
+	if err := uc.repo.ResetDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// PruneDeliveries 删除在 before 之前结束的投递日志，仍在重试中的投递不受影响
+// 投递与尝试记录在同一事务中删除，不会留下没有投递的尝试记录
+func (uc *WebhookUsecase) PruneDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	var n int64
+	err := uc.tx.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		n, err = uc.repo.DeleteFinishedDeliveries(ctx, before)
+		return err
+	})
+	return n, err
+}
+
+// Dispatch 为匹配事件类型的每个启用中的订阅生成一条待投递记录
+// 所有记录在一个事务中保存：失败时整体重试，不会出现部分订阅收不到的情况
+func (uc *WebhookUsecase) Dispatch(ctx context.Context, ev WebhookEvent) error {
+	payload, err := json.Marshal(webhookPayload{ID: ev.ID, Type: ev.Type, OccurredAt: ev.OccurredAt, Data: ev.Data})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	return uc.tx.InTx(ctx, func(ctx context.Context) error {
+		subs, err := uc.repo.ListActiveSubscriptions(ctx)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		var deliveries []*WebhookDelivery
+		for _, sub := range subs {
+			if !sub.Matches(ev.Type) {
+				continue
+			}
+			deliveries = append(deliveries, &WebhookDelivery{
+				SubscriptionID: sub.ID,
+				EventID:        ev.ID,
+				EventType:      ev.Type,
+				Payload:        payload,
+				Status:         WebhookDeliveryPending,
+				NextAttemptAt:  now,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			})
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+		return uc.repo.CreateDeliveries(ctx, deliveries)
+	})
+}
+
+// webhookPayload 发送给接收方的请求体
+type webhookPayload struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// owned 获取订阅并校验归属
+func (uc *WebhookUsecase) owned(ctx context.Context, userID, id int64) (*WebhookSubscription, error) {
+	sub, err := uc.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.UserID != userID {
+		// 不区分"不存在"和"无权访问"，避免泄露其他用户的订阅 ID
+		return nil, fmt.Errorf("webhook subscription %d: %w", id, ErrNotFound)
+	}
+	return sub, nil
+}
+
+// ownedDelivery 获取投递并校验它属于用户自己的订阅
+func (uc *WebhookUsecase) ownedDelivery(ctx context.Context, userID, subscriptionID, deliveryID int64) (*WebhookDelivery, error) {
+	if _, err := uc.owned(ctx, userID, subscriptionID); err != nil {
+		return nil, err
+	}
+	delivery, err := uc.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.SubscriptionID != subscriptionID {
+		return nil, fmt.Errorf("webhook delivery %d: %w", deliveryID, ErrNotFound)
+	}
+	return delivery, nil
+}
+
+// validateURL 校验接收地址：必须是带主机名的 http/https 绝对地址，且主机解析到允许访问的地址
+func (uc *WebhookUsecase) validateURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || len(rawURL) > maxWebhookURLLen {
+		return fmt.Errorf("%w: url must be an absolute http or https URL of at most %d characters", ErrInvalidArgument, maxWebhookURLLen)
+	}
+	if err := uc.guard.CheckTarget(ctx, u.Hostname()); err != nil {
+		return fmt.Errorf("%w: url: %v", ErrInvalidArgument, err)
+	}
+	return nil
+}
+
+// normalizeWebhookEventTypes 去重并校验事件类型
+func normalizeWebhookEventTypes(eventTypes []string) ([]string, error) {
+	if len(eventTypes) == 0 || len(eventTypes) > maxWebhookEventTypes {
+		return nil, fmt.Errorf("%w: event_types must contain 1-%d items", ErrInvalidArgument, maxWebhookEventTypes)
+	}
+	out := make([]string, 0, len(eventTypes))
+	for _, t := range eventTypes {
+		t = strings.TrimSpace(t)
+		if t != WebhookAllEvents && !slices.Contains(WebhookEventTypes, t) {
+			return nil, fmt.Errorf("%w: unknown event type %q, supported: %s",
+				ErrInvalidArgument, t, strings.Join(WebhookEventTypes, ", "))
+		}
+		if !slices.Contains(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+// validateWebhookSecret 校验调用方指定的签名密钥长度
+func validateWebhookSecret(secret string) error {
+	if len(secret) < minWebhookSecretLen || len(secret) > maxWebhookSecretLen {
+		return fmt.Errorf("%w: secret must be %d-%d characters", ErrInvalidArgument, minWebhookSecretLen, maxWebhookSecretLen)
+	}
+	return nil
+}
+
+// newWebhookSecret 生成 32 字节的随机签名密钥
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
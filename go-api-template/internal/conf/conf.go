@@ -1,14 +1,13 @@
 // Package conf 定义应用配置结构体和配置加载逻辑。
-// 采用 Viper 库实现配置文件读取和环境变量覆盖。
+// 采用 Viper 库实现分层配置文件合并、环境变量与命令行覆盖。
 package conf
 
 import (
 	"fmt"
-	"strings"
+	"slices"
 	"time"
 
 	"github.com/google/wire"
-	"github.com/spf13/viper"
 )
 
 // ProviderSet 配置模块的依赖注入 Provider
@@ -22,13 +21,38 @@ type Config struct {
 	Database DatabaseConfig `mapstructure:"database"`
 	Redis    RedisConfig    `mapstructure:"redis"`
 	JWT      JWTConfig      `mapstructure:"jwt"`
+	Secrets  SecretsConfig  `mapstructure:"secrets"`
+
+	RateLimit   RateLimitConfig   `mapstructure:"rate_limit"`
+	CORS        CORSConfig        `mapstructure:"cors"`
+	Compression CompressionConfig `mapstructure:"compression"`
+	Response    ResponseConfig    `mapstructure:"response"`
+	PanicReport PanicReportConfig `mapstructure:"panic_report"`
+	Idempotency IdempotencyConfig `mapstructure:"idempotency"`
+	Events      EventsConfig      `mapstructure:"events"`
+	Webhooks    WebhooksConfig    `mapstructure:"webhooks"`
+	Jobs        JobsConfig        `mapstructure:"jobs"`
+	Admin       AdminConfig       `mapstructure:"admin"`
+	Tenancy     TenancyConfig     `mapstructure:"tenancy"`
+	Features    FeaturesConfig    `mapstructure:"features"`
+	Greeter     GreeterConfig     `mapstructure:"greeter"`
+
+	// sources 记录每个配置项生效值的来源层，由 LoadConfig 填充
+	sources map[string]Source
+	// path 基础配置文件路径
+	path string
+	// secretKeys 由秘钥引用解析得到的配置项，Dump 时统一脱敏
+	secretKeys map[string]bool
+	// loadOpts 加载时使用的选项，热加载时以相同方式重新加载
+	loadOpts []LoadOption
 }
 
 // AppConfig 应用基础配置
 type AppConfig struct {
 	// 应用名称
 	Name string `mapstructure:"name"`
-	// 运行环境：development | production
+	// 运行环境：development | staging | production
+	// 决定加载哪个环境配置文件（config.<env>.yaml）
 	Env string `mapstructure:"env"`
 	// HTTP 服务监听端口
 	Port int `mapstructure:"port"`
@@ -43,6 +67,36 @@ type ServerConfig struct {
 	ReadTimeout time.Duration `mapstructure:"read_timeout"`
 	// 写入响应的超时时间
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+	// gRPC 服务监听端口
+	GRPCPort int `mapstructure:"grpc_port"`
+	// 单个请求的默认处理时限，到期后请求 context 被取消，HTTP 返回 504，gRPC 返回 DeadlineExceeded
+	// 客户端可以通过 X-Request-Timeout（HTTP）或 grpc-timeout 要求更短的时限，但不能超过这里的值
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	// 按路由覆盖 request_timeout
+	RouteTimeouts []RouteTimeoutConfig `mapstructure:"route_timeouts"`
+	// 请求体的默认大小上限（字节），超出时返回 413
+	MaxBodyBytes int64 `mapstructure:"max_body_bytes"`
+	// 按路由覆盖 max_body_bytes，路由写法与 route_timeouts 相同
+	RouteBodyLimits []RouteBodyLimitConfig `mapstructure:"route_body_limits"`
+	// 允许的请求体格式（不含 charset 等参数），带请求体但格式不在其中的请求返回 415
+	AllowedContentTypes []string `mapstructure:"allowed_content_types"`
+}
+
+// RouteBodyLimitConfig 单个路由的请求体大小上限
+type RouteBodyLimitConfig struct {
+	// HTTP 路由，写作 "方法 路由模板"，如 "POST /api/v1/orders"
+	Route string `mapstructure:"route"`
+	// 请求体大小上限（字节），0 表示不限制
+	MaxBytes int64 `mapstructure:"max_bytes"`
+}
+
+// RouteTimeoutConfig 单个路由的处理时限
+type RouteTimeoutConfig struct {
+	// HTTP 路由写作 "方法 路由模板"，如 "POST /api/v1/orders"、"GET /api/v1/orders/:id"；
+	// gRPC 方法写作完整方法名，如 "/order.v1.OrderService/CreateOrder"
+	Route string `mapstructure:"route"`
+	// 处理时限，0 表示不限时（仍受客户端要求的时限约束）
+	Timeout time.Duration `mapstructure:"timeout"`
 }
 
 // GetShutdownTimeout 获取优雅关闭超时时间，提供默认值
@@ -69,6 +123,58 @@ func (c *ServerConfig) GetWriteTimeout() time.Duration {
 	return c.WriteTimeout
 }
 
+// GetRequestTimeout 获取默认处理时限，提供默认值
+func (c *ServerConfig) GetRequestTimeout() time.Duration {
+	if c.RequestTimeout <= 0 {
+		return 15 * time.Second
+	}
+	return c.RequestTimeout
+}
+
+// GetRouteTimeout 获取路由的处理时限，未单独配置的路由使用 request_timeout，返回 0 表示不限时
+func (c *ServerConfig) GetRouteTimeout(route string) time.Duration {
+	for _, r := range c.RouteTimeouts {
+		if r.Route == route {
+			return r.Timeout
+		}
+	}
+	return c.GetRequestTimeout()
+}
+
+// GetMaxBodyBytes 获取请求体的默认大小上限，提供默认值（1 MiB）
+func (c *ServerConfig) GetMaxBodyBytes() int64 {
+	if c.MaxBodyBytes <= 0 {
+		return 1 << 20
+	}
+	return c.MaxBodyBytes
+}
+
+// GetRouteBodyLimit 获取路由的请求体大小上限，未单独配置的路由使用 max_body_bytes，返回 0 表示不限制
+func (c *ServerConfig) GetRouteBodyLimit(route string) int64 {
+	for _, r := range c.RouteBodyLimits {
+		if r.Route == route {
+			return r.MaxBytes
+		}
+	}
+	return c.GetMaxBodyBytes()
+}
+
+// GetAllowedContentTypes 获取允许的请求体格式，提供默认值
+func (c *ServerConfig) GetAllowedContentTypes() []string {
+	if len(c.AllowedContentTypes) == 0 {
+		return []string{"application/json", "application/x-protobuf", "application/msgpack", "application/x-msgpack"}
+	}
+	return c.AllowedContentTypes
+}
+
+// GetGRPCPort 获取 gRPC 监听端口，提供默认值
+func (c *ServerConfig) GetGRPCPort() int {
+	if c.GRPCPort <= 0 {
+		return 9090
+	}
+	return c.GRPCPort
+}
+
 // LogConfig 日志配置
 type LogConfig struct {
 	// 日志级别：debug | info | warn | error
@@ -79,7 +185,8 @@ type LogConfig struct {
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
-	// 数据库驱动：postgres | mysql | sqlite
+	// 数据库驱动：memory | postgres | mysql | sqlite
+	// memory 使用进程内存储，无需外部数据库，适合本地开发
 	Driver string `mapstructure:"driver"`
 	// 数据库主机地址
 	Host string `mapstructure:"host"`
@@ -89,8 +196,8 @@ type DatabaseConfig struct {
 	Database string `mapstructure:"database"`
 	// 数据库用户名
 	Username string `mapstructure:"username"`
-	// 数据库密码（敏感信息，建议通过环境变量覆盖）
-	Password string `mapstructure:"password"`
+	// 数据库密码（敏感信息，建议通过环境变量或秘钥引用设置）
+	Password Secret `mapstructure:"password"`
 
 	// 连接池配置
 	// 最大空闲连接数
@@ -102,33 +209,39 @@ type DatabaseConfig struct {
 }
 
 // DSN 生成数据库连接字符串
-func (c *DatabaseConfig) DSN() string {
+// DSN 内嵌密码，因此以 Secret 类型返回：误打印时被脱敏，建立连接时调用 Reveal
+func (c *DatabaseConfig) DSN() Secret {
 	switch c.Driver {
 	case "postgres":
-		return fmt.Sprintf(
+		return Secret(fmt.Sprintf(
 			"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-			c.Host, c.Port, c.Username, c.Password, c.Database,
-		)
+			c.Host, c.Port, c.Username, c.Password.Reveal(), c.Database,
+		))
 	case "mysql":
-		return fmt.Sprintf(
+		return Secret(fmt.Sprintf(
 			"%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-			c.Username, c.Password, c.Host, c.Port, c.Database,
-		)
+			c.Username, c.Password.Reveal(), c.Host, c.Port, c.Database,
+		))
 	case "sqlite":
-		return c.Database
+		return Secret(c.Database)
 	default:
 		return ""
 	}
 }
 
+// IsMemory 判断是否使用内存存储（未配置驱动时同样视为内存存储）
+func (c *DatabaseConfig) IsMemory() bool {
+	return c.Driver == "" || c.Driver == "memory"
+}
+
 // RedisConfig Redis 配置
 type RedisConfig struct {
 	// Redis 主机地址
 	Host string `mapstructure:"host"`
 	// Redis 端口
 	Port int `mapstructure:"port"`
-	// Redis 密码（敏感信息，建议通过环境变量覆盖）
-	Password string `mapstructure:"password"`
+	// Redis 密码（敏感信息，建议通过环境变量或秘钥引用设置）
+	Password Secret `mapstructure:"password"`
 	// 数据库索引
 	DB int `mapstructure:"db"`
 }
@@ -140,50 +253,788 @@ func (c *RedisConfig) Addr() string {
 
 // JWTConfig JWT 认证配置
 type JWTConfig struct {
-	// JWT 签名密钥（敏感信息，必须通过环境变量覆盖）
-	Secret string `mapstructure:"secret"`
+	// JWT 签名密钥（敏感信息，必须通过环境变量或秘钥引用设置）
+	Secret Secret `mapstructure:"secret"`
 	// Token 过期时间
 	ExpiresIn time.Duration `mapstructure:"expires_in"`
 }
 
-// LoadConfig 加载应用配置
-// 配置加载优先级（从低到高）：
-// 1. 配置文件默认值
-// 2. 配置文件中的值
-// 3. 环境变量覆盖
-//
-// 环境变量命名规则：将配置路径中的 "." 替换为 "_"，并全部大写
-// 例如：database.password -> DATABASE_PASSWORD
-func LoadConfig(configPath string) (*Config, error) {
-	v := viper.New()
+// RateLimitConfig 限流配置（按客户端 IP 的令牌桶）
+// 支持热加载，修改后立即对新请求生效
+type RateLimitConfig struct {
+	// 是否启用限流
+	Enabled bool `mapstructure:"enabled"`
+	// 每秒补充的令牌数（稳态允许的请求速率）
+	RPS float64 `mapstructure:"rps"`
+	// 令牌桶容量（允许的瞬时突发请求数）
+	Burst int `mapstructure:"burst"`
+}
+
+// CORSConfig 跨域资源共享配置
+// 浏览器中其他源的页面调用 API 时，只有这里允许的源能读取响应
+type CORSConfig struct {
+	// 是否启用；关闭时不输出任何 CORS 响应头，浏览器会拦截跨域请求
+	Enabled bool `mapstructure:"enabled"`
+	// 允许的源，如 https://app.example.com；
+	// https://*.example.com 匹配 example.com 的任意子域名（不含 example.com 本身）；
+	// * 允许所有源，只能用于开发环境，且不能与 allow_credentials 同时使用
+	AllowOrigins []string `mapstructure:"allow_origins"`
+	// 允许的请求方法，为空时为 GET、POST、PUT、PATCH、DELETE
+	AllowMethods []string `mapstructure:"allow_methods"`
+	// 允许的请求头，为空时为本服务用到的请求头（Authorization、Content-Type、Idempotency-Key 等）；
+	// 启用多租户并从请求头解析租户时，CORS 中间件会自动加上 tenancy.header
+	AllowHeaders []string `mapstructure:"allow_headers"`
+	// 允许浏览器脚本读取的响应头，为空时为 ETag、X-Request-ID、Idempotent-Replayed
+	ExposeHeaders []string `mapstructure:"expose_headers"`
+	// 是否允许携带 Cookie 等凭据
+	AllowCredentials bool `mapstructure:"allow_credentials"`
+	// 预检结果的缓存时间，0 时为 10 分钟
+	MaxAge time.Duration `mapstructure:"max_age"`
+}
+
+// AllowsAnyOrigin 判断是否允许所有源
+func (c *CORSConfig) AllowsAnyOrigin() bool {
+	return slices.Contains(c.AllowOrigins, "*")
+}
 
-	// 设置配置文件路径
-	v.SetConfigFile(configPath)
+// GetAllowMethods 获取允许的请求方法，提供默认值
+func (c *CORSConfig) GetAllowMethods() []string {
+	if len(c.AllowMethods) == 0 {
+		return []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	}
+	return c.AllowMethods
+}
 
-	// 设置环境变量前缀（可选，避免与系统环境变量冲突）
-	// 设置后，环境变量需要加上前缀：GO_API_DATABASE_PASSWORD
-	// 如果不需要前缀，可以注释掉这行
-	// v.SetEnvPrefix("GO_API")
+// GetAllowHeaders 获取允许的请求头，提供默认值
+func (c *CORSConfig) GetAllowHeaders() []string {
+	if len(c.AllowHeaders) == 0 {
+		return []string{"Authorization", "Content-Type", "Idempotency-Key", "If-Match", "If-None-Match", "Last-Event-ID", "X-Request-ID", "X-Request-Timeout"}
+	}
+	return c.AllowHeaders
+}
 
-	// 自动绑定环境变量
-	v.AutomaticEnv()
+// GetExposeHeaders 获取允许读取的响应头，提供默认值
+func (c *CORSConfig) GetExposeHeaders() []string {
+	if len(c.ExposeHeaders) == 0 {
+		return []string{"ETag", "X-Request-ID", "Idempotent-Replayed"}
+	}
+	return c.ExposeHeaders
+}
 
-	// 将配置路径中的 "." 替换为 "_"，以便环境变量可以覆盖嵌套配置
-	// 例如：database.password 可以被 DATABASE_PASSWORD 覆盖
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+// GetMaxAge 获取预检结果的缓存时间，提供默认值
+func (c *CORSConfig) GetMaxAge() time.Duration {
+	if c.MaxAge <= 0 {
+		return 10 * time.Minute
+	}
+	return c.MaxAge
+}
+
+// CompressionConfig 响应压缩配置
+// 按客户端的 Accept-Encoding 压缩 HTTP 响应；gRPC 按客户端使用的 grpc-encoding 压缩，不受此配置影响
+type CompressionConfig struct {
+	// 是否启用
+	Enabled bool `mapstructure:"enabled"`
+	// 启用的编码，按服务端偏好排列，客户端对多个编码的 q 值相同时选择靠前的一个；
+	// 可选 zstd、br、gzip，为空时为 zstd、br、gzip
+	Encodings []string `mapstructure:"encodings"`
+	// 响应体小于该字节数时不压缩，压缩收益抵不上 CPU 开销；0 时为 1024
+	MinSize int `mapstructure:"min_size"`
+	// 需要压缩的响应格式（不含 charset 等参数），为空时为 JSON 与 Swagger UI 用到的文本格式
+	ContentTypes []string `mapstructure:"content_types"`
+}
 
-	// 读取配置文件
-	if err := v.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+// GetEncodings 获取启用的编码，提供默认值
+func (c *CompressionConfig) GetEncodings() []string {
+	if len(c.Encodings) == 0 {
+		return []string{"zstd", "br", "gzip"}
 	}
+	return c.Encodings
+}
+
+// GetMinSize 获取压缩的最小响应体大小，提供默认值
+func (c *CompressionConfig) GetMinSize() int {
+	if c.MinSize <= 0 {
+		return 1024
+	}
+	return c.MinSize
+}
 
-	// 将配置映射到结构体
-	var config Config
-	if err := v.Unmarshal(&config); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+// GetContentTypes 获取需要压缩的响应格式，提供默认值
+func (c *CompressionConfig) GetContentTypes() []string {
+	if len(c.ContentTypes) == 0 {
+		return []string{"application/json", "application/x-protobuf", "application/msgpack", "application/x-msgpack",
+			"text/plain", "text/html", "text/css", "application/javascript", "image/svg+xml"}
 	}
+	return c.ContentTypes
+}
+
+// ResponseConfig HTTP 响应编码配置
+// 响应体格式按 Accept 在 JSON、protobuf、MessagePack 中协商，这里配置 proto 消息编码为 JSON 与 MessagePack 时的写法
+type ResponseConfig struct {
+	// 字段命名：proto 使用 proto 定义中的名称（如 user_id）；camel 使用 protojson 默认的小驼峰（如 userId）。
+	// 为空时为 proto，与请求体 DTO 的 JSON 字段名一致
+	FieldNaming string `mapstructure:"field_naming"`
+	// 是否输出未设置的字段（零值、空列表等）；默认省略，与 protojson 一致
+	EmitUnpopulated bool `mapstructure:"emit_unpopulated"`
+	// 枚举是否输出为数字；默认输出枚举名称
+	UseEnumNumbers bool `mapstructure:"use_enum_numbers"`
+}
+
+// UseProtoNames 判断字段名是否使用 proto 定义中的名称
+func (c *ResponseConfig) UseProtoNames() bool {
+	return c.FieldNaming != "camel"
+}
+
+// PanicReportConfig panic 上报配置
+// HTTP 与 gRPC 请求处理中发生的 panic 总会记录日志，这里配置额外的上报方式
+type PanicReportConfig struct {
+	// 上报方式，可同时启用多个：file | webhook；为空时只记录日志
+	//   file     每次 panic 写一个崩溃转储文件到 file.dir
+	//   webhook  以 HTTP POST 把 JSON 格式的报告发送到 webhook.url
+	Reporters []string `mapstructure:"reporters"`
+	// 同一调用栈指纹在此时间窗口内只上报一次，其余次数计入下一次上报的 occurrences
+	Window time.Duration `mapstructure:"window"`
+	// 每个时间窗口内最多上报的次数（所有指纹合计），防止大面积故障时刷屏
+	MaxPerWindow int `mapstructure:"max_per_window"`
+
+	File    PanicFileConfig    `mapstructure:"file"`
+	Webhook PanicWebhookConfig `mapstructure:"webhook"`
+}
 
-	return &config, nil
+// PanicFileConfig file 上报方式的配置
+type PanicFileConfig struct {
+	// 崩溃转储文件所在目录
+	Dir string `mapstructure:"dir"`
+	// 最多保留的文件数，超出时删除最旧的
+	MaxFiles int `mapstructure:"max_files"`
+}
+
+// PanicWebhookConfig webhook 上报方式的配置
+type PanicWebhookConfig struct {
+	// 接收报告的地址
+	URL string `mapstructure:"url"`
+	// 单次请求超时
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// GetWindow 获取去重时间窗口，提供默认值
+func (c *PanicReportConfig) GetWindow() time.Duration {
+	if c.Window <= 0 {
+		return time.Minute
+	}
+	return c.Window
+}
+
+// GetMaxPerWindow 获取每个时间窗口的上报上限，提供默认值
+func (c *PanicReportConfig) GetMaxPerWindow() int {
+	if c.MaxPerWindow <= 0 {
+		return 10
+	}
+	return c.MaxPerWindow
+}
+
+// GetDir 获取崩溃转储目录，提供默认值
+func (c *PanicFileConfig) GetDir() string {
+	if c.Dir == "" {
+		return "data/crash"
+	}
+	return c.Dir
+}
+
+// GetMaxFiles 获取最多保留的文件数，提供默认值
+func (c *PanicFileConfig) GetMaxFiles() int {
+	if c.MaxFiles <= 0 {
+		return 100
+	}
+	return c.MaxFiles
+}
+
+// GetTimeout 获取单次请求超时，提供默认值
+func (c *PanicWebhookConfig) GetTimeout() time.Duration {
+	if c.Timeout <= 0 {
+		return 5 * time.Second
+	}
+	return c.Timeout
+}
+
+// IdempotencyConfig 幂等键配置
+// 携带 Idempotency-Key 请求头的 POST 请求只会执行一次，重复请求重放首次的响应
+type IdempotencyConfig struct {
+	// 是否启用
+	Enabled bool `mapstructure:"enabled"`
+	// 响应存储：memory | redis
+	// memory 只在单个进程内生效，多实例部署时必须使用 redis（连接参数取自 redis 配置）
+	Store string `mapstructure:"store"`
+	// 首次响应的保存时间，过期后同一个键会被当作新请求
+	TTL time.Duration `mapstructure:"ttl"`
+	// 处理中标记的有效期，超过后视为首次请求已中断（如进程崩溃），允许重新执行
+	LockTimeout time.Duration `mapstructure:"lock_timeout"`
+	// 重复请求等待首次请求完成的最长时间，超时返回 409
+	WaitTimeout time.Duration `mapstructure:"wait_timeout"`
+}
+
+// IsRedis 判断是否使用 Redis 存储
+func (c *IdempotencyConfig) IsRedis() bool {
+	return c.Store == "redis"
+}
+
+// GetTTL 获取响应保存时间，提供默认值
+func (c *IdempotencyConfig) GetTTL() time.Duration {
+	if c.TTL <= 0 {
+		return 24 * time.Hour
+	}
+	return c.TTL
+}
+
+// GetLockTimeout 获取处理中标记的有效期，提供默认值
+func (c *IdempotencyConfig) GetLockTimeout() time.Duration {
+	if c.LockTimeout <= 0 {
+		return time.Minute
+	}
+	return c.LockTimeout
+}
+
+// GetWaitTimeout 获取重复请求的等待时间，提供默认值
+func (c *IdempotencyConfig) GetWaitTimeout() time.Duration {
+	if c.WaitTimeout <= 0 {
+		return 10 * time.Second
+	}
+	return c.WaitTimeout
+}
+
+// EventsConfig 领域事件投递配置
+// 事件总是与实体在同一事务中写入 outbox 表，这里配置的是把 outbox 中的事件投递出去的 relay
+type EventsConfig struct {
+	// 是否启动 relay；关闭时事件不写入 outbox，否则它们既不会投递也不会被清理
+	Enabled bool `mapstructure:"enabled"`
+	// 事件发布方式：bus | webhook | file
+	//   bus      进程内事件总线，同一进程中的订阅者接收
+	//   webhook  以 HTTP POST 发送到 webhook.url
+	//   file     以 JSON Lines 追加写入 file.path，每行带 NATS 风格的 subject，可作为消息队列的本地替身
+	Publisher string `mapstructure:"publisher"`
+	// 轮询 outbox 的间隔
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// 每次轮询最多投递的事件数
+	BatchSize int `mapstructure:"batch_size"`
+	// 最大投递次数，超过后事件标记为失败，不再重试
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// 首次重试的等待时间，之后每次翻倍
+	Backoff time.Duration `mapstructure:"backoff"`
+	// 重试等待时间上限
+	MaxBackoff time.Duration `mapstructure:"max_backoff"`
+	// 已投递事件在 outbox 中的保留时间，过期后清理
+	Retention time.Duration `mapstructure:"retention"`
+
+	Webhook EventWebhookConfig `mapstructure:"webhook"`
+	File    EventFileConfig    `mapstructure:"file"`
+}
+
+// EventWebhookConfig webhook 发布方式的配置
+type EventWebhookConfig struct {
+	// 接收事件的地址
+	URL string `mapstructure:"url"`
+	// 单次请求超时
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// EventFileConfig file 发布方式的配置
+type EventFileConfig struct {
+	// 输出文件路径
+	Path string `mapstructure:"path"`
+	// subject 前缀，最终 subject 为 <前缀>.<事件类型>
+	SubjectPrefix string `mapstructure:"subject_prefix"`
+}
+
+// GetPollInterval 获取轮询间隔，提供默认值
+func (c *EventsConfig) GetPollInterval() time.Duration {
+	if c.PollInterval <= 0 {
+		return time.Second
+	}
+	return c.PollInterval
+}
+
+// GetBatchSize 获取每批投递数量，提供默认值
+func (c *EventsConfig) GetBatchSize() int {
+	if c.BatchSize <= 0 {
+		return 100
+	}
+	return c.BatchSize
+}
+
+// GetMaxAttempts 获取最大投递次数，提供默认值
+func (c *EventsConfig) GetMaxAttempts() int {
+	if c.MaxAttempts <= 0 {
+		return 10
+	}
+	return c.MaxAttempts
+}
+
+// GetBackoff 获取首次重试等待时间，提供默认值
+func (c *EventsConfig) GetBackoff() time.Duration {
+	if c.Backoff <= 0 {
+		return time.Second
+	}
+	return c.Backoff
+}
+
+// GetMaxBackoff 获取重试等待时间上限，提供默认值
+func (c *EventsConfig) GetMaxBackoff() time.Duration {
+	if c.MaxBackoff <= 0 {
+		return 5 * time.Minute
+	}
+	return c.MaxBackoff
+}
+
+// GetRetention 获取已投递事件的保留时间，提供默认值
+func (c *EventsConfig) GetRetention() time.Duration {
+	if c.Retention <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return c.Retention
+}
+
+// GetTimeout 获取 webhook 请求超时，提供默认值
+func (c *EventWebhookConfig) GetTimeout() time.Duration {
+	if c.Timeout <= 0 {
+		return 5 * time.Second
+	}
+	return c.Timeout
+}
+
+// GetSubjectPrefix 获取 subject 前缀，提供默认值
+func (c *EventFileConfig) GetSubjectPrefix() string {
+	if c.SubjectPrefix == "" {
+		return "events"
+	}
+	return c.SubjectPrefix
+}
+
+// WebhooksConfig 对外 webhook 订阅的投递配置
+// 与 events.webhook 不同：后者把全部事件发送到一个固定地址，这里按用户创建的订阅分别投递并签名
+type WebhooksConfig struct {
+	// 是否分发与投递；关闭时仍可管理订阅，但事件不会生成投递记录
+	// 事件来自进程内事件总线，开启时要求 events.enabled 为 true 且 events.publisher 为 bus
+	Enabled bool `mapstructure:"enabled"`
+	// 并发投递的 worker 数量，限制同时向外发出的请求数
+	Workers int `mapstructure:"workers"`
+	// 轮询待投递记录的间隔
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// 单次请求超时
+	Timeout time.Duration `mapstructure:"timeout"`
+	// 最大尝试次数，超过后投递标记为失败，可手动重新投递
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// 首次重试的等待时间，之后每次翻倍
+	Backoff time.Duration `mapstructure:"backoff"`
+	// 重试等待时间上限
+	MaxBackoff time.Duration `mapstructure:"max_backoff"`
+	// 是否允许投递到回环与私有网络地址，仅用于本地开发与测试
+	// 默认只允许公网地址，防止用户借订阅访问内网服务；链路本地地址（云元数据接口）始终拒绝
+	AllowPrivateNetworks bool `mapstructure:"allow_private_networks"`
+}
+
+// GetWorkers 获取 worker 数量，提供默认值
+func (c *WebhooksConfig) GetWorkers() int {
+	if c.Workers <= 0 {
+		return 4
+	}
+	return c.Workers
+}
+
+// GetPollInterval 获取轮询间隔，提供默认值
+func (c *WebhooksConfig) GetPollInterval() time.Duration {
+	if c.PollInterval <= 0 {
+		return time.Second
+	}
+	return c.PollInterval
+}
+
+// GetTimeout 获取请求超时，提供默认值
+func (c *WebhooksConfig) GetTimeout() time.Duration {
+	if c.Timeout <= 0 {
+		return 10 * time.Second
+	}
+	return c.Timeout
+}
+
+// GetMaxAttempts 获取最大尝试次数，提供默认值
+func (c *WebhooksConfig) GetMaxAttempts() int {
+	if c.MaxAttempts <= 0 {
+		return 8
+	}
+	return c.MaxAttempts
+}
+
+// GetBackoff 获取首次重试等待时间，提供默认值
+func (c *WebhooksConfig) GetBackoff() time.Duration {
+	if c.Backoff <= 0 {
+		return 10 * time.Second
+	}
+	return c.Backoff
+}
+
+// GetMaxBackoff 获取重试等待时间上限，提供默认值
+func (c *WebhooksConfig) GetMaxBackoff() time.Duration {
+	if c.MaxBackoff <= 0 {
+		return time.Hour
+	}
+	return c.MaxBackoff
+}
+
+// JobsConfig 后台任务配置
+// 任务持久化在数据库或 Redis 中，进程重启后未完成的任务继续执行
+type JobsConfig struct {
+	// 是否启动 worker 与定时调度；关闭时仍可入队，重新开启后继续执行
+	Enabled bool `mapstructure:"enabled"`
+	// 任务存储：database | redis
+	// database 使用 database 配置的数据库（driver 为 memory 时任务只保存在进程内存中）；
+	// redis 的连接参数取自 redis 配置
+	Store string `mapstructure:"store"`
+	// 轮询到期任务的间隔
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// 单次执行的默认超时，可在注册处理函数时单独指定
+	Timeout time.Duration `mapstructure:"timeout"`
+	// 默认最大执行次数，超过后任务进入死信（dead），可通过管理接口重试
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// 首次重试的等待时间，之后每次翻倍
+	Backoff time.Duration `mapstructure:"backoff"`
+	// 重试等待时间上限
+	MaxBackoff time.Duration `mapstructure:"max_backoff"`
+	// 已成功或已取消的任务的保留时间，过期后清理；死信任务不会被清理
+	Retention time.Duration `mapstructure:"retention"`
+	// 各队列的配置，键为队列名；未配置的队列并发数为 1
+	Queues map[string]JobQueueConfig `mapstructure:"queues"`
+	// 定时任务，按 cron 表达式入队
+	Schedules []JobScheduleConfig `mapstructure:"schedules"`
+}
+
+// JobQueueConfig 单个队列的配置
+type JobQueueConfig struct {
+	// 每个进程中同时执行的任务数上限
+	Concurrency int `mapstructure:"concurrency"`
+}
+
+// JobScheduleConfig 定时任务配置
+type JobScheduleConfig struct {
+	// 名称，在所有定时任务中唯一；同一时刻的任务以名称和计划时间去重，多实例部署时只入队一次
+	Name string `mapstructure:"name"`
+	// 标准 5 段 cron 表达式（分 时 日 月 周），也支持 @daily、@every 1h 等写法
+	Cron string `mapstructure:"cron"`
+	// 任务类型，对应注册的处理函数
+	Type string `mapstructure:"type"`
+	// 队列，为空时使用处理函数注册时的队列
+	Queue string `mapstructure:"queue"`
+	// JSON 格式的任务参数，为空时为 {}
+	Payload string `mapstructure:"payload"`
+}
+
+// DefaultJobQueue 未指定队列的任务所在的队列
+const DefaultJobQueue = "default"
+
+// IsRedis 判断是否使用 Redis 存储
+func (c *JobsConfig) IsRedis() bool {
+	return c.Store == "redis"
+}
+
+// GetPollInterval 获取轮询间隔，提供默认值
+func (c *JobsConfig) GetPollInterval() time.Duration {
+	if c.PollInterval <= 0 {
+		return time.Second
+	}
+	return c.PollInterval
+}
+
+// GetTimeout 获取默认执行超时，提供默认值
+func (c *JobsConfig) GetTimeout() time.Duration {
+	if c.Timeout <= 0 {
+		return 5 * time.Minute
+	}
+	return c.Timeout
+}
+
+// GetMaxAttempts 获取默认最大执行次数，提供默认值
+func (c *JobsConfig) GetMaxAttempts() int {
+	if c.MaxAttempts <= 0 {
+		return 5
+	}
+	return c.MaxAttempts
+}
+
+// GetBackoff 获取首次重试等待时间，提供默认值
+func (c *JobsConfig) GetBackoff() time.Duration {
+	if c.Backoff <= 0 {
+		return 10 * time.Second
+	}
+	return c.Backoff
+}
+
+// GetMaxBackoff 获取重试等待时间上限，提供默认值
+func (c *JobsConfig) GetMaxBackoff() time.Duration {
+	if c.MaxBackoff <= 0 {
+		return 30 * time.Minute
+	}
+	return c.MaxBackoff
+}
+
+// GetRetention 获取已结束任务的保留时间，提供默认值
+func (c *JobsConfig) GetRetention() time.Duration {
+	if c.Retention <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return c.Retention
+}
+
+// GetConcurrency 获取队列的并发数，未配置时为 1
+func (c *JobsConfig) GetConcurrency(queue string) int {
+	if q, ok := c.Queues[queue]; ok && q.Concurrency > 0 {
+		return q.Concurrency
+	}
+	return 1
+}
+
+// AdminConfig 管理接口配置
+// 项目没有角色体系，管理接口只对这里列出的用户开放（仍需登录）
+type AdminConfig struct {
+	// 管理员用户名列表，为空时所有管理接口返回 403
+	// 管理接口操作的是整个部署（如任务队列），只有 default 租户的用户可以成为管理员，
+	// 否则任何租户注册一个同名用户就能获得管理权限
+	Users []string `mapstructure:"users"`
+}
+
+// IsAdmin 判断租户中的用户名是否为管理员
+func (c *AdminConfig) IsAdmin(tenantID, username string) bool {
+	return tenantID == DefaultTenant && username != "" && slices.Contains(c.Users, username)
+}
+
+// DefaultTenant 未启用多租户时所有请求所属的租户，也是升级前已有数据所属的租户
+const DefaultTenant = "default"
+
+// TenancyConfig 多租户配置
+// 一个部署为多个租户服务：每个请求先解析出所属租户，业务数据按租户隔离
+type TenancyConfig struct {
+	// 是否启用；关闭时所有请求都属于 default 租户，行为与单租户部署相同
+	Enabled bool `mapstructure:"enabled"`
+	// 解析租户的来源，按顺序取第一个带有租户的来源：
+	// header 请求头（gRPC 为同名 metadata），subdomain 子域名，jwt 访问令牌中的租户；
+	// 为空时为 header、subdomain、jwt，其中 subdomain 只在配置了 base_domain 时生效
+	Sources []string `mapstructure:"sources"`
+	// header 来源使用的请求头，为空时为 X-Tenant-ID
+	Header string `mapstructure:"header"`
+	// subdomain 来源的基础域名，如 api.example.com，请求 acme.api.example.com 属于租户 acme
+	BaseDomain string `mapstructure:"base_domain"`
+	// 所有来源都没有租户时使用的租户，为空时拒绝这类请求；该租户始终视为已开通
+	Default string `mapstructure:"default"`
+	// 租户列表及其配置覆盖，为空时接受任意合法的租户 ID
+	// 支持热加载：开通、停用租户或调整覆盖项不需要重启
+	Tenants map[string]TenantConfig `mapstructure:"tenants"`
+}
+
+// TenantConfig 单个租户的开通状态与配置覆盖，未设置的项沿用全局配置
+type TenantConfig struct {
+	// 是否开通；置为 false 可以暂停租户而保留其配置
+	// 显式开关也避免了没有覆盖项的租户写成空映射（acme: {}），空映射在合并配置时会被丢弃
+	Enabled bool `mapstructure:"enabled"`
+	// 覆盖 greeter.template
+	GreetingTemplate string `mapstructure:"greeting_template"`
+	// 覆盖 rate_limit 的速率与容量
+	RateLimit TenantRateLimitConfig `mapstructure:"rate_limit"`
+}
+
+// TenantRateLimitConfig 租户的限流覆盖，为 0 的项沿用 rate_limit 中的值
+// 是否启用限流仍由 rate_limit.enabled 统一决定
+type TenantRateLimitConfig struct {
+	RPS   float64 `mapstructure:"rps"`
+	Burst int     `mapstructure:"burst"`
+}
+
+// GetSources 获取解析租户的来源，提供默认值
+func (c *TenancyConfig) GetSources() []string {
+	if len(c.Sources) == 0 {
+		return []string{"header", "subdomain", "jwt"}
+	}
+	return c.Sources
+}
+
+// GetHeader 获取携带租户的请求头，提供默认值
+func (c *TenancyConfig) GetHeader() string {
+	if c.Header == "" {
+		return "X-Tenant-ID"
+	}
+	return c.Header
+}
+
+// Allows 判断租户是否已开通，未配置租户列表时接受任意租户
+func (c *TenancyConfig) Allows(tenantID string) bool {
+	if len(c.Tenants) == 0 || tenantID == c.Default {
+		return true
+	}
+	return c.Tenants[tenantID].Enabled
+}
+
+// GreetingTemplateFor 获取租户生效的问候语模板
+func (c *Config) GreetingTemplateFor(tenantID string) string {
+	if t, ok := c.Tenancy.Tenants[tenantID]; ok && t.GreetingTemplate != "" {
+		return t.GreetingTemplate
+	}
+	return c.Greeter.GetTemplate()
+}
+
+// RateLimitFor 获取租户生效的限流策略，base 为全局的 rate_limit
+func (c *TenancyConfig) RateLimitFor(tenantID string, base RateLimitConfig) RateLimitConfig {
+	policy := base
+	if t, ok := c.Tenants[tenantID]; ok {
+		if t.RateLimit.RPS > 0 {
+			policy.RPS = t.RateLimit.RPS
+		}
+		if t.RateLimit.Burst > 0 {
+			policy.Burst = t.RateLimit.Burst
+		}
+	}
+	return policy
+}
+
+// FeaturesConfig 功能开关配置
+// 开关可以写在 flags 中，也可以写在 file 指定的独立文件中（同名开关以文件为准）：
+// 独立文件便于由发布平台单独下发，两处都支持热加载
+type FeaturesConfig struct {
+	// 独立的开关文件（YAML 或 JSON，顶层为 flags），为空时只使用 flags；修改路径需要重启
+	File string `mapstructure:"file"`
+	// 开关定义，键为开关名
+	Flags map[string]FeatureFlagConfig `mapstructure:"flags"`
+}
+
+// FeatureFlagConfig 单个功能开关的定义
+// 按以下顺序求值，第一条命中的规则决定结果：
+//  1. enabled 为 false：关闭
+//  2. environments 非空且不包含当前 app.env：关闭
+//  3. users 包含当前用户名：开启
+//  4. tenants 非空且不包含当前租户：关闭
+//  5. 未设置 percentage：开启；否则按租户与用户的稳定哈希开启 percentage% 的请求方
+type FeatureFlagConfig struct {
+	// 总开关，关闭时其余规则都不生效
+	Enabled bool `mapstructure:"enabled"`
+	// 开关说明，在管理接口中展示
+	Description string `mapstructure:"description"`
+	// 只在这些环境中开启，为空时不限环境
+	Environments []string `mapstructure:"environments"`
+	// 只在这些租户中开启，为空时不限租户
+	Tenants []string `mapstructure:"tenants"`
+	// 始终开启的用户名，不受 tenants 与 percentage 限制，用于内部验证
+	Users []string `mapstructure:"users"`
+	// 灰度比例（0-100），未设置时为全部开启
+	// 同一用户在比例不变时结果稳定，调大比例时已开启的用户保持开启
+	Percentage *float64 `mapstructure:"percentage"`
+}
+
+// GreeterConfig 问候模块配置
+type GreeterConfig struct {
+	// 问候语模板，占位符：{name} 被问候者名称，{visitor} 访问序号
+	// 支持热加载
+	Template string `mapstructure:"template"`
+	// 新问候的实时推送（WatchGreetings / SSE）
+	Stream GreeterStreamConfig `mapstructure:"stream"`
+	// 交互式问候会话（gRPC GreetSession / WebSocket）
+	Session GreeterSessionConfig `mapstructure:"session"`
+}
+
+// GreeterSessionConfig 交互式问候会话配置
+type GreeterSessionConfig struct {
+	// 单个会话每秒允许发送的名称数，超出的消息回复 TOO_MANY_REQUESTS，会话不会断开
+	RPS float64 `mapstructure:"rps"`
+	// 单个会话允许的瞬时突发消息数
+	Burst int `mapstructure:"burst"`
+	// WebSocket 服务端发送 ping 的间隔
+	PingInterval time.Duration `mapstructure:"ping_interval"`
+	// 发送 ping 后等待 pong 的时间，超时视为连接已断开
+	PongTimeout time.Duration `mapstructure:"pong_timeout"`
+	// WebSocket 单条消息的最大字节数，超出时关闭连接
+	MaxMessageSize int64 `mapstructure:"max_message_size"`
+}
+
+// GetRPS 获取每秒消息数，提供默认值
+func (c *GreeterSessionConfig) GetRPS() float64 {
+	if c.RPS <= 0 {
+		return 5
+	}
+	return c.RPS
+}
+
+// GetBurst 获取突发消息数，提供默认值
+func (c *GreeterSessionConfig) GetBurst() int {
+	if c.Burst <= 0 {
+		return 10
+	}
+	return c.Burst
+}
+
+// GetPingInterval 获取 ping 间隔，提供默认值
+func (c *GreeterSessionConfig) GetPingInterval() time.Duration {
+	if c.PingInterval <= 0 {
+		return 30 * time.Second
+	}
+	return c.PingInterval
+}
+
+// GetPongTimeout 获取 pong 等待时间，提供默认值
+func (c *GreeterSessionConfig) GetPongTimeout() time.Duration {
+	if c.PongTimeout <= 0 {
+		return 10 * time.Second
+	}
+	return c.PongTimeout
+}
+
+// GetMaxMessageSize 获取单条消息的最大字节数，提供默认值
+func (c *GreeterSessionConfig) GetMaxMessageSize() int64 {
+	if c.MaxMessageSize <= 0 {
+		return 4096
+	}
+	return c.MaxMessageSize
+}
+
+// GreeterStreamConfig 问候推送配置
+// 推送源轮询数据库中的新记录，多实例部署时任一实例保存的问候都能推送给所有订阅者；
+// 本实例保存问候后会立即触发一次轮询，不必等到下一个间隔
+type GreeterStreamConfig struct {
+	// 轮询新问候的间隔，没有订阅者时不轮询
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// 每个订阅者的缓冲条数，缓冲写满（消费太慢）时断开该订阅者
+	Buffer int `mapstructure:"buffer"`
+	// SSE 心跳间隔，防止代理因连接空闲而断开
+	Heartbeat time.Duration `mapstructure:"heartbeat"`
+}
+
+// GetPollInterval 获取轮询间隔，提供默认值
+func (c *GreeterStreamConfig) GetPollInterval() time.Duration {
+	if c.PollInterval <= 0 {
+		return time.Second
+	}
+	return c.PollInterval
+}
+
+// GetBuffer 获取订阅者缓冲条数，提供默认值
+func (c *GreeterStreamConfig) GetBuffer() int {
+	if c.Buffer <= 0 {
+		return 64
+	}
+	return c.Buffer
+}
+
+// GetHeartbeat 获取 SSE 心跳间隔，提供默认值
+func (c *GreeterStreamConfig) GetHeartbeat() time.Duration {
+	if c.Heartbeat <= 0 {
+		return 15 * time.Second
+	}
+	return c.Heartbeat
+}
+
+// DefaultGreetingTemplate 未配置模板时使用的问候语
+const DefaultGreetingTemplate = "Hello, {name}! You are visitor #{visitor}."
+
+// GetTemplate 获取问候语模板，提供默认值
+func (c *GreeterConfig) GetTemplate() string {
+	if c.Template == "" {
+		return DefaultGreetingTemplate
+	}
+	return c.Template
 }
 
 // IsDevelopment 判断是否为开发环境
@@ -191,6 +1042,11 @@ func (c *Config) IsDevelopment() bool {
 	return c.App.Env == "development"
 }
 
+// IsStaging 判断是否为预发布环境
+func (c *Config) IsStaging() bool {
+	return c.App.Env == "staging"
+}
+
 // IsProduction 判断是否为生产环境
 func (c *Config) IsProduction() bool {
 	return c.App.Env == "production"
@@ -0,0 +1,280 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// SourceKind 配置来源层级
+// 数值越大优先级越高，后加载的层覆盖先加载的层
+type SourceKind int
+
+const (
+	// SourceBase 基础配置文件（config.yaml）
+	SourceBase SourceKind = iota
+	// SourceProfile 环境配置文件（config.<env>.yaml）
+	SourceProfile
+	// SourceLocal 本地覆盖文件（config.local.yaml，不提交 Git）
+	SourceLocal
+	// SourceEnv 环境变量
+	SourceEnv
+	// SourceFlag 命令行 --set key=value
+	SourceFlag
+)
+
+// String 返回来源层级的可读名称
+func (k SourceKind) String() string {
+	switch k {
+	case SourceBase:
+		return "base"
+	case SourceProfile:
+		return "profile"
+	case SourceLocal:
+		return "local"
+	case SourceEnv:
+		return "env"
+	case SourceFlag:
+		return "flag"
+	default:
+		return "unknown"
+	}
+}
+
+// Source 描述某个配置项的生效值来自哪一层
+type Source struct {
+	Kind SourceKind
+	// Name 来源的具体位置：文件路径、环境变量名或 --set 表达式
+	Name string
+}
+
+// String 返回形如 "profile(configs/config.production.yaml)" 的描述
+func (s Source) String() string {
+	return fmt.Sprintf("%s(%s)", s.Kind, s.Name)
+}
+
+// LoadOption 配置加载选项
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	env             string
+	overrides       []string
+	secretProviders []SecretProvider
+}
+
+// WithEnv 显式指定运行环境，优先级高于 APP_ENV 与配置文件中的 app.env
+func WithEnv(env string) LoadOption {
+	return func(o *loadOptions) {
+		o.env = env
+	}
+}
+
+// WithOverrides 追加命令行覆盖项，格式为 key=value（如 app.port=9090）
+func WithOverrides(overrides ...string) LoadOption {
+	return func(o *loadOptions) {
+		o.overrides = append(o.overrides, overrides...)
+	}
+}
+
+// LoadConfig 加载应用配置
+// 配置按层合并，优先级（从低到高）：
+// 1. 基础配置文件：configPath（必须存在）
+// 2. 环境配置文件：与基础文件同目录的 config.<env>.yaml（可选）
+// 3. 本地覆盖文件：与基础文件同目录的 config.local.yaml（可选，不提交 Git）
+// 4. 环境变量覆盖
+// 5. 命令行 --set key=value 覆盖
+//
+// 环境变量命名规则：将配置路径中的 "." 替换为 "_"，并全部大写
+// 例如：database.password -> DATABASE_PASSWORD
+//
+// 每个生效值的来源层会被记录下来，可通过 Config.Source 查询
+// 所有层合并完成后，形如 file:// env:// encfile:// 的秘钥引用会被解析为实际值
+func LoadConfig(configPath string, opts ...LoadOption) (*Config, error) {
+	var o loadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	overrides, err := parseOverrides(o.overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	sources := make(map[string]Source)
+
+	// 1. 基础配置文件
+	if err := mergeFile(v, configPath, SourceBase, sources); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// 环境配置文件由运行环境决定，必须在合并前确定 env
+	env := resolveEnv(o.env, overrides, v.GetString("app.env"))
+
+	// 2. 环境配置文件、3. 本地覆盖文件：均为可选
+	optionalLayers := []struct {
+		path string
+		kind SourceKind
+	}{
+		{siblingFile(configPath, env), SourceProfile},
+		{siblingFile(configPath, "local"), SourceLocal},
+	}
+	for _, layer := range optionalLayers {
+		if layer.path == "" {
+			continue
+		}
+		err := mergeFile(v, layer.path, layer.kind, sources)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read %s config %s: %w", layer.kind, layer.path, err)
+		}
+	}
+
+	// 4. 环境变量覆盖
+	// 将配置路径中的 "." 替换为 "_"，以便环境变量可以覆盖嵌套配置
+	// 例如：database.password 可以被 DATABASE_PASSWORD 覆盖
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	for _, key := range v.AllKeys() {
+		name := envName(key)
+		if os.Getenv(name) != "" {
+			sources[key] = Source{Kind: SourceEnv, Name: name}
+		}
+	}
+
+	// 5. 命令行覆盖，viper.Set 的优先级高于环境变量
+	for _, ov := range overrides {
+		v.Set(ov.key, ov.value)
+		sources[ov.key] = Source{Kind: SourceFlag, Name: ov.raw}
+	}
+
+	// 显式指定的环境与命令行覆盖同级，保证 app.env 与加载的环境配置文件一致
+	if o.env != "" {
+		v.Set("app.env", o.env)
+		sources["app.env"] = Source{Kind: SourceFlag, Name: "env=" + o.env}
+	}
+
+	// 秘钥引用必须在所有层合并后解析，任意一层都可以写引用
+	secretKeys, err := resolveSecrets(v, o.secretProviders)
+	if err != nil {
+		return nil, err
+	}
+
+	// 将配置映射到结构体
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	config.sources = sources
+	config.secretKeys = secretKeys
+	config.loadOpts = opts
+	config.path = configPath
+
+	return &config, nil
+}
+
+// Source 返回指定配置项（如 "database.host"）生效值的来源
+// 配置项未出现在任何一层时返回 false
+func (c *Config) Source(key string) (Source, bool) {
+	s, ok := c.sources[strings.ToLower(key)]
+	return s, ok
+}
+
+// Sources 返回所有配置项及其来源，按配置项名称排序
+func (c *Config) Sources() []KeySource {
+	result := make([]KeySource, 0, len(c.sources))
+	for key, src := range c.sources {
+		result = append(result, KeySource{Key: key, Source: src})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// Path 返回加载时使用的基础配置文件路径
+func (c *Config) Path() string {
+	return c.path
+}
+
+// KeySource 配置项与其来源的组合
+type KeySource struct {
+	Key    string
+	Source Source
+}
+
+// mergeFile 读取一个 YAML 文件并合并到 v，同时记录该文件提供的所有配置项
+func mergeFile(v *viper.Viper, path string, kind SourceKind, sources map[string]Source) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+
+	layer := viper.New()
+	layer.SetConfigFile(path)
+	if err := layer.ReadInConfig(); err != nil {
+		return err
+	}
+
+	if err := v.MergeConfigMap(layer.AllSettings()); err != nil {
+		return err
+	}
+	for _, key := range layer.AllKeys() {
+		sources[key] = Source{Kind: kind, Name: path}
+	}
+	return nil
+}
+
+// resolveEnv 确定运行环境
+// 优先级：显式指定 > --set app.env > APP_ENV > 基础配置文件中的 app.env
+func resolveEnv(explicit string, overrides []override, fileEnv string) string {
+	if explicit != "" {
+		return explicit
+	}
+	for i := len(overrides) - 1; i >= 0; i-- {
+		if overrides[i].key == "app.env" {
+			return overrides[i].value
+		}
+	}
+	if env := os.Getenv(envName("app.env")); env != "" {
+		return env
+	}
+	return fileEnv
+}
+
+// siblingFile 根据基础配置文件路径推导同目录下的分层文件路径
+// 例如：configs/config.yaml + production -> configs/config.production.yaml
+func siblingFile(basePath, layer string) string {
+	if layer == "" {
+		return ""
+	}
+	ext := filepath.Ext(basePath)
+	return strings.TrimSuffix(basePath, ext) + "." + layer + ext
+}
+
+// envName 将配置路径转换为环境变量名：database.password -> DATABASE_PASSWORD
+func envName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// override 一条命令行覆盖项
+type override struct {
+	key   string
+	value string
+	raw   string
+}
+
+// parseOverrides 解析 key=value 形式的覆盖项
+func parseOverrides(raw []string) ([]override, error) {
+	result := make([]override, 0, len(raw))
+	for _, item := range raw {
+		key, value, ok := strings.Cut(item, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid override %q: expected key=value", item)
+		}
+		result = append(result, override{key: key, value: value, raw: item})
+	}
+	return result, nil
+}
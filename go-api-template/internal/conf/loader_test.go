@@ -0,0 +1,128 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFile 在 dir 中写入配置文件并返回路径
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadConfigLayers(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("DATABASE_PORT", "6543")
+
+	dir := t.TempDir()
+	base := writeFile(t, dir, "config.yaml", `
+app:
+  env: staging
+  port: 8080
+log:
+  level: info
+database:
+  host: base-host
+  port: 5432
+  username: base-user
+`)
+	profile := writeFile(t, dir, "config.staging.yaml", `
+log:
+  level: debug
+database:
+  host: staging-host
+`)
+	local := writeFile(t, dir, "config.local.yaml", `
+database:
+  host: local-host
+`)
+	// 其他环境的配置文件不参与合并
+	writeFile(t, dir, "config.production.yaml", `
+log:
+  level: error
+`)
+
+	cfg, err := LoadConfig(base, WithOverrides("app.port=9091"))
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	tests := []struct {
+		key  string
+		got  any
+		want any
+		src  Source
+	}{
+		{"database.username", cfg.Database.Username, "base-user", Source{Kind: SourceBase, Name: base}},
+		{"log.level", cfg.Log.Level, "debug", Source{Kind: SourceProfile, Name: profile}},
+		{"database.host", cfg.Database.Host, "local-host", Source{Kind: SourceLocal, Name: local}},
+		{"database.port", cfg.Database.Port, 6543, Source{Kind: SourceEnv, Name: "DATABASE_PORT"}},
+		{"app.port", cfg.App.Port, 9091, Source{Kind: SourceFlag, Name: "app.port=9091"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("value = %v, want %v", tt.got, tt.want)
+			}
+			src, ok := cfg.Source(tt.key)
+			if !ok || src != tt.src {
+				t.Errorf("Source = %v (found %v), want %v", src, ok, tt.src)
+			}
+		})
+	}
+}
+
+func TestLoadConfigEnvSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		appEnv   string
+		opts     []LoadOption
+		wantEnv  string
+		wantLogs string
+	}{
+		{name: "file", wantEnv: "staging", wantLogs: "debug"},
+		{name: "APP_ENV", appEnv: "production", wantEnv: "production", wantLogs: "error"},
+		{name: "set flag", appEnv: "production", opts: []LoadOption{WithOverrides("app.env=staging")}, wantEnv: "staging", wantLogs: "debug"},
+		{name: "explicit", appEnv: "staging", opts: []LoadOption{WithEnv("production")}, wantEnv: "production", wantLogs: "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_ENV", tt.appEnv)
+			dir := t.TempDir()
+			base := writeFile(t, dir, "config.yaml", "app:\n  env: staging\nlog:\n  level: info\n")
+			writeFile(t, dir, "config.staging.yaml", "log:\n  level: debug\n")
+			writeFile(t, dir, "config.production.yaml", "log:\n  level: error\n")
+
+			cfg, err := LoadConfig(base, tt.opts...)
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+			if cfg.App.Env != tt.wantEnv || cfg.Log.Level != tt.wantLogs {
+				t.Errorf("env = %q, log.level = %q; want %q, %q", cfg.App.Env, cfg.Log.Level, tt.wantEnv, tt.wantLogs)
+			}
+		})
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	dir := t.TempDir()
+	base := writeFile(t, dir, "config.yaml", "app:\n  port: 8080\n")
+
+	if _, err := LoadConfig(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("LoadConfig(missing base file) succeeded, want error")
+	}
+	if _, err := LoadConfig(base, WithOverrides("no-equals-sign")); err == nil {
+		t.Error("LoadConfig(invalid override) succeeded, want error")
+	}
+	writeFile(t, dir, "config.local.yaml", "app: [not, a, map\n")
+	if _, err := LoadConfig(base); err == nil {
+		t.Error("LoadConfig(broken local file) succeeded, want error")
+	}
+}
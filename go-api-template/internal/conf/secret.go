@@ -0,0 +1,171 @@
+package conf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// redactedPlaceholder 敏感值在任何输出中的替代文本
+const redactedPlaceholder = "******"
+
+// Secret 敏感配置值（密码、密钥、DSN 等）
+// 通过 fmt、encoding/json、slog 输出时均会被脱敏，只有显式调用 Reveal 才能拿到明文，
+// 从类型层面杜绝"打印整个配置结构体"导致的泄露。
+type Secret string
+
+// Reveal 返回明文，仅应在真正使用该值的地方调用（如建立连接、签名）
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// IsEmpty 判断是否未配置
+func (s Secret) IsEmpty() bool {
+	return s == ""
+}
+
+// String 实现 fmt.Stringer，%s 和 %v 输出脱敏文本
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redactedPlaceholder
+}
+
+// GoString 实现 fmt.GoStringer，防止 %#v 绕过脱敏
+func (s Secret) GoString() string {
+	return fmt.Sprintf("conf.Secret(%q)", s.String())
+}
+
+// MarshalJSON 序列化为脱敏文本
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// LogValue 实现 slog.LogValuer，结构化日志中同样脱敏
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// SecretProvider 秘钥引用解析器
+// 配置值形如 "<scheme>://..." 时，由 Scheme 匹配的 Provider 解析为实际值。
+// 实现此接口即可接入 Vault、云厂商 KMS 等外部秘钥系统。
+type SecretProvider interface {
+	// Scheme 返回该 Provider 处理的 URL scheme，如 "file"、"env"
+	Scheme() string
+	// Resolve 解析引用并返回明文
+	Resolve(ctx context.Context, ref *url.URL) (string, error)
+}
+
+// WithSecretProviders 注册额外的秘钥 Provider，同 scheme 的 Provider 会覆盖内置实现
+func WithSecretProviders(providers ...SecretProvider) LoadOption {
+	return func(o *loadOptions) {
+		o.secretProviders = append(o.secretProviders, providers...)
+	}
+}
+
+// secretResolveTimeout 单次加载解析全部秘钥引用的最长时间
+// 外部 Provider 可能涉及网络调用，避免启动过程无限阻塞
+const secretResolveTimeout = 30 * time.Second
+
+// resolveSecrets 将 v 中所有秘钥引用替换为明文，返回被替换的配置项集合
+// 这些配置项在 Dump 中无论字段类型如何都会被脱敏
+func resolveSecrets(v *viper.Viper, extra []SecretProvider) (map[string]bool, error) {
+	providers := map[string]SecretProvider{}
+	for _, p := range builtinSecretProviders(v) {
+		providers[p.Scheme()] = p
+	}
+	for _, p := range extra {
+		providers[p.Scheme()] = p
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout)
+	defer cancel()
+
+	resolved := make(map[string]bool)
+	for _, key := range v.AllKeys() {
+		raw, ok := v.Get(key).(string)
+		if !ok {
+			continue
+		}
+		ref, provider := matchSecretRef(raw, providers)
+		if provider == nil {
+			continue
+		}
+
+		value, err := provider.Resolve(ctx, ref)
+		if err != nil {
+			// 错误信息只包含配置项和 scheme，引用本身可能含有敏感路径
+			return nil, fmt.Errorf("failed to resolve secret for %s (%s://): %w", key, ref.Scheme, err)
+		}
+		v.Set(key, value)
+		resolved[key] = true
+	}
+	return resolved, nil
+}
+
+// matchSecretRef 判断配置值是否为已注册 scheme 的秘钥引用
+func matchSecretRef(raw string, providers map[string]SecretProvider) (*url.URL, SecretProvider) {
+	scheme, _, ok := strings.Cut(raw, "://")
+	if !ok {
+		return nil, nil
+	}
+	provider, ok := providers[scheme]
+	if !ok {
+		return nil, nil
+	}
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return nil, nil
+	}
+	return ref, provider
+}
+
+// Dump 返回配置的嵌套 map 表示，用于打印有效配置
+// Secret 类型字段和从秘钥引用解析出的值均被替换为占位符
+func (c *Config) Dump() map[string]any {
+	return dumpStruct(reflect.ValueOf(*c), "", c.secretKeys, true)
+}
+
+// dumpStruct 按 mapstructure 标签递归展开结构体
+// redact 为 false 时保留明文，仅用于内部比较（如热加载计算变更项），不得输出
+func dumpStruct(rv reflect.Value, prefix string, secretKeys map[string]bool, redact bool) map[string]any {
+	out := make(map[string]any)
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get("mapstructure")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		fv := rv.Field(i)
+		switch {
+		case redact && fv.Type() == reflect.TypeOf(Secret("")):
+			out[name] = fv.Interface().(Secret).String()
+		case redact && secretKeys[key]:
+			out[name] = redactedPlaceholder
+		case fv.Kind() == reflect.Struct && fv.Type() != reflect.TypeOf(time.Time{}):
+			out[name] = dumpStruct(fv, key, secretKeys, redact)
+		case fv.Type() == reflect.TypeOf(time.Duration(0)):
+			out[name] = fv.Interface().(time.Duration).String()
+		default:
+			out[name] = fv.Interface()
+		}
+	}
+	return out
+}
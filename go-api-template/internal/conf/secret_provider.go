@@ -0,0 +1,238 @@
+package conf
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// SecretsConfig 秘钥解析配置
+// 配置值可以写成秘钥引用，加载时解析为明文：
+//
+//	file:///run/secrets/db_password  读取文件内容（Docker/K8s Secret 挂载）
+//	env://DB_PASSWORD                读取环境变量
+//	encfile://db_password            从本地加密文件中按名称读取
+type SecretsConfig struct {
+	// 加密秘钥文件路径（encfile:// 引用使用），为空时不启用
+	EncryptedFile string `mapstructure:"encrypted_file"`
+	// 存放解密密钥（base64 编码的 32 字节 AES-256 密钥）的环境变量名
+	KeyEnv string `mapstructure:"key_env"`
+}
+
+// GetKeyEnv 获取解密密钥环境变量名，提供默认值
+func (c *SecretsConfig) GetKeyEnv() string {
+	if c.KeyEnv == "" {
+		return "SECRETS_KEY"
+	}
+	return c.KeyEnv
+}
+
+// builtinSecretProviders 返回内置 Provider
+// encfile 的参数来自尚未反序列化的配置，因此直接从 viper 读取
+func builtinSecretProviders(v *viper.Viper) []SecretProvider {
+	sc := SecretsConfig{
+		EncryptedFile: v.GetString("secrets.encrypted_file"),
+		KeyEnv:        v.GetString("secrets.key_env"),
+	}
+	return []SecretProvider{
+		FileSecretProvider{},
+		EnvSecretProvider{},
+		NewEncryptedFileSecretProvider(sc.EncryptedFile, sc.GetKeyEnv()),
+	}
+}
+
+// refName 取出引用中 scheme 之后的部分
+// url.Parse 会把 "env://NAME" 的 NAME 解析为 Host，把 "file:///a/b" 解析为 Path
+func refName(ref *url.URL) string {
+	return ref.Host + ref.Path
+}
+
+// ==================== file:// ====================
+
+// FileSecretProvider 从文件读取秘钥，适配 Docker Secret / K8s Secret 卷挂载
+type FileSecretProvider struct{}
+
+// Scheme 实现 SecretProvider
+func (FileSecretProvider) Scheme() string { return "file" }
+
+// Resolve 读取文件内容，去掉末尾换行（echo 写入的文件通常带换行）
+func (FileSecretProvider) Resolve(_ context.Context, ref *url.URL) (string, error) {
+	content, err := os.ReadFile(refName(ref))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// ==================== env:// ====================
+
+// EnvSecretProvider 从指定环境变量读取秘钥
+// 与 DATABASE_PASSWORD 这类自动覆盖不同，它允许引用任意名称的变量（如平台注入的 PG_PASS）
+type EnvSecretProvider struct{}
+
+// Scheme 实现 SecretProvider
+func (EnvSecretProvider) Scheme() string { return "env" }
+
+// Resolve 读取环境变量，未设置视为错误，避免静默得到空密码
+func (EnvSecretProvider) Resolve(_ context.Context, ref *url.URL) (string, error) {
+	name := refName(ref)
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+// ==================== encfile:// ====================
+
+// EncryptedFileSecretProvider 从 AES-256-GCM 加密的本地文件读取秘钥
+// 加密文件可以提交到仓库，解密密钥通过环境变量注入，适合没有外部秘钥系统的环境。
+// 文件在第一次被引用时才读取和解密。
+type EncryptedFileSecretProvider struct {
+	path   string
+	keyEnv string
+
+	once    sync.Once
+	secrets map[string]string
+	err     error
+}
+
+// NewEncryptedFileSecretProvider 创建加密文件 Provider
+// path 为加密文件路径，keyEnv 为存放 base64 密钥的环境变量名
+func NewEncryptedFileSecretProvider(path, keyEnv string) *EncryptedFileSecretProvider {
+	return &EncryptedFileSecretProvider{path: path, keyEnv: keyEnv}
+}
+
+// Scheme 实现 SecretProvider
+func (p *EncryptedFileSecretProvider) Scheme() string { return "encfile" }
+
+// Resolve 按名称查找解密后的秘钥
+func (p *EncryptedFileSecretProvider) Resolve(_ context.Context, ref *url.URL) (string, error) {
+	p.once.Do(p.load)
+	if p.err != nil {
+		return "", p.err
+	}
+
+	name := strings.Trim(refName(ref), "/")
+	value, ok := p.secrets[name]
+	if !ok {
+		return "", fmt.Errorf("secret %q not found in encrypted file", name)
+	}
+	return value, nil
+}
+
+func (p *EncryptedFileSecretProvider) load() {
+	if p.path == "" {
+		p.err = errors.New("secrets.encrypted_file is not configured")
+		return
+	}
+	key, err := decodeSecretsKey(os.Getenv(p.keyEnv))
+	if err != nil {
+		p.err = fmt.Errorf("invalid key in %s: %w", p.keyEnv, err)
+		return
+	}
+	content, err := os.ReadFile(p.path)
+	if err != nil {
+		p.err = err
+		return
+	}
+	p.secrets, p.err = OpenSecrets(key, content)
+}
+
+// encryptedSecretsFile 加密文件的磁盘格式
+type encryptedSecretsFile struct {
+	Version    int    `json:"version"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// SealSecrets 将秘钥集合加密为加密文件内容，key 必须为 32 字节
+func SealSecrets(key []byte, secrets map[string]string) ([]byte, error) {
+	gcm, err := newSecretsGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(encryptedSecretsFile{
+		Version:    1,
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plaintext, nil)),
+	}, "", "  ")
+}
+
+// OpenSecrets 解密 SealSecrets 生成的文件内容
+func OpenSecrets(key []byte, content []byte) (map[string]string, error) {
+	var file encryptedSecretsFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("malformed encrypted secrets file: %w", err)
+	}
+	if file.Version != 1 {
+		return nil, fmt.Errorf("unsupported encrypted secrets file version %d", file.Version)
+	}
+
+	gcm, err := newSecretsGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(file.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("malformed nonce: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(file.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("malformed ciphertext: %w", err)
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		// GCM 认证失败意味着密钥错误或文件被篡改
+		return nil, errors.New("failed to decrypt secrets file: wrong key or corrupted file")
+	}
+
+	var secrets map[string]string
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("malformed secrets payload: %w", err)
+	}
+	return secrets, nil
+}
+
+// decodeSecretsKey 解码 base64 格式的 AES-256 密钥
+func decodeSecretsKey(encoded string) ([]byte, error) {
+	if encoded == "" {
+		return nil, errors.New("key is empty")
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func newSecretsGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,172 @@
+package conf
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecretRedaction(t *testing.T) {
+	s := Secret("hunter2")
+
+	var logBuf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&logBuf, nil)).Info("test", "password", s)
+	jsonOut, err := json.Marshal(struct{ Password Secret }{s})
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	outputs := map[string]string{
+		"%s":   fmt.Sprintf("%s", s),
+		"%v":   fmt.Sprintf("%v", s),
+		"%+v":  fmt.Sprintf("%+v", struct{ Password Secret }{s}),
+		"%#v":  fmt.Sprintf("%#v", s),
+		"json": string(jsonOut),
+		"slog": logBuf.String(),
+	}
+	for name, out := range outputs {
+		t.Run(name, func(t *testing.T) {
+			if strings.Contains(out, "hunter2") {
+				t.Errorf("output leaks secret: %s", out)
+			}
+			if !strings.Contains(out, redactedPlaceholder) {
+				t.Errorf("output missing placeholder: %s", out)
+			}
+		})
+	}
+
+	if s.Reveal() != "hunter2" {
+		t.Errorf("Reveal() = %q, want plaintext", s.Reveal())
+	}
+	if Secret("").String() != "" {
+		t.Error("empty secret should print as empty string")
+	}
+}
+
+// sealTestFile 生成加密秘钥文件，返回文件路径与 base64 密钥
+func sealTestFile(t *testing.T, secrets map[string]string) (string, string) {
+	t.Helper()
+	key := bytes.Repeat([]byte{7}, 32)
+	content, err := SealSecrets(key, secrets)
+	if err != nil {
+		t.Fatalf("SealSecrets: %v", err)
+	}
+	path := writeFile(t, t.TempDir(), "secrets.enc.json", string(content))
+	return path, base64.StdEncoding.EncodeToString(key)
+}
+
+func TestSecretProviders(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := writeFile(t, dir, "db_password", "from-file\n")
+	encFile, key := sealTestFile(t, map[string]string{"jwt_secret": "from-encfile"})
+	t.Setenv("TEST_PG_PASS", "from-env")
+	t.Setenv("TEST_SECRETS_KEY", key)
+	t.Setenv("TEST_WRONG_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, 32)))
+
+	tests := []struct {
+		name     string
+		provider SecretProvider
+		ref      string
+		want     string
+		wantErr  bool
+	}{
+		{"file", FileSecretProvider{}, "file://" + secretFile, "from-file", false},
+		{"file missing", FileSecretProvider{}, "file://" + filepath.Join(dir, "missing"), "", true},
+		{"env", EnvSecretProvider{}, "env://TEST_PG_PASS", "from-env", false},
+		{"env unset", EnvSecretProvider{}, "env://TEST_NOT_SET", "", true},
+		{"encfile", NewEncryptedFileSecretProvider(encFile, "TEST_SECRETS_KEY"), "encfile://jwt_secret", "from-encfile", false},
+		{"encfile unknown name", NewEncryptedFileSecretProvider(encFile, "TEST_SECRETS_KEY"), "encfile://other", "", true},
+		{"encfile wrong key", NewEncryptedFileSecretProvider(encFile, "TEST_WRONG_KEY"), "encfile://jwt_secret", "", true},
+		{"encfile no key", NewEncryptedFileSecretProvider(encFile, "TEST_NOT_SET"), "encfile://jwt_secret", "", true},
+		{"encfile not configured", NewEncryptedFileSecretProvider("", "TEST_SECRETS_KEY"), "encfile://jwt_secret", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := url.Parse(tt.ref)
+			if err != nil {
+				t.Fatalf("url.Parse: %v", err)
+			}
+			got, err := tt.provider.Resolve(context.Background(), ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenSecretsRejectsTampering(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	content, err := SealSecrets(key, map[string]string{"a": "b"})
+	if err != nil {
+		t.Fatalf("SealSecrets: %v", err)
+	}
+	var file encryptedSecretsFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	ciphertext, _ := base64.StdEncoding.DecodeString(file.Ciphertext)
+	ciphertext[0] ^= 0xff
+	file.Ciphertext = base64.StdEncoding.EncodeToString(ciphertext)
+	tampered, _ := json.Marshal(file)
+
+	if _, err := OpenSecrets(key, tampered); err == nil {
+		t.Error("OpenSecrets(tampered) succeeded, want error")
+	}
+	if _, err := SealSecrets(key[:16], nil); err == nil {
+		t.Error("SealSecrets(16-byte key) succeeded, want error")
+	}
+}
+
+func TestLoadConfigResolvesSecrets(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	dir := t.TempDir()
+	passwordFile := writeFile(t, dir, "db_password", "file-password\n")
+	encFile, key := sealTestFile(t, map[string]string{"jwt_secret": "sealed-jwt"})
+	t.Setenv("SECRETS_KEY", key)
+	t.Setenv("TEST_REDIS_PASS", "env-redis")
+
+	base := writeFile(t, dir, "config.yaml", fmt.Sprintf(`
+database:
+  password: file://%s
+redis:
+  password: env://TEST_REDIS_PASS
+jwt:
+  secret: encfile://jwt_secret
+secrets:
+  encrypted_file: %s
+`, passwordFile, encFile))
+
+	cfg, err := LoadConfig(base)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if got := cfg.Database.Password.Reveal(); got != "file-password" {
+		t.Errorf("database.password = %q", got)
+	}
+	if got := cfg.Redis.Password.Reveal(); got != "env-redis" {
+		t.Errorf("redis.password = %q", got)
+	}
+	if got := cfg.JWT.Secret.Reveal(); got != "sealed-jwt" {
+		t.Errorf("jwt.secret = %q", got)
+	}
+
+	dump, err := json.Marshal(cfg.Dump())
+	if err != nil {
+		t.Fatalf("json.Marshal(Dump): %v", err)
+	}
+	for _, plain := range []string{"file-password", "env-redis", "sealed-jwt"} {
+		if strings.Contains(string(dump), plain) {
+			t.Errorf("Dump leaks %q", plain)
+		}
+	}
+}
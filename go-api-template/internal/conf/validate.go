@@ -0,0 +1,280 @@
+package conf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"regexp"
+	"strings"
+
+	"github.com/robfig/cron/v3"
+
+	"go-api-template/internal/pkg/logger"
+)
+
+// Validate 校验配置的合法性
+// 启动时和热加载时都会调用，热加载的新配置校验失败会被整体丢弃
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.App.Port <= 0 || c.App.Port > 65535 {
+		errs = append(errs, fmt.Errorf("app.port must be between 1 and 65535, got %d", c.App.Port))
+	}
+	if _, err := logger.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("log.level: %w", err))
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "", "text", "json":
+	default:
+		errs = append(errs, fmt.Errorf("log.format must be text or json, got %q", c.Log.Format))
+	}
+	if c.RateLimit.Enabled && (c.RateLimit.RPS <= 0 || c.RateLimit.Burst <= 0) {
+		errs = append(errs, errors.New("rate_limit.rps and rate_limit.burst must be positive when rate limiting is enabled"))
+	}
+	errs = append(errs, c.validateCORS()...)
+	errs = append(errs, c.validateRouteTimeouts()...)
+	errs = append(errs, c.validateBodyLimits()...)
+	for _, enc := range c.Compression.Encodings {
+		switch enc {
+		case "zstd", "br", "gzip":
+		default:
+			errs = append(errs, fmt.Errorf("compression.encodings must contain only zstd, br or gzip, got %q", enc))
+		}
+	}
+	switch c.Response.FieldNaming {
+	case "", "proto", "camel":
+	default:
+		errs = append(errs, fmt.Errorf("response.field_naming must be proto or camel, got %q", c.Response.FieldNaming))
+	}
+	if c.Compression.MinSize < 0 {
+		errs = append(errs, errors.New("compression.min_size must not be negative"))
+	}
+	switch c.Idempotency.Store {
+	case "", "memory", "redis":
+	default:
+		errs = append(errs, fmt.Errorf("idempotency.store must be memory or redis, got %q", c.Idempotency.Store))
+	}
+	if c.Events.Enabled {
+		switch c.Events.Publisher {
+		case "", "bus":
+		case "webhook":
+			if c.Events.Webhook.URL == "" {
+				errs = append(errs, errors.New("events.webhook.url is required when events.publisher is webhook"))
+			}
+		case "file":
+			if c.Events.File.Path == "" {
+				errs = append(errs, errors.New("events.file.path is required when events.publisher is file"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("events.publisher must be bus, webhook or file, got %q", c.Events.Publisher))
+		}
+	}
+	if c.Webhooks.Enabled && (!c.Events.Enabled || (c.Events.Publisher != "" && c.Events.Publisher != "bus")) {
+		errs = append(errs, errors.New("webhooks.enabled requires events.enabled with events.publisher bus"))
+	}
+	switch c.Jobs.Store {
+	case "", "database", "redis":
+	default:
+		errs = append(errs, fmt.Errorf("jobs.store must be database or redis, got %q", c.Jobs.Store))
+	}
+	for name, q := range c.Jobs.Queues {
+		if q.Concurrency < 0 {
+			errs = append(errs, fmt.Errorf("jobs.queues.%s.concurrency must not be negative", name))
+		}
+	}
+	errs = append(errs, validateJobSchedules(c.Jobs.Schedules)...)
+	errs = append(errs, c.validatePanicReport()...)
+	if !strings.Contains(c.Greeter.GetTemplate(), "{name}") {
+		errs = append(errs, errors.New("greeter.template must contain the {name} placeholder"))
+	}
+	errs = append(errs, c.validateTenancy()...)
+	errs = append(errs, ValidateFeatureFlags("features.flags", c.Features.Flags)...)
+
+	return errors.Join(errs...)
+}
+
+// validateJobSchedules 校验定时任务：名称唯一、cron 表达式可解析、参数是合法的 JSON
+func validateJobSchedules(schedules []JobScheduleConfig) []error {
+	var errs []error
+	seen := make(map[string]bool, len(schedules))
+	for i, s := range schedules {
+		if s.Name == "" {
+			errs = append(errs, fmt.Errorf("jobs.schedules[%d].name is required", i))
+		} else if seen[s.Name] {
+			errs = append(errs, fmt.Errorf("jobs.schedules[%d].name %q is duplicated", i, s.Name))
+		}
+		seen[s.Name] = true
+		if _, err := cron.ParseStandard(s.Cron); err != nil {
+			errs = append(errs, fmt.Errorf("jobs.schedules[%d].cron: %w", i, err))
+		}
+		if s.Type == "" {
+			errs = append(errs, fmt.Errorf("jobs.schedules[%d].type is required", i))
+		}
+		if s.Payload != "" && !json.Valid([]byte(s.Payload)) {
+			errs = append(errs, fmt.Errorf("jobs.schedules[%d].payload must be valid JSON", i))
+		}
+	}
+	return errs
+}
+
+// validateCORS 校验允许的源
+// 生产环境必须逐个列出允许的源；允许所有源时不能同时允许凭据，否则任意网站都能以用户身份调用 API
+func (c *Config) validateCORS() []error {
+	if !c.CORS.Enabled {
+		return nil
+	}
+	var errs []error
+	if c.CORS.AllowsAnyOrigin() {
+		if c.IsProduction() {
+			errs = append(errs, errors.New("cors.allow_origins must list explicit origins in production, * is not allowed"))
+		}
+		if c.CORS.AllowCredentials {
+			errs = append(errs, errors.New("cors.allow_origins cannot contain * when cors.allow_credentials is true"))
+		}
+	}
+	for _, origin := range c.CORS.AllowOrigins {
+		if origin == "*" {
+			continue
+		}
+		scheme, host, ok := strings.Cut(origin, "://")
+		if !ok || scheme == "" || host == "" || strings.Contains(host, "/") {
+			errs = append(errs, fmt.Errorf("cors.allow_origins: %q must be scheme://host[:port]", origin))
+			continue
+		}
+		if strings.Contains(host, "*") && (!strings.HasPrefix(host, "*.") || strings.Count(host, "*") > 1) {
+			errs = append(errs, fmt.Errorf("cors.allow_origins: %q may only use * as the leftmost label, like https://*.example.com", origin))
+		}
+	}
+	return errs
+}
+
+// validateRouteTimeouts 校验按路由配置的处理时限
+func (c *Config) validateRouteTimeouts() []error {
+	var errs []error
+	seen := make(map[string]bool)
+	for i, r := range c.Server.RouteTimeouts {
+		if r.Route == "" {
+			errs = append(errs, fmt.Errorf("server.route_timeouts[%d].route is required", i))
+			continue
+		}
+		if seen[r.Route] {
+			errs = append(errs, fmt.Errorf("server.route_timeouts: duplicate route %q", r.Route))
+		}
+		seen[r.Route] = true
+		if r.Timeout < 0 {
+			errs = append(errs, fmt.Errorf("server.route_timeouts[%d].timeout must not be negative", i))
+		}
+	}
+	return errs
+}
+
+// validatePanicReport 校验 panic 上报方式
+func (c *Config) validatePanicReport() []error {
+	var errs []error
+	seen := make(map[string]bool)
+	for _, name := range c.PanicReport.Reporters {
+		switch name {
+		case "file":
+		case "webhook":
+			if c.PanicReport.Webhook.URL == "" {
+				errs = append(errs, errors.New("panic_report.webhook.url is required when panic_report.reporters contains webhook"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("panic_report.reporters must contain only file or webhook, got %q", name))
+		}
+		if seen[name] {
+			errs = append(errs, fmt.Errorf("panic_report.reporters: duplicate reporter %q", name))
+		}
+		seen[name] = true
+	}
+	return errs
+}
+
+// validateBodyLimits 校验请求体大小上限与允许的请求体格式
+func (c *Config) validateBodyLimits() []error {
+	var errs []error
+	if c.Server.MaxBodyBytes < 0 {
+		errs = append(errs, errors.New("server.max_body_bytes must not be negative"))
+	}
+	seen := make(map[string]bool)
+	for i, r := range c.Server.RouteBodyLimits {
+		if r.Route == "" {
+			errs = append(errs, fmt.Errorf("server.route_body_limits[%d].route is required", i))
+			continue
+		}
+		if seen[r.Route] {
+			errs = append(errs, fmt.Errorf("server.route_body_limits: duplicate route %q", r.Route))
+		}
+		seen[r.Route] = true
+		if r.MaxBytes < 0 {
+			errs = append(errs, fmt.Errorf("server.route_body_limits[%d].max_bytes must not be negative", i))
+		}
+	}
+	for _, ct := range c.Server.AllowedContentTypes {
+		if mediaType, params, err := mime.ParseMediaType(ct); err != nil || len(params) > 0 || mediaType != strings.ToLower(ct) {
+			errs = append(errs, fmt.Errorf("server.allowed_content_types: %q must be a bare lowercase media type like application/json", ct))
+		}
+	}
+	return errs
+}
+
+// tenantIDPattern 租户 ID 的格式：小写字母、数字与连字符，可以直接用作子域名
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// ValidTenantID 判断租户 ID 是否合法
+func ValidTenantID(id string) bool {
+	return tenantIDPattern.MatchString(id)
+}
+
+// featureFlagPattern 开关名：小写字母、数字与下划线
+// 开关名是配置中的键，不能包含 "."，否则会被拆成嵌套的配置项
+var featureFlagPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// ValidateFeatureFlags 校验功能开关定义，path 为错误信息中开关所在的位置
+// 配置文件与独立的开关文件共用同一套校验
+func ValidateFeatureFlags(path string, flags map[string]FeatureFlagConfig) []error {
+	var errs []error
+	for name, flag := range flags {
+		if !featureFlagPattern.MatchString(name) {
+			errs = append(errs, fmt.Errorf("%s: %q is not a valid flag name, use lowercase letters, digits and underscores", path, name))
+		}
+		if p := flag.Percentage; p != nil && (*p < 0 || *p > 100) {
+			errs = append(errs, fmt.Errorf("%s.%s.percentage must be between 0 and 100, got %v", path, name, *p))
+		}
+	}
+	return errs
+}
+
+// validateTenancy 校验多租户配置
+// 租户的配置覆盖同样要满足全局配置的约束，否则热加载一个租户的覆盖就能绕过校验
+func (c *Config) validateTenancy() []error {
+	var errs []error
+	t := c.Tenancy
+	for _, source := range t.Sources {
+		switch source {
+		case "header", "jwt":
+		case "subdomain":
+			if t.Enabled && t.BaseDomain == "" {
+				errs = append(errs, errors.New("tenancy.base_domain is required when tenancy.sources contains subdomain"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("tenancy.sources must contain only header, subdomain or jwt, got %q", source))
+		}
+	}
+	if t.Default != "" && !ValidTenantID(t.Default) {
+		errs = append(errs, fmt.Errorf("tenancy.default: %q is not a valid tenant id", t.Default))
+	}
+	for id, tc := range t.Tenants {
+		if !ValidTenantID(id) {
+			errs = append(errs, fmt.Errorf("tenancy.tenants: %q is not a valid tenant id, use lowercase letters, digits and hyphens", id))
+		}
+		if tc.GreetingTemplate != "" && !strings.Contains(tc.GreetingTemplate, "{name}") {
+			errs = append(errs, fmt.Errorf("tenancy.tenants.%s.greeting_template must contain the {name} placeholder", id))
+		}
+		if tc.RateLimit.RPS < 0 || tc.RateLimit.Burst < 0 {
+			errs = append(errs, fmt.Errorf("tenancy.tenants.%s.rate_limit must not be negative", id))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,288 @@
+package conf
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadablePrefixes 允许运行期变更的配置项前缀
+// 其余配置（端口、数据库连接等）在启动时已被消费，修改后必须重启才能生效
+var reloadablePrefixes = []string{
+	"log.level",
+	"rate_limit.",
+	"cors.",
+	"greeter.template",
+	"tenancy.tenants.",
+	"features.flags.",
+}
+
+// IsReloadable 判断配置项是否支持热加载
+func IsReloadable(key string) bool {
+	for _, prefix := range reloadablePrefixes {
+		if key == strings.TrimSuffix(prefix, ".") || strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeEvent 配置变更事件，只有校验通过且仅包含可热加载项的变更才会发布
+type ChangeEvent struct {
+	Old *Config
+	New *Config
+	// Keys 发生变化的配置项（如 "log.level"），已排序
+	Keys []string
+}
+
+// Changed 判断指定配置项或配置段（如 "rate_limit"）是否发生变化
+func (e ChangeEvent) Changed(keyOrSection string) bool {
+	for _, key := range e.Keys {
+		if key == keyOrSection || strings.HasPrefix(key, keyOrSection+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// reloadDebounce 合并短时间内的多次文件事件
+// 编辑器保存文件时常产生 写入/重命名/创建 多个事件
+const reloadDebounce = 300 * time.Millisecond
+
+// Watcher 监听配置文件变化和 SIGHUP 信号，重新加载配置并通知订阅者
+type Watcher struct {
+	current atomic.Pointer[Config]
+	// reloadMu 保证同一时刻只有一次重新加载（文件事件与 SIGHUP 可能同时触发）
+	reloadMu sync.Mutex
+
+	mu          sync.Mutex
+	subscribers map[int]func(ChangeEvent)
+	nextID      int
+
+	fsWatcher *fsnotify.Watcher
+	signals   chan os.Signal
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewWatcher 以启动时加载的配置创建 Watcher
+// 调用 Start 后才开始监听
+func NewWatcher(cfg *Config) *Watcher {
+	w := &Watcher{
+		subscribers: make(map[int]func(ChangeEvent)),
+		done:        make(chan struct{}),
+	}
+	w.current.Store(cfg)
+	return w
+}
+
+// Current 返回当前生效的配置
+// 热加载会整体替换配置对象，调用方不应缓存返回值
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// GreetingTemplate 返回租户当前生效的问候语模板
+// 每次调用都读取最新配置，使用方无需订阅变更事件
+func (w *Watcher) GreetingTemplate(tenantID string) string {
+	return w.Current().GreetingTemplateFor(tenantID)
+}
+
+// Subscribe 注册配置变更回调，返回取消订阅函数
+// 回调在 Watcher 的 goroutine 中串行执行，不应长时间阻塞
+func (w *Watcher) Subscribe(fn func(ChangeEvent)) (unsubscribe func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.nextID
+	w.nextID++
+	w.subscribers[id] = fn
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.subscribers, id)
+	}
+}
+
+// Start 开始监听配置目录和 SIGHUP 信号
+// 监听目录而不是文件：编辑器保存时常以"写临时文件 + 重命名"的方式替换原文件，
+// 直接监听文件会在第一次替换后丢失后续事件
+func (w *Watcher) Start() error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	cfg := w.Current()
+	if err := fsWatcher.Add(filepath.Dir(cfg.Path())); err != nil {
+		fsWatcher.Close()
+		return fmt.Errorf("failed to watch config directory: %w", err)
+	}
+	w.fsWatcher = fsWatcher
+
+	w.signals = make(chan os.Signal, 1)
+	signal.Notify(w.signals, syscall.SIGHUP)
+
+	go w.loop()
+	return nil
+}
+
+// Close 停止监听
+func (w *Watcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+		if w.signals != nil {
+			signal.Stop(w.signals)
+		}
+		if w.fsWatcher != nil {
+			err = w.fsWatcher.Close()
+		}
+	})
+	return err
+}
+
+func (w *Watcher) loop() {
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.fsWatcher.Events:
+			if !ok {
+				return
+			}
+			if w.isConfigFile(event.Name) && event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				debounce = time.After(reloadDebounce)
+			}
+		case err, ok := <-w.fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("config watcher error", "error", err)
+		case <-w.signals:
+			slog.Info("received SIGHUP, reloading config")
+			w.Reload()
+		case <-debounce:
+			debounce = nil
+			slog.Info("config file changed, reloading config")
+			w.Reload()
+		}
+	}
+}
+
+// isConfigFile 判断事件文件是否为参与合并的配置文件（基础、环境、本地覆盖）
+func (w *Watcher) isConfigFile(name string) bool {
+	cfg := w.Current()
+	base := filepath.Clean(cfg.Path())
+	name = filepath.Clean(name)
+	return name == base ||
+		name == filepath.Clean(siblingFile(base, cfg.App.Env)) ||
+		name == filepath.Clean(siblingFile(base, "local"))
+}
+
+// Reload 立即重新加载配置
+// 新配置校验失败或包含不可热加载项的变更时，整体拒绝并保留旧配置
+func (w *Watcher) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	old := w.Current()
+
+	next, err := LoadConfig(old.Path(), old.loadOpts...)
+	if err != nil {
+		slog.Warn("config reload failed, keeping current config", "error", err)
+		return err
+	}
+	if err := next.Validate(); err != nil {
+		slog.Warn("reloaded config is invalid, keeping current config", "error", err)
+		return err
+	}
+
+	keys := diffConfig(old, next)
+	if len(keys) == 0 {
+		slog.Info("config reloaded, no changes")
+		return nil
+	}
+
+	var rejected []string
+	for _, key := range keys {
+		if !IsReloadable(key) {
+			rejected = append(rejected, key)
+		}
+	}
+	if len(rejected) > 0 {
+		err := fmt.Errorf("non-reloadable config keys changed: %s", strings.Join(rejected, ", "))
+		slog.Warn("config reload rejected, restart required to apply these changes",
+			"keys", rejected)
+		return err
+	}
+
+	w.current.Store(next)
+	slog.Info("config reloaded", "changed", keys)
+	w.publish(ChangeEvent{Old: old, New: next, Keys: keys})
+	return nil
+}
+
+func (w *Watcher) publish(event ChangeEvent) {
+	w.mu.Lock()
+	subscribers := make([]func(ChangeEvent), 0, len(w.subscribers))
+	for _, fn := range w.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	w.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
+
+// diffConfig 返回两份配置之间值不同的配置项
+func diffConfig(a, b *Config) []string {
+	left := flatten(dumpStruct(reflect.ValueOf(*a), "", nil, false), "")
+	right := flatten(dumpStruct(reflect.ValueOf(*b), "", nil, false), "")
+
+	var keys []string
+	for key, lv := range left {
+		if rv, ok := right[key]; !ok || !reflect.DeepEqual(lv, rv) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range right {
+		if _, ok := left[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// flatten 将嵌套 map 展开为 "a.b.c" 形式的键
+func flatten(m map[string]any, prefix string) map[string]any {
+	out := make(map[string]any)
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]any); ok {
+			for nk, nv := range flatten(nested, key) {
+				out[nk] = nv
+			}
+			continue
+		}
+		out[key] = v
+	}
+	return out
+}
@@ -0,0 +1,194 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// watcherBaseConfig 热加载测试使用的基础配置，{level} 与 {port} 由用例替换
+const watcherBaseConfig = `
+app:
+  port: {port}
+log:
+  level: {level}
+rate_limit:
+  enabled: true
+  rps: 10
+  burst: 20
+`
+
+func renderWatcherConfig(level, port string) string {
+	return strings.NewReplacer("{level}", level, "{port}", port).Replace(watcherBaseConfig)
+}
+
+// newTestWatcher 写入基础配置并创建 Watcher，返回配置文件路径
+func newTestWatcher(t *testing.T) (*Watcher, string) {
+	t.Helper()
+	t.Setenv("APP_ENV", "")
+	path := writeFile(t, t.TempDir(), "config.yaml", renderWatcherConfig("info", "8080"))
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	return NewWatcher(cfg), path
+}
+
+func TestWatcherReload(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantErr   string
+		wantKeys  []string
+		wantLevel string
+	}{
+		{
+			name:      "reloadable change",
+			content:   renderWatcherConfig("debug", "8080"),
+			wantKeys:  []string{"log.level"},
+			wantLevel: "debug",
+		},
+		{
+			name:      "reloadable section",
+			content:   strings.Replace(renderWatcherConfig("info", "8080"), "rps: 10", "rps: 50", 1),
+			wantKeys:  []string{"rate_limit.rps"},
+			wantLevel: "info",
+		},
+		{
+			name:      "no changes",
+			content:   renderWatcherConfig("info", "8080"),
+			wantLevel: "info",
+		},
+		{
+			name:      "non-reloadable key rejected",
+			content:   renderWatcherConfig("debug", "9999"),
+			wantErr:   "app.port",
+			wantLevel: "info",
+		},
+		{
+			name:      "invalid config rejected",
+			content:   renderWatcherConfig("verbose", "8080"),
+			wantErr:   "log.level",
+			wantLevel: "info",
+		},
+		{
+			name:      "unparsable file rejected",
+			content:   "log: [broken\n",
+			wantErr:   "failed",
+			wantLevel: "info",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, path := newTestWatcher(t)
+			old := w.Current()
+
+			var events []ChangeEvent
+			w.Subscribe(func(e ChangeEvent) { events = append(events, e) })
+
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			err := w.Reload()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Reload error = %v, want containing %q", err, tt.wantErr)
+				}
+				if w.Current() != old {
+					t.Error("rejected reload replaced the current config")
+				}
+			} else if err != nil {
+				t.Fatalf("Reload: %v", err)
+			}
+
+			if got := w.Current().Log.Level; got != tt.wantLevel {
+				t.Errorf("log.level = %q, want %q", got, tt.wantLevel)
+			}
+			if len(tt.wantKeys) == 0 {
+				if len(events) != 0 {
+					t.Errorf("published %d events, want none", len(events))
+				}
+				return
+			}
+			if len(events) != 1 {
+				t.Fatalf("published %d events, want 1", len(events))
+			}
+			e := events[0]
+			if strings.Join(e.Keys, ",") != strings.Join(tt.wantKeys, ",") {
+				t.Errorf("event keys = %v, want %v", e.Keys, tt.wantKeys)
+			}
+			if e.Old != old || e.New != w.Current() {
+				t.Error("event does not carry the old and new configs")
+			}
+		})
+	}
+}
+
+func TestWatcherUnsubscribe(t *testing.T) {
+	w, path := newTestWatcher(t)
+	calls := 0
+	unsubscribe := w.Subscribe(func(ChangeEvent) { calls++ })
+	unsubscribe()
+
+	if err := os.WriteFile(path, []byte(renderWatcherConfig("warn", "8080")), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("unsubscribed callback called %d times", calls)
+	}
+}
+
+func TestWatcherFileChange(t *testing.T) {
+	w, path := newTestWatcher(t)
+	events := make(chan ChangeEvent, 1)
+	w.Subscribe(func(e ChangeEvent) { events <- e })
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer w.Close()
+
+	// 以"写临时文件 + 重命名"的方式保存，与常见编辑器一致
+	tmp := filepath.Join(filepath.Dir(path), ".config.yaml.tmp")
+	if err := os.WriteFile(tmp, []byte(renderWatcherConfig("error", "8080")), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatalf("rename config: %v", err)
+	}
+
+	select {
+	case e := <-events:
+		if !e.Changed("log") || e.New.Log.Level != "error" {
+			t.Errorf("unexpected event: keys=%v level=%q", e.Keys, e.New.Log.Level)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change event after rewriting the config file")
+	}
+}
+
+func TestIsReloadable(t *testing.T) {
+	tests := map[string]bool{
+		"log.level":               true,
+		"log.format":              false,
+		"rate_limit.rps":          true,
+		"cors.allow_origins":      true,
+		"greeter.template":        true,
+		"tenancy.tenants.acme":    true,
+		"tenancy.header":          false,
+		"features.flags.new_ui":   true,
+		"features.file":           false,
+		"app.port":                false,
+		"database.host":           false,
+		"rate_limit_extra.policy": false,
+	}
+	for key, want := range tests {
+		if got := IsReloadable(key); got != want {
+			t.Errorf("IsReloadable(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
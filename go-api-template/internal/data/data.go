@@ -1,8 +1,10 @@
 // Package data 是数据层，负责实现 biz 层定义的 Repository 接口。
-// 当前阶段使用内存存储，未来可替换为真实数据库，而 biz 层无需任何改动。
+// database.driver 为 memory 时使用内存存储，否则使用 database/sql 连接真实数据库，
+// 两种实现满足同一组接口，biz 层无需任何改动。
 package data
 
 import (
+	"database/sql"
 	"log"
 	"sync"
 
@@ -12,49 +14,80 @@ import (
 )
 
 // ProviderSet 聚合 data 层所有模块的 ProviderSet
-// NewData 是基础设施（数据库连接等），单独列出
+// 不包含 *Data 本身：注入器按需选择 NewData（连接配置的数据库）或 NewMemoryData（只组装依赖，不处理请求）
 // 各模块的 Repository 在各自文件中定义 ProviderSet
 var ProviderSet = wire.NewSet(
-	NewData,            // 基础设施：数据库连接
+	NewTransaction,     // 基础设施：跨仓储事务
+	OutboxProviderSet,  // 基础设施：领域事件发件箱
+	JobProviderSet,     // 基础设施：后台任务队列
 	GreeterProviderSet, // Greeter 模块
-	// UserProviderSet,  // 未来：用户模块
-	// OrderProviderSet, // 未来：订单模块
+	UserProviderSet,    // User 模块
+	OrderProviderSet,   // Order 模块
+	WebhookProviderSet, // Webhook 模块
 )
 
 // Data 是数据层的核心结构，持有所有数据连接和存储
-// 阶段三：使用内存存储
-// 阶段五：将替换为真实数据库连接（如 Ent Client）
 type Data struct {
 	// 配置信息
 	cfg *conf.Config
 
-	// 内存存储，使用 sync.Map 保证并发安全
-	// 阶段五将替换为数据库客户端
+	// 数据库连接池，为 nil 表示使用内存存储
+	db *sql.DB
+	// 当前数据库的 SQL 方言
+	dialect dialect
+
+	// 内存存储，使用 sync.Map 保证并发安全，键带有租户前缀（tenantKey）
 	greeterStore *sync.Map
-	// 用于生成自增 ID
+	// 各租户的问候统计，租户 → *greeterStats
+	greeterStats *sync.Map
+	// 用于生成自增 ID，所有租户共用一个序列，与 SQL 的自增主键一致
 	idCounter int64
 	// 保护 idCounter 的互斥锁
 	mu sync.Mutex
+	// 内存存储的事务锁，InTx 持有期间其他事务等待
+	memTx sync.Mutex
 }
 
 // NewData 创建并初始化 Data 实例
 // cfg 提供数据库连接配置
-// 阶段五：此处将初始化数据库连接
-func NewData(cfg *conf.Config) (*Data, error) {
+// 返回的 cleanup 函数由 Wire 汇总，在应用退出时关闭连接
+func NewData(cfg *conf.Config) (*Data, func(), error) {
+	d := newData(cfg, cfg.Database.Driver)
+
+	if !cfg.Database.IsMemory() {
+		db, err := openDB(&cfg.Database)
+		if err != nil {
+			return nil, nil, err
+		}
+		d.db = db
+	}
+
 	// 记录数据库配置信息（不包含密码）
 	log.Printf("Data layer initialized: driver=%s, host=%s, database=%s",
 		cfg.Database.Driver, cfg.Database.Host, cfg.Database.Database)
 
-	// 阶段五将在此处：
-	// 1. 创建数据库连接
-	// 2. 运行数据库迁移
-	// 3. 返回包含数据库客户端的 Data 实例
+	cleanup := func() {
+		if err := d.Close(); err != nil {
+			log.Printf("Failed to close data layer: %v", err)
+		}
+	}
+	return d, cleanup, nil
+}
+
+// NewMemoryData 创建始终使用内存存储的 Data，不连接配置的数据库
+// 供只需要组装依赖、不处理请求的场景使用（如 routes 子命令）
+func NewMemoryData(cfg *conf.Config) *Data {
+	return newData(cfg, "memory")
+}
 
+func newData(cfg *conf.Config, driver string) *Data {
 	return &Data{
 		cfg:          cfg,
+		dialect:      dialect{driver: driver},
 		greeterStore: &sync.Map{},
+		greeterStats: &sync.Map{},
 		idCounter:    0,
-	}, nil
+	}
 }
 
 // NextID 生成下一个自增 ID（并发安全）
@@ -66,8 +99,12 @@ func (d *Data) NextID() int64 {
 }
 
 // Close 关闭数据层资源（如数据库连接）
-// 阶段五：此处将关闭数据库连接
 func (d *Data) Close() error {
+	if d.db != nil {
+		if err := d.db.Close(); err != nil {
+			return err
+		}
+	}
 	log.Println("Data layer closed")
 	return nil
 }
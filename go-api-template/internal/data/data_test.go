@@ -0,0 +1,94 @@
+package data
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/tenant"
+)
+
+// testDrivers 数据层测试覆盖的存储实现：内存与 SQL（SQLite 临时文件，执行全部迁移）
+var testDrivers = []string{"memory", "sqlite"}
+
+// newTestData 创建指定驱动的 Data，测试结束时关闭
+func newTestData(t *testing.T, driver string) *Data {
+	t.Helper()
+	if driver == "memory" {
+		return NewMemoryData(&conf.Config{Database: conf.DatabaseConfig{Driver: driver}})
+	}
+
+	cfg := &conf.Config{Database: conf.DatabaseConfig{
+		Driver:   driver,
+		Database: filepath.Join(t.TempDir(), "test.db"),
+	}}
+	d, cleanup, err := NewData(cfg)
+	if err != nil {
+		t.Fatalf("NewData(%s): %v", driver, err)
+	}
+	t.Cleanup(cleanup)
+
+	m, err := NewMigrator(d)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	return d
+}
+
+// forEachDriver 对每种存储实现运行一次 fn
+func forEachDriver(t *testing.T, fn func(t *testing.T, d *Data)) {
+	for _, driver := range testDrivers {
+		t.Run(driver, func(t *testing.T) {
+			fn(t, newTestData(t, driver))
+		})
+	}
+}
+
+// tenantContext 返回属于指定租户的 context
+func tenantContext(id string) context.Context {
+	return tenant.NewContext(context.Background(), id)
+}
+
+// listPages 按 params 解析分页请求，沿着下一页游标读完全部页，返回每页记录的 ID 与满足过滤条件的总数
+// 游标经过 Codec 签发与解析，与 Service 层的用法一致
+func listPages[T any](t *testing.T, spec *pagination.Spec, params pagination.Params,
+	list func(*pagination.Request) (*pagination.Page[T], error), id func(T) int64) ([][]int64, int64) {
+	t.Helper()
+	codec, err := pagination.NewCodec(&conf.Config{JWT: conf.JWTConfig{Secret: "test-secret"}})
+	if err != nil {
+		t.Fatalf("NewCodec: %v", err)
+	}
+	var pages [][]int64
+	var total int64
+	for {
+		req, err := codec.Parse(spec, params)
+		if err != nil {
+			t.Fatalf("Parse(%+v): %v", params, err)
+		}
+		page, err := list(req)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		ids := make([]int64, 0, len(page.Items))
+		for _, item := range page.Items {
+			ids = append(ids, id(item))
+		}
+		pages = append(pages, ids)
+		if len(pages) == 1 {
+			total = page.Total
+		} else if page.Total != total {
+			t.Errorf("page %d total = %d, want %d as on the first page", len(pages), page.Total, total)
+		}
+		if params.Cursor = codec.Encode(req, page.Next); params.Cursor == "" {
+			return pages, total
+		}
+		if len(pages) > 100 {
+			t.Fatal("cursor never reached the last page")
+		}
+	}
+}
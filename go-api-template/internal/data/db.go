@@ -0,0 +1,61 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-api-template/internal/conf"
+
+	// 注册 database/sql 驱动
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// pingTimeout 启动时检查数据库连通性的超时时间
+const pingTimeout = 5 * time.Second
+
+// sqlDriverNames 配置中的驱动名到 database/sql 注册名的映射
+var sqlDriverNames = map[string]string{
+	"postgres": "pgx",
+	"mysql":    "mysql",
+	"sqlite":   "sqlite",
+}
+
+// openDB 根据配置打开数据库连接池并检查连通性
+func openDB(cfg *conf.DatabaseConfig) (*sql.DB, error) {
+	driverName, ok := sqlDriverNames[cfg.Driver]
+	if !ok {
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+
+	// DSN 含密码，只在此处取出明文，错误信息中不包含 DSN
+	db, err := sql.Open(driverName, cfg.DSN().Reveal())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s database: %w", cfg.Driver, err)
+	}
+
+	if cfg.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	if cfg.Driver == "sqlite" {
+		// SQLite 同一时刻只允许一个写连接，多连接并发写会返回 SQLITE_BUSY
+		db.SetMaxOpenConns(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to %s database: %w", cfg.Driver, err)
+	}
+	return db, nil
+}
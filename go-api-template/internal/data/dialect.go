@@ -0,0 +1,138 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// dialect 屏蔽不同数据库之间的 SQL 差异
+// 仓储代码统一使用 "?" 占位符和 PostgreSQL 风格的 DDL，由 dialect 在执行前改写
+type dialect struct {
+	driver string
+}
+
+// rebind 将 "?" 占位符改写为目标数据库的格式（PostgreSQL 使用 $1, $2 ...）
+func (d dialect) rebind(query string) string {
+	if d.driver != "postgres" {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	for i := 0; i < len(query); i++ {
+		if query[i] == '?' {
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(query[i])
+	}
+	return b.String()
+}
+
+// ddlReplacements PostgreSQL 类型到其他数据库类型的映射
+// 迁移文件只写一份 PostgreSQL 风格的 DDL，避免为每种数据库维护一套文件
+var ddlReplacements = map[string]*strings.Replacer{
+	"mysql": strings.NewReplacer(
+		"BIGSERIAL PRIMARY KEY", "BIGINT AUTO_INCREMENT PRIMARY KEY",
+		"TIMESTAMPTZ", "DATETIME(6)",
+		"BYTEA", "LONGBLOB",
+		"BOOLEAN", "TINYINT(1)",
+	),
+	"sqlite": strings.NewReplacer(
+		"BIGSERIAL PRIMARY KEY", "INTEGER PRIMARY KEY AUTOINCREMENT",
+		"TIMESTAMPTZ", "TIMESTAMP",
+		"BYTEA", "BLOB",
+	),
+}
+
+// dropIndexOn 匹配 MySQL 风格的 "DROP INDEX 索引 ON 表"
+// MySQL 的索引属于表，删除时必须指明表名；PostgreSQL、SQLite 的索引名在库内唯一，不接受 ON 子句
+var dropIndexOn = regexp.MustCompile(`^(DROP INDEX \w+) ON \w+$`)
+
+// ddl 改写迁移语句中的类型，以及 DROP INDEX 的写法
+// 迁移文件中的 DROP INDEX 按 MySQL 写法带上表名，其他数据库执行前去掉
+func (d dialect) ddl(stmt string) string {
+	if d.driver != "mysql" {
+		stmt = dropIndexOn.ReplaceAllString(stmt, "$1")
+	}
+	if r, ok := ddlReplacements[d.driver]; ok {
+		return r.Replace(stmt)
+	}
+	return stmt
+}
+
+// insertReturningID 执行 INSERT 并返回自增主键
+// PostgreSQL 与 SQLite 支持 RETURNING，MySQL 需要使用 LastInsertId
+func (d dialect) insertReturningID(ctx context.Context, db execQuerier, query string, args ...any) (int64, error) {
+	if d.driver == "mysql" {
+		result, err := db.ExecContext(ctx, d.rebind(query), args...)
+		if err != nil {
+			return 0, err
+		}
+		return result.LastInsertId()
+	}
+
+	var id int64
+	err := db.QueryRowContext(ctx, d.rebind(query+" RETURNING id"), args...).Scan(&id)
+	return id, err
+}
+
+// countForUpdate 在事务中统计表中满足 where 条件的行数，并阻止其他事务在本事务结束前插入新行
+// 用于"读取计数 -> 按计数生成数据 -> 插入"必须串行的场景（如分配访问序号）：
+//   - PostgreSQL：SHARE ROW EXCLUSIVE 锁与自身互斥，但不阻塞普通读取；锁的是整张表，不同条件之间也会串行
+//   - MySQL：COUNT(*) ... FOR UPDATE 对扫描到的索引加 next-key 锁，阻塞其他事务插入
+//   - SQLite：连接池只有一个连接，事务本身已经串行
+func (d dialect) countForUpdate(ctx context.Context, tx execQuerier, table, where string, args ...any) (int64, error) {
+	query := "SELECT COUNT(*) FROM " + table + " WHERE " + where
+	switch d.driver {
+	case "postgres":
+		if _, err := tx.ExecContext(ctx, "LOCK TABLE "+table+" IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+			return 0, err
+		}
+	case "mysql":
+		query += " FOR UPDATE"
+	}
+
+	var count int64
+	err := tx.QueryRowContext(ctx, d.rebind(query), args...).Scan(&count)
+	return count, err
+}
+
+// execQuerier 是 *sql.DB 与 *sql.Tx 的公共方法集
+// 仓储方法依赖此接口，从而可以在事务内外复用同一份代码
+type execQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// isUniqueViolation 判断错误是否为唯一约束冲突
+// 仓储据此把数据库错误转换为 biz.ErrAlreadyExists，
+// 即使"先查询再插入"的检查被并发请求绕过，数据库约束仍能兜底
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505" // unique_violation
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062 // ER_DUP_ENTRY
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+	}
+	return false
+}
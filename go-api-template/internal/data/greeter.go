@@ -2,53 +2,151 @@ package data
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"sync/atomic"
 
 	"github.com/google/wire"
 
 	"go-api-template/internal/biz"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/tenant"
 )
 
 // GreeterProviderSet 是 Greeter 模块数据层的依赖提供者集合
 var GreeterProviderSet = wire.NewSet(NewGreeterRepo)
 
 // greeterRepo 实现 biz.GreeterRepo 接口
-// 当前使用内存 Map 存储，未来可替换为数据库实现
+// 使用内存 Map 存储，database.driver 为 memory 时生效
 type greeterRepo struct {
 	data *Data
 }
 
+// greeterStats 单个租户的问候统计
+// ID 由所有租户共用的序列分配，不能再用 idCounter 代替租户内的总数与最新 ID
+type greeterStats struct {
+	count  atomic.Int64
+	latest atomic.Int64
+}
+
 // NewGreeterRepo 创建 GreeterRepo 实例
-// 返回接口类型，隐藏实现细节
+// 返回接口类型，隐藏实现细节：根据数据库配置选择 SQL 或内存实现
 func NewGreeterRepo(data *Data) biz.GreeterRepo {
+	if data.db != nil {
+		return &sqlGreeterRepo{data: data}
+	}
 	return &greeterRepo{data: data}
 }
 
 // Save 保存问候记录到内存存储
 func (r *greeterRepo) Save(ctx context.Context, g *biz.Greeter) (*biz.Greeter, error) {
+	byName, err := scoped(ctx, "name:"+g.Name)
+	if err != nil {
+		return nil, err
+	}
+
 	// 分配新 ID
 	g.ID = r.data.NextID()
 
-	// 存储到 sync.Map，使用 ID 作为 key
-	r.data.greeterStore.Store(g.ID, g)
+	// 存储到 sync.Map，使用租户与 ID 作为 key
+	r.data.greeterStore.Store(tenantKey[int64]{tenant: byName.tenant, key: g.ID}, g)
 
-	// 同时以 name 为 key 存储，便于按名称查询
-	r.data.greeterStore.Store("name:"+g.Name, g)
+	// 同时以租户与 name 为 key 存储，便于按名称查询
+	r.data.greeterStore.Store(byName, g)
 
+	stats := r.stats(byName.tenant)
+	stats.count.Add(1)
+	stats.latest.Store(g.ID)
 	return g, nil
 }
 
+// Get 按 ID 获取租户的问候记录
+func (r *greeterRepo) Get(ctx context.Context, id int64) (*biz.Greeter, error) {
+	key, err := scoped(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := r.data.greeterStore.Load(key)
+	if !ok {
+		return nil, fmt.Errorf("greeting %d: %w", id, biz.ErrNotFound)
+	}
+	return value.(*biz.Greeter), nil
+}
+
 // GetByName 根据名称获取最近的问候记录
 func (r *greeterRepo) GetByName(ctx context.Context, name string) (*biz.Greeter, error) {
-	value, ok := r.data.greeterStore.Load("name:" + name)
+	key, err := scoped(ctx, "name:"+name)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := r.data.greeterStore.Load(key)
 	if !ok {
 		return nil, nil
 	}
 	return value.(*biz.Greeter), nil
 }
 
-// Count 获取问候记录总数
+// Count 获取租户的问候记录总数
 func (r *greeterRepo) Count(ctx context.Context) (int64, error) {
-	// idCounter 就是当前的记录总数
-	return atomic.LoadInt64(&r.data.idCounter), nil
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return r.stats(tenantID).count.Load(), nil
+}
+
+// ListSince 遍历内存存储中租户以 ID 为 key 的记录，按 ID 升序返回
+func (r *greeterRepo) ListSince(ctx context.Context, afterID int64, name string, limit int) ([]*biz.Greeter, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []*biz.Greeter
+	r.data.greeterStore.Range(func(key, value any) bool {
+		k, ok := key.(tenantKey[int64])
+		if !ok || k.tenant != tenantID || k.key <= afterID {
+			return true
+		}
+		g := value.(*biz.Greeter)
+		if name == "" || g.Name == name {
+			out = append(out, g)
+		}
+		return true
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// LatestID 返回租户最新一条问候记录的 ID
+func (r *greeterRepo) LatestID(ctx context.Context) (int64, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return r.stats(tenantID).latest.Load(), nil
+}
+
+// List 遍历租户以 ID 为 key 的记录，在内存中按 req 过滤、排序并分页
+func (r *greeterRepo) List(ctx context.Context, req *pagination.Request) (*pagination.Page[*biz.Greeter], error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []*biz.Greeter
+	r.data.greeterStore.Range(func(key, value any) bool {
+		if k, ok := key.(tenantKey[int64]); ok && k.tenant == tenantID {
+			out = append(out, value.(*biz.Greeter))
+		}
+		return true
+	})
+	return pagination.Paginate(out, req, biz.GreeterListValue), nil
+}
+
+// stats 返回租户的问候统计，第一次访问时创建
+func (r *greeterRepo) stats(tenantID string) *greeterStats {
+	value, _ := r.data.greeterStats.LoadOrStore(tenantID, &greeterStats{})
+	return value.(*greeterStats)
 }
@@ -0,0 +1,182 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/tenant"
+)
+
+// sqlGreeterRepo 基于 database/sql 实现 biz.GreeterRepo
+// 所有语句都以 tenant_id 为条件，只读写 ctx 中租户的记录
+type sqlGreeterRepo struct {
+	data *Data
+}
+
+// greeterColumns 查询问候记录时的列顺序，与各处 Scan 保持一致
+const greeterColumns = "id, name, message, version, created_at"
+
+// Save 插入问候记录并回填自增 ID
+func (r *sqlGreeterRepo) Save(ctx context.Context, g *biz.Greeter) (*biz.Greeter, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := r.data.dialect.insertReturningID(ctx, r.data.conn(ctx),
+		"INSERT INTO greeters (tenant_id, name, message, version, created_at) VALUES (?, ?, ?, ?, ?)",
+		tenantID, g.Name, g.Message, g.Version, g.CreatedAt.UTC())
+	if err != nil {
+		return nil, err
+	}
+	g.ID = id
+	return g, nil
+}
+
+// Get 按 ID 获取租户的问候记录
+func (r *sqlGreeterRepo) Get(ctx context.Context, id int64) (*biz.Greeter, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var g biz.Greeter
+	err = r.data.conn(ctx).QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT "+greeterColumns+" FROM greeters WHERE tenant_id = ? AND id = ?"), tenantID, id).
+		Scan(&g.ID, &g.Name, &g.Message, &g.Version, &g.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("greeting %d: %w", id, biz.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// GetByName 根据名称获取最近的问候记录，不存在时返回 nil
+func (r *sqlGreeterRepo) GetByName(ctx context.Context, name string) (*biz.Greeter, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var g biz.Greeter
+	err = r.data.conn(ctx).QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT "+greeterColumns+" FROM greeters WHERE tenant_id = ? AND name = ? ORDER BY id DESC LIMIT 1"), tenantID, name).
+		Scan(&g.ID, &g.Name, &g.Message, &g.Version, &g.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// Count 获取租户的问候记录总数
+// 在事务中调用时会锁定 greeters 的写入直到事务结束，
+// 保证并发的"计数 + 保存"依次执行，每个访问者拿到不同的序号
+func (r *sqlGreeterRepo) Count(ctx context.Context) (int64, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if inTx(ctx) {
+		return r.data.dialect.countForUpdate(ctx, r.data.conn(ctx), "greeters", "tenant_id = ?", tenantID)
+	}
+	var count int64
+	err = r.data.conn(ctx).QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT COUNT(*) FROM greeters WHERE tenant_id = ?"), tenantID).Scan(&count)
+	return count, err
+}
+
+// ListSince 按 ID 升序返回 afterID 之后的问候记录
+func (r *sqlGreeterRepo) ListSince(ctx context.Context, afterID int64, name string, limit int) ([]*biz.Greeter, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := "SELECT " + greeterColumns + " FROM greeters WHERE tenant_id = ? AND id > ?"
+	args := []any{tenantID, afterID}
+	if name != "" {
+		query += " AND name = ?"
+		args = append(args, name)
+	}
+	query += " ORDER BY id LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := r.data.conn(ctx).QueryContext(ctx, r.data.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*biz.Greeter
+	for rows.Next() {
+		var g biz.Greeter
+		if err := rows.Scan(&g.ID, &g.Name, &g.Message, &g.Version, &g.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, &g)
+	}
+	return out, rows.Err()
+}
+
+// greeterListColumns GreeterListSpec 中的字段对应的列
+var greeterListColumns = map[string]string{
+	"id":         "id",
+	"created_at": "created_at",
+	"name":       "name",
+}
+
+// List 先按过滤条件统计总数，再按游标或偏移查询一页
+func (r *sqlGreeterRepo) List(ctx context.Context, req *pagination.Request) (*pagination.Page[*biz.Greeter], error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	clauses := req.SQL(greeterListColumns)
+	db := r.data.conn(ctx)
+
+	var total int64
+	where, args := clauses.FilterWhere("tenant_id = ?", tenantID)
+	if err := db.QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT COUNT(*) FROM greeters WHERE "+where), args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	where, args = clauses.Where("tenant_id = ?", tenantID)
+	rows, err := db.QueryContext(ctx, r.data.dialect.rebind(
+		"SELECT "+greeterColumns+" FROM greeters WHERE "+where+" ORDER BY "+clauses.OrderBy+" LIMIT ? OFFSET ?"),
+		append(args, clauses.Limit, clauses.Offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*biz.Greeter
+	for rows.Next() {
+		var g biz.Greeter
+		if err := rows.Scan(&g.ID, &g.Name, &g.Message, &g.Version, &g.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, &g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return pagination.NewPage(out, total, req, biz.GreeterListValue), nil
+}
+
+// LatestID 返回租户最大的问候记录 ID，没有记录时返回 0
+func (r *sqlGreeterRepo) LatestID(ctx context.Context) (int64, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var id sql.NullInt64
+	err = r.data.conn(ctx).QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT MAX(id) FROM greeters WHERE tenant_id = ?"), tenantID).Scan(&id)
+	return id.Int64, err
+}
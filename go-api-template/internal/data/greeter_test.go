@@ -0,0 +1,64 @@
+package data
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/pkg/pagination"
+)
+
+func TestGreeterRepoList(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, d *Data) {
+		repo := NewGreeterRepo(d)
+		acme := tenantContext("acme")
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		// 创建时间与保存顺序相反，用于区分按 ID 与按 created_at 排序
+		names := []string{"alice", "bob", "alice", "carol", "alice"}
+		ids := make([]int64, len(names))
+		for i, name := range names {
+			g, err := repo.Save(acme, &biz.Greeter{Name: name, Message: "hi", Version: 1,
+				CreatedAt: start.Add(time.Duration(len(names)-i) * time.Hour)})
+			if err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			ids[i] = g.ID
+		}
+		// 其他租户的问候不出现在列表中
+		if _, err := repo.Save(tenantContext("globex"), &biz.Greeter{Name: "alice", Message: "hi", Version: 1, CreatedAt: start}); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		tests := []struct {
+			name      string
+			params    pagination.Params
+			wantPages [][]int64
+			wantTotal int64
+		}{
+			{name: "newest first", params: pagination.Params{Limit: 2},
+				wantPages: [][]int64{{ids[4], ids[3]}, {ids[2], ids[1]}, {ids[0]}}, wantTotal: 5},
+			{name: "filter by name", params: pagination.Params{Limit: 2, Filter: "name=alice"},
+				wantPages: [][]int64{{ids[4], ids[2]}, {ids[0]}}, wantTotal: 3},
+			{name: "oldest created first", params: pagination.Params{Limit: 3, Sort: "created_at"},
+				wantPages: [][]int64{{ids[4], ids[3], ids[2]}, {ids[1], ids[0]}}, wantTotal: 5},
+			{name: "filter by created_at", params: pagination.Params{Filter: "created_at>=2024-01-01T04:00:00Z,name!=alice"},
+				wantPages: [][]int64{{ids[1]}}, wantTotal: 1},
+			{name: "offset", params: pagination.Params{Limit: 2, Offset: 3},
+				wantPages: [][]int64{{ids[1], ids[0]}}, wantTotal: 5},
+			{name: "no match", params: pagination.Params{Filter: "name=dave"},
+				wantPages: [][]int64{{}}, wantTotal: 0},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				pages, total := listPages(t, biz.GreeterListSpec, tt.params,
+					func(req *pagination.Request) (*pagination.Page[*biz.Greeter], error) { return repo.List(acme, req) },
+					func(g *biz.Greeter) int64 { return g.ID })
+				if !slices.EqualFunc(pages, tt.wantPages, slices.Equal) || total != tt.wantTotal {
+					t.Errorf("pages = %v (total %d), want %v (total %d)", pages, total, tt.wantPages, tt.wantTotal)
+				}
+			})
+		}
+	})
+}
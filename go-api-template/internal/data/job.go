@@ -0,0 +1,20 @@
+package data
+
+import (
+	"github.com/google/wire"
+
+	"go-api-template/internal/pkg/jobs"
+)
+
+// JobProviderSet 后台任务数据库存储的依赖提供者集合
+// jobs.store 为 database 时 jobs.NewStore 使用这里提供的实现
+var JobProviderSet = wire.NewSet(NewJobStore)
+
+// NewJobStore 创建基于应用数据库的任务存储
+// database.driver 为 memory 时任务只保存在进程内存中
+func NewJobStore(data *Data) jobs.DBStore {
+	if data.db != nil {
+		return &sqlJobStore{data: data}
+	}
+	return jobs.NewMemoryStore()
+}
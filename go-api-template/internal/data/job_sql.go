@@ -0,0 +1,234 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-api-template/internal/pkg/jobs"
+)
+
+// sqlJobStore 基于 database/sql 实现 jobs.DBStore
+type sqlJobStore struct {
+	data *Data
+}
+
+// jobColumns 查询时的列顺序，与 scanJob 保持一致
+const jobColumns = "id, queue, job_type, payload, status, attempts, max_attempts, run_at, last_error, unique_key, created_at, updated_at, finished_at"
+
+// Enqueue 实现 jobs.Store
+// 通过 conn(ctx) 执行，调用方在事务中时任务与业务数据一起提交或回滚
+func (r *sqlJobStore) Enqueue(ctx context.Context, job *jobs.Job) error {
+	var uniqueKey any
+	if job.UniqueKey != "" {
+		uniqueKey = job.UniqueKey
+	}
+	id, err := r.data.dialect.insertReturningID(ctx, r.data.conn(ctx),
+		"INSERT INTO jobs (queue, job_type, payload, status, attempts, max_attempts, run_at, last_error, unique_key, created_at, updated_at) VALUES (?, ?, ?, ?, 0, ?, ?, '', ?, ?, ?)",
+		job.Queue, job.Type, string(job.Payload), string(jobs.StatusQueued), job.MaxAttempts, job.RunAt.UTC(),
+		uniqueKey, job.CreatedAt.UTC(), job.UpdatedAt.UTC())
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: %s", jobs.ErrDuplicate, job.UniqueKey)
+	}
+	if err != nil {
+		return err
+	}
+	job.ID = id
+	return nil
+}
+
+// Claim 实现 jobs.Store
+// 与 outbox 相同：先查出到期任务，再以 "run_at <= now" 为条件逐条改为执行中并推迟到租约结束，
+// 条件更新只有一个实例能成功，多实例部署时同一任务不会被并发执行；
+// 执行次数已用完的过期任务以同样的条件更新移入死信
+func (r *sqlJobStore) Claim(ctx context.Context, queue string, now time.Time, limit int, lease time.Duration) ([]*jobs.Job, error) {
+	now = now.UTC()
+	due, err := r.query(ctx,
+		"SELECT "+jobColumns+" FROM jobs WHERE queue = ? AND status IN (?, ?) AND run_at <= ? ORDER BY run_at, id LIMIT ?",
+		queue, string(jobs.StatusQueued), string(jobs.StatusRunning), now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := due[:0]
+	for _, job := range due {
+		if job.LeaseExhausted(now) {
+			if err := r.deadLetterExpired(ctx, job, now); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		result, err := r.data.db.ExecContext(ctx, r.data.dialect.rebind(
+			"UPDATE jobs SET status = ?, attempts = attempts + 1, run_at = ?, updated_at = ? WHERE id = ? AND status IN (?, ?) AND run_at <= ?"),
+			string(jobs.StatusRunning), now.Add(lease), now, job.ID, string(jobs.StatusQueued), string(jobs.StatusRunning), now)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return nil, err
+		} else if n == 1 {
+			job.Status = jobs.StatusRunning
+			job.Attempts++
+			job.RunAt = now.Add(lease)
+			job.UpdatedAt = now
+			claimed = append(claimed, job)
+		}
+	}
+	return claimed, nil
+}
+
+// deadLetterExpired 把租约已过期且执行次数已用完的任务移入死信
+// 条件与 Claim 相同，其他实例已领取或处理过该任务时不做任何修改
+func (r *sqlJobStore) deadLetterExpired(ctx context.Context, job *jobs.Job, now time.Time) error {
+	_, err := r.data.db.ExecContext(ctx, r.data.dialect.rebind(
+		"UPDATE jobs SET status = ?, last_error = ?, updated_at = ?, finished_at = ? WHERE id = ? AND status = ? AND attempts = ? AND run_at <= ?"),
+		string(jobs.StatusDead), jobs.ErrLeaseExpired.Error(), now, now,
+		job.ID, string(jobs.StatusRunning), job.Attempts, now)
+	return err
+}
+
+// Complete 实现 jobs.Store
+func (r *sqlJobStore) Complete(ctx context.Context, id int64, attempt int, at time.Time) error {
+	_, err := r.data.db.ExecContext(ctx, r.data.dialect.rebind(
+		"UPDATE jobs SET status = ?, updated_at = ?, finished_at = ? WHERE id = ? AND status = ? AND attempts = ?"),
+		string(jobs.StatusSucceeded), at.UTC(), at.UTC(), id, string(jobs.StatusRunning), attempt)
+	return err
+}
+
+// Fail 实现 jobs.Store
+func (r *sqlJobStore) Fail(ctx context.Context, id int64, attempt int, next time.Time, reason string, at time.Time) error {
+	if next.IsZero() {
+		_, err := r.data.db.ExecContext(ctx, r.data.dialect.rebind(
+			"UPDATE jobs SET status = ?, last_error = ?, updated_at = ?, finished_at = ? WHERE id = ? AND status = ? AND attempts = ?"),
+			string(jobs.StatusDead), reason, at.UTC(), at.UTC(), id, string(jobs.StatusRunning), attempt)
+		return err
+	}
+	_, err := r.data.db.ExecContext(ctx, r.data.dialect.rebind(
+		"UPDATE jobs SET status = ?, run_at = ?, last_error = ?, updated_at = ? WHERE id = ? AND status = ? AND attempts = ?"),
+		string(jobs.StatusQueued), next.UTC(), reason, at.UTC(), id, string(jobs.StatusRunning), attempt)
+	return err
+}
+
+// Get 实现 jobs.Store
+func (r *sqlJobStore) Get(ctx context.Context, id int64) (*jobs.Job, error) {
+	job, err := scanJob(r.data.db.QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT "+jobColumns+" FROM jobs WHERE id = ?"), id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("job %d: %w", id, jobs.ErrNotFound)
+	}
+	return job, err
+}
+
+// List 实现 jobs.Store
+func (r *sqlJobStore) List(ctx context.Context, f jobs.Filter) ([]*jobs.Job, error) {
+	query := "SELECT " + jobColumns + " FROM jobs WHERE 1 = 1"
+	var args []any
+	if f.Status != "" {
+		query += " AND status = ?"
+		args = append(args, string(f.Status))
+	}
+	if f.Queue != "" {
+		query += " AND queue = ?"
+		args = append(args, f.Queue)
+	}
+	if f.Type != "" {
+		query += " AND job_type = ?"
+		args = append(args, f.Type)
+	}
+	query += " ORDER BY id DESC"
+	if f.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, f.Limit)
+	}
+	return r.query(ctx, query, args...)
+}
+
+// Retry 实现 jobs.Store
+func (r *sqlJobStore) Retry(ctx context.Context, id int64, now time.Time) (*jobs.Job, error) {
+	now = now.UTC()
+	return r.transition(ctx, id,
+		"UPDATE jobs SET status = ?, attempts = 0, run_at = ?, updated_at = ?, finished_at = NULL WHERE id = ? AND status IN (?, ?, ?)",
+		string(jobs.StatusQueued), now, now, id,
+		string(jobs.StatusDead), string(jobs.StatusCancelled), string(jobs.StatusSucceeded))
+}
+
+// Cancel 实现 jobs.Store
+func (r *sqlJobStore) Cancel(ctx context.Context, id int64, now time.Time) (*jobs.Job, error) {
+	now = now.UTC()
+	return r.transition(ctx, id,
+		"UPDATE jobs SET status = ?, updated_at = ?, finished_at = ? WHERE id = ? AND status = ?",
+		string(jobs.StatusCancelled), now, now, id, string(jobs.StatusQueued))
+}
+
+// Purge 实现 jobs.Store，死信任务保留到人工处理
+func (r *sqlJobStore) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.data.db.ExecContext(ctx, r.data.dialect.rebind(
+		"DELETE FROM jobs WHERE status IN (?, ?) AND finished_at < ?"),
+		string(jobs.StatusSucceeded), string(jobs.StatusCancelled), before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// transition 执行管理操作的条件更新并返回更新后的任务
+// 没有行被更新时，任务不存在返回 ErrNotFound，否则说明状态不允许该操作
+func (r *sqlJobStore) transition(ctx context.Context, id int64, query string, args ...any) (*jobs.Job, error) {
+	result, err := r.data.db.ExecContext(ctx, r.data.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	job, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("job %d is %s: %w", id, job.Status, jobs.ErrInvalidState)
+	}
+	return job, nil
+}
+
+// query 执行查询并扫描全部任务
+func (r *sqlJobStore) query(ctx context.Context, query string, args ...any) ([]*jobs.Job, error) {
+	rows, err := r.data.db.QueryContext(ctx, r.data.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*jobs.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, job)
+	}
+	return out, rows.Err()
+}
+
+// scanJob 按 jobColumns 的顺序扫描一行任务
+func scanJob(row rowScanner) (*jobs.Job, error) {
+	var job jobs.Job
+	var payload, status string
+	var uniqueKey sql.NullString
+	var finishedAt sql.NullTime
+	err := row.Scan(&job.ID, &job.Queue, &job.Type, &payload, &status, &job.Attempts, &job.MaxAttempts,
+		&job.RunAt, &job.LastError, &uniqueKey, &job.CreatedAt, &job.UpdatedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	job.Payload = []byte(payload)
+	job.Status = jobs.Status(status)
+	job.UniqueKey = uniqueKey.String
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return &job, nil
+}
@@ -0,0 +1,250 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"go-api-template/internal/pkg/jobs"
+)
+
+const testLease = time.Minute
+
+// enqueueTestJob 在 default 队列写入一个立即可执行的任务
+func enqueueTestJob(t *testing.T, store jobs.Store, now time.Time, maxAttempts int) *jobs.Job {
+	t.Helper()
+	job := &jobs.Job{
+		Queue:       "default",
+		Type:        "test.job",
+		Payload:     json.RawMessage(`{"n":1}`),
+		Status:      jobs.StatusQueued,
+		MaxAttempts: maxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := store.Enqueue(context.Background(), job); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	return job
+}
+
+// claimOne 领取并断言恰好领到 id 对应的任务
+func claimOne(t *testing.T, store jobs.Store, now time.Time, id int64) *jobs.Job {
+	t.Helper()
+	claimed, err := store.Claim(context.Background(), "default", now, 10, testLease)
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != id {
+		t.Fatalf("Claim = %d jobs, want job %d", len(claimed), id)
+	}
+	return claimed[0]
+}
+
+func getJob(t *testing.T, store jobs.Store, id int64) *jobs.Job {
+	t.Helper()
+	job, err := store.Get(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	return job
+}
+
+// testNow 截断到秒，避免不同数据库的时间精度影响比较
+func testNow() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+func TestJobStoreClaimLease(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, d *Data) {
+		store := NewJobStore(d)
+		ctx := context.Background()
+		now := testNow()
+		job := enqueueTestJob(t, store, now, 3)
+
+		first := claimOne(t, store, now, job.ID)
+		if first.Status != jobs.StatusRunning || first.Attempts != 1 {
+			t.Fatalf("claimed job = {status:%s attempts:%d}, want running/1", first.Status, first.Attempts)
+		}
+		// 租约期间不会被再次领取
+		if again, err := store.Claim(ctx, "default", now.Add(time.Second), 10, testLease); err != nil || len(again) != 0 {
+			t.Fatalf("Claim during lease = %d jobs, %v; want none", len(again), err)
+		}
+
+		// 租约过期后被重新领取，执行次数即新的租约凭证
+		expired := now.Add(testLease + time.Second)
+		second := claimOne(t, store, expired, job.ID)
+		if second.Attempts != 2 {
+			t.Fatalf("re-claimed attempts = %d, want 2", second.Attempts)
+		}
+
+		// 第一次执行迟到的结果被忽略
+		if err := store.Complete(ctx, job.ID, first.Attempts, expired); err != nil {
+			t.Fatalf("stale Complete: %v", err)
+		}
+		if err := store.Fail(ctx, job.ID, first.Attempts, time.Time{}, "stale", expired); err != nil {
+			t.Fatalf("stale Fail: %v", err)
+		}
+		if got := getJob(t, store, job.ID); got.Status != jobs.StatusRunning || got.LastError != "" {
+			t.Fatalf("after stale results job = {status:%s error:%q}, want running with no error", got.Status, got.LastError)
+		}
+
+		if err := store.Complete(ctx, job.ID, second.Attempts, expired); err != nil {
+			t.Fatalf("Complete: %v", err)
+		}
+		got := getJob(t, store, job.ID)
+		if got.Status != jobs.StatusSucceeded || got.FinishedAt == nil {
+			t.Fatalf("after Complete job = {status:%s finished:%v}, want succeeded", got.Status, got.FinishedAt)
+		}
+	})
+}
+
+func TestJobStoreFail(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, d *Data) {
+		store := NewJobStore(d)
+		ctx := context.Background()
+		now := testNow()
+
+		// 重新排队：next 时间前不可领取
+		job := enqueueTestJob(t, store, now, 3)
+		claimed := claimOne(t, store, now, job.ID)
+		next := now.Add(time.Hour)
+		if err := store.Fail(ctx, job.ID, claimed.Attempts, next, "boom", now); err != nil {
+			t.Fatalf("Fail: %v", err)
+		}
+		got := getJob(t, store, job.ID)
+		if got.Status != jobs.StatusQueued || got.LastError != "boom" || !got.RunAt.Equal(next) {
+			t.Fatalf("after Fail job = {status:%s error:%q run_at:%v}, want queued/boom/%v", got.Status, got.LastError, got.RunAt, next)
+		}
+		if due, err := store.Claim(ctx, "default", now.Add(time.Minute), 10, testLease); err != nil || len(due) != 0 {
+			t.Fatalf("Claim before retry time = %d jobs, %v; want none", len(due), err)
+		}
+		claimed = claimOne(t, store, next, job.ID)
+
+		// next 为零值进入死信
+		if err := store.Fail(ctx, job.ID, claimed.Attempts, time.Time{}, "fatal", next); err != nil {
+			t.Fatalf("Fail: %v", err)
+		}
+		got = getJob(t, store, job.ID)
+		if got.Status != jobs.StatusDead || got.LastError != "fatal" || got.FinishedAt == nil {
+			t.Fatalf("after Fail job = {status:%s error:%q}, want dead/fatal", got.Status, got.LastError)
+		}
+	})
+}
+
+func TestJobStoreDeadLettersExhaustedLease(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, d *Data) {
+		store := NewJobStore(d)
+		now := testNow()
+		exhausted := enqueueTestJob(t, store, now, 1)
+		retryable := enqueueTestJob(t, store, now, 2)
+
+		claimed, err := store.Claim(context.Background(), "default", now, 10, testLease)
+		if err != nil || len(claimed) != 2 {
+			t.Fatalf("Claim = %d jobs, %v; want 2", len(claimed), err)
+		}
+
+		// 两个 worker 都没有上报结果：执行次数用完的进入死信，另一个被重新领取
+		expired := now.Add(testLease + time.Second)
+		claimOne(t, store, expired, retryable.ID)
+
+		got := getJob(t, store, exhausted.ID)
+		if got.Status != jobs.StatusDead || got.LastError != jobs.ErrLeaseExpired.Error() || got.FinishedAt == nil {
+			t.Fatalf("exhausted job = {status:%s error:%q}, want dead with lease-expired error", got.Status, got.LastError)
+		}
+		if got.Attempts != 1 {
+			t.Errorf("exhausted job attempts = %d, want 1", got.Attempts)
+		}
+	})
+}
+
+func TestJobStoreTransitions(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, d *Data) {
+		store := NewJobStore(d)
+		ctx := context.Background()
+		now := testNow()
+
+		queued := enqueueTestJob(t, store, now.Add(time.Hour), 3)
+		running := enqueueTestJob(t, store, now, 3)
+		claimOne(t, store, now, running.ID)
+
+		tests := []struct {
+			name    string
+			op      func(ctx context.Context, id int64, now time.Time) (*jobs.Job, error)
+			id      int64
+			wantErr error
+			want    jobs.Status
+		}{
+			{"retry queued", store.Retry, queued.ID, jobs.ErrInvalidState, ""},
+			{"cancel running", store.Cancel, running.ID, jobs.ErrInvalidState, ""},
+			{"cancel unknown", store.Cancel, 999, jobs.ErrNotFound, ""},
+			{"cancel queued", store.Cancel, queued.ID, nil, jobs.StatusCancelled},
+			{"retry cancelled", store.Retry, queued.ID, nil, jobs.StatusQueued},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				job, err := tt.op(ctx, tt.id, now)
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Fatalf("err = %v, want %v", err, tt.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				if job.Status != tt.want {
+					t.Errorf("status = %s, want %s", job.Status, tt.want)
+				}
+			})
+		}
+
+		// 重新排队后执行次数清零，可立即领取
+		if got := getJob(t, store, queued.ID); got.Attempts != 0 || got.FinishedAt != nil {
+			t.Errorf("retried job = {attempts:%d finished:%v}, want 0/nil", got.Attempts, got.FinishedAt)
+		}
+		claimOne(t, store, now, queued.ID)
+	})
+}
+
+func TestJobStoreUniqueKeyAndPurge(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, d *Data) {
+		store := NewJobStore(d)
+		ctx := context.Background()
+		now := testNow()
+
+		newJob := func() *jobs.Job {
+			return &jobs.Job{Queue: "default", Type: "test.job", Payload: json.RawMessage(`{}`),
+				Status: jobs.StatusQueued, MaxAttempts: 3, RunAt: now, UniqueKey: "report:2026-10-18",
+				CreatedAt: now, UpdatedAt: now}
+		}
+		job := newJob()
+		if err := store.Enqueue(ctx, job); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+		if err := store.Enqueue(ctx, newJob()); !errors.Is(err, jobs.ErrDuplicate) {
+			t.Fatalf("duplicate Enqueue = %v, want ErrDuplicate", err)
+		}
+
+		claimed := claimOne(t, store, now, job.ID)
+		if err := store.Complete(ctx, job.ID, claimed.Attempts, now); err != nil {
+			t.Fatalf("Complete: %v", err)
+		}
+		if n, err := store.Purge(ctx, now); err != nil || n != 0 {
+			t.Fatalf("Purge(before finish) = %d, %v; want 0", n, err)
+		}
+		if n, err := store.Purge(ctx, now.Add(time.Second)); err != nil || n != 1 {
+			t.Fatalf("Purge = %d, %v; want 1", n, err)
+		}
+		if _, err := store.Get(ctx, job.ID); !errors.Is(err, jobs.ErrNotFound) {
+			t.Fatalf("Get purged job = %v, want ErrNotFound", err)
+		}
+		// 清理后同一个 UniqueKey 可以再次入队
+		if err := store.Enqueue(ctx, newJob()); err != nil {
+			t.Fatalf("Enqueue after purge: %v", err)
+		}
+	})
+}
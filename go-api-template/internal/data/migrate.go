@@ -0,0 +1,264 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationFiles 迁移脚本随二进制一起发布，部署时无需额外拷贝 SQL 文件
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationsTable 记录已执行迁移的表
+const migrationsTable = "schema_migrations"
+
+// ErrMemoryDriver 内存存储不需要也无法执行迁移
+var ErrMemoryDriver = errors.New("database.driver is memory, migrations require a SQL database")
+
+// Migration 一个版本的迁移脚本
+type Migration struct {
+	Version int64
+	Name    string
+	up      string
+	down    string
+}
+
+// MigrationStatus 迁移的执行状态
+type MigrationStatus struct {
+	Migration
+	// AppliedAt 执行时间，未执行时为 nil
+	AppliedAt *time.Time
+}
+
+// Migrator 执行数据库迁移
+// 迁移文件命名：<版本号>_<名称>.up.sql / <版本号>_<名称>.down.sql
+type Migrator struct {
+	data       *Data
+	migrations []Migration
+}
+
+// NewMigrator 创建迁移执行器
+func NewMigrator(data *Data) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{data: data, migrations: migrations}, nil
+}
+
+// loadMigrations 读取并按版本号排序所有迁移脚本
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, file := range entries {
+		base := path.Base(file)
+		direction := ""
+		switch {
+		case strings.HasSuffix(base, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(base, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s must end with .up.sql or .down.sql", base)
+		}
+
+		stem := strings.TrimSuffix(base, "."+direction+".sql")
+		versionText, name, ok := strings.Cut(stem, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s must be named <version>_<name>", base)
+		}
+		version, err := strconv.ParseInt(versionText, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has invalid version: %w", base, err)
+		}
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.up = string(content)
+		} else {
+			m.down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" {
+			return nil, fmt.Errorf("migration %d_%s is missing its up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up 按顺序执行所有未执行的迁移，返回本次执行的迁移
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+		err := m.run(ctx, mig.up, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, m.data.dialect.rebind(
+				"INSERT INTO "+migrationsTable+" (version, name, applied_at) VALUES (?, ?, ?)"),
+				mig.Version, mig.Name, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d_%s failed: %w", mig.Version, mig.Name, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Down 回滚最近执行的 steps 个迁移，返回本次回滚的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if mig.down == "" {
+			return done, fmt.Errorf("migration %d_%s has no down script", mig.Version, mig.Name)
+		}
+		err := m.run(ctx, mig.down, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, m.data.dialect.rebind(
+				"DELETE FROM "+migrationsTable+" WHERE version = ?"), mig.Version)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("rollback %d_%s failed: %w", mig.Version, mig.Name, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Status 返回所有迁移及其执行状态
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		status := MigrationStatus{Migration: mig}
+		if at, ok := applied[mig.Version]; ok {
+			status.AppliedAt = &at
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+// applied 查询已执行的迁移版本，必要时创建迁移记录表
+func (m *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	if m.data.db == nil {
+		return nil, ErrMemoryDriver
+	}
+
+	_, err := m.data.db.ExecContext(ctx, m.data.dialect.ddl(
+		"CREATE TABLE IF NOT EXISTS "+migrationsTable+
+			" (version BIGINT PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMPTZ NOT NULL)"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", migrationsTable, err)
+	}
+
+	rows, err := m.data.db.QueryContext(ctx, "SELECT version, applied_at FROM "+migrationsTable)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+// run 在事务中执行脚本并记录迁移状态
+// 注意：MySQL 的 DDL 会隐式提交事务，失败时可能留下部分执行的结果
+func (m *Migrator) run(ctx context.Context, script string, record func(tx *sql.Tx) error) error {
+	tx, err := m.data.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, m.data.dialect.ddl(stmt)); err != nil {
+			return err
+		}
+	}
+	if err := record(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// splitStatements 按行尾分号拆分脚本，并去掉 "--" 注释行
+// 部分驱动（如 MySQL 默认配置）不支持一次执行多条语句
+func splitStatements(script string) []string {
+	var (
+		stmts   []string
+		current strings.Builder
+	)
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteByte('\n')
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		stmts = append(stmts, rest)
+	}
+	return stmts
+}
@@ -0,0 +1,10 @@
+-- 问候记录表
+CREATE TABLE greeters (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    message VARCHAR(500) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+-- GetByName 按名称查询最近一条记录
+CREATE INDEX idx_greeters_name ON greeters (name);
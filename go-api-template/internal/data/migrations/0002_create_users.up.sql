@@ -0,0 +1,13 @@
+-- 用户表
+CREATE TABLE users (
+    id BIGSERIAL PRIMARY KEY,
+    username VARCHAR(32) NOT NULL,
+    email VARCHAR(254) NOT NULL DEFAULT '',
+    nickname VARCHAR(50) NOT NULL DEFAULT '',
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- 登录名全局唯一，并发注册同名用户时由数据库保证只有一个成功
+CREATE UNIQUE INDEX idx_users_username ON users (username);
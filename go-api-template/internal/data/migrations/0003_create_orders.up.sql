@@ -0,0 +1,29 @@
+-- 订单表
+-- status 保存状态编码（pending/paid/...），合法流转由 biz 层状态机保证
+CREATE TABLE orders (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    product VARCHAR(200) NOT NULL,
+    quantity INTEGER NOT NULL,
+    amount BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- ListByUser 按用户查询订单
+CREATE INDEX idx_orders_user_id ON orders (user_id);
+
+-- 订单状态流转审计记录，只追加不修改
+CREATE TABLE order_transitions (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    event VARCHAR(20) NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    note VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_order_transitions_order_id ON order_transitions (order_id);
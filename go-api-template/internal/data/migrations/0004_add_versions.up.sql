@@ -0,0 +1,5 @@
+-- 乐观并发控制的版本号：每次更新时加一，更新语句以 "WHERE version = 读取时的版本" 为条件
+-- 已有数据从 1 开始，与新建记录的初始版本一致
+ALTER TABLE users ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE orders ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,17 @@
+-- 事务性发件箱：领域事件与业务数据在同一事务中写入，由 relay 异步投递
+-- status: pending 待投递 | published 已投递 | failed 超过最大投递次数
+-- next_attempt_at 同时用作领取租约：领取时推迟到租约结束，避免多个 relay 重复投递
+CREATE TABLE outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(100) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    last_error VARCHAR(1000) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    published_at TIMESTAMPTZ NULL
+);
+
+-- Claim 按状态和投递时间查询
+CREATE INDEX idx_outbox_events_status_next_attempt ON outbox_events (status, next_attempt_at);
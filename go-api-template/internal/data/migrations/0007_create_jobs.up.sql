@@ -0,0 +1,24 @@
+-- 后台任务队列
+-- status: queued 等待执行 | running 执行中 | succeeded 已成功 | dead 死信 | cancelled 已取消
+-- run_at 对排队中的任务是计划执行时间，对执行中的任务是领取租约的到期时间
+-- unique_key 为空时是 NULL，不参与唯一约束
+CREATE TABLE jobs (
+    id BIGSERIAL PRIMARY KEY,
+    queue VARCHAR(100) NOT NULL,
+    job_type VARCHAR(100) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    run_at TIMESTAMPTZ NOT NULL,
+    last_error VARCHAR(1000) NOT NULL DEFAULT '',
+    unique_key VARCHAR(200) NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NULL
+);
+
+CREATE UNIQUE INDEX idx_jobs_unique_key ON jobs (unique_key);
+-- Claim 按队列、状态和执行时间查询；清理按状态和结束时间查询
+CREATE INDEX idx_jobs_queue_status_run_at ON jobs (queue, status, run_at);
+CREATE INDEX idx_jobs_status_finished_at ON jobs (status, finished_at);
@@ -0,0 +1,20 @@
+-- 不同租户存在同名用户时，恢复全局唯一的用户名索引会失败，需要先手工合并数据
+DROP INDEX idx_webhook_subscriptions_tenant_user_id ON webhook_subscriptions;
+
+DROP INDEX idx_orders_tenant_user_id ON orders;
+
+DROP INDEX idx_greeters_tenant_name ON greeters;
+
+DROP INDEX idx_users_tenant_username ON users;
+
+CREATE UNIQUE INDEX idx_users_username ON users (username);
+
+ALTER TABLE outbox_events DROP COLUMN tenant_id;
+
+ALTER TABLE webhook_subscriptions DROP COLUMN tenant_id;
+
+ALTER TABLE orders DROP COLUMN tenant_id;
+
+ALTER TABLE users DROP COLUMN tenant_id;
+
+ALTER TABLE greeters DROP COLUMN tenant_id;
@@ -0,0 +1,23 @@
+-- 多租户：业务数据按 tenant_id 隔离，所有查询都以 tenant_id 为条件
+-- 已有数据归属默认租户，与未启用多租户时请求所属的租户一致
+-- 订单流转记录、webhook 投递记录通过所属的订单、订阅确定租户，不单独保存
+ALTER TABLE greeters ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+ALTER TABLE users ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+ALTER TABLE orders ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+ALTER TABLE webhook_subscriptions ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+ALTER TABLE outbox_events ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+-- 用户名只在租户内唯一
+DROP INDEX idx_users_username ON users;
+
+CREATE UNIQUE INDEX idx_users_tenant_username ON users (tenant_id, username);
+
+CREATE INDEX idx_greeters_tenant_name ON greeters (tenant_id, name);
+
+CREATE INDEX idx_orders_tenant_user_id ON orders (tenant_id, user_id);
+
+CREATE INDEX idx_webhook_subscriptions_tenant_user_id ON webhook_subscriptions (tenant_id, user_id);
@@ -0,0 +1 @@
+ALTER TABLE greeters DROP COLUMN version;
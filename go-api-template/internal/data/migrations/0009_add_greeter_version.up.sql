@@ -0,0 +1,3 @@
+-- 问候记录的版本号，作为 HTTP 响应头 ETag 的取值
+-- 问候记录保存后不再修改，已有数据与新记录一样使用初始版本 1
+ALTER TABLE greeters ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,145 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/wire"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/tenant"
+)
+
+// OrderProviderSet 是 Order 模块数据层的依赖提供者集合
+var OrderProviderSet = wire.NewSet(NewOrderRepo)
+
+// orderRepo 实现 biz.OrderRepo 接口
+// 使用内存 Map 存储，database.driver 为 memory 时生效；
+// 一把锁同时保护订单与审计记录，流转的"检查版本 + 更新 + 记录"在锁内原子完成；
+// 订单 ID 在所有租户间唯一，键带有租户前缀，按 ID 查找不会取到其他租户的订单
+type orderRepo struct {
+	mu          sync.RWMutex
+	orders      map[tenantKey[int64]]*biz.Order
+	transitions map[tenantKey[int64]][]*biz.OrderTransition
+	nextID      int64
+	nextTransID int64
+}
+
+// NewOrderRepo 创建 OrderRepo 实例
+// 根据数据库配置选择 SQL 或内存实现
+func NewOrderRepo(data *Data) biz.OrderRepo {
+	if data.db != nil {
+		return &sqlOrderRepo{data: data}
+	}
+	return &orderRepo{
+		orders:      make(map[tenantKey[int64]]*biz.Order),
+		transitions: make(map[tenantKey[int64]][]*biz.OrderTransition),
+	}
+}
+
+// Create 保存新订单与创建记录
+func (r *orderRepo) Create(ctx context.Context, o *biz.Order, t *biz.OrderTransition) (*biz.Order, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	o.ID = r.nextID
+	stored := *o
+	key := tenantKey[int64]{tenant: tenantID, key: o.ID}
+	r.orders[key] = &stored
+
+	t.OrderID = o.ID
+	r.appendTransition(key, t)
+	return o, nil
+}
+
+// Get 按 ID 获取订单
+func (r *orderRepo) Get(ctx context.Context, id int64) (*biz.Order, error) {
+	key, err := scoped(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.orders[key]
+	if !ok {
+		return nil, fmt.Errorf("order %d: %w", id, biz.ErrNotFound)
+	}
+	clone := *stored
+	return &clone, nil
+}
+
+// ListByUser 在内存中按 req 过滤、排序并分页
+func (r *orderRepo) ListByUser(ctx context.Context, userID int64, req *pagination.Request) (*pagination.Page[*biz.Order], error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []*biz.Order
+	for key, stored := range r.orders {
+		if key.tenant == tenantID && stored.UserID == userID {
+			clone := *stored
+			out = append(out, &clone)
+		}
+	}
+	return pagination.Paginate(out, req, biz.OrderListValue), nil
+}
+
+// Transition 检查版本未变化后更新订单并追加审计记录
+func (r *orderRepo) Transition(ctx context.Context, o *biz.Order, t *biz.OrderTransition) error {
+	key, err := scoped(ctx, o.ID)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.orders[key]
+	if !ok {
+		return fmt.Errorf("order %d: %w", o.ID, biz.ErrNotFound)
+	}
+	if stored.Version != o.Version {
+		return fmt.Errorf("order %d: %w: modified concurrently", o.ID, biz.ErrVersionMismatch)
+	}
+	o.Version++
+	stored.Version = o.Version
+	stored.Status = o.Status
+	stored.UpdatedAt = o.UpdatedAt
+	r.appendTransition(key, t)
+	return nil
+}
+
+// ListTransitions 按时间顺序列出订单的流转记录
+func (r *orderRepo) ListTransitions(ctx context.Context, orderID int64) ([]*biz.OrderTransition, error) {
+	key, err := scoped(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]*biz.OrderTransition, 0, len(r.transitions[key]))
+	for _, stored := range r.transitions[key] {
+		clone := *stored
+		out = append(out, &clone)
+	}
+	return out, nil
+}
+
+// appendTransition 分配 ID 并保存 key 对应订单的审计记录，调用方需持有写锁
+func (r *orderRepo) appendTransition(key tenantKey[int64], t *biz.OrderTransition) {
+	r.nextTransID++
+	t.ID = r.nextTransID
+	stored := *t
+	r.transitions[key] = append(r.transitions[key], &stored)
+}
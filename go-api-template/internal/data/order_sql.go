@@ -0,0 +1,206 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/tenant"
+)
+
+// sqlOrderRepo 基于 database/sql 实现 biz.OrderRepo
+type sqlOrderRepo struct {
+	data *Data
+}
+
+// orderColumns 查询订单时的列顺序，与 scanOrder 保持一致
+const orderColumns = "id, user_id, product, quantity, amount, status, version, created_at, updated_at"
+
+// Create 在同一事务中插入订单与创建记录
+// 调用方已开启事务时加入该事务
+func (r *sqlOrderRepo) Create(ctx context.Context, o *biz.Order, t *biz.OrderTransition) (*biz.Order, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = r.data.InTx(ctx, func(ctx context.Context) error {
+		id, err := r.data.dialect.insertReturningID(ctx, r.data.conn(ctx),
+			"INSERT INTO orders (tenant_id, user_id, product, quantity, amount, status, version, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			tenantID, o.UserID, o.Product, o.Quantity, o.Amount, o.Status.String(), o.Version, o.CreatedAt.UTC(), o.UpdatedAt.UTC())
+		if err != nil {
+			return err
+		}
+		o.ID = id
+		t.OrderID = id
+		return r.insertTransition(ctx, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Get 按 ID 获取订单
+func (r *sqlOrderRepo) Get(ctx context.Context, id int64) (*biz.Order, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o, err := scanOrder(r.data.conn(ctx).QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT "+orderColumns+" FROM orders WHERE id = ? AND tenant_id = ?"), id, tenantID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("order %d: %w", id, biz.ErrNotFound)
+	}
+	return o, err
+}
+
+// orderListColumns OrderListSpec 中的字段对应的列
+var orderListColumns = map[string]string{
+	"id":         "id",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"amount":     "amount",
+	"quantity":   "quantity",
+	"status":     "status",
+	"product":    "product",
+}
+
+// ListByUser 先按过滤条件统计总数，再按游标或偏移查询一页
+func (r *sqlOrderRepo) ListByUser(ctx context.Context, userID int64, req *pagination.Request) (*pagination.Page[*biz.Order], error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	clauses := req.SQL(orderListColumns)
+	db := r.data.conn(ctx)
+
+	var total int64
+	where, args := clauses.FilterWhere("tenant_id = ? AND user_id = ?", tenantID, userID)
+	if err := db.QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT COUNT(*) FROM orders WHERE "+where), args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	where, args = clauses.Where("tenant_id = ? AND user_id = ?", tenantID, userID)
+	rows, err := db.QueryContext(ctx, r.data.dialect.rebind(
+		"SELECT "+orderColumns+" FROM orders WHERE "+where+" ORDER BY "+clauses.OrderBy+" LIMIT ? OFFSET ?"),
+		append(args, clauses.Limit, clauses.Offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*biz.Order
+	for rows.Next() {
+		o, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return pagination.NewPage(out, total, req, biz.OrderListValue), nil
+}
+
+// Transition 以 "WHERE version = 读取时的版本" 条件更新订单，并在同一事务中写入审计记录
+// 条件更新保证两个并发请求基于同一版本流转时只有一个成功
+func (r *sqlOrderRepo) Transition(ctx context.Context, o *biz.Order, t *biz.OrderTransition) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	return r.data.InTx(ctx, func(ctx context.Context) error {
+		result, err := r.data.conn(ctx).ExecContext(ctx, r.data.dialect.rebind(
+			"UPDATE orders SET status = ?, version = version + 1, updated_at = ? WHERE id = ? AND tenant_id = ? AND version = ?"),
+			o.Status.String(), o.UpdatedAt.UTC(), o.ID, tenantID, o.Version)
+		if err != nil {
+			return err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("order %d: %w: modified concurrently", o.ID, biz.ErrVersionMismatch)
+		}
+		if err := r.insertTransition(ctx, t); err != nil {
+			return err
+		}
+		o.Version++
+		return nil
+	})
+}
+
+// ListTransitions 按时间顺序列出订单的流转记录
+// 流转记录没有 tenant_id 列，通过所属订单限定租户
+func (r *sqlOrderRepo) ListTransitions(ctx context.Context, orderID int64) ([]*biz.OrderTransition, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.data.conn(ctx).QueryContext(ctx, r.data.dialect.rebind(
+		"SELECT id, order_id, from_status, to_status, event, actor, note, created_at FROM order_transitions "+
+			"WHERE order_id = ? AND order_id IN (SELECT id FROM orders WHERE tenant_id = ?) ORDER BY id"),
+		orderID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*biz.OrderTransition
+	for rows.Next() {
+		var (
+			t        biz.OrderTransition
+			from, to string
+			event    string
+		)
+		if err := rows.Scan(&t.ID, &t.OrderID, &from, &to, &event, &t.Actor, &t.Note, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		// 创建记录的 from 为 "unknown"，解析失败时保留零值即可
+		t.From, _ = biz.ParseOrderStatus(from)
+		if t.To, err = biz.ParseOrderStatus(to); err != nil {
+			return nil, err
+		}
+		t.Event = biz.OrderEvent(event)
+		out = append(out, &t)
+	}
+	return out, rows.Err()
+}
+
+func (r *sqlOrderRepo) insertTransition(ctx context.Context, t *biz.OrderTransition) error {
+	id, err := r.data.dialect.insertReturningID(ctx, r.data.conn(ctx),
+		"INSERT INTO order_transitions (order_id, from_status, to_status, event, actor, note, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		t.OrderID, t.From.String(), t.To.String(), string(t.Event), t.Actor, t.Note, t.CreatedAt.UTC())
+	if err != nil {
+		return err
+	}
+	t.ID = id
+	return nil
+}
+
+// rowScanner 是 *sql.Row 与 *sql.Rows 的公共方法
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanOrder 按 orderColumns 的顺序读取一行
+func scanOrder(row rowScanner) (*biz.Order, error) {
+	var (
+		o      biz.Order
+		status string
+	)
+	if err := row.Scan(&o.ID, &o.UserID, &o.Product, &o.Quantity, &o.Amount, &status, &o.Version, &o.CreatedAt, &o.UpdatedAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if o.Status, err = biz.ParseOrderStatus(status); err != nil {
+		return nil, fmt.Errorf("order %d: %w", o.ID, err)
+	}
+	return &o, nil
+}
@@ -0,0 +1,185 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/wire"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/pkg/event"
+	"go-api-template/internal/pkg/tenant"
+)
+
+// OutboxProviderSet 是事件发件箱的依赖提供者集合
+// 同一个 Outbox 既供 biz 层写入事件，也作为 event.Relay 的事件来源
+var OutboxProviderSet = wire.NewSet(
+	NewOutbox,
+	wire.Bind(new(biz.EventOutbox), new(Outbox)),
+	wire.Bind(new(event.Source), new(Outbox)),
+)
+
+// outbox_events.status 的取值
+const (
+	outboxPending   = "pending"
+	outboxPublished = "published"
+	outboxFailed    = "failed"
+)
+
+// Outbox 事件发件箱：biz 层在事务中写入，relay 从中领取并投递
+type Outbox interface {
+	biz.EventOutbox
+	event.Source
+}
+
+// NewOutbox 创建 Outbox 实例
+// 根据数据库配置选择 SQL 或内存实现；events.enabled 为 false 时 Relay 不会运行，
+// 写入的事件既不会投递也不会被清理，因此直接丢弃
+func NewOutbox(data *Data) Outbox {
+	var outbox Outbox
+	if data.db != nil {
+		outbox = &sqlOutbox{data: data}
+	} else {
+		outbox = &memoryOutbox{entries: make(map[int64]*outboxEntry)}
+	}
+	if !data.cfg.Events.Enabled {
+		return discardOutbox{outbox}
+	}
+	return outbox
+}
+
+// discardOutbox 未开启事件投递时使用的发件箱，Add 不写入任何事件
+type discardOutbox struct {
+	Outbox
+}
+
+// Add 实现 biz.EventOutbox
+func (discardOutbox) Add(context.Context, ...biz.Event) error {
+	return nil
+}
+
+// encodeEvents 把 ctx 中租户产生的领域事件序列化为待投递的消息
+func encodeEvents(ctx context.Context, events []biz.Event, now time.Time) ([]event.Message, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]event.Message, 0, len(events))
+	for _, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode event %s: %w", e.EventType(), err)
+		}
+		msgs = append(msgs, event.Message{Type: e.EventType(), Tenant: tenantID, Payload: payload, OccurredAt: now})
+	}
+	return msgs, nil
+}
+
+// memoryOutbox 内存发件箱，database.driver 为 memory 时生效
+// 内存存储没有回滚能力，事件在 Add 时即生效
+type memoryOutbox struct {
+	mu      sync.Mutex
+	entries map[int64]*outboxEntry
+	nextID  int64
+}
+
+// outboxEntry 内存发件箱中的一条事件及其投递状态
+type outboxEntry struct {
+	msg         event.Message
+	status      string
+	nextAttempt time.Time
+	lastError   string
+	publishedAt time.Time
+}
+
+// Add 实现 biz.EventOutbox
+func (o *memoryOutbox) Add(ctx context.Context, events ...biz.Event) error {
+	now := time.Now().UTC()
+	msgs, err := encodeEvents(ctx, events, now)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, msg := range msgs {
+		o.nextID++
+		msg.ID = o.nextID
+		o.entries[msg.ID] = &outboxEntry{msg: msg, status: outboxPending, nextAttempt: now}
+	}
+	return nil
+}
+
+// Claim 实现 event.Source，按 ID 顺序领取已到投递时间的事件
+func (o *memoryOutbox) Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]event.Message, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var due []*outboxEntry
+	for _, e := range o.entries {
+		if e.status == outboxPending && !e.nextAttempt.After(now) {
+			due = append(due, e)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].msg.ID < due[j].msg.ID })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	msgs := make([]event.Message, 0, len(due))
+	for _, e := range due {
+		e.nextAttempt = now.Add(lease)
+		msgs = append(msgs, e.msg)
+	}
+	return msgs, nil
+}
+
+// MarkPublished 实现 event.Source
+func (o *memoryOutbox) MarkPublished(ctx context.Context, id int64, at time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if e, ok := o.entries[id]; ok {
+		e.status = outboxPublished
+		e.publishedAt = at
+	}
+	return nil
+}
+
+// MarkFailed 实现 event.Source
+func (o *memoryOutbox) MarkFailed(ctx context.Context, id int64, attempts int, next time.Time, reason string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	e, ok := o.entries[id]
+	if !ok {
+		return nil
+	}
+	e.msg.Attempts = attempts
+	e.lastError = reason
+	if next.IsZero() {
+		e.status = outboxFailed
+		return nil
+	}
+	e.nextAttempt = next
+	return nil
+}
+
+// Purge 实现 event.Source
+func (o *memoryOutbox) Purge(ctx context.Context, before time.Time) (int64, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var n int64
+	for id, e := range o.entries {
+		if e.status == outboxPublished && e.publishedAt.Before(before) {
+			delete(o.entries, id)
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,110 @@
+package data
+
+import (
+	"context"
+	"time"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/pkg/event"
+)
+
+// sqlOutbox 基于 database/sql 实现 Outbox
+type sqlOutbox struct {
+	data *Data
+}
+
+// Add 实现 biz.EventOutbox
+// 通过 conn(ctx) 执行，调用方在事务中时事件与实体一起提交或回滚
+func (o *sqlOutbox) Add(ctx context.Context, events ...biz.Event) error {
+	now := time.Now().UTC()
+	msgs, err := encodeEvents(ctx, events, now)
+	if err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		_, err := o.data.conn(ctx).ExecContext(ctx, o.data.dialect.rebind(
+			"INSERT INTO outbox_events (tenant_id, event_type, payload, status, attempts, next_attempt_at, created_at) VALUES (?, ?, ?, ?, 0, ?, ?)"),
+			msg.Tenant, msg.Type, string(msg.Payload), outboxPending, now, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Claim 实现 event.Source
+// 先查出已到投递时间的事件，再逐条以 "next_attempt_at <= now" 为条件把投递时间推迟 lease；
+// 条件更新只有一个 relay 能成功，多实例部署时同一事件不会被并发投递
+func (o *sqlOutbox) Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]event.Message, error) {
+	now = now.UTC()
+	rows, err := o.data.db.QueryContext(ctx, o.data.dialect.rebind(
+		"SELECT id, tenant_id, event_type, payload, attempts, created_at FROM outbox_events WHERE status = ? AND next_attempt_at <= ? ORDER BY id LIMIT ?"),
+		outboxPending, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	var due []event.Message
+	for rows.Next() {
+		var msg event.Message
+		var payload string
+		if err := rows.Scan(&msg.ID, &msg.Tenant, &msg.Type, &payload, &msg.Attempts, &msg.OccurredAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		msg.Payload = []byte(payload)
+		due = append(due, msg)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	claimed := due[:0]
+	for _, msg := range due {
+		result, err := o.data.db.ExecContext(ctx, o.data.dialect.rebind(
+			"UPDATE outbox_events SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at <= ?"),
+			now.Add(lease), msg.ID, outboxPending, now)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return nil, err
+		} else if n == 1 {
+			claimed = append(claimed, msg)
+		}
+	}
+	return claimed, nil
+}
+
+// MarkPublished 实现 event.Source
+func (o *sqlOutbox) MarkPublished(ctx context.Context, id int64, at time.Time) error {
+	_, err := o.data.db.ExecContext(ctx, o.data.dialect.rebind(
+		"UPDATE outbox_events SET status = ?, published_at = ? WHERE id = ?"),
+		outboxPublished, at.UTC(), id)
+	return err
+}
+
+// MarkFailed 实现 event.Source
+func (o *sqlOutbox) MarkFailed(ctx context.Context, id int64, attempts int, next time.Time, reason string) error {
+	if next.IsZero() {
+		_, err := o.data.db.ExecContext(ctx, o.data.dialect.rebind(
+			"UPDATE outbox_events SET status = ?, attempts = ?, last_error = ? WHERE id = ?"),
+			outboxFailed, attempts, reason, id)
+		return err
+	}
+	_, err := o.data.db.ExecContext(ctx, o.data.dialect.rebind(
+		"UPDATE outbox_events SET attempts = ?, next_attempt_at = ?, last_error = ? WHERE id = ?"),
+		attempts, next.UTC(), reason, id)
+	return err
+}
+
+// Purge 实现 event.Source
+func (o *sqlOutbox) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result, err := o.data.db.ExecContext(ctx, o.data.dialect.rebind(
+		"DELETE FROM outbox_events WHERE status = ? AND published_at < ?"),
+		outboxPublished, before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
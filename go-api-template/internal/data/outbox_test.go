@@ -0,0 +1,39 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"go-api-template/internal/biz"
+)
+
+func TestOutboxEventsDisabled(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		wantClaim int
+	}{
+		{"enabled outbox keeps events", true, 1},
+		{"disabled outbox discards events", false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forEachDriver(t, func(t *testing.T, d *Data) {
+				d.cfg.Events.Enabled = tt.enabled
+				outbox := NewOutbox(d)
+				ctx := tenantContext("acme")
+
+				if err := outbox.Add(ctx, biz.GreetingCreated{GreeterID: 1, Name: "alice"}); err != nil {
+					t.Fatalf("Add: %v", err)
+				}
+				msgs, err := outbox.Claim(ctx, time.Now().Add(time.Second), 10, time.Minute)
+				if err != nil {
+					t.Fatalf("Claim: %v", err)
+				}
+				if len(msgs) != tt.wantClaim {
+					t.Errorf("claimed %d events, want %d", len(msgs), tt.wantClaim)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package data
+
+import (
+	"context"
+
+	"go-api-template/internal/pkg/tenant"
+)
+
+// tenantKey 内存存储中按租户隔离的键，相当于给原有的键加上租户前缀
+// 不同租户的同名记录（如同名用户）互不覆盖，按键查找也不会取到其他租户的记录
+type tenantKey[K comparable] struct {
+	tenant string
+	key    K
+}
+
+// scoped 返回 ctx 中租户下的键，ctx 中没有租户时返回 tenant.ErrMissing
+func scoped[K comparable](ctx context.Context, key K) (tenantKey[K], error) {
+	id, err := tenant.Require(ctx)
+	if err != nil {
+		return tenantKey[K]{}, err
+	}
+	return tenantKey[K]{tenant: id, key: key}, nil
+}
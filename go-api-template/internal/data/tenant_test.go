@@ -0,0 +1,160 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/tenant"
+)
+
+func TestGreeterRepoTenantIsolation(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, d *Data) {
+		repo := NewGreeterRepo(d)
+		acme, globex := tenantContext("acme"), tenantContext("globex")
+
+		saved, err := repo.Save(acme, &biz.Greeter{Name: "alice", Message: "Hello alice", Version: 1, CreatedAt: time.Now()})
+		if err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		// 写入方自己能读到
+		if got, err := repo.Get(acme, saved.ID); err != nil || got.Name != "alice" {
+			t.Fatalf("acme Get = %v, %v; want alice", got, err)
+		}
+
+		if _, err := repo.Get(globex, saved.ID); !errors.Is(err, biz.ErrNotFound) {
+			t.Errorf("globex Get = %v, want ErrNotFound", err)
+		}
+		// GetByName 不存在时返回 nil
+		if got, err := repo.GetByName(globex, "alice"); err != nil || got != nil {
+			t.Errorf("globex GetByName = %+v, %v; want nil", got, err)
+		}
+		if n, err := repo.Count(globex); err != nil || n != 0 {
+			t.Errorf("globex Count = %d, %v; want 0", n, err)
+		}
+		if list, err := repo.ListSince(globex, 0, "", 10); err != nil || len(list) != 0 {
+			t.Errorf("globex ListSince = %d records, %v; want none", len(list), err)
+		}
+		if list, err := repo.ListSince(globex, 0, "alice", 10); err != nil || len(list) != 0 {
+			t.Errorf("globex ListSince(alice) = %d records, %v; want none", len(list), err)
+		}
+		if id, err := repo.LatestID(globex); err != nil || id != 0 {
+			t.Errorf("globex LatestID = %d, %v; want 0", id, err)
+		}
+
+		// 同名记录互不覆盖，访问序号按租户分别计数
+		if _, err := repo.Save(globex, &biz.Greeter{Name: "alice", Message: "Hi alice", Version: 1, CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("globex Save: %v", err)
+		}
+		if got, err := repo.GetByName(acme, "alice"); err != nil || got.Message != "Hello alice" {
+			t.Errorf("acme GetByName = %v, %v; want its own record", got, err)
+		}
+		if n, err := repo.Count(acme); err != nil || n != 1 {
+			t.Errorf("acme Count = %d, %v; want 1", n, err)
+		}
+	})
+}
+
+func TestOrderRepoTenantIsolation(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, d *Data) {
+		repo := NewOrderRepo(d)
+		acme, globex := tenantContext("acme"), tenantContext("globex")
+		now := time.Now().UTC().Truncate(time.Second)
+
+		order, err := repo.Create(acme,
+			&biz.Order{UserID: 7, Product: "book", Quantity: 1, Amount: 1000, Status: biz.OrderStatusPending, Version: 1, CreatedAt: now, UpdatedAt: now},
+			&biz.OrderTransition{To: biz.OrderStatusPending, Event: biz.OrderEventCreate, Actor: "user:7", CreatedAt: now})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		if _, err := repo.Get(globex, order.ID); !errors.Is(err, biz.ErrNotFound) {
+			t.Errorf("globex Get = %v, want ErrNotFound", err)
+		}
+		req := &pagination.Request{Limit: 10, Sort: []pagination.SortField{{Field: "id", Desc: true}}}
+		page, err := repo.ListByUser(globex, 7, req)
+		if err != nil || len(page.Items) != 0 || page.Total != 0 {
+			t.Errorf("globex ListByUser = %+v, %v; want empty", page, err)
+		}
+		if list, err := repo.ListTransitions(globex, order.ID); err != nil || len(list) != 0 {
+			t.Errorf("globex ListTransitions = %d records, %v; want none", len(list), err)
+		}
+		// 以其他租户的身份流转订单视为订单不存在
+		pay := &biz.OrderTransition{From: biz.OrderStatusPending, To: biz.OrderStatusPaid, Event: biz.OrderEventPay, Actor: "user:7", CreatedAt: now}
+		stale := *order
+		if err := repo.Transition(globex, &stale, pay); err == nil {
+			t.Error("globex Transition succeeded, want an error")
+		}
+		if got, err := repo.Get(acme, order.ID); err != nil || got.Status != biz.OrderStatusPending {
+			t.Errorf("acme Get after foreign Transition = %+v, %v; want still pending", got, err)
+		}
+
+		page, err = repo.ListByUser(acme, 7, req)
+		if err != nil || len(page.Items) != 1 || page.Total != 1 {
+			t.Errorf("acme ListByUser = %+v, %v; want its order", page, err)
+		}
+	})
+}
+
+func TestWebhookStoreTenantIsolation(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, d *Data) {
+		store := NewWebhookStore(d)
+		acme, globex := tenantContext("acme"), tenantContext("globex")
+		now := time.Now().UTC().Truncate(time.Second)
+		req := &pagination.Request{Limit: 10, Sort: []pagination.SortField{{Field: "id", Desc: true}}}
+
+		sub, err := store.CreateSubscription(acme, &biz.WebhookSubscription{UserID: 7, URL: "https://hooks.example.com",
+			EventTypes: []string{biz.WebhookAllEvents}, Secret: "s", Active: true, CreatedAt: now, UpdatedAt: now})
+		if err != nil {
+			t.Fatalf("CreateSubscription: %v", err)
+		}
+		delivery := &biz.WebhookDelivery{SubscriptionID: sub.ID, EventID: 1, EventType: "order.created", Payload: []byte(`{}`),
+			Status: biz.WebhookDeliveryPending, NextAttemptAt: now, CreatedAt: now, UpdatedAt: now}
+		if err := store.CreateDeliveries(acme, []*biz.WebhookDelivery{delivery}); err != nil {
+			t.Fatalf("CreateDeliveries: %v", err)
+		}
+
+		tests := []struct {
+			name string
+			op   func(ctx context.Context) error
+		}{
+			{"GetSubscription", func(ctx context.Context) error { _, err := store.GetSubscription(ctx, sub.ID); return err }},
+			{"UpdateSubscription", func(ctx context.Context) error { return store.UpdateSubscription(ctx, sub) }},
+			{"DeleteSubscription", func(ctx context.Context) error { return store.DeleteSubscription(ctx, sub.ID) }},
+			{"CreateDeliveries", func(ctx context.Context) error {
+				return store.CreateDeliveries(ctx, []*biz.WebhookDelivery{{SubscriptionID: sub.ID, EventID: 2, EventType: "order.paid",
+					Payload: []byte(`{}`), Status: biz.WebhookDeliveryPending, NextAttemptAt: now, CreatedAt: now, UpdatedAt: now}})
+			}},
+			{"GetDelivery", func(ctx context.Context) error { _, err := store.GetDelivery(ctx, delivery.ID); return err }},
+			{"ListDeliveries", func(ctx context.Context) error { _, err := store.ListDeliveries(ctx, sub.ID, req); return err }},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if err := tt.op(globex); !errors.Is(err, biz.ErrNotFound) {
+					t.Errorf("globex %s = %v, want ErrNotFound", tt.name, err)
+				}
+				// context 中没有租户时直接拒绝
+				if err := tt.op(context.Background()); !errors.Is(err, tenant.ErrMissing) {
+					t.Errorf("%s without tenant = %v, want ErrMissing", tt.name, err)
+				}
+			})
+		}
+		if list, err := store.ListSubscriptions(globex, 7); err != nil || len(list) != 0 {
+			t.Errorf("globex ListSubscriptions = %d, %v; want none", len(list), err)
+		}
+
+		// 其他租户的操作没有产生影响
+		if page, err := store.ListDeliveries(acme, sub.ID, req); err != nil || len(page.Items) != 1 || page.Total != 1 {
+			t.Errorf("acme ListDeliveries = %+v, %v; want 1", page, err)
+		}
+		if err := store.DeleteSubscription(acme, sub.ID); err != nil {
+			t.Fatalf("acme DeleteSubscription: %v", err)
+		}
+		if _, err := store.ListDeliveries(acme, sub.ID, req); !errors.Is(err, biz.ErrNotFound) {
+			t.Errorf("ListDeliveries after delete = %v, want ErrNotFound", err)
+		}
+	})
+}
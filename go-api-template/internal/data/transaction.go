@@ -0,0 +1,75 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-api-template/internal/biz"
+)
+
+// txKey context 中存放 *sql.Tx 的键
+type txKey struct{}
+
+// memTxKey context 中标记"已持有内存事务锁"的键
+type memTxKey struct{}
+
+// NewTransaction 返回 biz.Transaction 的实现
+func NewTransaction(d *Data) biz.Transaction {
+	return d
+}
+
+// InTx 实现 biz.Transaction
+//
+// SQL 存储：开启数据库事务并放入 ctx，仓储通过 conn(ctx) 取到同一个 *sql.Tx。
+// 内存存储：没有回滚能力，用一把全局锁让事务串行执行，提供与可串行化隔离级别等价的
+// "读取-计算-写入"原子性；fn 返回错误时已执行的写入不会撤销。
+func (d *Data) InTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if d.db == nil {
+		if ctx.Value(memTxKey{}) != nil {
+			return fn(ctx)
+		}
+		d.memTx.Lock()
+		defer d.memTx.Unlock()
+		return fn(context.WithValue(ctx, memTxKey{}, struct{}{}))
+	}
+
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		// panic 时同样回滚，再继续向上抛出，交给 Recovery 处理
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			err = fmt.Errorf("failed to commit transaction: %w", err)
+		}
+	}()
+	return fn(context.WithValue(ctx, txKey{}, tx))
+}
+
+// conn 返回 ctx 中的事务，没有事务时返回连接池
+// SQL 仓储统一通过它执行语句，从而自动加入调用方开启的事务
+func (d *Data) conn(ctx context.Context) execQuerier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return d.db
+}
+
+// inTx 判断 ctx 是否处于 SQL 事务中
+func inTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return ok
+}
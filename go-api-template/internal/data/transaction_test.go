@@ -0,0 +1,137 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go-api-template/internal/biz"
+)
+
+// visitorTemplate 问候消息只包含访问序号，便于从消息中取回序号
+type visitorTemplate struct{}
+
+func (visitorTemplate) GreetingTemplate(string) string { return "{visitor}" }
+
+func TestSayHelloConcurrentVisitors(t *testing.T) {
+	const workers, perWorker = 8, 10
+	forEachDriver(t, func(t *testing.T, d *Data) {
+		uc := biz.NewGreeterUsecase(NewGreeterRepo(d), NewTransaction(d), visitorTemplate{}, NewOutbox(d))
+		ctx := tenantContext("acme")
+
+		var mu sync.Mutex
+		var visitors []int
+		var wg sync.WaitGroup
+		for w := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range perWorker {
+					g, err := uc.SayHello(ctx, "user"+strconv.Itoa(w*perWorker+i))
+					if err != nil {
+						t.Errorf("SayHello: %v", err)
+						return
+					}
+					n, err := strconv.Atoi(g.Message)
+					if err != nil {
+						t.Errorf("message %q is not a visitor number", g.Message)
+						return
+					}
+					mu.Lock()
+					visitors = append(visitors, n)
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		// 事务内的"计数-保存"是原子的：序号既不重复也不跳号
+		slices.Sort(visitors)
+		if len(visitors) != workers*perWorker {
+			t.Fatalf("got %d greetings, want %d", len(visitors), workers*perWorker)
+		}
+		for i, n := range visitors {
+			if n != i+1 {
+				t.Fatalf("visitor numbers = %v, want 1..%d without gaps or duplicates", visitors, workers*perWorker)
+			}
+		}
+	})
+}
+
+func TestInTxRollback(t *testing.T) {
+	errAbort := errors.New("abort")
+	tests := []struct {
+		name   string
+		err    error
+		nested bool
+		// wantSaved 事务结束后保留的记录数，按驱动区分：内存存储没有回滚能力，写入不会撤销
+		wantSaved map[string]int64
+	}{
+		{name: "commit", wantSaved: map[string]int64{"memory": 1, "sqlite": 1}},
+		{name: "error rolls back", err: errAbort, wantSaved: map[string]int64{"memory": 1, "sqlite": 0}},
+		{name: "nested error rolls back the outer transaction", err: errAbort, nested: true,
+			wantSaved: map[string]int64{"memory": 1, "sqlite": 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forEachDriver(t, func(t *testing.T, d *Data) {
+				repo := NewGreeterRepo(d)
+				ctx := tenantContext("acme")
+				fn := func(ctx context.Context) error {
+					if _, err := repo.Save(ctx, &biz.Greeter{Name: "alice", Message: "hi", Version: 1, CreatedAt: time.Now()}); err != nil {
+						t.Fatalf("Save: %v", err)
+					}
+					return tt.err
+				}
+
+				err := d.InTx(ctx, func(ctx context.Context) error {
+					if tt.nested {
+						// 内层 InTx 加入外层事务，而不是开启新事务
+						return d.InTx(ctx, fn)
+					}
+					return fn(ctx)
+				})
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("InTx = %v, want %v", err, tt.err)
+				}
+
+				driver := d.cfg.Database.Driver
+				n, err := repo.Count(ctx)
+				if err != nil {
+					t.Fatalf("Count: %v", err)
+				}
+				if n != tt.wantSaved[driver] {
+					t.Errorf("%s kept %d greetings, want %d", driver, n, tt.wantSaved[driver])
+				}
+			})
+		})
+	}
+}
+
+func TestInTxPanicRollsBack(t *testing.T) {
+	d := newTestData(t, "sqlite")
+	repo := NewGreeterRepo(d)
+	ctx := tenantContext("acme")
+
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Errorf("recovered %v, want the original panic", p)
+			}
+		}()
+		_ = d.InTx(ctx, func(ctx context.Context) error {
+			if _, err := repo.Save(ctx, &biz.Greeter{Name: "alice", Message: "hi", Version: 1, CreatedAt: time.Now()}); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			panic("boom")
+		})
+	}()
+
+	if n, err := repo.Count(ctx); err != nil || n != 0 {
+		t.Errorf("Count = %d, %v; want the panicking transaction rolled back", n, err)
+	}
+}
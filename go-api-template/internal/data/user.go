@@ -0,0 +1,118 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/wire"
+
+	"go-api-template/internal/biz"
+)
+
+// UserProviderSet 是 User 模块数据层的依赖提供者集合
+var UserProviderSet = wire.NewSet(NewUserRepo)
+
+// userRepo 实现 biz.UserRepo 接口
+// 使用内存 Map 存储，database.driver 为 memory 时生效；
+// 用户名只在租户内唯一，键带有租户前缀，不同租户可以注册同名用户
+type userRepo struct {
+	mu         sync.RWMutex
+	users      map[tenantKey[int64]]*biz.User
+	byUsername map[tenantKey[string]]int64
+	nextID     int64
+}
+
+// NewUserRepo 创建 UserRepo 实例
+// 根据数据库配置选择 SQL 或内存实现
+func NewUserRepo(data *Data) biz.UserRepo {
+	if data.db != nil {
+		return &sqlUserRepo{data: data}
+	}
+	return &userRepo{
+		users:      make(map[tenantKey[int64]]*biz.User),
+		byUsername: make(map[tenantKey[string]]int64),
+	}
+}
+
+// Create 保存新用户，用户名已存在时返回 ErrAlreadyExists
+func (r *userRepo) Create(ctx context.Context, u *biz.User) (*biz.User, error) {
+	byUsername, err := scoped(ctx, u.Username)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byUsername[byUsername]; ok {
+		return nil, fmt.Errorf("username %q: %w", u.Username, biz.ErrAlreadyExists)
+	}
+	r.nextID++
+	u.ID = r.nextID
+	// 存储副本，避免调用方后续修改影响已保存的数据
+	stored := *u
+	r.users[tenantKey[int64]{tenant: byUsername.tenant, key: stored.ID}] = &stored
+	r.byUsername[byUsername] = stored.ID
+	return u, nil
+}
+
+// GetByID 按 ID 获取用户
+func (r *userRepo) GetByID(ctx context.Context, id int64) (*biz.User, error) {
+	key, err := scoped(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.users[key]
+	if !ok {
+		return nil, fmt.Errorf("user %d: %w", id, biz.ErrNotFound)
+	}
+	clone := *stored
+	return &clone, nil
+}
+
+// GetByUsername 按登录名获取用户
+func (r *userRepo) GetByUsername(ctx context.Context, username string) (*biz.User, error) {
+	key, err := scoped(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.byUsername[key]
+	if !ok {
+		return nil, fmt.Errorf("username %q: %w", username, biz.ErrNotFound)
+	}
+	clone := *r.users[tenantKey[int64]{tenant: key.tenant, key: id}]
+	return &clone, nil
+}
+
+// Update 在版本一致时更新可修改的字段，用户名与创建时间保持不变
+func (r *userRepo) Update(ctx context.Context, u *biz.User) (*biz.User, error) {
+	key, err := scoped(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[key]
+	if !ok {
+		return nil, fmt.Errorf("user %d: %w", u.ID, biz.ErrNotFound)
+	}
+	if stored.Version != u.Version {
+		return nil, fmt.Errorf("user %d: %w: modified concurrently", u.ID, biz.ErrVersionMismatch)
+	}
+	u.Version++
+	stored.Version = u.Version
+	stored.Email = u.Email
+	stored.Nickname = u.Nickname
+	stored.PasswordHash = u.PasswordHash
+	stored.UpdatedAt = u.UpdatedAt
+
+	clone := *stored
+	return &clone, nil
+}
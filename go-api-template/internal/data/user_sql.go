@@ -0,0 +1,114 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/pkg/tenant"
+)
+
+// sqlUserRepo 基于 database/sql 实现 biz.UserRepo
+// 所有语句都以 tenant_id 为条件，用户名在 (tenant_id, username) 上唯一
+type sqlUserRepo struct {
+	data *Data
+}
+
+// userColumns 查询用户时的列顺序，与 scanUser 保持一致
+const userColumns = "id, username, email, nickname, password_hash, version, created_at, updated_at"
+
+// Create 插入用户并回填自增 ID，用户名冲突由唯一索引检测
+func (r *sqlUserRepo) Create(ctx context.Context, u *biz.User) (*biz.User, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := r.data.dialect.insertReturningID(ctx, r.data.conn(ctx),
+		"INSERT INTO users (tenant_id, username, email, nickname, password_hash, version, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		tenantID, u.Username, u.Email, u.Nickname, u.PasswordHash, u.Version, u.CreatedAt.UTC(), u.UpdatedAt.UTC())
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("username %q: %w", u.Username, biz.ErrAlreadyExists)
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.ID = id
+	return u, nil
+}
+
+// GetByID 按 ID 获取用户
+func (r *sqlUserRepo) GetByID(ctx context.Context, id int64) (*biz.User, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u, err := scanUser(r.data.conn(ctx).QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT "+userColumns+" FROM users WHERE id = ? AND tenant_id = ?"), id, tenantID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("user %d: %w", id, biz.ErrNotFound)
+	}
+	return u, err
+}
+
+// GetByUsername 按登录名获取用户
+func (r *sqlUserRepo) GetByUsername(ctx context.Context, username string) (*biz.User, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u, err := scanUser(r.data.conn(ctx).QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT "+userColumns+" FROM users WHERE tenant_id = ? AND username = ?"), tenantID, username))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("username %q: %w", username, biz.ErrNotFound)
+	}
+	return u, err
+}
+
+// Update 以 "WHERE version = 读取时的版本" 条件更新可修改的字段，用户名与创建时间保持不变
+func (r *sqlUserRepo) Update(ctx context.Context, u *biz.User) (*biz.User, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.data.conn(ctx).ExecContext(ctx, r.data.dialect.rebind(
+		"UPDATE users SET email = ?, nickname = ?, password_hash = ?, version = version + 1, updated_at = ? WHERE id = ? AND tenant_id = ? AND version = ?"),
+		u.Email, u.Nickname, u.PasswordHash, u.UpdatedAt.UTC(), u.ID, tenantID, u.Version)
+	if err != nil {
+		return nil, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, r.missed(ctx, tenantID, u.ID)
+	}
+	u.Version++
+	return u, nil
+}
+
+// missed 条件更新未命中时区分记录已删除与版本已变化
+func (r *sqlUserRepo) missed(ctx context.Context, tenantID string, id int64) error {
+	var exists int
+	err := r.data.conn(ctx).QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT 1 FROM users WHERE id = ? AND tenant_id = ?"), id, tenantID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("user %d: %w", id, biz.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("user %d: %w: modified concurrently", id, biz.ErrVersionMismatch)
+}
+
+// scanUser 按 userColumns 的顺序读取一行
+func scanUser(row *sql.Row) (*biz.User, error) {
+	var u biz.User
+	err := row.Scan(&u.ID, &u.Username, &u.Email, &u.Nickname, &u.PasswordHash, &u.Version, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
@@ -0,0 +1,384 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/wire"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/tenant"
+	"go-api-template/internal/pkg/webhook"
+)
+
+// WebhookProviderSet 是 Webhook 模块数据层的依赖提供者集合
+// 同一个 WebhookStore 既是 biz 层的仓储，也是投递 Dispatcher 的数据来源
+var WebhookProviderSet = wire.NewSet(
+	NewWebhookStore,
+	wire.Bind(new(biz.WebhookRepo), new(WebhookStore)),
+	wire.Bind(new(webhook.Source), new(WebhookStore)),
+)
+
+// WebhookStore Webhook 订阅与投递记录的存储
+type WebhookStore interface {
+	biz.WebhookRepo
+	webhook.Source
+}
+
+// NewWebhookStore 创建 WebhookStore 实例
+// 根据数据库配置选择 SQL 或内存实现
+func NewWebhookStore(data *Data) WebhookStore {
+	if data.db != nil {
+		return &sqlWebhookStore{data: data}
+	}
+	return &webhookStore{
+		subscriptions: make(map[int64]*biz.WebhookSubscription),
+		tenants:       make(map[int64]string),
+		deliveries:    make(map[int64]*biz.WebhookDelivery),
+		attempts:      make(map[int64][]*biz.WebhookAttempt),
+	}
+}
+
+// webhookStore 内存实现，database.driver 为 memory 时生效
+// 一把锁保护全部数据，投递状态的更新与尝试记录的追加在锁内原子完成；
+// 订阅记录所属的租户，投递与尝试记录通过所属订阅限定租户
+type webhookStore struct {
+	mu            sync.RWMutex
+	subscriptions map[int64]*biz.WebhookSubscription
+	// tenants 订阅 ID → 租户
+	tenants       map[int64]string
+	deliveries    map[int64]*biz.WebhookDelivery
+	attempts      map[int64][]*biz.WebhookAttempt
+	nextSubID     int64
+	nextDelID     int64
+	nextAttemptID int64
+}
+
+// CreateSubscription 保存新订阅
+func (r *webhookStore) CreateSubscription(ctx context.Context, s *biz.WebhookSubscription) (*biz.WebhookSubscription, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextSubID++
+	s.ID = r.nextSubID
+	r.subscriptions[s.ID] = cloneSubscription(s)
+	r.tenants[s.ID] = tenantID
+	return s, nil
+}
+
+// GetSubscription 按 ID 获取订阅
+func (r *webhookStore) GetSubscription(ctx context.Context, id int64) (*biz.WebhookSubscription, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.subscriptions[id]
+	if !ok || r.tenants[id] != tenantID {
+		return nil, fmt.Errorf("webhook subscription %d: %w", id, biz.ErrNotFound)
+	}
+	return cloneSubscription(stored), nil
+}
+
+// ListSubscriptions 按 ID 倒序列出用户的订阅
+func (r *webhookStore) ListSubscriptions(ctx context.Context, userID int64) ([]*biz.WebhookSubscription, error) {
+	return r.listSubscriptions(ctx, func(s *biz.WebhookSubscription) bool { return s.UserID == userID })
+}
+
+// ListActiveSubscriptions 列出租户全部启用中的订阅
+func (r *webhookStore) ListActiveSubscriptions(ctx context.Context) ([]*biz.WebhookSubscription, error) {
+	return r.listSubscriptions(ctx, func(s *biz.WebhookSubscription) bool { return s.Active })
+}
+
+// listSubscriptions 按 ID 倒序列出租户中满足条件的订阅
+func (r *webhookStore) listSubscriptions(ctx context.Context, match func(*biz.WebhookSubscription) bool) ([]*biz.WebhookSubscription, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []*biz.WebhookSubscription
+	for id, stored := range r.subscriptions {
+		if r.tenants[id] == tenantID && match(stored) {
+			out = append(out, cloneSubscription(stored))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID > out[j].ID })
+	return out, nil
+}
+
+// owns 判断订阅是否属于 ctx 中的租户，调用方需持有锁
+func (r *webhookStore) owns(ctx context.Context, subscriptionID int64) (bool, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return false, err
+	}
+	_, ok := r.subscriptions[subscriptionID]
+	return ok && r.tenants[subscriptionID] == tenantID, nil
+}
+
+// UpdateSubscription 更新订阅
+func (r *webhookStore) UpdateSubscription(ctx context.Context, s *biz.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if ok, err := r.owns(ctx, s.ID); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("webhook subscription %d: %w", s.ID, biz.ErrNotFound)
+	}
+	r.subscriptions[s.ID] = cloneSubscription(s)
+	return nil
+}
+
+// DeleteSubscription 删除订阅及其投递记录
+func (r *webhookStore) DeleteSubscription(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if ok, err := r.owns(ctx, id); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("webhook subscription %d: %w", id, biz.ErrNotFound)
+	}
+	delete(r.subscriptions, id)
+	delete(r.tenants, id)
+	for deliveryID, d := range r.deliveries {
+		if d.SubscriptionID == id {
+			delete(r.deliveries, deliveryID)
+			delete(r.attempts, deliveryID)
+		}
+	}
+	return nil
+}
+
+// CreateDeliveries 保存一批待投递记录，订阅必须属于 ctx 中的租户
+func (r *webhookStore) CreateDeliveries(ctx context.Context, deliveries []*biz.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range deliveries {
+		if ok, err := r.owns(ctx, d.SubscriptionID); err != nil {
+			return err
+		} else if !ok {
+			return fmt.Errorf("webhook subscription %d: %w", d.SubscriptionID, biz.ErrNotFound)
+		}
+	}
+	for _, d := range deliveries {
+		r.nextDelID++
+		d.ID = r.nextDelID
+		stored := *d
+		r.deliveries[d.ID] = &stored
+	}
+	return nil
+}
+
+// GetDelivery 按 ID 获取投递
+func (r *webhookStore) GetDelivery(ctx context.Context, id int64) (*biz.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, err := r.delivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	clone := *stored
+	return &clone, nil
+}
+
+// delivery 按 ID 查找属于 ctx 中租户的投递，调用方需持有锁
+func (r *webhookStore) delivery(ctx context.Context, id int64) (*biz.WebhookDelivery, error) {
+	stored, ok := r.deliveries[id]
+	if !ok {
+		return nil, fmt.Errorf("webhook delivery %d: %w", id, biz.ErrNotFound)
+	}
+	if ok, err := r.owns(ctx, stored.SubscriptionID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("webhook delivery %d: %w", id, biz.ErrNotFound)
+	}
+	return stored, nil
+}
+
+// ListDeliveries 在内存中按 req 过滤、排序并分页
+func (r *webhookStore) ListDeliveries(ctx context.Context, subscriptionID int64, req *pagination.Request) (*pagination.Page[*biz.WebhookDelivery], error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if ok, err := r.owns(ctx, subscriptionID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("webhook subscription %d: %w", subscriptionID, biz.ErrNotFound)
+	}
+	var out []*biz.WebhookDelivery
+	for _, stored := range r.deliveries {
+		if stored.SubscriptionID == subscriptionID {
+			clone := *stored
+			out = append(out, &clone)
+		}
+	}
+	return pagination.Paginate(out, req, biz.WebhookDeliveryListValue), nil
+}
+
+// ListAttempts 按时间顺序列出投递的尝试记录
+func (r *webhookStore) ListAttempts(ctx context.Context, deliveryID int64) ([]*biz.WebhookAttempt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, err := r.delivery(ctx, deliveryID); errors.Is(err, biz.ErrNotFound) {
+		return []*biz.WebhookAttempt{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	out := make([]*biz.WebhookAttempt, 0, len(r.attempts[deliveryID]))
+	for _, stored := range r.attempts[deliveryID] {
+		clone := *stored
+		out = append(out, &clone)
+	}
+	return out, nil
+}
+
+// ResetDelivery 把已结束的投递重新置为待投递
+func (r *webhookStore) ResetDelivery(ctx context.Context, d *biz.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.delivery(ctx, d.ID)
+	if err != nil {
+		return err
+	}
+	if stored.Status == biz.WebhookDeliveryPending {
+		return fmt.Errorf("webhook delivery %d: %w: delivery is still in progress", d.ID, biz.ErrConflict)
+	}
+	resetDelivery(stored, time.Now())
+	*d = *stored
+	return nil
+}
+
+// DeleteFinishedDeliveries 删除在 before 之前结束的投递及其尝试记录
+// 由定时清理任务调用，按统一的保留期清理所有租户的记录
+func (r *webhookStore) DeleteFinishedDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for id, d := range r.deliveries {
+		if d.Status != biz.WebhookDeliveryPending && d.UpdatedAt.Before(before) {
+			delete(r.deliveries, id)
+			delete(r.attempts, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Claim 实现 webhook.Source，按 ID 顺序领取已到投递时间的记录
+// 投递器为所有租户发送，领取与记录结果不限定租户
+func (r *webhookStore) Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]webhook.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*biz.WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.Status == biz.WebhookDeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	out := make([]webhook.Delivery, 0, len(due))
+	for _, d := range due {
+		sub := r.subscriptions[d.SubscriptionID]
+		d.NextAttemptAt = now.Add(lease)
+		out = append(out, webhook.Delivery{
+			ID:        d.ID,
+			EventID:   d.EventID,
+			EventType: d.EventType,
+			Payload:   d.Payload,
+			Attempts:  d.Attempts,
+			URL:       sub.URL,
+			Secret:    sub.Secret,
+		})
+	}
+	return out, nil
+}
+
+// Record 实现 webhook.Source
+func (r *webhookStore) Record(ctx context.Context, a webhook.Attempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.deliveries[a.DeliveryID]
+	if !ok {
+		// 投递过程中订阅被删除，结果无处记录
+		return nil
+	}
+	applyAttempt(d, a)
+	r.nextAttemptID++
+	r.attempts[d.ID] = append(r.attempts[d.ID], attemptRecord(r.nextAttemptID, a))
+	return nil
+}
+
+// applyAttempt 按一次尝试的结果更新投递状态
+func applyAttempt(d *biz.WebhookDelivery, a webhook.Attempt) {
+	d.Attempts = a.Number
+	d.LastStatusCode = a.StatusCode
+	d.LastError = a.Error
+	d.UpdatedAt = a.At
+	switch {
+	case a.Succeeded:
+		d.Status = biz.WebhookDeliverySucceeded
+		at := a.At
+		d.DeliveredAt = &at
+	case a.NextAttempt.IsZero():
+		d.Status = biz.WebhookDeliveryFailed
+	default:
+		d.NextAttemptAt = a.NextAttempt
+	}
+}
+
+// resetDelivery 把投递恢复为待投递状态，立即可被领取
+func resetDelivery(d *biz.WebhookDelivery, now time.Time) {
+	d.Status = biz.WebhookDeliveryPending
+	d.Attempts = 0
+	d.DeliveredAt = nil
+	d.NextAttemptAt = now
+	d.UpdatedAt = now
+}
+
+// attemptRecord 把尝试结果转换为领域对象
+func attemptRecord(id int64, a webhook.Attempt) *biz.WebhookAttempt {
+	return &biz.WebhookAttempt{
+		ID:         id,
+		DeliveryID: a.DeliveryID,
+		Attempt:    a.Number,
+		StatusCode: a.StatusCode,
+		Error:      a.Error,
+		Duration:   a.Duration,
+		CreatedAt:  a.At,
+	}
+}
+
+// cloneSubscription 复制订阅，避免调用方修改存储中的切片
+func cloneSubscription(s *biz.WebhookSubscription) *biz.WebhookSubscription {
+	clone := *s
+	clone.EventTypes = slices.Clone(s.EventTypes)
+	return &clone
+}
@@ -0,0 +1,430 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/tenant"
+	"go-api-template/internal/pkg/webhook"
+)
+
+// sqlWebhookStore 基于 database/sql 实现 WebhookStore
+// 订阅以 tenant_id 列限定租户，投递与尝试记录通过所属订阅限定租户
+type sqlWebhookStore struct {
+	data *Data
+}
+
+// webhookTenantSubscriptions 投递属于租户的条件，参数为租户
+const webhookTenantSubscriptions = "subscription_id IN (SELECT id FROM webhook_subscriptions WHERE tenant_id = ?)"
+
+// 查询时的列顺序，与对应的 scan 函数保持一致
+const (
+	webhookSubscriptionColumns = "id, user_id, url, event_types, secret, active, created_at, updated_at"
+	webhookDeliveryColumns     = "id, subscription_id, event_id, event_type, payload, status, attempts, last_status_code, last_error, next_attempt_at, created_at, updated_at, delivered_at"
+)
+
+// CreateSubscription 插入订阅并回填自增 ID
+// 事件类型以逗号分隔保存，类型名中不会出现逗号
+func (r *sqlWebhookStore) CreateSubscription(ctx context.Context, s *biz.WebhookSubscription) (*biz.WebhookSubscription, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := r.data.dialect.insertReturningID(ctx, r.data.conn(ctx),
+		"INSERT INTO webhook_subscriptions (tenant_id, user_id, url, event_types, secret, active, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		tenantID, s.UserID, s.URL, strings.Join(s.EventTypes, ","), s.Secret, s.Active, s.CreatedAt.UTC(), s.UpdatedAt.UTC())
+	if err != nil {
+		return nil, err
+	}
+	s.ID = id
+	return s, nil
+}
+
+// GetSubscription 按 ID 获取订阅
+func (r *sqlWebhookStore) GetSubscription(ctx context.Context, id int64) (*biz.WebhookSubscription, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s, err := scanWebhookSubscription(r.data.conn(ctx).QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT "+webhookSubscriptionColumns+" FROM webhook_subscriptions WHERE id = ? AND tenant_id = ?"), id, tenantID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("webhook subscription %d: %w", id, biz.ErrNotFound)
+	}
+	return s, err
+}
+
+// ListSubscriptions 按 ID 倒序列出用户的订阅
+func (r *sqlWebhookStore) ListSubscriptions(ctx context.Context, userID int64) ([]*biz.WebhookSubscription, error) {
+	return r.querySubscriptions(ctx, "user_id = ? ORDER BY id DESC", userID)
+}
+
+// ListActiveSubscriptions 列出租户全部启用中的订阅
+func (r *sqlWebhookStore) ListActiveSubscriptions(ctx context.Context) ([]*biz.WebhookSubscription, error) {
+	return r.querySubscriptions(ctx, "active = ? ORDER BY id", true)
+}
+
+// querySubscriptions 查询并扫描租户中满足条件的全部订阅，cond 为 tenant_id 条件之后的部分
+func (r *sqlWebhookStore) querySubscriptions(ctx context.Context, cond string, args ...any) ([]*biz.WebhookSubscription, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.data.conn(ctx).QueryContext(ctx, r.data.dialect.rebind(
+		"SELECT "+webhookSubscriptionColumns+" FROM webhook_subscriptions WHERE tenant_id = ? AND "+cond),
+		append([]any{tenantID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*biz.WebhookSubscription
+	for rows.Next() {
+		s, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// UpdateSubscription 更新订阅
+func (r *sqlWebhookStore) UpdateSubscription(ctx context.Context, s *biz.WebhookSubscription) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	result, err := r.data.conn(ctx).ExecContext(ctx, r.data.dialect.rebind(
+		"UPDATE webhook_subscriptions SET url = ?, event_types = ?, secret = ?, active = ?, updated_at = ? WHERE id = ? AND tenant_id = ?"),
+		s.URL, strings.Join(s.EventTypes, ","), s.Secret, s.Active, s.UpdatedAt.UTC(), s.ID, tenantID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("webhook subscription %d: %w", s.ID, biz.ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteSubscription 在同一事务中删除订阅、投递与尝试记录
+func (r *sqlWebhookStore) DeleteSubscription(ctx context.Context, id int64) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	return r.data.InTx(ctx, func(ctx context.Context) error {
+		// 先确认订阅属于当前租户，之后的语句只按订阅 ID 删除
+		if err := r.checkSubscription(ctx, id, tenantID); err != nil {
+			return err
+		}
+		stmts := []string{
+			"DELETE FROM webhook_attempts WHERE delivery_id IN (SELECT id FROM webhook_deliveries WHERE subscription_id = ?)",
+			"DELETE FROM webhook_deliveries WHERE subscription_id = ?",
+			"DELETE FROM webhook_subscriptions WHERE id = ?",
+		}
+		for _, stmt := range stmts {
+			if _, err := r.data.conn(ctx).ExecContext(ctx, r.data.dialect.rebind(stmt), id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// checkSubscription 确认订阅存在且属于 tenantID，否则返回包装了 ErrNotFound 的错误
+func (r *sqlWebhookStore) checkSubscription(ctx context.Context, id int64, tenantID string) error {
+	var exists int
+	err := r.data.conn(ctx).QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT 1 FROM webhook_subscriptions WHERE id = ? AND tenant_id = ?"), id, tenantID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("webhook subscription %d: %w", id, biz.ErrNotFound)
+	}
+	return err
+}
+
+// CreateDeliveries 插入一批待投递记录，订阅必须属于 ctx 中的租户
+// 通过 conn(ctx) 执行，调用方在事务中时全部记录一起提交或回滚
+func (r *sqlWebhookStore) CreateDeliveries(ctx context.Context, deliveries []*biz.WebhookDelivery) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	checked := make(map[int64]bool)
+	for _, d := range deliveries {
+		if !checked[d.SubscriptionID] {
+			if err := r.checkSubscription(ctx, d.SubscriptionID, tenantID); err != nil {
+				return err
+			}
+			checked[d.SubscriptionID] = true
+		}
+		id, err := r.data.dialect.insertReturningID(ctx, r.data.conn(ctx),
+			"INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at, updated_at) VALUES (?, ?, ?, ?, ?, 0, ?, ?, ?)",
+			d.SubscriptionID, d.EventID, d.EventType, string(d.Payload), string(d.Status),
+			d.NextAttemptAt.UTC(), d.CreatedAt.UTC(), d.UpdatedAt.UTC())
+		if err != nil {
+			return err
+		}
+		d.ID = id
+	}
+	return nil
+}
+
+// GetDelivery 按 ID 获取属于 ctx 中租户的投递
+func (r *sqlWebhookStore) GetDelivery(ctx context.Context, id int64) (*biz.WebhookDelivery, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.getDelivery(ctx, id, "id = ? AND "+webhookTenantSubscriptions, id, tenantID)
+}
+
+// getDelivery 按条件获取一条投递，不存在时返回包装了 ErrNotFound 的错误
+func (r *sqlWebhookStore) getDelivery(ctx context.Context, id int64, where string, args ...any) (*biz.WebhookDelivery, error) {
+	d, err := scanWebhookDelivery(r.data.conn(ctx).QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries WHERE "+where), args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("webhook delivery %d: %w", id, biz.ErrNotFound)
+	}
+	return d, err
+}
+
+// webhookDeliveryListColumns WebhookDeliveryListSpec 中的字段对应的列
+var webhookDeliveryListColumns = map[string]string{
+	"id":               "id",
+	"created_at":       "created_at",
+	"updated_at":       "updated_at",
+	"status":           "status",
+	"event_type":       "event_type",
+	"last_status_code": "last_status_code",
+}
+
+// ListDeliveries 先按过滤条件统计总数，再按游标或偏移查询一页
+func (r *sqlWebhookStore) ListDeliveries(ctx context.Context, subscriptionID int64, req *pagination.Request) (*pagination.Page[*biz.WebhookDelivery], error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkSubscription(ctx, subscriptionID, tenantID); err != nil {
+		return nil, err
+	}
+	clauses := req.SQL(webhookDeliveryListColumns)
+	db := r.data.conn(ctx)
+
+	var total int64
+	where, args := clauses.FilterWhere("subscription_id = ?", subscriptionID)
+	if err := db.QueryRowContext(ctx, r.data.dialect.rebind(
+		"SELECT COUNT(*) FROM webhook_deliveries WHERE "+where), args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	where, args = clauses.Where("subscription_id = ?", subscriptionID)
+	rows, err := db.QueryContext(ctx, r.data.dialect.rebind(
+		"SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries WHERE "+where+" ORDER BY "+clauses.OrderBy+" LIMIT ? OFFSET ?"),
+		append(args, clauses.Limit, clauses.Offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*biz.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return pagination.NewPage(out, total, req, biz.WebhookDeliveryListValue), nil
+}
+
+// ListAttempts 按时间顺序列出投递的尝试记录
+func (r *sqlWebhookStore) ListAttempts(ctx context.Context, deliveryID int64) ([]*biz.WebhookAttempt, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.data.conn(ctx).QueryContext(ctx, r.data.dialect.rebind(
+		"SELECT id, delivery_id, attempt, status_code, error, duration_ms, created_at FROM webhook_attempts "+
+			"WHERE delivery_id = ? AND delivery_id IN (SELECT id FROM webhook_deliveries WHERE "+webhookTenantSubscriptions+") ORDER BY id"),
+		deliveryID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*biz.WebhookAttempt
+	for rows.Next() {
+		var a biz.WebhookAttempt
+		var durationMS int64
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.Attempt, &a.StatusCode, &a.Error, &durationMS, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		a.Duration = time.Duration(durationMS) * time.Millisecond
+		out = append(out, &a)
+	}
+	return out, rows.Err()
+}
+
+// ResetDelivery 以 "status <> pending" 为条件把投递恢复为待投递
+// 条件更新保证并发的重新投递请求只有一个生效
+func (r *sqlWebhookStore) ResetDelivery(ctx context.Context, d *biz.WebhookDelivery) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	result, err := r.data.conn(ctx).ExecContext(ctx, r.data.dialect.rebind(
+		"UPDATE webhook_deliveries SET status = ?, attempts = 0, delivered_at = NULL, next_attempt_at = ?, updated_at = ? WHERE id = ? AND status <> ? AND "+webhookTenantSubscriptions),
+		string(biz.WebhookDeliveryPending), now.UTC(), now.UTC(), d.ID, string(biz.WebhookDeliveryPending), tenantID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("webhook delivery %d: %w: delivery is still in progress", d.ID, biz.ErrConflict)
+	}
+	resetDelivery(d, now)
+	return nil
+}
+
+// DeleteFinishedDeliveries 删除在 before 之前结束的投递及其尝试记录
+// 由定时清理任务调用，按统一的保留期清理所有租户的记录；
+// 先删尝试记录再删投递，两条语句通过 conn(ctx) 在调用方的事务中执行
+func (r *sqlWebhookStore) DeleteFinishedDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	_, err := r.data.conn(ctx).ExecContext(ctx, r.data.dialect.rebind(
+		"DELETE FROM webhook_attempts WHERE delivery_id IN (SELECT id FROM webhook_deliveries WHERE status <> ? AND updated_at < ?)"),
+		string(biz.WebhookDeliveryPending), before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	result, err := r.data.conn(ctx).ExecContext(ctx, r.data.dialect.rebind(
+		"DELETE FROM webhook_deliveries WHERE status <> ? AND updated_at < ?"),
+		string(biz.WebhookDeliveryPending), before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Claim 实现 webhook.Source
+// 投递器为所有租户发送，领取与记录结果不限定租户；
+// 与 outbox 相同：先查出到期记录，再以 "next_attempt_at <= now" 为条件逐条推迟到租约结束，
+// 条件更新只有一个实例能成功，多实例部署时同一投递不会被并发发送
+func (r *sqlWebhookStore) Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]webhook.Delivery, error) {
+	now = now.UTC()
+	rows, err := r.data.db.QueryContext(ctx, r.data.dialect.rebind(
+		"SELECT d.id, d.event_id, d.event_type, d.payload, d.attempts, s.url, s.secret FROM webhook_deliveries d "+
+			"JOIN webhook_subscriptions s ON s.id = d.subscription_id "+
+			"WHERE d.status = ? AND d.next_attempt_at <= ? ORDER BY d.id LIMIT ?"),
+		string(biz.WebhookDeliveryPending), now, limit)
+	if err != nil {
+		return nil, err
+	}
+	var due []webhook.Delivery
+	for rows.Next() {
+		var d webhook.Delivery
+		var payload string
+		if err := rows.Scan(&d.ID, &d.EventID, &d.EventType, &payload, &d.Attempts, &d.URL, &d.Secret); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		d.Payload = []byte(payload)
+		due = append(due, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	claimed := due[:0]
+	for _, d := range due {
+		result, err := r.data.db.ExecContext(ctx, r.data.dialect.rebind(
+			"UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at <= ?"),
+			now.Add(lease), d.ID, string(biz.WebhookDeliveryPending), now)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return nil, err
+		} else if n == 1 {
+			claimed = append(claimed, d)
+		}
+	}
+	return claimed, nil
+}
+
+// Record 实现 webhook.Source，在同一事务中更新投递状态并追加尝试记录
+func (r *sqlWebhookStore) Record(ctx context.Context, a webhook.Attempt) error {
+	return r.data.InTx(ctx, func(ctx context.Context) error {
+		d, err := r.getDelivery(ctx, a.DeliveryID, "id = ?", a.DeliveryID)
+		if errors.Is(err, biz.ErrNotFound) {
+			// 投递过程中订阅被删除，结果无处记录
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		applyAttempt(d, a)
+
+		_, err = r.data.conn(ctx).ExecContext(ctx, r.data.dialect.rebind(
+			"UPDATE webhook_deliveries SET status = ?, attempts = ?, last_status_code = ?, last_error = ?, next_attempt_at = ?, updated_at = ?, delivered_at = ? WHERE id = ?"),
+			string(d.Status), d.Attempts, d.LastStatusCode, d.LastError, d.NextAttemptAt.UTC(), d.UpdatedAt.UTC(), utcOrNil(d.DeliveredAt), d.ID)
+		if err != nil {
+			return err
+		}
+		_, err = r.data.conn(ctx).ExecContext(ctx, r.data.dialect.rebind(
+			"INSERT INTO webhook_attempts (delivery_id, attempt, status_code, error, duration_ms, created_at) VALUES (?, ?, ?, ?, ?, ?)"),
+			a.DeliveryID, a.Number, a.StatusCode, a.Error, a.Duration.Milliseconds(), a.At.UTC())
+		return err
+	})
+}
+
+// scanWebhookSubscription 按 webhookSubscriptionColumns 的顺序扫描一行订阅
+func scanWebhookSubscription(row rowScanner) (*biz.WebhookSubscription, error) {
+	var s biz.WebhookSubscription
+	var eventTypes string
+	if err := row.Scan(&s.ID, &s.UserID, &s.URL, &eventTypes, &s.Secret, &s.Active, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	s.EventTypes = strings.Split(eventTypes, ",")
+	return &s, nil
+}
+
+// scanWebhookDelivery 按 webhookDeliveryColumns 的顺序扫描一行投递
+func scanWebhookDelivery(row rowScanner) (*biz.WebhookDelivery, error) {
+	var d biz.WebhookDelivery
+	var payload, status string
+	var deliveredAt sql.NullTime
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &status, &d.Attempts,
+		&d.LastStatusCode, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt, &deliveredAt)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = []byte(payload)
+	d.Status = biz.WebhookDeliveryStatus(status)
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return &d, nil
+}
+
+// utcOrNil 把可空时间转换为数据库参数
+func utcOrNil(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
@@ -0,0 +1,223 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/webhook"
+)
+
+// TestWebhookRedelivery 投递失败达到最大次数后，手动重新投递以相同的投递 ID 再次发送
+// 覆盖 biz 用例、存储与 Dispatcher 的完整链路，每次尝试都写入投递日志
+func TestWebhookRedelivery(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, d *Data) {
+		var (
+			accept      atomic.Bool
+			mu          sync.Mutex
+			deliveryIDs []string
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			deliveryIDs = append(deliveryIDs, r.Header.Get(webhook.HeaderDeliveryID))
+			mu.Unlock()
+			if !accept.Load() {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		}))
+		defer srv.Close()
+
+		cfg := &conf.Config{Webhooks: conf.WebhooksConfig{
+			Enabled:              true,
+			PollInterval:         10 * time.Millisecond,
+			MaxAttempts:          2,
+			Backoff:              10 * time.Millisecond,
+			AllowPrivateNetworks: true,
+		}}
+		store := NewWebhookStore(d)
+		guard := webhook.NewGuard(cfg)
+		uc := biz.NewWebhookUsecase(store, NewTransaction(d), guard)
+		dispatcher := webhook.NewDispatcher(cfg, store, guard)
+		dispatcher.Start()
+		defer dispatcher.Stop(context.Background())
+
+		ctx := tenantContext("acme")
+		const userID = 1
+		sub, err := uc.CreateSubscription(ctx, userID, srv.URL, []string{biz.WebhookAllEvents}, "")
+		if err != nil {
+			t.Fatalf("CreateSubscription: %v", err)
+		}
+		err = uc.Dispatch(ctx, biz.WebhookEvent{
+			ID:         42,
+			Type:       biz.GreetingCreated{}.EventType(),
+			Data:       json.RawMessage(`{"id":1}`),
+			OccurredAt: time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("Dispatch: %v", err)
+		}
+		page, err := uc.ListDeliveries(ctx, userID, sub.ID, &pagination.Request{Limit: 10, Sort: []pagination.SortField{{Field: "id", Desc: true}}})
+		if err != nil || len(page.Items) != 1 {
+			t.Fatalf("ListDeliveries = %+v, %v; want 1 delivery", page, err)
+		}
+		deliveryID := page.Items[0].ID
+
+		waitStatus := func(want biz.WebhookDeliveryStatus) []*biz.WebhookAttempt {
+			t.Helper()
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				delivery, attempts, err := uc.GetDelivery(ctx, userID, sub.ID, deliveryID)
+				if err != nil {
+					t.Fatalf("GetDelivery: %v", err)
+				}
+				if delivery.Status == want {
+					return attempts
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("delivery status = %s after %d attempts, want %s", delivery.Status, delivery.Attempts, want)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+
+		attempts := waitStatus(biz.WebhookDeliveryFailed)
+		if len(attempts) != 2 {
+			t.Fatalf("attempts before redelivery = %d, want 2", len(attempts))
+		}
+		for i, a := range attempts {
+			if a.Attempt != i+1 || a.StatusCode != http.StatusBadGateway || a.Error == "" {
+				t.Errorf("attempt %d = {n:%d status:%d err:%q}, want failed 502", i, a.Attempt, a.StatusCode, a.Error)
+			}
+		}
+
+		accept.Store(true)
+		if _, err := uc.Redeliver(ctx, userID, sub.ID, deliveryID); err != nil {
+			t.Fatalf("Redeliver: %v", err)
+		}
+		attempts = waitStatus(biz.WebhookDeliverySucceeded)
+		if len(attempts) != 3 {
+			t.Fatalf("attempts after redelivery = %d, want 3", len(attempts))
+		}
+		// 重新投递的尝试次数从 1 重新计算
+		if last := attempts[2]; last.Attempt != 1 || last.StatusCode != http.StatusOK || last.Error != "" {
+			t.Errorf("redelivery attempt = {n:%d status:%d err:%q}, want first attempt with 200", last.Attempt, last.StatusCode, last.Error)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(deliveryIDs) != 3 {
+			t.Fatalf("receiver got %d requests, want 3", len(deliveryIDs))
+		}
+		for _, id := range deliveryIDs {
+			if id != deliveryIDs[0] {
+				t.Errorf("delivery IDs = %v, want the same ID for every attempt", deliveryIDs)
+				break
+			}
+		}
+	})
+}
+
+// TestWebhookSubscriptionRejectsPrivateURL 订阅地址指向回环、内网或元数据地址时拒绝创建与修改
+func TestWebhookSubscriptionRejectsPrivateURL(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, d *Data) {
+		cfg := &conf.Config{}
+		uc := biz.NewWebhookUsecase(NewWebhookStore(d), NewTransaction(d), webhook.NewGuard(cfg))
+		ctx := tenantContext("acme")
+
+		for _, rawURL := range []string{
+			"http://127.0.0.1:8080/hook",
+			"http://localhost/hook",
+			"http://10.1.2.3/hook",
+			"http://[::1]/hook",
+			"http://169.254.169.254/latest/meta-data/",
+			"http://[fd00:ec2::254]/",
+		} {
+			if _, err := uc.CreateSubscription(ctx, 1, rawURL, []string{biz.WebhookAllEvents}, ""); !errors.Is(err, biz.ErrInvalidArgument) {
+				t.Errorf("CreateSubscription(%s) = %v, want ErrInvalidArgument", rawURL, err)
+			}
+		}
+
+		sub, err := uc.CreateSubscription(ctx, 1, "https://93.184.216.34/hook", []string{biz.WebhookAllEvents}, "")
+		if err != nil {
+			t.Fatalf("CreateSubscription(public): %v", err)
+		}
+		private := "http://192.168.0.10/hook"
+		_, err = uc.UpdateSubscription(ctx, 1, sub.ID, biz.WebhookSubscriptionUpdate{URL: &private})
+		if !errors.Is(err, biz.ErrInvalidArgument) {
+			t.Errorf("UpdateSubscription(%s) = %v, want ErrInvalidArgument", private, err)
+		}
+	})
+}
+
+func TestWebhookListDeliveries(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, d *Data) {
+		store := NewWebhookStore(d)
+		acme := tenantContext("acme")
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		var subs []*biz.WebhookSubscription
+		for range 2 {
+			sub, err := store.CreateSubscription(acme, &biz.WebhookSubscription{UserID: 7, URL: "https://hooks.example.com",
+				EventTypes: []string{biz.WebhookAllEvents}, Secret: "s", Active: true, CreatedAt: start, UpdatedAt: start})
+			if err != nil {
+				t.Fatalf("CreateSubscription: %v", err)
+			}
+			subs = append(subs, sub)
+		}
+		// 第二个订阅的投递不出现在第一个订阅的投递日志中
+		var deliveries []*biz.WebhookDelivery
+		for i, eventType := range []string{"order.created", "order.paid", "order.created", "order.paid", "order.created"} {
+			at := start.Add(time.Duration(i) * time.Hour)
+			deliveries = append(deliveries, &biz.WebhookDelivery{SubscriptionID: subs[0].ID, EventID: int64(i), EventType: eventType,
+				Payload: []byte(`{}`), Status: biz.WebhookDeliveryPending, NextAttemptAt: at, CreatedAt: at, UpdatedAt: at})
+		}
+		deliveries = append(deliveries, &biz.WebhookDelivery{SubscriptionID: subs[1].ID, EventID: 9, EventType: "order.paid",
+			Payload: []byte(`{}`), Status: biz.WebhookDeliveryPending, NextAttemptAt: start, CreatedAt: start, UpdatedAt: start})
+		if err := store.CreateDeliveries(acme, deliveries); err != nil {
+			t.Fatalf("CreateDeliveries: %v", err)
+		}
+		ids := make([]int64, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+
+		tests := []struct {
+			name      string
+			params    pagination.Params
+			wantPages [][]int64
+			wantTotal int64
+		}{
+			{name: "newest first", params: pagination.Params{Limit: 3},
+				wantPages: [][]int64{{ids[4], ids[3], ids[2]}, {ids[1], ids[0]}}, wantTotal: 5},
+			{name: "filter by event type", params: pagination.Params{Limit: 1, Filter: "event_type=order.paid"},
+				wantPages: [][]int64{{ids[3]}, {ids[1]}}, wantTotal: 2},
+			{name: "filter by status and time", params: pagination.Params{Sort: "created_at",
+				Filter: "status=pending|failed,created_at<2024-01-01T02:00:00Z"},
+				wantPages: [][]int64{{ids[0], ids[1]}}, wantTotal: 2},
+			{name: "no match", params: pagination.Params{Filter: "status=failed"},
+				wantPages: [][]int64{{}}, wantTotal: 0},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				pages, total := listPages(t, biz.WebhookDeliveryListSpec, tt.params,
+					func(req *pagination.Request) (*pagination.Page[*biz.WebhookDelivery], error) {
+						return store.ListDeliveries(acme, subs[0].ID, req)
+					},
+					func(d *biz.WebhookDelivery) int64 { return d.ID })
+				if !slices.EqualFunc(pages, tt.wantPages, slices.Equal) || total != tt.wantTotal {
+					t.Errorf("pages = %v (total %d), want %v (total %d)", pages, total, tt.wantPages, tt.wantTotal)
+				}
+			})
+		}
+	})
+}
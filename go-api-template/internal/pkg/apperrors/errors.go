@@ -1,7 +1,12 @@
 package apperrors
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"reflect"
 	"strings"
 
 	"go-api-template/internal/pkg/reason"
@@ -12,8 +17,9 @@ import (
 // FieldError 字段级别的错误信息
 // 用于验证错误时，提供每个字段的具体错误
 type FieldError struct {
-	Field   string `json:"field"`   // 字段名（JSON 格式）
-	Message string `json:"message"` // 错误描述
+	Field   string `json:"field,omitempty"`  // 字段名（JSON 格式），请求体整体不是合法 JSON 时为空
+	Message string `json:"message"`          // 错误描述
+	Offset  int64  `json:"offset,omitempty"` // 出错位置在请求体中的字节偏移，仅 JSON 解析错误提供
 }
 
 // AppError 应用错误类型
@@ -100,16 +106,21 @@ func Forbidden(message string) *AppError {
 	return New(reason.Forbidden, message)
 }
 
+// PayloadTooLarge 创建请求体过大错误
+func PayloadTooLarge(limit int64) *AppError {
+	return New(reason.PayloadTooLarge, fmt.Sprintf("请求体不能超过 %d 字节", limit))
+}
+
 // ==================== 验证错误转换 ====================
 
-// FromValidationError 将 validator 库的错误转换为 AppError
-// 提取每个字段的验证错误，生成友好的错误消息
+// FromValidationError 将请求绑定（JSON 解析、参数验证）返回的错误转换为 AppError
+// 提取每个字段的验证错误，生成友好的错误消息；JSON 解析错误转换为带字段与偏移的 FieldError，
+// 解码器的原始消息包含 Go 类型名等内部细节，只作为 Cause 进入日志
 func FromValidationError(err error) *AppError {
 	// 尝试断言为 validator.ValidationErrors
 	validationErrors, ok := err.(validator.ValidationErrors)
 	if !ok {
-		// 不是验证错误，可能是 JSON 解析错误等
-		return InvalidParams(err.Error())
+		return fromDecodeError(err)
 	}
 
 	// 转换每个字段错误
@@ -124,6 +135,73 @@ func FromValidationError(err error) *AppError {
 	return InvalidParamsWithDetails("请求参数验证失败", details)
 }
 
+// fromDecodeError 转换请求体读取与 JSON 解析阶段的错误
+func fromDecodeError(err error) *AppError {
+	// 解析请求体的代码已经给出了业务错误
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var (
+		tooLarge   *http.MaxBytesError
+		syntaxErr  *json.SyntaxError
+		typeErr    *json.UnmarshalTypeError
+		invalidErr *json.InvalidUnmarshalError
+	)
+	switch {
+	case errors.As(err, &tooLarge):
+		return PayloadTooLarge(tooLarge.Limit)
+	case errors.Is(err, io.EOF):
+		return Wrap(reason.InvalidParams, "请求体不能为空", err)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return Wrap(reason.InvalidParams, "请求体不是合法的 JSON", err).
+			WithDetails([]FieldError{{Message: "JSON 不完整"}})
+	case errors.As(err, &syntaxErr):
+		return Wrap(reason.InvalidParams, "请求体不是合法的 JSON", err).
+			WithDetails([]FieldError{{Message: "JSON 语法错误", Offset: syntaxErr.Offset}})
+	case errors.As(err, &typeErr):
+		return Wrap(reason.InvalidParams, "请求参数类型错误", err).
+			WithDetails([]FieldError{{
+				Field:   typeErr.Field,
+				Message: fmt.Sprintf("应为%s", jsonTypeName(typeErr.Type)),
+				Offset:  typeErr.Offset,
+			}})
+	case errors.As(err, &invalidErr):
+		// 绑定目标不是指针，属于代码错误而非请求错误
+		return Internal("请求解析失败", err)
+	default:
+		return Wrap(reason.InvalidParams, "请求参数格式错误", err)
+	}
+}
+
+// jsonTypeName 返回 Go 类型对应的 JSON 类型描述
+func jsonTypeName(t reflect.Type) string {
+	if t == nil {
+		return "合法的值"
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "字符串"
+	case reflect.Bool:
+		return "布尔值"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "整数（且不超出取值范围）"
+	case reflect.Float32, reflect.Float64:
+		return "数字"
+	case reflect.Slice, reflect.Array:
+		return "数组"
+	case reflect.Map, reflect.Struct:
+		return "对象"
+	default:
+		return "合法的值"
+	}
+}
+
 // toJSONFieldName 将结构体字段名转换为 JSON 字段名（小写驼峰）
 // 简单实现：首字母小写
 func toJSONFieldName(field string) string {
@@ -0,0 +1,18 @@
+package auth
+
+import "context"
+
+// claimsKey context 中存放当前用户身份的键
+// 使用未导出类型，避免与其他包的键冲突
+type claimsKey struct{}
+
+// NewContext 返回携带用户身份的 context
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext 取出当前用户身份，未认证的请求返回 false
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
@@ -0,0 +1,151 @@
+// Package compress 提供 gzip、zstd、brotli 三种内容编码的压缩与解压。
+// 编码器与解码器创建成本较高（zstd、brotli 尤其明显），统一通过 sync.Pool 复用；
+// HTTP 响应压缩与 gRPC 消息压缩使用同一组 Codec。
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// 编码名称，同时用作 HTTP 的 Content-Encoding 与 gRPC 的 grpc-encoding
+const (
+	Gzip   = "gzip"
+	Zstd   = "zstd"
+	Brotli = "br"
+)
+
+// brotliLevel brotli 的压缩级别，默认的 11 对动态响应太慢，4 在压缩率与速度之间较均衡
+const brotliLevel = 4
+
+// encoder 三种编码器共有的方法
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// decoder 三种解码器共有的方法
+type decoder interface {
+	io.Reader
+	Reset(io.Reader) error
+}
+
+// Codec 一种内容编码
+type Codec struct {
+	name     string
+	encoders sync.Pool
+	decoders sync.Pool
+	// newDecoder 创建解码器并以 r 初始化；gzip 在创建时就要读取头部，因此不能先创建再 Reset
+	newDecoder func(r io.Reader) (decoder, error)
+}
+
+var codecs = map[string]*Codec{
+	Gzip: newCodec(Gzip,
+		func() encoder { return gzip.NewWriter(nil) },
+		func(r io.Reader) (decoder, error) { return gzip.NewReader(r) }),
+	Zstd: newCodec(Zstd,
+		func() encoder {
+			// 并发度为 1 时编码在调用方 goroutine 中同步进行，不会为每个编码器常驻后台 goroutine
+			w, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+			return w
+		},
+		func(r io.Reader) (decoder, error) { return zstd.NewReader(r, zstd.WithDecoderConcurrency(1)) }),
+	Brotli: newCodec(Brotli,
+		func() encoder { return brotli.NewWriterLevel(nil, brotliLevel) },
+		func(r io.Reader) (decoder, error) { return brotli.NewReader(r), nil }),
+}
+
+// newCodec 创建 Codec
+func newCodec(name string, newEncoder func() encoder, newDecoder func(io.Reader) (decoder, error)) *Codec {
+	c := &Codec{name: name, newDecoder: newDecoder}
+	c.encoders.New = func() any { return newEncoder() }
+	return c
+}
+
+// Lookup 按名称查找 Codec，不支持的编码返回 nil
+func Lookup(name string) *Codec {
+	return codecs[name]
+}
+
+// Names 返回所有支持的编码名称
+func Names() []string {
+	return []string{Zstd, Brotli, Gzip}
+}
+
+// Name 返回编码名称
+func (c *Codec) Name() string {
+	return c.name
+}
+
+// Writer 从池中取出编码器，压缩后写入 w；Close 写出剩余数据并把编码器放回池中，之后不能再使用
+func (c *Codec) Writer(w io.Writer) *Writer {
+	enc := c.encoders.Get().(encoder)
+	enc.Reset(w)
+	return &Writer{enc: enc, codec: c}
+}
+
+// Reader 从池中取出解码器解压 r；读到 io.EOF 时解码器自动放回池中
+func (c *Codec) Reader(r io.Reader) (io.Reader, error) {
+	if dec, ok := c.decoders.Get().(decoder); ok {
+		if err := dec.Reset(r); err != nil {
+			c.decoders.Put(dec)
+			return nil, err
+		}
+		return &Reader{dec: dec, codec: c}, nil
+	}
+	dec, err := c.newDecoder(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{dec: dec, codec: c}, nil
+}
+
+// Writer 池化的编码器
+type Writer struct {
+	enc   encoder
+	codec *Codec
+}
+
+// Write 压缩并写入数据
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.enc.Write(p)
+}
+
+// Flush 把已缓冲的数据压缩后写出，用于需要立即送达的场景
+func (w *Writer) Flush() error {
+	return w.enc.Flush()
+}
+
+// Close 写出剩余数据并归还编码器
+func (w *Writer) Close() error {
+	err := w.enc.Close()
+	// 解除对目标 Writer 的引用，避免池中的编码器持有已结束请求的响应
+	w.enc.Reset(nil)
+	w.codec.encoders.Put(w.enc)
+	w.enc = nil
+	return err
+}
+
+// Reader 池化的解码器
+type Reader struct {
+	dec   decoder
+	codec *Codec
+}
+
+// Read 读取解压后的数据
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.dec == nil {
+		return 0, io.EOF
+	}
+	n, err := r.dec.Read(p)
+	if err == io.EOF {
+		r.codec.decoders.Put(r.dec)
+		r.dec = nil
+	}
+	return n, err
+}
@@ -0,0 +1,122 @@
+package crash
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// queueSize 等待后台上报的报告数上限，队列满时丢弃新报告（日志中仍有记录）
+const queueSize = 64
+
+// Aggregator 按指纹去重、按时间窗口限流，再在后台交给下游上报
+//   - 同一指纹在 window 内只上报第一次，之后的次数累计到下一次上报的 Occurrences；
+//   - 每个 window 内最多上报 limit 次，超出的同样累计，大面积故障时不会刷爆下游；
+//   - 下游（写文件、发 webhook）在后台 goroutine 中执行，Report 不阻塞请求。
+type Aggregator struct {
+	next   PanicReporter
+	window time.Duration
+	limit  int
+
+	mu sync.Mutex
+	// seen 每个指纹最近一次上报的时间与之后被抑制的次数
+	seen map[string]*aggregate
+	// windowStart、sent 当前限流窗口的起点与已上报次数
+	windowStart time.Time
+	sent        int
+
+	queue  chan *Report
+	done   chan struct{}
+	closed bool
+}
+
+// aggregate 一个指纹的去重状态
+type aggregate struct {
+	reportedAt time.Time
+	suppressed int
+}
+
+// NewAggregator 创建 Aggregator 并启动后台上报
+func NewAggregator(next PanicReporter, window time.Duration, limit int) *Aggregator {
+	a := &Aggregator{
+		next:   next,
+		window: window,
+		limit:  limit,
+		seen:   make(map[string]*aggregate),
+		queue:  make(chan *Report, queueSize),
+		done:   make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// Report 实现 PanicReporter，被去重或限流的报告只计数
+func (a *Aggregator) Report(_ context.Context, r *Report) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed || !a.admit(r) {
+		return nil
+	}
+	select {
+	case a.queue <- r:
+	default:
+		slog.Warn("Panic report queue is full, dropping report", "fingerprint", r.Fingerprint, "request_id", r.RequestID)
+	}
+	return nil
+}
+
+// admit 判断报告是否需要上报，需要时把此前被抑制的次数计入 r.Occurrences，调用方持有 a.mu
+func (a *Aggregator) admit(r *Report) bool {
+	now := r.Time
+	if now.Sub(a.windowStart) >= a.window {
+		a.windowStart, a.sent = now, 0
+		a.sweep(now)
+	}
+	agg, ok := a.seen[r.Fingerprint]
+	if !ok {
+		agg = &aggregate{}
+		a.seen[r.Fingerprint] = agg
+	}
+	if (ok && now.Sub(agg.reportedAt) < a.window) || a.sent >= a.limit {
+		agg.suppressed++
+		return false
+	}
+	r.Occurrences = agg.suppressed + 1
+	agg.reportedAt, agg.suppressed = now, 0
+	a.sent++
+	return true
+}
+
+// sweep 清理已过去重窗口且没有待计入次数的指纹，避免 seen 无限增长
+// 有待计入次数的指纹保留到下次发生，指纹数受代码中可能 panic 的位置限制
+func (a *Aggregator) sweep(now time.Time) {
+	for fp, agg := range a.seen {
+		if agg.suppressed == 0 && now.Sub(agg.reportedAt) >= a.window {
+			delete(a.seen, fp)
+		}
+	}
+}
+
+// run 在后台依次上报
+func (a *Aggregator) run() {
+	defer close(a.done)
+	for r := range a.queue {
+		if err := a.next.Report(context.Background(), r); err != nil {
+			slog.Error("Failed to report panic", "fingerprint", r.Fingerprint, "request_id", r.RequestID, "error", err)
+		}
+	}
+}
+
+// Close 停止接收报告，等待队列中的报告上报完，之后的报告直接忽略
+func (a *Aggregator) Close() {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+	a.closed = true
+	close(a.queue)
+	a.mu.Unlock()
+	<-a.done
+}
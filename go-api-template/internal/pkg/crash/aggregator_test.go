@@ -0,0 +1,118 @@
+package crash
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder 记录收到的报告
+type recorder struct {
+	mu      sync.Mutex
+	reports []*Report
+}
+
+func (r *recorder) Report(_ context.Context, report *Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, report)
+	return nil
+}
+
+// occurrence 一次 panic：发生时间（相对起点）与指纹
+type occurrence struct {
+	at          time.Duration
+	fingerprint string
+}
+
+// reported 一次上报：指纹与累计次数
+type reported struct {
+	fingerprint string
+	occurrences int
+}
+
+func TestAggregator(t *testing.T) {
+	const window = time.Minute
+	tests := []struct {
+		name  string
+		limit int
+		in    []occurrence
+		want  []reported
+	}{
+		{
+			name:  "distinct fingerprints all reported",
+			limit: 10,
+			in:    []occurrence{{0, "a"}, {time.Second, "b"}, {2 * time.Second, "c"}},
+			want:  []reported{{"a", 1}, {"b", 1}, {"c", 1}},
+		},
+		{
+			name:  "duplicates within window suppressed",
+			limit: 10,
+			in:    []occurrence{{0, "a"}, {time.Second, "a"}, {30 * time.Second, "a"}, {59 * time.Second, "a"}},
+			want:  []reported{{"a", 1}},
+		},
+		{
+			// 窗口内被抑制的次数计入窗口过后的下一次上报
+			name:  "suppressed count carried to next report",
+			limit: 10,
+			in:    []occurrence{{0, "a"}, {time.Second, "a"}, {2 * time.Second, "a"}, {window, "a"}, {window + time.Second, "a"}},
+			want:  []reported{{"a", 1}, {"a", 3}},
+		},
+		{
+			// 去重窗口从该指纹上一次上报开始计算，而不是从限流窗口的起点
+			name:  "dedup window per fingerprint",
+			limit: 10,
+			in:    []occurrence{{0, "a"}, {30 * time.Second, "b"}, {window, "b"}, {window + 30*time.Second, "b"}},
+			want:  []reported{{"a", 1}, {"b", 1}, {"b", 2}},
+		},
+		{
+			name:  "limit per window",
+			limit: 2,
+			in:    []occurrence{{0, "a"}, {time.Second, "b"}, {2 * time.Second, "c"}, {3 * time.Second, "c"}, {window, "c"}},
+			want:  []reported{{"a", 1}, {"b", 1}, {"c", 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recorder{}
+			a := NewAggregator(sink, window, tt.limit)
+			start := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+			for _, o := range tt.in {
+				r := &Report{Time: start.Add(o.at), Fingerprint: o.fingerprint, Occurrences: 1}
+				if err := a.Report(context.Background(), r); err != nil {
+					t.Fatalf("Report: %v", err)
+				}
+			}
+			// Close 等待后台上报完成
+			a.Close()
+
+			var got []reported
+			for _, r := range sink.reports {
+				got = append(got, reported{r.Fingerprint, r.Occurrences})
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("reported %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("report %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAggregatorClose(t *testing.T) {
+	sink := &recorder{}
+	a := NewAggregator(sink, time.Minute, 10)
+	a.Close()
+	// 关闭后的报告直接忽略，重复关闭不会阻塞
+	if err := a.Report(context.Background(), &Report{Time: time.Now(), Fingerprint: "a"}); err != nil {
+		t.Fatalf("Report after Close: %v", err)
+	}
+	a.Close()
+	if len(sink.reports) != 0 {
+		t.Errorf("reported %d after Close, want 0", len(sink.reports))
+	}
+}
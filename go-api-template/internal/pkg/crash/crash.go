@@ -0,0 +1,174 @@
+// Package crash 上报请求处理中发生的 panic
+// HTTP 的 Recovery 中间件与 gRPC 的恢复拦截器捕获 panic 后构造 Report，交给 PanicReporter；
+// 具体上报方式（崩溃转储文件、webhook）可以组合，统一经过 Aggregator 按调用栈去重与限流，
+// 同一个 bug 在高并发下反复触发时只产生一份报告。
+package crash
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+
+	"go-api-template/internal/conf"
+)
+
+// ProviderSet panic 上报组件的依赖提供者集合
+var ProviderSet = wire.NewSet(NewReporter)
+
+// 请求的协议
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+// Report 一次 panic 的报告
+type Report struct {
+	Time     time.Time `json:"time"`
+	Protocol string    `json:"protocol"`
+	// RequestID 请求 ID，与日志中的 request_id 对应
+	RequestID string `json:"request_id,omitempty"`
+	// Route HTTP 为 "方法 路由模板"（如 POST /api/v1/orders/:id/pay），gRPC 为方法全名
+	Route string `json:"route"`
+	// Headers 请求头（gRPC 为元数据），认证信息等敏感值已替换为 [REDACTED]
+	Headers map[string][]string `json:"headers,omitempty"`
+	// Panic panic 的值
+	Panic string `json:"panic"`
+	Stack string `json:"stack"`
+	// Fingerprint 由调用栈中的函数名计算，同一位置的 panic 指纹相同，不受参数与 goroutine 编号影响
+	Fingerprint string `json:"fingerprint"`
+	// Occurrences 自上一次上报以来同一指纹发生的次数（含本次），被去重或限流的次数计入这里
+	Occurrences int `json:"occurrences"`
+}
+
+// New 构造报告，计算指纹并脱敏请求头
+func New(protocol, requestID, route string, headers map[string][]string, value any, stack []byte) *Report {
+	return &Report{
+		Time:        time.Now(),
+		Protocol:    protocol,
+		RequestID:   requestID,
+		Route:       route,
+		Headers:     SanitizeHeaders(headers),
+		Panic:       fmt.Sprint(value),
+		Stack:       string(stack),
+		Fingerprint: Fingerprint(stack),
+		Occurrences: 1,
+	}
+}
+
+// PanicReporter 上报 panic
+// 由请求 goroutine 在返回 500 之前调用，实现应尽快返回
+type PanicReporter interface {
+	Report(ctx context.Context, r *Report) error
+}
+
+// Nop 不上报，只依靠日志
+type Nop struct{}
+
+// Report 实现 PanicReporter
+func (Nop) Report(context.Context, *Report) error { return nil }
+
+// Multi 依次交给多个 PanicReporter，返回所有失败
+type Multi []PanicReporter
+
+// Report 实现 PanicReporter
+func (m Multi) Report(ctx context.Context, r *Report) error {
+	var errs []error
+	for _, reporter := range m {
+		if err := reporter.Report(ctx, r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NewReporter 按 panic_report 配置创建上报器
+// 文件写入与 webhook 请求由 Aggregator 在后台完成，不阻塞返回 500；cleanup 等待已接收的报告处理完
+func NewReporter(cfg *conf.Config) (PanicReporter, func(), error) {
+	var sinks Multi
+	for _, name := range cfg.PanicReport.Reporters {
+		switch name {
+		case "file":
+			r, err := NewFileReporter(cfg.PanicReport.File)
+			if err != nil {
+				return nil, nil, err
+			}
+			sinks = append(sinks, r)
+		case "webhook":
+			sinks = append(sinks, NewWebhookReporter(cfg.PanicReport.Webhook))
+		default:
+			return nil, nil, fmt.Errorf("unknown panic_report reporter %q", name)
+		}
+	}
+	if len(sinks) == 0 {
+		return Nop{}, func() {}, nil
+	}
+	a := NewAggregator(sinks, cfg.PanicReport.GetWindow(), cfg.PanicReport.GetMaxPerWindow())
+	return a, a.Close, nil
+}
+
+// Fingerprint 计算调用栈的指纹
+// 只取 debug.Stack 输出中的函数名：参数值与 goroutine 编号每次都不同；
+// 文件与行号也不参与计算，发布新版本后同一处的 panic 仍归为一类
+func Fingerprint(stack []byte) string {
+	h := sha256.New()
+	for line := range strings.SplitSeq(string(stack), "\n") {
+		if line == "" || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "goroutine ") {
+			continue
+		}
+		if i := strings.LastIndexByte(line, '('); i > 0 {
+			line = line[:i]
+		}
+		h.Write([]byte(line))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// redacted 替换敏感请求头的值
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders 值需要脱敏的请求头（小写），另外名称中含有 token、secret、password、signature 的也会脱敏
+var sensitiveHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+	"x-api-key":           true,
+}
+
+// SanitizeHeaders 复制请求头并脱敏其中的认证信息
+// HTTP 请求头与 gRPC 元数据都是 map[string][]string，名称比较不区分大小写
+func SanitizeHeaders(headers map[string][]string) map[string][]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	out := make(map[string][]string, len(headers))
+	for name, values := range headers {
+		if isSensitive(name) {
+			out[name] = []string{redacted}
+			continue
+		}
+		out[name] = append([]string(nil), values...)
+	}
+	return out
+}
+
+// isSensitive 判断请求头的值是否需要脱敏
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	if sensitiveHeaders[name] {
+		return true
+	}
+	for _, word := range []string{"token", "secret", "password", "signature"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package crash
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/internal/conf"
+)
+
+func TestFingerprint(t *testing.T) {
+	stack := func(goroutine, arg, line string) []byte {
+		return []byte("goroutine " + goroutine + " [running]:\n" +
+			"runtime/debug.Stack()\n\t/usr/local/go/src/runtime/debug/stack.go:26 +0x5e\n" +
+			"go-api-template/internal/biz.(*OrderUsecase).Pay(0xc000" + arg + ", {0x1, 0x2})\n" +
+			"\t/app/internal/biz/order.go:" + line + " +0x1f\n")
+	}
+	base := Fingerprint(stack("7", "123", "120"))
+
+	tests := []struct {
+		name  string
+		stack []byte
+		same  bool
+	}{
+		{"different goroutine and arguments", stack("42", "999", "120"), true},
+		// 发布新版本后行号变化，仍归为同一类
+		{"different line", stack("7", "123", "131"), true},
+		{"different function", []byte("goroutine 7 [running]:\nruntime/debug.Stack()\n" +
+			"go-api-template/internal/biz.(*OrderUsecase).Refund(0xc000123)\n"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fingerprint(tt.stack) == base; got != tt.same {
+				t.Errorf("fingerprint equal = %v, want %v", got, tt.same)
+			}
+		})
+	}
+	if len(base) != 16 {
+		t.Errorf("fingerprint %q has length %d, want 16", base, len(base))
+	}
+}
+
+func TestSanitizeHeaders(t *testing.T) {
+	headers := map[string][]string{
+		"Authorization":        {"Bearer abc"},
+		"cookie":               {"session=1"},
+		"X-Api-Key":            {"k"},
+		"X-Refresh-Token":      {"t"},
+		"X-Hub-Signature":      {"sha256=..."},
+		"Content-Type":         {"application/json"},
+		"x-request-id":         {"req-1"},
+		"X-Forwarded-For":      {"10.0.0.1", "10.0.0.2"},
+		"grpc-accept-encoding": {"gzip"},
+	}
+	got := SanitizeHeaders(headers)
+	want := map[string][]string{
+		"Authorization":        {redacted},
+		"cookie":               {redacted},
+		"X-Api-Key":            {redacted},
+		"X-Refresh-Token":      {redacted},
+		"X-Hub-Signature":      {redacted},
+		"Content-Type":         {"application/json"},
+		"x-request-id":         {"req-1"},
+		"X-Forwarded-For":      {"10.0.0.1", "10.0.0.2"},
+		"grpc-accept-encoding": {"gzip"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SanitizeHeaders = %v, want %v", got, want)
+	}
+	// 返回副本，不修改原请求头
+	got["X-Forwarded-For"][0] = "changed"
+	if headers["X-Forwarded-For"][0] != "10.0.0.1" || headers["Authorization"][0] != "Bearer abc" {
+		t.Error("SanitizeHeaders modified its input")
+	}
+	if SanitizeHeaders(nil) != nil {
+		t.Error("SanitizeHeaders(nil) != nil")
+	}
+}
+
+func TestNewReporter(t *testing.T) {
+	tests := []struct {
+		name      string
+		reporters []string
+		wantNop   bool
+		wantErr   bool
+	}{
+		{name: "none", wantNop: true},
+		{name: "file", reporters: []string{"file"}},
+		{name: "file and webhook", reporters: []string{"file", "webhook"}},
+		{name: "unknown", reporters: []string{"sentry"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &conf.Config{PanicReport: conf.PanicReportConfig{
+				Reporters: tt.reporters,
+				File:      conf.PanicFileConfig{Dir: t.TempDir()},
+				Webhook:   conf.PanicWebhookConfig{URL: "http://127.0.0.1:1/panics"},
+			}}
+			r, cleanup, err := NewReporter(cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("NewReporter succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewReporter: %v", err)
+			}
+			defer cleanup()
+			if _, nop := r.(Nop); nop != tt.wantNop {
+				t.Errorf("reporter = %T, want Nop %v", r, tt.wantNop)
+			}
+		})
+	}
+}
+
+func TestFileReporterPrunes(t *testing.T) {
+	dir := t.TempDir()
+	f, err := NewFileReporter(conf.PanicFileConfig{Dir: dir, MaxFiles: 2})
+	if err != nil {
+		t.Fatalf("NewFileReporter: %v", err)
+	}
+	start := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		r := New(ProtocolHTTP, "req", "GET /", map[string][]string{"Authorization": {"Bearer abc"}}, "boom", []byte("main.main()\n"))
+		r.Time = start.Add(time.Duration(i) * time.Second)
+		if err := f.Report(context.Background(), r); err != nil {
+			t.Fatalf("Report: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("dump files = %d, want 2", len(entries))
+	}
+	// 最旧的文件被删除
+	if entries[0].Name() < "20261018T080001" {
+		t.Errorf("oldest remaining dump = %s, want the first one pruned", entries[0].Name())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if want := "Authorization: " + redacted; !strings.Contains(string(data), want) {
+		t.Errorf("dump does not contain %q:\n%s", want, data)
+	}
+	if strings.Contains(string(data), "Bearer abc") {
+		t.Error("dump contains the raw Authorization header")
+	}
+}
+
+func TestMulti(t *testing.T) {
+	failing := reporterFunc(func(context.Context, *Report) error { return errors.New("sink down") })
+	ok := &recorder{}
+	err := Multi{failing, ok}.Report(context.Background(), &Report{Fingerprint: "a"})
+	if err == nil || len(ok.reports) != 1 {
+		t.Errorf("Multi.Report = %v with %d reports, want error and the second sink still called", err, len(ok.reports))
+	}
+}
+
+type reporterFunc func(context.Context, *Report) error
+
+func (f reporterFunc) Report(ctx context.Context, r *Report) error { return f(ctx, r) }
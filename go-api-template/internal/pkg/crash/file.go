@@ -0,0 +1,92 @@
+package crash
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"go-api-template/internal/conf"
+)
+
+// FileReporter 每次 panic 写一个崩溃转储文件
+// 文件名为 <时间>-<指纹>.txt，按名称排序即按时间排序；文件数超过 max_files 时删除最旧的。
+// 转储包含请求头与调用栈，权限为 0600
+type FileReporter struct {
+	mu       sync.Mutex
+	dir      string
+	maxFiles int
+}
+
+// NewFileReporter 创建转储目录
+func NewFileReporter(cfg conf.PanicFileConfig) (*FileReporter, error) {
+	dir := cfg.GetDir()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create crash dump directory: %w", err)
+	}
+	return &FileReporter{dir: dir, maxFiles: cfg.GetMaxFiles()}, nil
+}
+
+// Report 实现 PanicReporter
+func (f *FileReporter) Report(_ context.Context, r *Report) error {
+	name := r.Time.UTC().Format("20060102T150405.000000000Z") + "-" + r.Fingerprint + ".txt"
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := os.WriteFile(filepath.Join(f.dir, name), formatDump(r), 0o600); err != nil {
+		return err
+	}
+	return f.prune()
+}
+
+// prune 删除超出 maxFiles 的最旧文件
+func (f *FileReporter) prune() error {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".txt") {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) <= f.maxFiles {
+		return nil
+	}
+	slices.Sort(names)
+	for _, name := range names[:len(names)-f.maxFiles] {
+		if err := os.Remove(filepath.Join(f.dir, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatDump 转储文件的内容：先是请求信息，再是调用栈，便于直接阅读
+func formatDump(r *Report) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "time:        %s\n", r.Time.Format("2006-01-02T15:04:05.000Z07:00"))
+	fmt.Fprintf(&b, "protocol:    %s\n", r.Protocol)
+	fmt.Fprintf(&b, "route:       %s\n", r.Route)
+	fmt.Fprintf(&b, "request_id:  %s\n", r.RequestID)
+	fmt.Fprintf(&b, "fingerprint: %s\n", r.Fingerprint)
+	fmt.Fprintf(&b, "occurrences: %d\n", r.Occurrences)
+	fmt.Fprintf(&b, "panic:       %s\n", r.Panic)
+	b.WriteString("\nheaders:\n")
+	names := make([]string, 0, len(r.Headers))
+	for name := range r.Headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %s: %s\n", name, strings.Join(r.Headers[name], ", "))
+	}
+	b.WriteString("\n")
+	b.WriteString(r.Stack)
+	return b.Bytes()
+}
@@ -0,0 +1,53 @@
+package crash
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go-api-template/internal/conf"
+)
+
+// WebhookReporter 以 HTTP POST 把报告发送到固定地址（如告警系统的接收端）
+// 请求体为 JSON 编码的 Report，2xx 响应视为成功；失败只记录日志，不重试
+type WebhookReporter struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookReporter 创建 webhook 上报器
+func NewWebhookReporter(cfg conf.PanicWebhookConfig) *WebhookReporter {
+	return &WebhookReporter{
+		url:    cfg.URL,
+		client: &http.Client{Timeout: cfg.GetTimeout()},
+	}
+}
+
+// Report 实现 PanicReporter
+func (w *WebhookReporter) Report(ctx context.Context, r *Report) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// 读完响应体以便复用连接
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("panic webhook %s responded %s", w.url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Handler 处理一条事件，返回错误时该事件会被整体重新投递
+// 同一事件可能被投递多次，Handler 需要是幂等的
+type Handler func(ctx context.Context, msg Message) error
+
+// AllEvents 订阅全部事件类型
+const AllEvents = "*"
+
+// Bus 进程内事件总线
+// 作为 Publisher 时同步调用订阅者：全部订阅者成功才算投递成功
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewBus 创建事件总线
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe 订阅指定类型的事件，eventType 为 AllEvents 时接收全部事件
+func (b *Bus) Subscribe(eventType string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], h)
+}
+
+// Publish 实现 Publisher，依次调用订阅者
+// 某个订阅者失败不影响其他订阅者执行，但整条事件会被重试，已成功的订阅者会再次收到
+func (b *Bus) Publish(ctx context.Context, msg Message) error {
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.handlers[msg.Type])+len(b.handlers[AllEvents]))
+	handlers = append(handlers, b.handlers[msg.Type]...)
+	handlers = append(handlers, b.handlers[AllEvents]...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		if err := h(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("event %d (%s): %w", msg.ID, msg.Type, errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Package event 把 outbox 中的领域事件投递给下游
+// 业务代码只负责在事务中把事件写入 outbox（见 biz.EventOutbox），
+// Relay 在后台轮询 outbox，通过可替换的 Publisher 投递，失败时按指数退避重试。
+// 投递语义为至少一次：发布成功但标记完成之前进程退出时，事件会被再次投递，下游应按 Message.ID 去重。
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/wire"
+
+	"go-api-template/internal/conf"
+)
+
+// ProviderSet 事件投递组件的依赖提供者集合
+var ProviderSet = wire.NewSet(NewBus, NewPublisher, NewRelay)
+
+// Message 一条待投递的事件
+type Message struct {
+	// ID outbox 中的自增 ID，全局唯一，下游据此去重
+	ID int64 `json:"id"`
+	// Type 事件类型，如 greeter.greeting_created
+	Type string `json:"type"`
+	// Tenant 产生事件的租户，下游处理事件时应在该租户内读写数据
+	Tenant string `json:"tenant"`
+	// Payload 事件内容（JSON）
+	Payload json.RawMessage `json:"payload"`
+	// OccurredAt 事件发生时间
+	OccurredAt time.Time `json:"occurred_at"`
+	// Attempts 此前已投递失败的次数
+	Attempts int `json:"-"`
+}
+
+// Publisher 把事件发送给下游
+// 返回错误表示投递失败，Relay 会稍后重试；实现不需要自己重试
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// Source 待投递事件的来源，由 data 层的 outbox 实现
+type Source interface {
+	// Claim 取出最多 limit 条已到投递时间的事件，并在 lease 时长内不再交给其他 Claim 调用，
+	// 多个实例同时运行 Relay 时同一事件不会被并发投递
+	Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]Message, error)
+	// MarkPublished 标记事件已投递
+	MarkPublished(ctx context.Context, id int64, at time.Time) error
+	// MarkFailed 记录一次投递失败，next 为下次投递时间；next 为零值表示放弃投递
+	MarkFailed(ctx context.Context, id int64, attempts int, next time.Time, reason string) error
+	// Purge 删除 before 之前已投递的事件，返回删除数量
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// NewPublisher 按 events.publisher 配置创建发布者
+// bus 方式使用注入的进程内总线，其他模块通过同一个 Bus 订阅事件
+func NewPublisher(cfg *conf.Config, bus *Bus) (Publisher, func(), error) {
+	switch cfg.Events.Publisher {
+	case "", "bus":
+		return bus, func() {}, nil
+	case "webhook":
+		return NewWebhookPublisher(cfg.Events.Webhook), func() {}, nil
+	case "file":
+		p, err := NewFilePublisher(cfg.Events.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		return p, func() { _ = p.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown events.publisher %q", cfg.Events.Publisher)
+	}
+}
@@ -0,0 +1,73 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go-api-template/internal/conf"
+)
+
+// FilePublisher 以 JSON Lines 格式把事件追加写入本地文件
+// 每行带有 NATS 风格的 subject（<前缀>.<事件类型>，以 "." 分层），
+// 本地开发时可以代替消息队列，日后切换到 NATS 等消息系统时 subject 与消息体保持不变
+type FilePublisher struct {
+	mu     sync.Mutex
+	file   *os.File
+	prefix string
+}
+
+// fileRecord 文件中每一行的格式
+type fileRecord struct {
+	Subject    string          `json:"subject"`
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	Tenant     string          `json:"tenant"`
+	Data       json.RawMessage `json:"data"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// NewFilePublisher 打开（必要时创建）输出文件
+func NewFilePublisher(cfg conf.EventFileConfig) (*FilePublisher, error) {
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create event file directory: %w", err)
+	}
+	f, err := os.OpenFile(cfg.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event file: %w", err)
+	}
+	return &FilePublisher{file: f, prefix: cfg.GetSubjectPrefix()}, nil
+}
+
+// Publish 实现 Publisher，写入并刷盘后才返回成功
+func (p *FilePublisher) Publish(_ context.Context, msg Message) error {
+	line, err := json.Marshal(fileRecord{
+		Subject:    p.prefix + "." + msg.Type,
+		ID:         msg.ID,
+		Type:       msg.Type,
+		Tenant:     msg.Tenant,
+		Data:       msg.Payload,
+		OccurredAt: msg.OccurredAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return p.file.Sync()
+}
+
+// Close 关闭输出文件
+func (p *FilePublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.file.Close()
+}
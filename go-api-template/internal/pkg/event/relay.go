@@ -0,0 +1,183 @@
+package event
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"go-api-template/internal/conf"
+)
+
+const (
+	// claimLease 领取事件后对其他 Relay 隐藏的时长，需大于一批事件的投递耗时；
+	// 超过后未完成的事件会被重新领取，这只会造成重复投递，不会丢失事件
+	claimLease = time.Minute
+	// purgeInterval 清理已投递事件的间隔
+	purgeInterval = time.Hour
+	// maxFailureReason 记录的失败原因最大长度
+	maxFailureReason = 500
+)
+
+// Relay 在后台轮询 outbox 并投递事件
+// 由应用生命周期管理：serve 启动时 Start，优雅关闭时 Stop
+type Relay struct {
+	source    Source
+	publisher Publisher
+	cfg       conf.EventsConfig
+
+	stop      chan struct{}
+	done      chan struct{}
+	lastPurge time.Time
+}
+
+// NewRelay 创建 Relay
+func NewRelay(cfg *conf.Config, source Source, publisher Publisher) *Relay {
+	return &Relay{
+		source:    source,
+		publisher: publisher,
+		cfg:       cfg.Events,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start 启动后台轮询，events.enabled 为 false 时什么也不做
+func (r *Relay) Start() {
+	if !r.cfg.Enabled {
+		close(r.done)
+		return
+	}
+	slog.Info("Event relay started", "publisher", r.cfg.Publisher, "poll_interval", r.cfg.GetPollInterval())
+	go r.run()
+}
+
+// Stop 停止轮询，等待正在投递的一批事件完成（或直到 ctx 超时）
+// 未投递的事件留在 outbox 中，下次启动后继续投递
+func (r *Relay) Stop(ctx context.Context) error {
+	select {
+	case <-r.stop:
+	default:
+		close(r.stop)
+	}
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run 轮询循环：一批事件已满时立即处理下一批，否则等待一个轮询间隔
+func (r *Relay) run() {
+	defer close(r.done)
+
+	// 投递过程中收到 Stop 时取消正在进行的发布，事件稍后会被重新投递
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-r.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(r.cfg.GetPollInterval())
+	defer ticker.Stop()
+	for {
+		n := r.relayBatch(ctx)
+		r.purge(ctx)
+		if n >= r.cfg.GetBatchSize() {
+			continue
+		}
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relayBatch 领取并投递一批事件，返回领取到的数量
+func (r *Relay) relayBatch(ctx context.Context) int {
+	msgs, err := r.source.Claim(ctx, time.Now(), r.cfg.GetBatchSize(), claimLease)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("Failed to claim outbox events", "error", err)
+		}
+		return 0
+	}
+
+	for _, msg := range msgs {
+		if ctx.Err() != nil {
+			break
+		}
+		r.deliver(ctx, msg)
+	}
+	return len(msgs)
+}
+
+// deliver 投递一条事件并记录结果
+// 记录结果使用独立的 context：即使正在关闭，也要尽量把"已投递"写回，减少重复投递
+func (r *Relay) deliver(ctx context.Context, msg Message) {
+	pubErr := r.publisher.Publish(ctx, msg)
+	recordCtx := context.WithoutCancel(ctx)
+	now := time.Now()
+
+	if pubErr == nil {
+		if err := r.source.MarkPublished(recordCtx, msg.ID, now); err != nil {
+			slog.Error("Failed to mark event published", "id", msg.ID, "error", err)
+		}
+		return
+	}
+
+	attempts := msg.Attempts + 1
+	var next time.Time
+	if attempts < r.cfg.GetMaxAttempts() {
+		next = now.Add(r.backoff(attempts))
+		slog.Warn("Event delivery failed, will retry",
+			"id", msg.ID, "type", msg.Type, "attempts", attempts, "retry_at", next, "error", pubErr)
+	} else {
+		slog.Error("Event delivery failed, giving up",
+			"id", msg.ID, "type", msg.Type, "attempts", attempts, "error", pubErr)
+	}
+	reason := pubErr.Error()
+	if len(reason) > maxFailureReason {
+		reason = reason[:maxFailureReason]
+	}
+	if err := r.source.MarkFailed(recordCtx, msg.ID, attempts, next, reason); err != nil {
+		slog.Error("Failed to record event failure", "id", msg.ID, "error", err)
+	}
+}
+
+// backoff 第 attempts 次失败后的等待时间：指数增长、有上限，并加入 ±20% 的抖动，
+// 避免下游恢复时大量事件在同一时刻重试
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := r.cfg.GetBackoff()
+	for i := 1; i < attempts && d < r.cfg.GetMaxBackoff(); i++ {
+		d *= 2
+	}
+	d = min(d, r.cfg.GetMaxBackoff())
+	jitter := 0.8 + 0.4*rand.Float64()
+	return time.Duration(float64(d) * jitter)
+}
+
+// purge 定期清理超过保留时间的已投递事件
+func (r *Relay) purge(ctx context.Context) {
+	now := time.Now()
+	if now.Sub(r.lastPurge) < purgeInterval {
+		return
+	}
+	r.lastPurge = now
+	n, err := r.source.Purge(ctx, now.Add(-r.cfg.GetRetention()))
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("Failed to purge outbox", "error", err)
+		}
+		return
+	}
+	if n > 0 {
+		slog.Info("Outbox purged", "deleted", n)
+	}
+}
@@ -0,0 +1,229 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-api-template/internal/conf"
+)
+
+// failure 一次 MarkFailed 调用
+type failure struct {
+	id       int64
+	attempts int
+	next     time.Time
+	reason   string
+}
+
+// fakeSource 内存中的事件来源，记录 Relay 的调用
+type fakeSource struct {
+	mu        sync.Mutex
+	pending   []Message
+	published []int64
+	failures  []failure
+	purges    []time.Time
+}
+
+func (s *fakeSource) Claim(_ context.Context, _ time.Time, limit int, _ time.Duration) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := min(limit, len(s.pending))
+	msgs := s.pending[:n]
+	s.pending = s.pending[n:]
+	return msgs, nil
+}
+
+func (s *fakeSource) MarkPublished(_ context.Context, id int64, _ time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.published = append(s.published, id)
+	return nil
+}
+
+func (s *fakeSource) MarkFailed(_ context.Context, id int64, attempts int, next time.Time, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{id, attempts, next, reason})
+	return nil
+}
+
+func (s *fakeSource) Purge(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purges = append(s.purges, before)
+	return 0, nil
+}
+
+func (s *fakeSource) publishedIDs() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int64(nil), s.published...)
+}
+
+// publisherFunc 把函数适配为 Publisher
+type publisherFunc func(ctx context.Context, msg Message) error
+
+func (f publisherFunc) Publish(ctx context.Context, msg Message) error { return f(ctx, msg) }
+
+// newTestRelay 使用 cfg 创建 Relay，测试结束时停止
+func newTestRelay(t *testing.T, cfg conf.EventsConfig, source Source, publisher Publisher) *Relay {
+	t.Helper()
+	r := NewRelay(&conf.Config{Events: cfg}, source, publisher)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := r.Stop(ctx); err != nil {
+			t.Errorf("Stop: %v", err)
+		}
+	})
+	return r
+}
+
+// waitFor 等待 cond 成立
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRelayDelivers(t *testing.T) {
+	source := &fakeSource{pending: []Message{{ID: 1, Type: "a"}, {ID: 2, Type: "b"}, {ID: 3, Type: "a"}}}
+	var mu sync.Mutex
+	var delivered []int64
+	publisher := publisherFunc(func(_ context.Context, msg Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		delivered = append(delivered, msg.ID)
+		return nil
+	})
+	// 每批 2 条：第一批已满时立即领取下一批，不必等待轮询间隔
+	r := newTestRelay(t, conf.EventsConfig{Enabled: true, BatchSize: 2, PollInterval: time.Hour}, source, publisher)
+	r.Start()
+
+	waitFor(t, "all events published", func() bool { return len(source.publishedIDs()) == 3 })
+	mu.Lock()
+	defer mu.Unlock()
+	for i, id := range []int64{1, 2, 3} {
+		if delivered[i] != id {
+			t.Errorf("delivered = %v, want [1 2 3] in order", delivered)
+			break
+		}
+	}
+}
+
+func TestRelayDisabled(t *testing.T) {
+	source := &fakeSource{pending: []Message{{ID: 1}}}
+	r := newTestRelay(t, conf.EventsConfig{}, source, publisherFunc(func(context.Context, Message) error {
+		t.Error("disabled relay published an event")
+		return nil
+	}))
+	r.Start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := r.Stop(ctx); err != nil {
+		t.Fatalf("Stop = %v, want immediate return", err)
+	}
+	if len(source.pending) != 1 || len(source.purges) != 0 {
+		t.Errorf("disabled relay touched the source: pending %d, purges %d", len(source.pending), len(source.purges))
+	}
+}
+
+func TestRelayDeliverFailure(t *testing.T) {
+	cfg := conf.EventsConfig{MaxAttempts: 3, Backoff: time.Second, MaxBackoff: time.Minute}
+	tests := []struct {
+		name       string
+		attempts   int
+		err        error
+		wantNext   bool
+		wantReason string
+	}{
+		{name: "first failure is retried", attempts: 0, err: errors.New("boom"), wantNext: true, wantReason: "boom"},
+		{name: "last attempt gives up", attempts: 2, err: errors.New("boom"), wantNext: false, wantReason: "boom"},
+		{
+			name: "long reason is truncated", attempts: 0, err: errors.New(strings.Repeat("x", 2*maxFailureReason)),
+			wantNext: true, wantReason: strings.Repeat("x", maxFailureReason),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &fakeSource{}
+			r := NewRelay(&conf.Config{Events: cfg}, source, publisherFunc(func(context.Context, Message) error {
+				return tt.err
+			}))
+
+			before := time.Now()
+			r.deliver(context.Background(), Message{ID: 7, Attempts: tt.attempts})
+
+			if len(source.published) != 0 || len(source.failures) != 1 {
+				t.Fatalf("published %v, failures %v; want one failure", source.published, source.failures)
+			}
+			f := source.failures[0]
+			if f.id != 7 || f.attempts != tt.attempts+1 || f.reason != tt.wantReason {
+				t.Errorf("failure = {id %d, attempts %d, reason %.20q}, want {7, %d, %.20q}",
+					f.id, f.attempts, f.reason, tt.attempts+1, tt.wantReason)
+			}
+			if got := !f.next.IsZero(); got != tt.wantNext {
+				t.Fatalf("retry scheduled = %v, want %v", got, tt.wantNext)
+			}
+			if tt.wantNext && (f.next.Before(before.Add(800*time.Millisecond)) || f.next.After(time.Now().Add(1200*time.Millisecond))) {
+				t.Errorf("next attempt in %v, want the first backoff of 1s ±20%%", f.next.Sub(before))
+			}
+		})
+	}
+}
+
+func TestRelayBackoff(t *testing.T) {
+	r := NewRelay(&conf.Config{Events: conf.EventsConfig{Backoff: time.Second, MaxBackoff: 10 * time.Second}}, nil, nil)
+	tests := []struct {
+		attempts int
+		base     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{20, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			got := r.backoff(tt.attempts)
+			if lo, hi := tt.base*8/10, tt.base*12/10; got < lo || got > hi {
+				t.Fatalf("backoff(%d) = %v, want within [%v, %v]", tt.attempts, got, lo, hi)
+			}
+		}
+	}
+}
+
+func TestRelayPurge(t *testing.T) {
+	source := &fakeSource{}
+	r := NewRelay(&conf.Config{Events: conf.EventsConfig{Retention: time.Hour}}, source, nil)
+
+	start := time.Now()
+	r.purge(context.Background())
+	// 距上次清理不足 purgeInterval，不再清理
+	r.purge(context.Background())
+
+	if len(source.purges) != 1 {
+		t.Fatalf("purges = %d, want 1", len(source.purges))
+	}
+	if before := source.purges[0]; before.Before(start.Add(-time.Hour)) || before.After(time.Now().Add(-time.Hour)) {
+		t.Errorf("purged before %v, want now minus the 1h retention", before)
+	}
+
+	r.lastPurge = time.Now().Add(-purgeInterval)
+	r.purge(context.Background())
+	if len(source.purges) != 2 {
+		t.Errorf("purges = %d after purgeInterval, want 2", len(source.purges))
+	}
+}
@@ -0,0 +1,56 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go-api-template/internal/conf"
+)
+
+// WebhookPublisher 以 HTTP POST 把事件发送到固定地址
+// 请求体为 JSON 编码的 Message，2xx 响应视为投递成功
+type WebhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookPublisher 创建 webhook 发布者
+func NewWebhookPublisher(cfg conf.EventWebhookConfig) *WebhookPublisher {
+	return &WebhookPublisher{
+		url:    cfg.URL,
+		client: &http.Client{Timeout: cfg.GetTimeout()},
+	}
+}
+
+// Publish 实现 Publisher
+func (p *WebhookPublisher) Publish(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatInt(msg.ID, 10))
+	req.Header.Set("X-Event-Type", msg.Type)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// 读完响应体以便复用连接
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded %s", p.url, resp.Status)
+	}
+	return nil
+}
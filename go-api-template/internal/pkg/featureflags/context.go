@@ -0,0 +1,27 @@
+package featureflags
+
+import "context"
+
+// managerKey context 中存放 Manager 的键
+type managerKey struct{}
+
+// NewContext 返回携带 Manager 的 context
+func NewContext(ctx context.Context, m *Manager) context.Context {
+	return context.WithValue(ctx, managerKey{}, m)
+}
+
+// FromContext 取出 context 中的 Manager
+func FromContext(ctx context.Context) (*Manager, bool) {
+	m, ok := ctx.Value(managerKey{}).(*Manager)
+	return m, ok && m != nil
+}
+
+// Enabled 判断开关对当前请求是否开启，供 biz 层使用
+// context 中没有 Manager 时返回 false，新行为只在接入了开关的请求中生效
+func Enabled(ctx context.Context, name string) bool {
+	m, ok := FromContext(ctx)
+	if !ok {
+		return false
+	}
+	return m.Check(ctx, name).Enabled
+}
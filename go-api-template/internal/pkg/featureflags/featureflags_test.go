@@ -0,0 +1,157 @@
+package featureflags
+
+import (
+	"fmt"
+	"testing"
+
+	"go-api-template/internal/conf"
+)
+
+func percentage(p float64) *float64 { return &p }
+
+func TestEvaluate(t *testing.T) {
+	base := conf.FeatureFlagConfig{Enabled: true}
+	tests := []struct {
+		name        string
+		def         conf.FeatureFlagConfig
+		subject     Subject
+		wantEnabled bool
+		wantReason  Reason
+	}{
+		{name: "disabled", def: conf.FeatureFlagConfig{Users: []string{"alice"}}, subject: Subject{User: "alice"}, wantReason: ReasonDisabled},
+		{name: "enabled", def: base, subject: Subject{Tenant: "acme"}, wantEnabled: true, wantReason: ReasonEnabled},
+		{
+			name:       "environment not listed",
+			def:        conf.FeatureFlagConfig{Enabled: true, Environments: []string{"staging"}, Users: []string{"alice"}},
+			subject:    Subject{Environment: "production", User: "alice"},
+			wantReason: ReasonEnvironment,
+		},
+		{
+			name:        "environment listed",
+			def:         conf.FeatureFlagConfig{Enabled: true, Environments: []string{"staging"}},
+			subject:     Subject{Environment: "staging"},
+			wantEnabled: true, wantReason: ReasonEnabled,
+		},
+		// users 不受 tenants 与 percentage 限制
+		{
+			name:        "user overrides tenants and rollout",
+			def:         conf.FeatureFlagConfig{Enabled: true, Tenants: []string{"acme"}, Users: []string{"alice"}, Percentage: percentage(0)},
+			subject:     Subject{Tenant: "globex", User: "alice"},
+			wantEnabled: true, wantReason: ReasonUser,
+		},
+		{
+			name:       "tenant not listed",
+			def:        conf.FeatureFlagConfig{Enabled: true, Tenants: []string{"acme"}},
+			subject:    Subject{Tenant: "globex", User: "bob"},
+			wantReason: ReasonTenant,
+		},
+		{
+			name:       "anonymous user does not match empty user",
+			def:        conf.FeatureFlagConfig{Enabled: true, Tenants: []string{"acme"}, Users: []string{""}},
+			subject:    Subject{Tenant: "globex"},
+			wantReason: ReasonTenant,
+		},
+		{
+			name:        "tenant listed",
+			def:         conf.FeatureFlagConfig{Enabled: true, Tenants: []string{"acme"}},
+			subject:     Subject{Tenant: "acme"},
+			wantEnabled: true, wantReason: ReasonEnabled,
+		},
+		{name: "rollout zero", def: conf.FeatureFlagConfig{Enabled: true, Percentage: percentage(0)}, subject: Subject{Tenant: "acme", User: "bob"}, wantReason: ReasonRollout},
+		{name: "rollout full", def: conf.FeatureFlagConfig{Enabled: true, Percentage: percentage(100)}, subject: Subject{Tenant: "acme", User: "bob"}, wantEnabled: true, wantReason: ReasonRollout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluate(Flag{FeatureFlagConfig: tt.def, Name: "new_checkout"}, tt.subject)
+			if got.Enabled != tt.wantEnabled || got.Reason != tt.wantReason || got.Flag != "new_checkout" {
+				t.Errorf("evaluate = %+v, want enabled %v reason %s", got, tt.wantEnabled, tt.wantReason)
+			}
+		})
+	}
+}
+
+// subjects 生成 n 个同租户下的用户
+func subjects(n int) []Subject {
+	out := make([]Subject, n)
+	for i := range out {
+		out[i] = Subject{Tenant: "acme", User: fmt.Sprintf("user-%d", i)}
+	}
+	return out
+}
+
+// rolloutSet 返回按 p% 灰度时开启的对象下标
+func rolloutSet(name string, p float64, subjects []Subject) map[int]bool {
+	flag := Flag{FeatureFlagConfig: conf.FeatureFlagConfig{Enabled: true, Percentage: percentage(p)}, Name: name}
+	out := make(map[int]bool)
+	for i, s := range subjects {
+		if evaluate(flag, s).Enabled {
+			out[i] = true
+		}
+	}
+	return out
+}
+
+func TestRolloutDistribution(t *testing.T) {
+	all := subjects(10000)
+	for _, p := range []float64{1, 10, 25, 50, 90} {
+		got := float64(len(rolloutSet("new_checkout", p, all))) * 100 / float64(len(all))
+		// 哈希分桶的误差在 ±2 个百分点以内
+		if got < p-2 || got > p+2 {
+			t.Errorf("rollout %v%% enabled %.2f%% of users", p, got)
+		}
+	}
+}
+
+func TestRolloutStable(t *testing.T) {
+	all := subjects(2000)
+
+	// 同一对象多次求值结果一致
+	first, second := rolloutSet("new_checkout", 30, all), rolloutSet("new_checkout", 30, all)
+	if len(first) != len(second) {
+		t.Fatalf("repeated evaluation enabled %d then %d users", len(first), len(second))
+	}
+	for i := range first {
+		if !second[i] {
+			t.Fatalf("user %d flipped between evaluations", i)
+		}
+	}
+
+	// 调大比例时已开启的用户保持开启
+	wider := rolloutSet("new_checkout", 60, all)
+	for i := range first {
+		if !wider[i] {
+			t.Errorf("user %d enabled at 30%% but not at 60%%", i)
+		}
+	}
+
+	// 开关名参与哈希，不同开关放量到的用户不同
+	other := rolloutSet("dark_mode", 30, all)
+	overlap := 0
+	for i := range first {
+		if other[i] {
+			overlap++
+		}
+	}
+	// 相互独立时重叠约为 30% * 30% = 9%
+	if overlap == len(first) || float64(overlap) > float64(len(all))*0.15 {
+		t.Errorf("flags at 30%% overlap on %d of %d enabled users, want them independent", overlap, len(first))
+	}
+}
+
+func TestRolloutAnonymousByTenant(t *testing.T) {
+	// 未登录请求按租户分桶：同一租户结果一致，不同租户各自决定
+	tenants := make([]Subject, 1000)
+	for i := range tenants {
+		tenants[i] = Subject{Tenant: fmt.Sprintf("tenant-%d", i)}
+	}
+	set := rolloutSet("new_checkout", 50, tenants)
+	if len(set) == 0 || len(set) == len(tenants) {
+		t.Fatalf("anonymous rollout enabled %d of %d tenants, want a split", len(set), len(tenants))
+	}
+	for i, s := range tenants {
+		flag := Flag{FeatureFlagConfig: conf.FeatureFlagConfig{Enabled: true, Percentage: percentage(50)}, Name: "new_checkout"}
+		if evaluate(flag, s).Enabled != set[i] {
+			t.Fatalf("tenant %s flipped between evaluations", s.Tenant)
+		}
+	}
+}
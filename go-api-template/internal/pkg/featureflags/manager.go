@@ -0,0 +1,226 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/pkg/tenant"
+)
+
+// fileReloadDebounce 合并短时间内的多次文件事件，与配置文件的热加载一致
+const fileReloadDebounce = 300 * time.Millisecond
+
+// Manager 保存当前生效的开关定义并负责求值
+// 配置与开关文件中的定义合并后整体替换，求值时无需加锁
+type Manager struct {
+	env  string
+	file string
+
+	// mu 保护两个来源的定义，合并结果发布到 flags
+	mu          sync.Mutex
+	configFlags map[string]conf.FeatureFlagConfig
+	fileFlags   map[string]conf.FeatureFlagConfig
+	flags       atomic.Pointer[map[string]Flag]
+
+	// unknown 已记录过警告的未定义开关，每个开关只警告一次
+	unknown sync.Map
+
+	fsWatcher *fsnotify.Watcher
+	done      chan struct{}
+}
+
+// NewManager 加载配置与开关文件中的定义，并订阅两者的变更
+// 启动时开关文件读取或校验失败直接返回错误；运行期重新加载失败时保留旧的定义并记录警告
+// 返回的 cleanup 停止监听开关文件
+func NewManager(watcher *conf.Watcher) (*Manager, func(), error) {
+	cfg := watcher.Current()
+	m := &Manager{
+		env:         cfg.App.Env,
+		file:        cfg.Features.File,
+		configFlags: cfg.Features.Flags,
+		done:        make(chan struct{}),
+	}
+	if m.file != "" {
+		flags, err := loadFile(m.file)
+		if err != nil {
+			return nil, nil, err
+		}
+		m.fileFlags = flags
+	}
+	m.publish()
+
+	unsubscribe := watcher.Subscribe(func(e conf.ChangeEvent) {
+		if e.Changed("features.flags") {
+			m.mu.Lock()
+			m.configFlags = e.New.Features.Flags
+			m.publish()
+			m.mu.Unlock()
+			slog.Info("Feature flags reloaded", "source", SourceConfig)
+		}
+	})
+	if m.file != "" {
+		if err := m.watchFile(); err != nil {
+			unsubscribe()
+			return nil, nil, err
+		}
+	}
+
+	cleanup := func() {
+		unsubscribe()
+		close(m.done)
+		if m.fsWatcher != nil {
+			_ = m.fsWatcher.Close()
+		}
+	}
+	return m, cleanup, nil
+}
+
+// publish 合并两个来源的定义并替换当前生效的开关，调用方需持有 mu（构造时除外）
+// 同名开关以开关文件为准：文件通常由发布平台下发，是更新的意图
+func (m *Manager) publish() {
+	flags := make(map[string]Flag, len(m.configFlags)+len(m.fileFlags))
+	for name, def := range m.configFlags {
+		flags[name] = Flag{FeatureFlagConfig: def, Name: name, Source: SourceConfig}
+	}
+	for name, def := range m.fileFlags {
+		flags[name] = Flag{FeatureFlagConfig: def, Name: name, Source: SourceFile}
+	}
+	m.flags.Store(&flags)
+}
+
+// Flags 返回当前生效的开关，按名称排序
+func (m *Manager) Flags() []Flag {
+	flags := *m.flags.Load()
+	out := make([]Flag, 0, len(flags))
+	for _, flag := range flags {
+		out = append(out, flag)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Evaluate 对指定对象求值，不记录指标与日志，供管理接口预览
+func (m *Manager) Evaluate(name string, s Subject) Evaluation {
+	flag, ok := (*m.flags.Load())[name]
+	if !ok {
+		return Evaluation{Flag: name, Reason: ReasonUnknown}
+	}
+	return evaluate(flag, s)
+}
+
+// Check 按 ctx 中的租户、用户与当前环境求值，并记录指标与日志
+// 业务代码通过 Enabled(ctx, name) 间接调用
+func (m *Manager) Check(ctx context.Context, name string) Evaluation {
+	s := m.Subject(ctx)
+	e := m.Evaluate(name, s)
+	record(e)
+	if e.Reason == ReasonUnknown {
+		if _, warned := m.unknown.LoadOrStore(name, struct{}{}); !warned {
+			slog.WarnContext(ctx, "Feature flag is not defined, treating as disabled", "flag", name)
+		}
+		return e
+	}
+	slog.DebugContext(ctx, "Feature flag evaluated", "flag", name, "enabled", e.Enabled, "reason", e.Reason,
+		"tenant", s.Tenant, "user", s.User, "env", s.Environment)
+	return e
+}
+
+// Subject 由 ctx 中的租户、用户与当前环境构造求值对象
+func (m *Manager) Subject(ctx context.Context) Subject {
+	s := Subject{Environment: m.env}
+	s.Tenant, _ = tenant.FromContext(ctx)
+	if claims, ok := auth.FromContext(ctx); ok {
+		s.User = claims.Username
+	}
+	return s
+}
+
+// Environment 返回求值使用的运行环境（app.env）
+func (m *Manager) Environment() string {
+	return m.env
+}
+
+// loadFile 读取并校验开关文件，格式由扩展名决定（YAML 或 JSON）
+func loadFile(path string) (map[string]conf.FeatureFlagConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read feature flag file %s: %w", path, err)
+	}
+	var flags map[string]conf.FeatureFlagConfig
+	if err := v.UnmarshalKey("flags", &flags); err != nil {
+		return nil, fmt.Errorf("failed to parse feature flag file %s: %w", path, err)
+	}
+	if errs := conf.ValidateFeatureFlags("flags", flags); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid feature flag file %s: %w", path, errors.Join(errs...))
+	}
+	return flags, nil
+}
+
+// watchFile 监听开关文件所在目录，文件变化后重新加载
+// 与配置文件一样监听目录而不是文件，"写临时文件 + 重命名"的保存方式不会丢失后续事件
+func (m *Manager) watchFile() error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create feature flag watcher: %w", err)
+	}
+	if err := fsWatcher.Add(filepath.Dir(m.file)); err != nil {
+		_ = fsWatcher.Close()
+		return fmt.Errorf("failed to watch feature flag directory: %w", err)
+	}
+	m.fsWatcher = fsWatcher
+	go m.loop()
+	return nil
+}
+
+func (m *Manager) loop() {
+	file := filepath.Clean(m.file)
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-m.done:
+			return
+		case event, ok := <-m.fsWatcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) == file && event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				debounce = time.After(fileReloadDebounce)
+			}
+		case err, ok := <-m.fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("feature flag watcher error", "error", err)
+		case <-debounce:
+			debounce = nil
+			m.reloadFile()
+		}
+	}
+}
+
+// reloadFile 重新加载开关文件，失败时保留旧的定义
+func (m *Manager) reloadFile() {
+	flags, err := loadFile(m.file)
+	if err != nil {
+		slog.Warn("Feature flag file reload rejected, keeping previous flags", "error", err)
+		return
+	}
+	m.mu.Lock()
+	m.fileFlags = flags
+	m.publish()
+	m.mu.Unlock()
+	slog.Info("Feature flags reloaded", "source", SourceFile, "file", m.file, "flags", len(flags))
+}
@@ -0,0 +1,170 @@
+package featureflags
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/pkg/tenant"
+)
+
+// writeFile 写入 dir 下的文件，返回路径
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+// newTestManager 创建 Manager，测试结束时停止监听
+func newTestManager(t *testing.T, watcher *conf.Watcher) *Manager {
+	t.Helper()
+	m, cleanup, err := NewManager(watcher)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	t.Cleanup(cleanup)
+	return m
+}
+
+// waitFor 等待 cond 成立
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestManagerConfigReload(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	render := func(enabled string) string {
+		return "app:\n  port: 8080\nfeatures:\n  flags:\n    new_checkout:\n      enabled: " + enabled + "\n"
+	}
+	path := writeFile(t, t.TempDir(), "config.yaml", render("false"))
+	cfg, err := conf.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	watcher := conf.NewWatcher(cfg)
+	m := newTestManager(t, watcher)
+
+	if e := m.Evaluate("new_checkout", Subject{}); e.Enabled || e.Reason != ReasonDisabled {
+		t.Fatalf("before reload = %+v, want disabled", e)
+	}
+
+	writeFile(t, filepath.Dir(path), "config.yaml", render("true"))
+	if err := watcher.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if e := m.Evaluate("new_checkout", Subject{}); !e.Enabled || e.Reason != ReasonEnabled {
+		t.Errorf("after reload = %+v, want enabled", e)
+	}
+}
+
+func TestManagerFileReload(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "flags.yaml", "flags:\n  new_checkout:\n    enabled: true\n    percentage: 0\n")
+	m := newTestManager(t, conf.NewWatcher(&conf.Config{Features: conf.FeaturesConfig{
+		File: path,
+		Flags: map[string]conf.FeatureFlagConfig{
+			"new_checkout": {Enabled: false},
+			"dark_mode":    {Enabled: true},
+		},
+	}}))
+
+	// 同名开关以开关文件为准
+	flags := m.Flags()
+	if len(flags) != 2 || flags[0].Name != "dark_mode" || flags[0].Source != SourceConfig ||
+		flags[1].Name != "new_checkout" || flags[1].Source != SourceFile {
+		t.Fatalf("Flags = %+v, want dark_mode from config and new_checkout from file", flags)
+	}
+	subject := Subject{Tenant: "acme", User: "alice"}
+	if e := m.Evaluate("new_checkout", subject); e.Enabled || e.Reason != ReasonRollout {
+		t.Fatalf("before reload = %+v, want rollout off", e)
+	}
+
+	// 修改文件后由监听自动重新加载
+	writeFile(t, dir, "flags.yaml", "flags:\n  new_checkout:\n    enabled: true\n    percentage: 100\n")
+	waitFor(t, "flag file reload", func() bool { return m.Evaluate("new_checkout", subject).Enabled })
+
+	// 校验失败的文件被拒绝，保留旧的定义
+	writeFile(t, dir, "flags.yaml", "flags:\n  new_checkout:\n    enabled: true\n    percentage: 150\n")
+	m.reloadFile()
+	if e := m.Evaluate("new_checkout", subject); !e.Enabled {
+		t.Errorf("after rejected reload = %+v, want previous definition kept", e)
+	}
+}
+
+func TestNewManagerRejectsInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"missing file", filepath.Join(dir, "missing.yaml")},
+		{"invalid flag", writeFile(t, dir, "invalid.yaml", "flags:\n  new_checkout:\n    enabled: true\n    percentage: -1\n")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := NewManager(conf.NewWatcher(&conf.Config{Features: conf.FeaturesConfig{File: tt.path}})); err == nil {
+				t.Fatal("NewManager succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestManagerCheck(t *testing.T) {
+	m := newTestManager(t, conf.NewWatcher(&conf.Config{
+		App: conf.AppConfig{Env: "staging"},
+		Features: conf.FeaturesConfig{Flags: map[string]conf.FeatureFlagConfig{
+			"beta": {Enabled: true, Environments: []string{"staging"}, Tenants: []string{"acme"}, Users: []string{"carol"}},
+		}},
+	}))
+	ctx := NewContext(tenant.NewContext(context.Background(), "globex"), m)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		flag string
+		want bool
+	}{
+		{"other tenant", ctx, "beta", false},
+		{"listed tenant", tenant.NewContext(ctx, "acme"), "beta", true},
+		{"listed user", auth.NewContext(ctx, &auth.Claims{Username: "carol"}), "beta", true},
+		{"unknown flag", tenant.NewContext(ctx, "acme"), "missing", false},
+		// 没有接入开关的调用方视为关闭
+		{"no manager", tenant.NewContext(context.Background(), "acme"), "beta", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Enabled(tt.ctx, tt.flag); got != tt.want {
+				t.Errorf("Enabled(%s) = %v, want %v", tt.flag, got, tt.want)
+			}
+		})
+	}
+
+	if e := m.Check(ctx, "missing"); e.Reason != ReasonUnknown {
+		t.Errorf("Check(missing) reason = %s, want unknown", e.Reason)
+	}
+	if s := m.Subject(auth.NewContext(ctx, &auth.Claims{Username: "carol"})); s != (Subject{Environment: "staging", Tenant: "globex", User: "carol"}) {
+		t.Errorf("Subject = %+v", s)
+	}
+
+	// 每次求值都计入指标
+	on, off := Counts("beta")
+	Enabled(tenant.NewContext(ctx, "acme"), "beta")
+	Enabled(ctx, "beta")
+	if gotOn, gotOff := Counts("beta"); gotOn != on+1 || gotOff != off+1 {
+		t.Errorf("Counts = %d/%d, want %d/%d", gotOn, gotOff, on+1, off+1)
+	}
+}
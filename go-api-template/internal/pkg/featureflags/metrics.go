@@ -0,0 +1,32 @@
+package featureflags
+
+import "expvar"
+
+// evaluations 开关求值次数，键为 "开关名.on" / "开关名.off"
+// 通过 expvar 发布，可由 /debug/vars 抓取；管理接口也展示同样的计数
+var evaluations = expvar.NewMap("feature_flag_evaluations")
+
+// record 记录一次求值
+func record(e Evaluation) {
+	evaluations.Add(counterKey(e.Flag, e.Enabled), 1)
+}
+
+// Counts 返回开关自进程启动以来开启与关闭的求值次数
+func Counts(name string) (on, off int64) {
+	return counterValue(counterKey(name, true)), counterValue(counterKey(name, false))
+}
+
+func counterKey(name string, enabled bool) string {
+	if enabled {
+		return name + ".on"
+	}
+	return name + ".off"
+}
+
+func counterValue(key string) int64 {
+	v, ok := evaluations.Get(key).(*expvar.Int)
+	if !ok {
+		return 0
+	}
+	return v.Value()
+}
@@ -0,0 +1,105 @@
+// Package feed 实现进程内的扇出推送：一个生产者把消息广播给多个订阅者。
+// 每个订阅者拥有独立的缓冲区，消费过慢的订阅者会被断开，不会拖慢生产者和其他订阅者。
+package feed
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrSlowConsumer 订阅者的缓冲区已满，被断开
+	ErrSlowConsumer = errors.New("feed: subscriber is too slow")
+	// ErrClosed 推送源已关闭（服务停止）
+	ErrClosed = errors.New("feed: closed")
+)
+
+// Hub 把消息广播给所有订阅者
+type Hub[T any] struct {
+	mu     sync.Mutex
+	subs   map[*Subscription[T]]struct{}
+	closed bool
+}
+
+// NewHub 创建 Hub
+func NewHub[T any]() *Hub[T] {
+	return &Hub[T]{subs: make(map[*Subscription[T]]struct{})}
+}
+
+// Subscription 一个订阅者
+// C 在订阅结束（取消、被断开或 Hub 关闭）后被关闭，此时 Err 返回结束原因
+type Subscription[T any] struct {
+	C <-chan T
+
+	hub  *Hub[T]
+	ch   chan T
+	once sync.Once
+	err  error
+}
+
+// Subscribe 创建缓冲 buffer 条消息的订阅者，Hub 已关闭时返回 ErrClosed
+func (h *Hub[T]) Subscribe(buffer int) (*Subscription[T], error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrClosed
+	}
+	ch := make(chan T, buffer)
+	sub := &Subscription[T]{C: ch, hub: h, ch: ch}
+	h.subs[sub] = struct{}{}
+	return sub, nil
+}
+
+// Publish 把消息放入所有订阅者的缓冲区，不会阻塞
+// 缓冲区已满的订阅者以 ErrSlowConsumer 断开
+func (h *Hub[T]) Publish(msg T) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		select {
+		case sub.ch <- msg:
+		default:
+			h.drop(sub, ErrSlowConsumer)
+		}
+	}
+}
+
+// Len 返回当前订阅者数量
+func (h *Hub[T]) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// Close 以 ErrClosed 断开所有订阅者，之后的 Subscribe 返回 ErrClosed；可重复调用
+func (h *Hub[T]) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for sub := range h.subs {
+		h.drop(sub, ErrClosed)
+	}
+}
+
+// drop 移除订阅者并关闭其 channel，调用方需持有 h.mu
+func (h *Hub[T]) drop(sub *Subscription[T], err error) {
+	sub.once.Do(func() {
+		delete(h.subs, sub)
+		sub.err = err
+		close(sub.ch)
+	})
+}
+
+// Cancel 取消订阅，订阅者主动离开时调用；可重复调用
+func (s *Subscription[T]) Cancel() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.drop(s, nil)
+}
+
+// Err 返回订阅结束的原因，仅在 C 被关闭后有意义；主动取消时为 nil
+func (s *Subscription[T]) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.err
+}
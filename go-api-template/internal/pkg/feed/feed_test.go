@@ -0,0 +1,98 @@
+package feed
+
+import (
+	"errors"
+	"testing"
+)
+
+// drain 读取 sub 中的全部消息，直到 C 被关闭
+func drain[T any](sub *Subscription[T]) []T {
+	var got []T
+	for msg := range sub.C {
+		got = append(got, msg)
+	}
+	return got
+}
+
+func TestHubFanOut(t *testing.T) {
+	hub := NewHub[int]()
+	subs := make([]*Subscription[int], 3)
+	for i := range subs {
+		sub, err := hub.Subscribe(10)
+		if err != nil {
+			t.Fatalf("Subscribe: %v", err)
+		}
+		subs[i] = sub
+	}
+	if hub.Len() != 3 {
+		t.Fatalf("Len = %d, want 3", hub.Len())
+	}
+
+	for i := 1; i <= 3; i++ {
+		hub.Publish(i)
+	}
+	hub.Close()
+
+	for i, sub := range subs {
+		got := drain(sub)
+		if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+			t.Errorf("subscriber %d got %v, want [1 2 3]", i, got)
+		}
+		if !errors.Is(sub.Err(), ErrClosed) {
+			t.Errorf("subscriber %d Err = %v, want ErrClosed", i, sub.Err())
+		}
+	}
+}
+
+func TestHubSlowConsumer(t *testing.T) {
+	hub := NewHub[int]()
+	slow, _ := hub.Subscribe(1)
+	fast, _ := hub.Subscribe(10)
+
+	hub.Publish(1)
+	// slow 的缓冲区已满，第二条消息使它被断开，fast 不受影响
+	hub.Publish(2)
+	hub.Publish(3)
+
+	if got := drain(slow); len(got) != 1 || got[0] != 1 {
+		t.Errorf("slow got %v, want the buffered [1]", got)
+	}
+	if !errors.Is(slow.Err(), ErrSlowConsumer) {
+		t.Errorf("slow Err = %v, want ErrSlowConsumer", slow.Err())
+	}
+	if hub.Len() != 1 {
+		t.Errorf("Len = %d, want only the fast subscriber left", hub.Len())
+	}
+
+	fast.Cancel()
+	if got := drain(fast); len(got) != 3 {
+		t.Errorf("fast got %v, want all three messages", got)
+	}
+	if err := fast.Err(); err != nil {
+		t.Errorf("cancelled Err = %v, want nil", err)
+	}
+}
+
+func TestHubClose(t *testing.T) {
+	hub := NewHub[int]()
+	sub, _ := hub.Subscribe(1)
+
+	hub.Close()
+	hub.Close()
+	sub.Cancel()
+
+	if _, ok := <-sub.C; ok {
+		t.Error("C is still open after Close")
+	}
+	// 先被 Close 断开，之后的 Cancel 不改变结束原因
+	if !errors.Is(sub.Err(), ErrClosed) {
+		t.Errorf("Err = %v, want ErrClosed", sub.Err())
+	}
+	if _, err := hub.Subscribe(1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Subscribe after Close = %v, want ErrClosed", err)
+	}
+	hub.Publish(1)
+	if hub.Len() != 0 {
+		t.Errorf("Len = %d after Close, want 0", hub.Len())
+	}
+}
@@ -0,0 +1,81 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval 清理过期记录的最小间隔
+const sweepInterval = time.Minute
+
+// MemoryStore 进程内的幂等存储，只适用于单实例部署
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+// memoryEntry 记录及其过期时间
+type memoryEntry struct {
+	rec       Record
+	expiresAt time.Time
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries:   make(map[string]memoryEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// Begin 实现 Store.Begin
+func (s *MemoryStore) Begin(_ context.Context, key, fingerprint string, lockTTL time.Duration) (*Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	if e, ok := s.entries[key]; ok && now.Before(e.expiresAt) {
+		rec := e.rec
+		return &rec, false, nil
+	}
+	s.entries[key] = memoryEntry{
+		rec:       Record{Fingerprint: fingerprint},
+		expiresAt: now.Add(lockTTL),
+	}
+	return nil, true, nil
+}
+
+// Complete 实现 Store.Complete
+func (s *MemoryStore) Complete(_ context.Context, key string, rec *Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryEntry{rec: *rec, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Release 实现 Store.Release
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// sweep 定期清理过期记录，调用方需持有锁
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,71 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix 幂等记录在 Redis 中的键前缀
+const redisKeyPrefix = "idempotency:"
+
+// RedisStore 基于 Redis 的幂等存储，多个实例共享同一份记录
+// 占用使用 SET NX，保证并发的重复请求中只有一个能开始执行
+type RedisStore struct {
+	client redis.Cmdable
+}
+
+// NewRedisStore 创建 Redis 存储
+func NewRedisStore(client redis.Cmdable) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Begin 实现 Store.Begin
+func (s *RedisStore) Begin(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*Record, bool, error) {
+	pending, err := json.Marshal(Record{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, false, err
+	}
+	// 已有记录恰好在 SET NX 与 GET 之间过期时重新占用，重试一次即可
+	for range 2 {
+		ok, err := s.client.SetNX(ctx, redisKeyPrefix+key, pending, lockTTL).Result()
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			return nil, true, nil
+		}
+
+		data, err := s.client.Get(ctx, redisKeyPrefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		var rec Record
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, false, fmt.Errorf("idempotency record %q: %w", key, err)
+		}
+		return &rec, false, nil
+	}
+	return nil, false, fmt.Errorf("idempotency record %q: expired repeatedly while reading", key)
+}
+
+// Complete 实现 Store.Complete
+func (s *RedisStore) Complete(ctx context.Context, key string, rec *Record, ttl time.Duration) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, redisKeyPrefix+key, data, ttl).Err()
+}
+
+// Release 实现 Store.Release
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, redisKeyPrefix+key).Err()
+}
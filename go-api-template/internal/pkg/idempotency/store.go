@@ -0,0 +1,79 @@
+// Package idempotency 保存带幂等键请求的首次响应，供重复请求重放
+// 存储只负责"占用键 / 保存响应 / 释放键"三个原子操作，
+// 请求指纹比较、等待与重放等 HTTP 语义由 server/middleware 实现
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/redis/go-redis/v9"
+
+	"go-api-template/internal/conf"
+)
+
+// ProviderSet 幂等存储的依赖提供者集合
+var ProviderSet = wire.NewSet(NewStore)
+
+// Response 首次请求的响应快照
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// Record 幂等键对应的记录
+type Record struct {
+	// Fingerprint 首次请求的内容指纹，同一个键被用于不同请求时据此拒绝
+	Fingerprint string `json:"fingerprint"`
+	// Response 首次请求的响应，为 nil 表示首次请求仍在处理中
+	Response *Response `json:"response,omitempty"`
+}
+
+// Completed 判断首次请求是否已经完成
+func (r *Record) Completed() bool {
+	return r.Response != nil
+}
+
+// Store 幂等记录的存储
+type Store interface {
+	// Begin 原子地占用 key：key 不存在时写入"处理中"记录（lockTTL 后自动过期）并返回 (nil, true)；
+	// key 已存在时返回已有记录和 false
+	Begin(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*Record, bool, error)
+	// Complete 保存首次请求的响应，ttl 内的重复请求都会重放该响应
+	Complete(ctx context.Context, key string, rec *Record, ttl time.Duration) error
+	// Release 删除 key，首次请求失败（如服务端错误）时调用，允许客户端重试
+	Release(ctx context.Context, key string) error
+}
+
+// NewStore 按 idempotency.store 配置创建存储
+// 未启用幂等键时同样返回内存存储，它不占用任何外部资源
+func NewStore(cfg *conf.Config) (Store, func(), error) {
+	if !cfg.Idempotency.Enabled || !cfg.Idempotency.IsRedis() {
+		return NewMemoryStore(), func() {}, nil
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Addr(),
+		Password: cfg.Redis.Password.Reveal(),
+		DB:       cfg.Redis.DB,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, nil, fmt.Errorf("failed to connect redis %s: %w", cfg.Redis.Addr(), err)
+	}
+	slog.Info("Idempotency store initialized", "store", "redis", "addr", cfg.Redis.Addr())
+
+	cleanup := func() {
+		if err := client.Close(); err != nil {
+			slog.Error("Failed to close redis client", "error", err)
+		}
+	}
+	return NewRedisStore(client), cleanup, nil
+}
@@ -0,0 +1,145 @@
+// Package jobs 提供持久化的后台任务队列
+// 任务写入 Store（数据库或 Redis）后由 Manager 领取执行：每个队列有独立的并发上限，
+// 失败按指数退避重试，超过最大次数进入死信；支持延迟执行与 cron 定时入队，
+// 关闭时等待执行中的任务完成。
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/wire"
+
+	"go-api-template/internal/conf"
+)
+
+// ProviderSet 后台任务的依赖提供者集合
+var ProviderSet = wire.NewSet(NewStore, NewManager)
+
+// Status 任务状态
+type Status string
+
+const (
+	// StatusQueued 等待执行：尚未到执行时间、等待重试或等待空闲的 worker
+	StatusQueued Status = "queued"
+	// StatusRunning 执行中；run_at 同时是领取租约的到期时间，进程崩溃后任务在租约到期后被重新领取
+	StatusRunning Status = "running"
+	// StatusSucceeded 执行成功
+	StatusSucceeded Status = "succeeded"
+	// StatusDead 超过最大执行次数或返回了不可重试的错误，等待人工处理
+	StatusDead Status = "dead"
+	// StatusCancelled 执行前被取消
+	StatusCancelled Status = "cancelled"
+)
+
+// Statuses 全部任务状态，用于参数校验
+var Statuses = []Status{StatusQueued, StatusRunning, StatusSucceeded, StatusDead, StatusCancelled}
+
+// Job 一个后台任务
+type Job struct {
+	ID    int64
+	Queue string
+	Type  string
+	// Payload JSON 格式的任务参数，由处理函数解码
+	Payload json.RawMessage
+	Status  Status
+	// Attempts 已领取执行的次数，领取时加一
+	Attempts    int
+	MaxAttempts int
+	// RunAt 排队中的任务为计划执行时间，执行中的任务为租约到期时间
+	RunAt     time.Time
+	LastError string
+	// UniqueKey 非空时，存储中同一个键只能有一个任务，直到该任务被清理
+	UniqueKey  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	FinishedAt *time.Time
+}
+
+// LeaseExhausted 判断任务是否为租约已过期、且执行次数已达上限的执行中任务
+// 这样的任务不再领取，由 Claim 直接移入死信
+func (j *Job) LeaseExhausted(now time.Time) bool {
+	return j.Status == StatusRunning && !j.RunAt.After(now) && j.Attempts >= j.MaxAttempts
+}
+
+// Filter 任务列表的筛选条件，零值字段不参与筛选
+type Filter struct {
+	Status Status
+	Queue  string
+	Type   string
+	// Limit 最多返回的条数
+	Limit int
+}
+
+// Match 判断任务是否满足筛选条件
+func (f Filter) Match(job *Job) bool {
+	return (f.Status == "" || job.Status == f.Status) &&
+		(f.Queue == "" || job.Queue == f.Queue) &&
+		(f.Type == "" || job.Type == f.Type)
+}
+
+var (
+	// ErrNotFound 任务不存在
+	ErrNotFound = errors.New("job not found")
+	// ErrDuplicate 存储中已有相同 UniqueKey 的任务
+	ErrDuplicate = errors.New("job with the same unique key already exists")
+	// ErrInvalidState 任务当前状态不允许该操作（如取消执行中的任务）
+	ErrInvalidState = errors.New("job state does not allow this operation")
+	// ErrUnknownType 没有为任务类型注册处理函数
+	ErrUnknownType = errors.New("unknown job type")
+	// ErrLeaseExpired 最后一次执行的租约到期仍未上报结果（通常是 worker 崩溃），任务进入死信时记录为失败原因
+	ErrLeaseExpired = errors.New("lease expired before the last attempt reported a result")
+)
+
+// Store 任务的持久化存储
+// 状态变更都是条件更新，多个进程共享同一个存储时任务不会被并发执行
+//
+// 领取时递增的执行次数同时是租约凭证：Complete 与 Fail 只在任务仍处于执行中、
+// 且执行次数等于领取时的 attempt 时生效。租约过期后任务被重新领取，执行次数随之变化，
+// 原 worker 迟到的结果不会覆盖新一次执行的状态
+type Store interface {
+	// Enqueue 保存新任务并回填 ID；UniqueKey 已存在时返回 ErrDuplicate
+	Enqueue(ctx context.Context, job *Job) error
+	// Claim 领取队列中最多 limit 个已到执行时间的任务（包括租约已过期的执行中任务），
+	// 把它们标记为执行中、执行次数加一，并在 lease 时长内不再交给其他 Claim 调用；
+	// 租约已过期且执行次数已达上限的任务不再领取，以 ErrLeaseExpired 为原因进入死信
+	Claim(ctx context.Context, queue string, now time.Time, limit int, lease time.Duration) ([]*Job, error)
+	// Complete 把本次领取（执行次数为 attempt）的任务标记为成功
+	Complete(ctx context.Context, id int64, attempt int, at time.Time) error
+	// Fail 记录本次领取（执行次数为 attempt）的执行失败：next 非零时在 next 重新排队，零值表示进入死信
+	Fail(ctx context.Context, id int64, attempt int, next time.Time, reason string, at time.Time) error
+	// Get 按 ID 获取任务
+	Get(ctx context.Context, id int64) (*Job, error)
+	// List 按 ID 倒序列出满足条件的任务
+	List(ctx context.Context, f Filter) ([]*Job, error)
+	// Retry 把死信、已取消或已成功的任务重新排队，执行次数清零
+	Retry(ctx context.Context, id int64, now time.Time) (*Job, error)
+	// Cancel 取消排队中的任务，执行中的任务不能取消
+	Cancel(ctx context.Context, id int64, now time.Time) (*Job, error)
+	// Purge 删除在 before 之前成功或取消的任务，返回删除的数量
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// DBStore 基于应用数据库的 Store，由 data 层提供
+// 与 Store 区分类型，使 Wire 能在数据库与 Redis 实现之间选择
+type DBStore interface {
+	Store
+}
+
+// NewStore 按 jobs.store 配置创建存储
+// database 使用 data 层提供的实现（database.driver 为 memory 时是内存实现）
+func NewStore(cfg *conf.Config, db DBStore) (Store, func(), error) {
+	if !cfg.Jobs.IsRedis() {
+		return db, func() {}, nil
+	}
+
+	store, cleanup, err := newRedisStore(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	slog.Info("Job store initialized", "store", "redis", "addr", cfg.Redis.Addr())
+	return store, cleanup, nil
+}
@@ -0,0 +1,466 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math/rand/v2"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	"go-api-template/internal/conf"
+)
+
+const (
+	// maxErrorLen 记录的失败原因最大长度
+	maxErrorLen = 1000
+	// purgeInterval 清理过期任务的间隔
+	purgeInterval = time.Hour
+)
+
+// HandlerFunc 任务处理函数
+// 返回 nil 表示成功；返回错误时按退避策略重试，用 Permanent 包装的错误直接进入死信
+type HandlerFunc func(ctx context.Context, job *Job) error
+
+// handler 注册的处理函数及其选项
+type handler struct {
+	fn          HandlerFunc
+	queue       string
+	maxAttempts int
+	timeout     time.Duration
+}
+
+// HandlerOption 注册处理函数时的选项
+type HandlerOption func(*handler)
+
+// WithQueue 指定任务类型默认所在的队列
+func WithQueue(queue string) HandlerOption {
+	return func(h *handler) { h.queue = queue }
+}
+
+// WithMaxAttempts 指定任务类型的最大执行次数，覆盖 jobs.max_attempts
+func WithMaxAttempts(n int) HandlerOption {
+	return func(h *handler) { h.maxAttempts = n }
+}
+
+// WithTimeout 指定单次执行的超时，覆盖 jobs.timeout
+func WithTimeout(d time.Duration) HandlerOption {
+	return func(h *handler) { h.timeout = d }
+}
+
+// permanentError 不可重试的错误
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 把错误标记为不可重试，处理函数返回它时任务直接进入死信
+// 适用于重试也不会成功的情况，如参数无法解码
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Manager 注册处理函数、入队任务，并在 Start 后执行任务与定时调度
+// 处理函数需要在 Start 之前注册（通常在各模块的构造函数中）
+type Manager struct {
+	store Store
+	cfg   conf.JobsConfig
+
+	mu        sync.RWMutex
+	handlers  map[string]*handler
+	schedules []*schedule
+
+	stop chan struct{}
+	done chan struct{}
+	// cancel 取消执行中任务的 context，Stop 超时时调用
+	cancel context.CancelFunc
+}
+
+// NewManager 创建 Manager，并加载 jobs.schedules 中的定时任务
+func NewManager(cfg *conf.Config, store Store) (*Manager, error) {
+	m := &Manager{
+		store:    store,
+		cfg:      cfg.Jobs,
+		handlers: make(map[string]*handler),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, s := range cfg.Jobs.Schedules {
+		payload := json.RawMessage(s.Payload)
+		if s.Payload == "" {
+			payload = json.RawMessage("{}")
+		}
+		if err := m.addSchedule(s.Name, s.Cron, s.Type, s.Queue, payload); err != nil {
+			return nil, fmt.Errorf("jobs.schedules %q: %w", s.Name, err)
+		}
+	}
+	return m, nil
+}
+
+// Register 为任务类型注册处理函数，重复注册时后者覆盖前者
+func (m *Manager) Register(jobType string, fn HandlerFunc, opts ...HandlerOption) {
+	h := &handler{
+		fn:          fn,
+		queue:       conf.DefaultJobQueue,
+		maxAttempts: m.cfg.GetMaxAttempts(),
+		timeout:     m.cfg.GetTimeout(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[jobType] = h
+}
+
+// Handle 以强类型参数注册处理函数，任务参数按 JSON 解码为 T
+// 参数无法解码时任务直接进入死信
+func Handle[T any](m *Manager, jobType string, fn func(ctx context.Context, payload T) error, opts ...HandlerOption) {
+	m.Register(jobType, func(ctx context.Context, job *Job) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return Permanent(fmt.Errorf("decode payload: %w", err))
+		}
+		return fn(ctx, payload)
+	}, opts...)
+}
+
+// enqueueOptions 入队选项
+type enqueueOptions struct {
+	queue       string
+	runAt       time.Time
+	maxAttempts int
+	uniqueKey   string
+}
+
+// EnqueueOption 入队时的选项
+type EnqueueOption func(*enqueueOptions)
+
+// Delay 延迟 d 后执行
+func Delay(d time.Duration) EnqueueOption {
+	return func(o *enqueueOptions) { o.runAt = time.Now().Add(d) }
+}
+
+// At 在指定时间执行
+func At(t time.Time) EnqueueOption {
+	return func(o *enqueueOptions) { o.runAt = t }
+}
+
+// Queue 放入指定队列，覆盖处理函数注册时的队列
+func Queue(queue string) EnqueueOption {
+	return func(o *enqueueOptions) { o.queue = queue }
+}
+
+// MaxAttempts 指定本任务的最大执行次数
+func MaxAttempts(n int) EnqueueOption {
+	return func(o *enqueueOptions) { o.maxAttempts = n }
+}
+
+// UniqueKey 去重键：存储中已有相同键的任务时 Enqueue 返回 ErrDuplicate
+func UniqueKey(key string) EnqueueOption {
+	return func(o *enqueueOptions) { o.uniqueKey = key }
+}
+
+// Enqueue 把任务写入存储，payload 以 JSON 编码
+// 使用数据库存储时，在 TxManager.InTx 中调用会与业务数据在同一事务中提交
+func (m *Manager) Enqueue(ctx context.Context, jobType string, payload any, opts ...EnqueueOption) (*Job, error) {
+	m.mu.RLock()
+	h, ok := m.handlers[jobType]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, jobType)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode payload of job %s: %w", jobType, err)
+	}
+	now := time.Now()
+	o := enqueueOptions{queue: h.queue, runAt: now, maxAttempts: h.maxAttempts}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	job := &Job{
+		Queue:       o.queue,
+		Type:        jobType,
+		Payload:     data,
+		Status:      StatusQueued,
+		MaxAttempts: o.maxAttempts,
+		RunAt:       o.runAt.UTC(),
+		UniqueKey:   o.uniqueKey,
+		CreatedAt:   now.UTC(),
+		UpdatedAt:   now.UTC(),
+	}
+	if err := m.store.Enqueue(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Get 按 ID 获取任务
+func (m *Manager) Get(ctx context.Context, id int64) (*Job, error) {
+	return m.store.Get(ctx, id)
+}
+
+// List 按 ID 倒序列出任务
+func (m *Manager) List(ctx context.Context, f Filter) ([]*Job, error) {
+	return m.store.List(ctx, f)
+}
+
+// Retry 把死信、已取消或已成功的任务重新排队并立即执行
+func (m *Manager) Retry(ctx context.Context, id int64) (*Job, error) {
+	return m.store.Retry(ctx, id, time.Now().UTC())
+}
+
+// Cancel 取消排队中的任务
+func (m *Manager) Cancel(ctx context.Context, id int64) (*Job, error) {
+	return m.store.Cancel(ctx, id, time.Now().UTC())
+}
+
+// Start 为每个队列启动轮询循环与 worker，并启动定时调度与过期任务清理
+// jobs.enabled 为 false 时什么也不做
+func (m *Manager) Start() {
+	if !m.cfg.Enabled {
+		close(m.done)
+		return
+	}
+
+	// Stop 超时后才取消执行中的任务，正常关闭时等待它们完成
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	m.mu.RLock()
+	queues := m.queues()
+	for _, s := range m.schedules {
+		if _, ok := m.handlers[s.jobType]; !ok {
+			slog.Warn("No handler registered for scheduled job", "schedule", s.name, "type", s.jobType)
+		}
+	}
+	m.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for queue, lease := range queues {
+		concurrency := m.cfg.GetConcurrency(queue)
+		tasks := make(chan *Job)
+		var workers sync.WaitGroup
+		for range concurrency {
+			workers.Add(1)
+			go m.worker(ctx, tasks, &workers)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.poll(ctx, queue, concurrency, lease, tasks)
+			// 轮询循环退出后关闭任务通道，worker 执行完手头的任务后退出
+			close(tasks)
+			workers.Wait()
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		m.runSchedules(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		m.purgeLoop()
+	}()
+	go func() {
+		wg.Wait()
+		close(m.done)
+	}()
+	slog.Info("Job manager started", "queues", slices.Sorted(maps.Keys(queues)), "schedules", len(m.schedules))
+}
+
+// Stop 停止领取新任务与定时调度，等待执行中的任务完成
+// ctx 超时后取消执行中的任务，它们重新排队，由下次启动（或其他实例）继续执行
+func (m *Manager) Stop(ctx context.Context) error {
+	select {
+	case <-m.stop:
+	default:
+		close(m.stop)
+	}
+	select {
+	case <-m.done:
+		return nil
+	case <-ctx.Done():
+		if m.cancel != nil {
+			m.cancel()
+		}
+		return ctx.Err()
+	}
+}
+
+// queues 返回需要启动 worker 的队列及其租约时长：jobs.queues 中配置的队列与处理函数使用的队列
+// 任务在通道中最多等待一轮执行，租约需要覆盖它和本次执行，取队列中最长超时的两倍再加一分钟
+func (m *Manager) queues() map[string]time.Duration {
+	queues := make(map[string]time.Duration)
+	for name := range m.cfg.Queues {
+		queues[name] = 2*m.cfg.GetTimeout() + time.Minute
+	}
+	for _, h := range m.handlers {
+		queues[h.queue] = max(queues[h.queue], 2*h.timeout+time.Minute)
+	}
+	for _, s := range m.schedules {
+		if s.queue != "" {
+			queues[s.queue] = max(queues[s.queue], 2*m.cfg.GetTimeout()+time.Minute)
+		}
+	}
+	return queues
+}
+
+// poll 队列的轮询循环：每次最多领取并发数个任务，领满时立即继续，否则等待一个轮询间隔
+func (m *Manager) poll(ctx context.Context, queue string, limit int, lease time.Duration, tasks chan<- *Job) {
+	ticker := time.NewTicker(m.cfg.GetPollInterval())
+	defer ticker.Stop()
+
+	for {
+		jobs, err := m.store.Claim(ctx, queue, time.Now().UTC(), limit, lease)
+		if err != nil {
+			slog.Error("Failed to claim jobs", "queue", queue, "error", err)
+		}
+		for i, job := range jobs {
+			select {
+			case tasks <- job:
+			case <-m.stop:
+				// 已领取但未开始的任务立即放回队列，不必等待租约到期
+				m.release(jobs[i:])
+				return
+			}
+		}
+		if len(jobs) >= limit {
+			select {
+			case <-m.stop:
+				return
+			default:
+				continue
+			}
+		}
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// release 把已领取但未执行的任务放回队列（领取时增加的执行次数不会撤销）
+func (m *Manager) release(jobs []*Job) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	for _, job := range jobs {
+		if err := m.store.Fail(ctx, job.ID, job.Attempts, now, "released on shutdown", now); err != nil {
+			slog.Error("Failed to release job", "job_id", job.ID, "error", err)
+		}
+	}
+}
+
+// worker 从任务通道取出任务执行，直到通道关闭
+func (m *Manager) worker(ctx context.Context, tasks <-chan *Job, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for job := range tasks {
+		m.run(ctx, job)
+	}
+}
+
+// run 执行一个任务并记录结果
+func (m *Manager) run(ctx context.Context, job *Job) {
+	m.mu.RLock()
+	h, ok := m.handlers[job.Type]
+	m.mu.RUnlock()
+
+	start := time.Now()
+	var err error
+	if ok {
+		jobCtx, cancel := context.WithTimeout(ctx, h.timeout)
+		err = call(jobCtx, h.fn, job)
+		cancel()
+	} else {
+		// 可能是由新版本入队、旧版本实例领取，按普通失败重试，等待能处理它的实例
+		err = fmt.Errorf("%w: %s", ErrUnknownType, job.Type)
+	}
+
+	// 记录结果使用独立的 context：即使 Stop 已超时，也要尽量把结果写回，减少重复执行
+	recordCtx := context.WithoutCancel(ctx)
+	now := time.Now().UTC()
+	logAttrs := []any{"job_id", job.ID, "type", job.Type, "queue", job.Queue, "attempt", job.Attempts, "duration", time.Since(start)}
+	if err == nil {
+		if err := m.store.Complete(recordCtx, job.ID, job.Attempts, now); err != nil {
+			slog.Error("Failed to complete job", "job_id", job.ID, "error", err)
+		}
+		slog.Debug("Job succeeded", logAttrs...)
+		return
+	}
+
+	reason := err.Error()
+	if len(reason) > maxErrorLen {
+		reason = reason[:maxErrorLen]
+	}
+	var next time.Time
+	var permanent *permanentError
+	switch {
+	case ctx.Err() != nil:
+		// 因关闭而中断，立即重新排队
+		next = now
+		slog.Warn("Job interrupted by shutdown, requeued", append(logAttrs, "error", err)...)
+	case errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts:
+		slog.Error("Job failed, moved to dead letter", append(logAttrs, "error", err)...)
+	default:
+		next = now.Add(m.backoff(job.Attempts))
+		slog.Warn("Job failed, will retry", append(logAttrs, "retry_at", next, "error", err)...)
+	}
+	if err := m.store.Fail(recordCtx, job.ID, job.Attempts, next, reason, now); err != nil {
+		slog.Error("Failed to record job failure", "job_id", job.ID, "error", err)
+	}
+}
+
+// call 调用处理函数，panic 视为执行失败
+func call(ctx context.Context, fn HandlerFunc, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Job handler panicked", "job_id", job.ID, "type", job.Type, "panic", r, "stack", string(debug.Stack()))
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx, job)
+}
+
+// purgeLoop 定期删除超过保留期的已结束任务
+func (m *Manager) purgeLoop() {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			before := time.Now().Add(-m.cfg.GetRetention()).UTC()
+			n, err := m.store.Purge(context.Background(), before)
+			if err != nil {
+				slog.Error("Failed to purge finished jobs", "error", err)
+			} else if n > 0 {
+				slog.Info("Purged finished jobs", "count", n)
+			}
+		}
+	}
+}
+
+// backoff 第 attempts 次失败后的等待时间：指数增长、有上限，并加入 ±20% 的抖动
+func (m *Manager) backoff(attempts int) time.Duration {
+	wait := m.cfg.GetBackoff()
+	for i := 1; i < attempts && wait < m.cfg.GetMaxBackoff(); i++ {
+		wait *= 2
+	}
+	wait = min(wait, m.cfg.GetMaxBackoff())
+	jitter := 0.8 + 0.4*rand.Float64()
+	return time.Duration(float64(wait) * jitter)
+}
@@ -0,0 +1,286 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api-template/internal/conf"
+)
+
+// newTestManager 创建使用内存存储、快速轮询与退避的 Manager
+func newTestManager(t *testing.T) (*Manager, *MemoryStore) {
+	t.Helper()
+	store := NewMemoryStore()
+	m, err := NewManager(&conf.Config{Jobs: conf.JobsConfig{
+		Enabled:      true,
+		PollInterval: 5 * time.Millisecond,
+		MaxAttempts:  3,
+		Backoff:      time.Millisecond,
+		MaxBackoff:   5 * time.Millisecond,
+	}}, store)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	return m, store
+}
+
+// startManager 启动 Manager，测试结束时停止
+func startManager(t *testing.T, m *Manager) {
+	t.Helper()
+	m.Start()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := m.Stop(ctx); err != nil {
+			t.Errorf("Stop: %v", err)
+		}
+	})
+}
+
+// waitStatus 等待任务进入指定状态
+func waitStatus(t *testing.T, m *Manager, id int64, want Status) *Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := m.Get(context.Background(), id)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if job.Status == want {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %d status = %s after %d attempts, want %s", id, job.Status, job.Attempts, want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+type greetPayload struct {
+	Name string `json:"name"`
+}
+
+func TestManagerRunsJob(t *testing.T) {
+	m, _ := newTestManager(t)
+	got := make(chan string, 1)
+	Handle(m, "greet", func(ctx context.Context, p greetPayload) error {
+		got <- p.Name
+		return nil
+	})
+	startManager(t, m)
+
+	job, err := m.Enqueue(context.Background(), "greet", greetPayload{Name: "gopher"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	select {
+	case name := <-got:
+		if name != "gopher" {
+			t.Errorf("payload name = %q, want gopher", name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler was not called")
+	}
+	done := waitStatus(t, m, job.ID, StatusSucceeded)
+	if done.Attempts != 1 || done.FinishedAt == nil {
+		t.Errorf("job = {attempts:%d finished:%v}, want 1 attempt and finished", done.Attempts, done.FinishedAt)
+	}
+}
+
+func TestManagerFailures(t *testing.T) {
+	tests := []struct {
+		name         string
+		fn           HandlerFunc
+		wantAttempts int
+		wantError    string
+	}{
+		{
+			name:         "retries until max attempts",
+			fn:           func(context.Context, *Job) error { return errors.New("upstream unavailable") },
+			wantAttempts: 3,
+			wantError:    "upstream unavailable",
+		},
+		{
+			name:         "permanent error skips retries",
+			fn:           func(context.Context, *Job) error { return Permanent(errors.New("bad input")) },
+			wantAttempts: 1,
+			wantError:    "bad input",
+		},
+		{
+			name:         "panic counts as failure",
+			fn:           func(context.Context, *Job) error { panic("nil map") },
+			wantAttempts: 3,
+			wantError:    "panic: nil map",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestManager(t)
+			var calls atomic.Int32
+			m.Register("flaky", func(ctx context.Context, job *Job) error {
+				calls.Add(1)
+				return tt.fn(ctx, job)
+			})
+			startManager(t, m)
+
+			job, err := m.Enqueue(context.Background(), "flaky", struct{}{})
+			if err != nil {
+				t.Fatalf("Enqueue: %v", err)
+			}
+			dead := waitStatus(t, m, job.ID, StatusDead)
+			if dead.Attempts != tt.wantAttempts || int(calls.Load()) != tt.wantAttempts {
+				t.Errorf("attempts = %d (calls %d), want %d", dead.Attempts, calls.Load(), tt.wantAttempts)
+			}
+			if !strings.Contains(dead.LastError, tt.wantError) {
+				t.Errorf("last error = %q, want it to contain %q", dead.LastError, tt.wantError)
+			}
+
+			// 死信可以手动重试，执行次数重新计算
+			retried, err := m.Retry(context.Background(), job.ID)
+			if err != nil {
+				t.Fatalf("Retry: %v", err)
+			}
+			if retried.Status != StatusQueued || retried.Attempts != 0 {
+				t.Errorf("retried job = {status:%s attempts:%d}, want queued/0", retried.Status, retried.Attempts)
+			}
+		})
+	}
+}
+
+func TestManagerRetrySucceeds(t *testing.T) {
+	m, _ := newTestManager(t)
+	var calls atomic.Int32
+	m.Register("eventually", func(context.Context, *Job) error {
+		if calls.Add(1) < 2 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+	startManager(t, m)
+
+	job, err := m.Enqueue(context.Background(), "eventually", struct{}{})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	done := waitStatus(t, m, job.ID, StatusSucceeded)
+	if done.Attempts != 2 || done.LastError != "not yet" {
+		t.Errorf("job = {attempts:%d error:%q}, want 2 attempts with the first error kept", done.Attempts, done.LastError)
+	}
+}
+
+func TestManagerEnqueue(t *testing.T) {
+	m, _ := newTestManager(t)
+	m.Register("noop", func(context.Context, *Job) error { return nil }, WithQueue("mail"), WithMaxAttempts(5))
+	ctx := context.Background()
+
+	if _, err := m.Enqueue(ctx, "missing", nil); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("Enqueue(unregistered) = %v, want ErrUnknownType", err)
+	}
+
+	job, err := m.Enqueue(ctx, "noop", nil)
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if job.Queue != "mail" || job.MaxAttempts != 5 || job.Status != StatusQueued {
+		t.Errorf("job = {queue:%s max:%d status:%s}, want handler defaults mail/5/queued", job.Queue, job.MaxAttempts, job.Status)
+	}
+
+	runAt := time.Now().Add(time.Hour)
+	job, err = m.Enqueue(ctx, "noop", nil, Queue("bulk"), MaxAttempts(1), At(runAt), UniqueKey("k"))
+	if err != nil {
+		t.Fatalf("Enqueue with options: %v", err)
+	}
+	if job.Queue != "bulk" || job.MaxAttempts != 1 || !job.RunAt.Equal(runAt.UTC()) {
+		t.Errorf("job = {queue:%s max:%d run_at:%v}, want bulk/1/%v", job.Queue, job.MaxAttempts, job.RunAt, runAt.UTC())
+	}
+	if _, err := m.Enqueue(ctx, "noop", nil, UniqueKey("k")); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Enqueue(duplicate key) = %v, want ErrDuplicate", err)
+	}
+
+	// 排队中的任务可以取消，取消后不能再次取消
+	if _, err := m.Cancel(ctx, job.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if _, err := m.Cancel(ctx, job.ID); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("second Cancel = %v, want ErrInvalidState", err)
+	}
+}
+
+func TestManagerDelayedJobWaits(t *testing.T) {
+	m, _ := newTestManager(t)
+	var calls atomic.Int32
+	m.Register("later", func(context.Context, *Job) error {
+		calls.Add(1)
+		return nil
+	})
+	startManager(t, m)
+
+	job, err := m.Enqueue(context.Background(), "later", nil, Delay(time.Hour))
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if calls.Load() != 0 {
+		t.Fatal("delayed job ran before its run time")
+	}
+	if got, _ := m.Get(context.Background(), job.ID); got.Status != StatusQueued {
+		t.Errorf("status = %s, want queued", got.Status)
+	}
+}
+
+// TestManagerIgnoresStaleResult 租约过期后被重新领取的任务，原 worker 迟到的结果不会覆盖新状态
+func TestManagerIgnoresStaleResult(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now().UTC()
+	job := &Job{Queue: "default", Type: "slow", Status: StatusQueued, MaxAttempts: 3, RunAt: now}
+	if err := store.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	stale, _ := store.Claim(ctx, "default", now, 1, time.Second)
+	fresh, _ := store.Claim(ctx, "default", now.Add(2*time.Second), 1, time.Second)
+	if len(stale) != 1 || len(fresh) != 1 {
+		t.Fatalf("claims = %d, %d; want 1, 1", len(stale), len(fresh))
+	}
+
+	m, err := NewManager(&conf.Config{}, store)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	m.Register("slow", func(context.Context, *Job) error { return errors.New("stale failure") })
+	// 原 worker 以第一次领取的执行次数上报失败，应被忽略
+	m.run(ctx, stale[0])
+
+	got, _ := store.Get(ctx, job.ID)
+	if got.Status != StatusRunning || got.Attempts != 2 || got.LastError != "" {
+		t.Fatalf("job = {status:%s attempts:%d error:%q}, want still running attempt 2", got.Status, got.Attempts, got.LastError)
+	}
+}
+
+func TestManagerBackoff(t *testing.T) {
+	m, err := NewManager(&conf.Config{Jobs: conf.JobsConfig{Backoff: time.Second, MaxBackoff: 5 * time.Second}}, NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{10, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		got := m.backoff(tt.attempts)
+		if low, high := tt.want*8/10, tt.want*12/10; got < low || got > high {
+			t.Errorf("backoff(%d) = %v, want within [%v, %v]", tt.attempts, got, low, high)
+		}
+	}
+}
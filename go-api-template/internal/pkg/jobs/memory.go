@@ -0,0 +1,238 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore 进程内的任务存储，进程退出后任务丢失，只适用于开发与测试
+type MemoryStore struct {
+	mu     sync.Mutex
+	jobs   map[int64]*Job
+	unique map[string]int64
+	nextID int64
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs:   make(map[int64]*Job),
+		unique: make(map[string]int64),
+	}
+}
+
+// Enqueue 实现 Store.Enqueue
+func (s *MemoryStore) Enqueue(_ context.Context, job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job.UniqueKey != "" {
+		if _, ok := s.unique[job.UniqueKey]; ok {
+			return fmt.Errorf("%w: %s", ErrDuplicate, job.UniqueKey)
+		}
+	}
+	s.nextID++
+	job.ID = s.nextID
+	s.jobs[job.ID] = cloneJob(job)
+	if job.UniqueKey != "" {
+		s.unique[job.UniqueKey] = job.ID
+	}
+	return nil
+}
+
+// Claim 实现 Store.Claim，按执行时间和 ID 顺序领取
+func (s *MemoryStore) Claim(_ context.Context, queue string, now time.Time, limit int, lease time.Duration) ([]*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*Job
+	for _, job := range s.jobs {
+		if job.Queue != queue || !claimable(job, now) {
+			continue
+		}
+		if job.LeaseExhausted(now) {
+			job.LastError = ErrLeaseExpired.Error()
+			finish(job, StatusDead, now)
+			continue
+		}
+		due = append(due, job)
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].RunAt.Equal(due[j].RunAt) {
+			return due[i].RunAt.Before(due[j].RunAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	out := make([]*Job, 0, len(due))
+	for _, job := range due {
+		job.Status = StatusRunning
+		job.Attempts++
+		job.RunAt = now.Add(lease)
+		job.UpdatedAt = now
+		out = append(out, cloneJob(job))
+	}
+	return out, nil
+}
+
+// Complete 实现 Store.Complete
+func (s *MemoryStore) Complete(_ context.Context, id int64, attempt int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok || !leased(job, attempt) {
+		return nil
+	}
+	finish(job, StatusSucceeded, at)
+	return nil
+}
+
+// Fail 实现 Store.Fail
+func (s *MemoryStore) Fail(_ context.Context, id int64, attempt int, next time.Time, reason string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok || !leased(job, attempt) {
+		return nil
+	}
+	job.LastError = reason
+	if next.IsZero() {
+		finish(job, StatusDead, at)
+		return nil
+	}
+	job.Status = StatusQueued
+	job.RunAt = next
+	job.UpdatedAt = at
+	return nil
+}
+
+// Get 实现 Store.Get
+func (s *MemoryStore) Get(_ context.Context, id int64) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("job %d: %w", id, ErrNotFound)
+	}
+	return cloneJob(job), nil
+}
+
+// List 实现 Store.List
+func (s *MemoryStore) List(_ context.Context, f Filter) ([]*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []*Job
+	for _, job := range s.jobs {
+		if f.Match(job) {
+			out = append(out, cloneJob(job))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID > out[j].ID })
+	if f.Limit > 0 && len(out) > f.Limit {
+		out = out[:f.Limit]
+	}
+	return out, nil
+}
+
+// Retry 实现 Store.Retry
+func (s *MemoryStore) Retry(_ context.Context, id int64, now time.Time) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("job %d: %w", id, ErrNotFound)
+	}
+	if !retryable(job.Status) {
+		return nil, fmt.Errorf("job %d is %s: %w", id, job.Status, ErrInvalidState)
+	}
+	job.Status = StatusQueued
+	job.Attempts = 0
+	job.RunAt = now
+	job.UpdatedAt = now
+	job.FinishedAt = nil
+	return cloneJob(job), nil
+}
+
+// Cancel 实现 Store.Cancel
+func (s *MemoryStore) Cancel(_ context.Context, id int64, now time.Time) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("job %d: %w", id, ErrNotFound)
+	}
+	if job.Status != StatusQueued {
+		return nil, fmt.Errorf("job %d is %s: %w", id, job.Status, ErrInvalidState)
+	}
+	finish(job, StatusCancelled, now)
+	return cloneJob(job), nil
+}
+
+// Purge 实现 Store.Purge
+func (s *MemoryStore) Purge(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for id, job := range s.jobs {
+		if purgeable(job.Status) && job.FinishedAt != nil && job.FinishedAt.Before(before) {
+			delete(s.jobs, id)
+			if job.UniqueKey != "" {
+				delete(s.unique, job.UniqueKey)
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+// claimable 判断任务能否被领取：排队中且已到执行时间，或执行中但租约已过期
+func claimable(job *Job, now time.Time) bool {
+	return (job.Status == StatusQueued || job.Status == StatusRunning) && !job.RunAt.After(now)
+}
+
+// leased 判断任务是否仍处于执行次数为 attempt 的那次领取中
+func leased(job *Job, attempt int) bool {
+	return job.Status == StatusRunning && job.Attempts == attempt
+}
+
+// retryable 判断处于该状态的任务能否重新排队
+func retryable(status Status) bool {
+	return status == StatusDead || status == StatusCancelled || status == StatusSucceeded
+}
+
+// purgeable 判断处于该状态的任务过了保留期后能否清理，死信任务保留到人工处理
+func purgeable(status Status) bool {
+	return status == StatusSucceeded || status == StatusCancelled
+}
+
+// finish 把任务置为终态
+func finish(job *Job, status Status, at time.Time) {
+	job.Status = status
+	job.UpdatedAt = at
+	job.FinishedAt = &at
+}
+
+// cloneJob 复制任务，避免调用方修改存储中的数据
+func cloneJob(job *Job) *Job {
+	clone := *job
+	clone.Payload = slices.Clone(job.Payload)
+	if job.FinishedAt != nil {
+		at := *job.FinishedAt
+		clone.FinishedAt = &at
+	}
+	return &clone
+}
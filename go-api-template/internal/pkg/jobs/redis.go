@@ -0,0 +1,426 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"go-api-template/internal/conf"
+)
+
+// Redis 中的键：
+//
+//	jobs:seq            任务 ID 序列
+//	jobs:job:<id>       任务（hash）
+//	jobs:queue:<queue>  队列中排队与执行中的任务（zset，score 为 run_at 毫秒）
+//	jobs:ids            全部任务（zset，score 为 ID），用于列表
+//	jobs:finished       可清理的已结束任务（zset，score 为结束时间毫秒）
+//	jobs:unique:<key>   UniqueKey 到任务 ID
+//
+// 状态变更都在 Lua 脚本中完成，保证多个进程共享同一个 Redis 时的原子性；
+// 脚本会访问由任务 ID 拼出的键，因此不支持 Redis Cluster
+const (
+	redisKeyPrefix = "jobs:"
+	redisSeqKey    = redisKeyPrefix + "seq"
+	redisJobPrefix = redisKeyPrefix + "job:"
+	redisQueuePfx  = redisKeyPrefix + "queue:"
+	redisIDsKey    = redisKeyPrefix + "ids"
+	redisFinished  = redisKeyPrefix + "finished"
+	redisUniquePfx = redisKeyPrefix + "unique:"
+
+	// redisPageSize 列表与清理每批处理的任务数
+	redisPageSize = 500
+)
+
+// enqueueScript 检查 UniqueKey、分配 ID 并写入任务，已存在相同 UniqueKey 时返回 0
+var enqueueScript = redis.NewScript(`
+if ARGV[8] ~= '' and redis.call('EXISTS', KEYS[4]) == 1 then
+  return 0
+end
+local id = redis.call('INCR', KEYS[1])
+redis.call('HSET', ARGV[1] .. id,
+  'queue', ARGV[2], 'type', ARGV[3], 'payload', ARGV[4], 'status', 'queued',
+  'attempts', 0, 'max_attempts', ARGV[5], 'run_at', ARGV[6], 'last_error', '',
+  'unique_key', ARGV[8], 'created_at', ARGV[7], 'updated_at', ARGV[7], 'finished_at', '')
+redis.call('ZADD', KEYS[3], ARGV[6], id)
+redis.call('ZADD', KEYS[2], id, id)
+if ARGV[8] ~= '' then
+  redis.call('SET', KEYS[4], id)
+end
+return id
+`)
+
+// claimScript 领取到期任务：执行次数加一，并把 score 推迟到租约到期时间
+// 租约已过期且执行次数已达上限的执行中任务移出队列并进入死信，失败原因为 ARGV[5]
+var claimScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[2], 'LIMIT', 0, ARGV[3])
+local claimed = {}
+for _, id in ipairs(ids) do
+  local key = ARGV[1] .. id
+  local job = redis.call('HMGET', key, 'status', 'attempts', 'max_attempts')
+  if job[1] == 'running' and tonumber(job[2]) >= tonumber(job[3]) then
+    redis.call('HSET', key, 'status', 'dead', 'last_error', ARGV[5], 'updated_at', ARGV[2], 'finished_at', ARGV[2])
+    redis.call('ZREM', KEYS[1], id)
+  else
+    redis.call('HINCRBY', key, 'attempts', 1)
+    redis.call('HSET', key, 'status', 'running', 'run_at', ARGV[4], 'updated_at', ARGV[2])
+    redis.call('ZADD', KEYS[1], ARGV[4], id)
+    table.insert(claimed, id)
+  end
+end
+return claimed
+`)
+
+// transitionScript 在任务处于 ARGV[1] 列出的状态之一时把它置为 ARGV[2]
+// ARGV[4] 为空表示进入终态（移出队列），否则在该时间重新排队
+// ARGV[10] 非空时还要求执行次数等于它（租约凭证）
+// 返回 1 成功，0 状态不允许，-1 任务不存在
+var transitionScript = redis.NewScript(`
+local status = redis.call('HGET', KEYS[1], 'status')
+if not status then
+  return -1
+end
+if not string.find(ARGV[1], ',' .. status .. ',', 1, true) then
+  return 0
+end
+if ARGV[10] ~= '' and redis.call('HGET', KEYS[1], 'attempts') ~= ARGV[10] then
+  return 0
+end
+local queue = ARGV[8] .. redis.call('HGET', KEYS[1], 'queue')
+redis.call('HSET', KEYS[1], 'status', ARGV[2], 'updated_at', ARGV[3])
+if ARGV[5] == '1' then
+  redis.call('HSET', KEYS[1], 'last_error', ARGV[6])
+end
+if ARGV[7] == '1' then
+  redis.call('HSET', KEYS[1], 'attempts', 0)
+end
+if ARGV[4] == '' then
+  redis.call('ZREM', queue, ARGV[9])
+  redis.call('HSET', KEYS[1], 'finished_at', ARGV[3])
+  if ARGV[2] == 'succeeded' or ARGV[2] == 'cancelled' then
+    redis.call('ZADD', KEYS[2], ARGV[3], ARGV[9])
+  end
+else
+  redis.call('HSET', KEYS[1], 'run_at', ARGV[4], 'finished_at', '')
+  redis.call('ZADD', queue, ARGV[4], ARGV[9])
+  redis.call('ZREM', KEYS[2], ARGV[9])
+end
+return 1
+`)
+
+// purgeScript 删除一批在 ARGV[3] 之前结束的任务及其 UniqueKey，返回删除的数量
+var purgeScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[3], 'LIMIT', 0, ARGV[4])
+for _, id in ipairs(ids) do
+  local key = ARGV[1] .. id
+  local unique = redis.call('HGET', key, 'unique_key')
+  if unique and unique ~= '' then
+    redis.call('DEL', ARGV[2] .. unique)
+  end
+  redis.call('DEL', key)
+  redis.call('ZREM', KEYS[1], id)
+  redis.call('ZREM', KEYS[2], id)
+end
+return #ids
+`)
+
+// RedisStore 基于 Redis 的任务存储，多个实例共享同一个队列
+type RedisStore struct {
+	client redis.Cmdable
+}
+
+// NewRedisStore 创建 Redis 存储
+func NewRedisStore(client redis.Cmdable) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// newRedisStore 按 redis 配置连接 Redis 并创建存储
+func newRedisStore(cfg *conf.Config) (*RedisStore, func(), error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Addr(),
+		Password: cfg.Redis.Password.Reveal(),
+		DB:       cfg.Redis.DB,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, nil, fmt.Errorf("failed to connect redis %s: %w", cfg.Redis.Addr(), err)
+	}
+
+	cleanup := func() {
+		if err := client.Close(); err != nil {
+			slog.Error("Failed to close redis client", "error", err)
+		}
+	}
+	return NewRedisStore(client), cleanup, nil
+}
+
+// Enqueue 实现 Store.Enqueue
+func (s *RedisStore) Enqueue(ctx context.Context, job *Job) error {
+	keys := []string{redisSeqKey, redisIDsKey, redisQueuePfx + job.Queue, redisUniquePfx + job.UniqueKey}
+	id, err := enqueueScript.Run(ctx, s.client, keys,
+		redisJobPrefix, job.Queue, job.Type, string(job.Payload), job.MaxAttempts,
+		job.RunAt.UnixMilli(), job.CreatedAt.UnixMilli(), job.UniqueKey).Int64()
+	if err != nil {
+		return err
+	}
+	if id == 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicate, job.UniqueKey)
+	}
+	job.ID = id
+	return nil
+}
+
+// Claim 实现 Store.Claim
+func (s *RedisStore) Claim(ctx context.Context, queue string, now time.Time, limit int, lease time.Duration) ([]*Job, error) {
+	ids, err := claimScript.Run(ctx, s.client, []string{redisQueuePfx + queue},
+		redisJobPrefix, now.UnixMilli(), limit, now.Add(lease).UnixMilli(), ErrLeaseExpired.Error()).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+	return s.load(ctx, ids)
+}
+
+// Complete 实现 Store.Complete
+func (s *RedisStore) Complete(ctx context.Context, id int64, attempt int, at time.Time) error {
+	_, err := s.transition(ctx, id, redisTransition{
+		from:    []Status{StatusRunning},
+		to:      StatusSucceeded,
+		at:      at,
+		attempt: attempt,
+	})
+	return err
+}
+
+// Fail 实现 Store.Fail
+func (s *RedisStore) Fail(ctx context.Context, id int64, attempt int, next time.Time, reason string, at time.Time) error {
+	t := redisTransition{
+		from:     []Status{StatusRunning},
+		to:       StatusDead,
+		at:       at,
+		setError: true,
+		reason:   reason,
+		attempt:  attempt,
+	}
+	if !next.IsZero() {
+		t.to = StatusQueued
+		t.runAt = next
+	}
+	_, err := s.transition(ctx, id, t)
+	return err
+}
+
+// Get 实现 Store.Get
+func (s *RedisStore) Get(ctx context.Context, id int64) (*Job, error) {
+	fields, err := s.client.HGetAll(ctx, redisJobKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("job %d: %w", id, ErrNotFound)
+	}
+	return parseRedisJob(id, fields)
+}
+
+// List 实现 Store.List，按 ID 倒序分批读取，直到凑满 Limit 条
+func (s *RedisStore) List(ctx context.Context, f Filter) ([]*Job, error) {
+	var out []*Job
+	for start := int64(0); ; start += redisPageSize {
+		ids, err := s.client.ZRevRange(ctx, redisIDsKey, start, start+redisPageSize-1).Result()
+		if err != nil {
+			return nil, err
+		}
+		jobs, err := s.load(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range jobs {
+			if !f.Match(job) {
+				continue
+			}
+			out = append(out, job)
+			if f.Limit > 0 && len(out) >= f.Limit {
+				return out, nil
+			}
+		}
+		if len(ids) < redisPageSize {
+			return out, nil
+		}
+	}
+}
+
+// Retry 实现 Store.Retry
+func (s *RedisStore) Retry(ctx context.Context, id int64, now time.Time) (*Job, error) {
+	return s.transitionAndGet(ctx, id, redisTransition{
+		from:          []Status{StatusDead, StatusCancelled, StatusSucceeded},
+		to:            StatusQueued,
+		at:            now,
+		runAt:         now,
+		resetAttempts: true,
+	})
+}
+
+// Cancel 实现 Store.Cancel
+func (s *RedisStore) Cancel(ctx context.Context, id int64, now time.Time) (*Job, error) {
+	return s.transitionAndGet(ctx, id, redisTransition{
+		from: []Status{StatusQueued},
+		to:   StatusCancelled,
+		at:   now,
+	})
+}
+
+// Purge 实现 Store.Purge
+func (s *RedisStore) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	for {
+		n, err := purgeScript.Run(ctx, s.client, []string{redisFinished, redisIDsKey},
+			redisJobPrefix, redisUniquePfx, before.UnixMilli(), redisPageSize).Int64()
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if n < redisPageSize {
+			return total, nil
+		}
+	}
+}
+
+// redisTransition 一次状态变更的参数
+type redisTransition struct {
+	from []Status
+	to   Status
+	at   time.Time
+	// runAt 零值表示进入终态，否则在该时间重新排队
+	runAt         time.Time
+	setError      bool
+	reason        string
+	resetAttempts bool
+	// attempt 大于 0 时要求任务的执行次数等于它，即仍处于同一次领取
+	attempt int
+}
+
+// transition 执行状态变更，任务不存在时返回 ErrNotFound，状态不允许时返回 (false, nil)
+func (s *RedisStore) transition(ctx context.Context, id int64, t redisTransition) (bool, error) {
+	from := make([]string, len(t.from))
+	for i, status := range t.from {
+		from[i] = string(status)
+	}
+	runAt := ""
+	if !t.runAt.IsZero() {
+		runAt = strconv.FormatInt(t.runAt.UnixMilli(), 10)
+	}
+	attempt := ""
+	if t.attempt > 0 {
+		attempt = strconv.Itoa(t.attempt)
+	}
+	result, err := transitionScript.Run(ctx, s.client, []string{redisJobKey(id), redisFinished},
+		","+strings.Join(from, ",")+",", string(t.to), t.at.UnixMilli(), runAt,
+		redisFlag(t.setError), t.reason, redisFlag(t.resetAttempts), redisQueuePfx, id, attempt).Int64()
+	if err != nil {
+		return false, err
+	}
+	if result < 0 {
+		return false, fmt.Errorf("job %d: %w", id, ErrNotFound)
+	}
+	return result == 1, nil
+}
+
+// transitionAndGet 执行管理操作触发的状态变更并返回变更后的任务
+func (s *RedisStore) transitionAndGet(ctx context.Context, id int64, t redisTransition) (*Job, error) {
+	ok, err := s.transition(ctx, id, t)
+	if err != nil {
+		return nil, err
+	}
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("job %d is %s: %w", id, job.Status, ErrInvalidState)
+	}
+	return job, nil
+}
+
+// load 批量读取任务，已被清理的任务跳过
+func (s *RedisStore) load(ctx context.Context, ids []string) ([]*Job, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HGetAll(ctx, redisJobPrefix+id)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	out := make([]*Job, 0, len(ids))
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			continue
+		}
+		id, err := strconv.ParseInt(ids[i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("job id %q: %w", ids[i], err)
+		}
+		job, err := parseRedisJob(id, fields)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, job)
+	}
+	return out, nil
+}
+
+// parseRedisJob 把 hash 字段解析为任务
+func parseRedisJob(id int64, fields map[string]string) (*Job, error) {
+	job := &Job{
+		ID:        id,
+		Queue:     fields["queue"],
+		Type:      fields["type"],
+		Payload:   json.RawMessage(fields["payload"]),
+		Status:    Status(fields["status"]),
+		LastError: fields["last_error"],
+		UniqueKey: fields["unique_key"],
+	}
+	var err error
+	parseInt := func(name string) int64 {
+		v, e := strconv.ParseInt(fields[name], 10, 64)
+		if e != nil && err == nil {
+			err = fmt.Errorf("job %d field %s: %w", id, name, e)
+		}
+		return v
+	}
+	job.Attempts = int(parseInt("attempts"))
+	job.MaxAttempts = int(parseInt("max_attempts"))
+	job.RunAt = time.UnixMilli(parseInt("run_at"))
+	job.CreatedAt = time.UnixMilli(parseInt("created_at"))
+	job.UpdatedAt = time.UnixMilli(parseInt("updated_at"))
+	if fields["finished_at"] != "" {
+		at := time.UnixMilli(parseInt("finished_at"))
+		job.FinishedAt = &at
+	}
+	return job, err
+}
+
+// redisJobKey 任务 hash 的键
+func redisJobKey(id int64) string {
+	return redisJobPrefix + strconv.FormatInt(id, 10)
+}
+
+// redisFlag 把布尔值编码为脚本参数
+func redisFlag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,116 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// schedule 一个定时任务
+type schedule struct {
+	name    string
+	spec    cron.Schedule
+	jobType string
+	// queue 为空时使用处理函数注册时的队列
+	queue   string
+	payload json.RawMessage
+}
+
+// Schedule 按 cron 表达式（标准 5 段或 @daily 等写法）定时入队任务，需要在 Start 之前调用
+// 每个计划时间的任务以 "schedule:<name>:<计划时间>" 为 UniqueKey 入队，
+// 多个实例同时调度时只有一个能入队成功；进程停止期间错过的时间不会补跑
+func (m *Manager) Schedule(name, spec, jobType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode payload of schedule %s: %w", name, err)
+	}
+	return m.addSchedule(name, spec, jobType, "", data)
+}
+
+// addSchedule 解析 cron 表达式并保存定时任务
+func (m *Manager) addSchedule(name, spec, jobType, queue string, payload json.RawMessage) error {
+	parsed, err := cron.ParseStandard(spec)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, s := range m.schedules {
+		if s.name == name {
+			return fmt.Errorf("schedule %s already exists", name)
+		}
+	}
+	m.schedules = append(m.schedules, &schedule{
+		name:    name,
+		spec:    parsed,
+		jobType: jobType,
+		queue:   queue,
+		payload: payload,
+	})
+	return nil
+}
+
+// runSchedules 调度循环：睡眠到最近的计划时间，入队所有到期的定时任务
+func (m *Manager) runSchedules(ctx context.Context) {
+	m.mu.RLock()
+	schedules := m.schedules
+	m.mu.RUnlock()
+	if len(schedules) == 0 {
+		return
+	}
+
+	next := make([]time.Time, len(schedules))
+	now := time.Now()
+	for i, s := range schedules {
+		next[i] = s.spec.Next(now)
+	}
+	for {
+		earliest := next[0]
+		for _, t := range next[1:] {
+			if t.Before(earliest) {
+				earliest = t
+			}
+		}
+		timer := time.NewTimer(time.Until(earliest))
+		select {
+		case <-m.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		for i, s := range schedules {
+			if next[i].After(now) {
+				continue
+			}
+			m.fire(ctx, s, next[i])
+			next[i] = s.spec.Next(now)
+		}
+	}
+}
+
+// fire 入队定时任务在 slot 时刻的一次执行
+func (m *Manager) fire(ctx context.Context, s *schedule, slot time.Time) {
+	var opts []EnqueueOption
+	if s.queue != "" {
+		opts = append(opts, Queue(s.queue))
+	}
+	opts = append(opts, UniqueKey(fmt.Sprintf("schedule:%s:%d", s.name, slot.Unix())))
+
+	job, err := m.Enqueue(ctx, s.jobType, s.payload, opts...)
+	switch {
+	case errors.Is(err, ErrDuplicate):
+		// 其他实例已入队
+	case err != nil:
+		slog.Error("Failed to enqueue scheduled job", "schedule", s.name, "type", s.jobType, "error", err)
+	default:
+		slog.Info("Scheduled job enqueued", "schedule", s.name, "type", s.jobType, "job_id", job.ID)
+	}
+}
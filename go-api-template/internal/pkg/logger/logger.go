@@ -0,0 +1,76 @@
+// Package logger 基于标准库 log/slog 初始化全局日志
+// 日志级别保存在 slog.LevelVar 中，运行期可以动态调整（配置热加载使用）
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// level 全局日志级别，所有 Handler 共享
+var level = new(slog.LevelVar)
+
+// Setup 根据配置初始化全局日志
+// 同时接管标准库 log 的输出，已有的 log.Printf 调用会以 INFO 级别进入 slog
+func Setup(levelName, format string) error {
+	return SetupWriter(os.Stderr, levelName, format)
+}
+
+// SetupWriter 与 Setup 相同，但允许指定输出目标
+func SetupWriter(w io.Writer, levelName, format string) error {
+	if err := SetLevel(levelName); err != nil {
+		return err
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	case "", "text":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return fmt.Errorf("unknown log format %q", format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	// slog.SetDefault 会把 log 包的输出桥接到 slog，去掉 log 自带的时间前缀避免重复
+	log.SetFlags(0)
+	return nil
+}
+
+// SetLevel 动态修改日志级别
+func SetLevel(levelName string) error {
+	l, err := ParseLevel(levelName)
+	if err != nil {
+		return err
+	}
+	level.Set(l)
+	return nil
+}
+
+// Level 返回当前日志级别
+func Level() slog.Level {
+	return level.Level()
+}
+
+// ParseLevel 解析配置中的日志级别：debug | info | warn | error
+// 空字符串视为 info
+func ParseLevel(levelName string) (slog.Level, error) {
+	switch strings.ToLower(levelName) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q", levelName)
+	}
+}
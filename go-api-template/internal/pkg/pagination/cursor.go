@@ -0,0 +1,160 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/wire"
+
+	"go-api-template/internal/conf"
+)
+
+// ProviderSet 分页组件的依赖提供者集合
+var ProviderSet = wire.NewSet(NewCodec)
+
+// signatureSize 游标签名截取的字节数，足以防止伪造，同时让游标保持简短
+const signatureSize = 16
+
+// Codec 解析分页参数，签发与校验游标
+type Codec struct {
+	key []byte
+}
+
+// NewCodec 创建 Codec
+// 签名密钥由 JWT 密钥派生：不需要额外配置，且与 JWT 签名使用不同的密钥
+func NewCodec(cfg *conf.Config) (*Codec, error) {
+	if cfg.JWT.Secret.IsEmpty() {
+		return nil, errors.New("pagination: jwt.secret is required to sign cursors")
+	}
+	mac := hmac.New(sha256.New, []byte(cfg.JWT.Secret.Reveal()))
+	mac.Write([]byte("pagination-cursor"))
+	return &Codec{key: mac.Sum(nil)}, nil
+}
+
+// cursorPayload 游标的内容
+type cursorPayload struct {
+	// Digest 签发时排序与过滤条件的摘要
+	Digest string `json:"d"`
+	// Keys 上一页最后一条记录的排序键
+	Keys []string `json:"k"`
+}
+
+// Parse 按 spec 校验分页参数
+func (c *Codec) Parse(spec *Spec, p Params) (*Request, error) {
+	req := &Request{Limit: int(p.Limit), Offset: int(p.Offset)}
+	switch {
+	case req.Limit == 0:
+		req.Limit = spec.getDefaultLimit()
+	case req.Limit < 0 || req.Limit > spec.getMaxLimit():
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalid, spec.getMaxLimit())
+	}
+	if req.Offset < 0 || req.Offset > spec.getMaxOffset() {
+		return nil, fmt.Errorf("%w: offset must be between 0 and %d", ErrInvalid, spec.getMaxOffset())
+	}
+	if req.Offset > 0 && p.Cursor != "" {
+		return nil, fmt.Errorf("%w: cursor and offset cannot be used together", ErrInvalid)
+	}
+
+	var err error
+	if req.Sort, err = parseSort(spec, p.Sort); err != nil {
+		return nil, err
+	}
+	if req.Filters, err = parseFilter(spec, p.Filter); err != nil {
+		return nil, err
+	}
+	req.digest = digest(req)
+
+	if p.Cursor != "" {
+		if req.After, err = c.decode(spec, req, p.Cursor); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// Encode 签发指向 after 之后的游标，after 通常为 Page.Next；没有下一页时返回空字符串
+func (c *Codec) Encode(req *Request, after []any) string {
+	if len(after) == 0 {
+		return ""
+	}
+	payload := cursorPayload{Digest: req.digest, Keys: make([]string, len(after))}
+	for i, v := range after {
+		payload.Keys[i] = formatValue(v)
+	}
+	data, _ := json.Marshal(payload)
+	body := base64.RawURLEncoding.EncodeToString(data)
+	return body + "." + base64.RawURLEncoding.EncodeToString(c.sign(body))
+}
+
+// decode 校验游标签名，并按当前排序字段的类型还原排序键
+func (c *Codec) decode(spec *Spec, req *Request, cursor string) ([]any, error) {
+	invalid := fmt.Errorf("%w: malformed cursor", ErrInvalid)
+	body, sig, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return nil, invalid
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, c.sign(body)) {
+		return nil, invalid
+	}
+	data, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, invalid
+	}
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, invalid
+	}
+	// 游标只在签发时的排序与过滤条件下有意义，条件变化后应从第一页重新开始
+	if payload.Digest != req.digest || len(payload.Keys) != len(req.Sort) {
+		return nil, fmt.Errorf("%w: cursor does not match the current sort and filter", ErrInvalid)
+	}
+	after := make([]any, len(req.Sort))
+	for i, s := range req.Sort {
+		f, _ := spec.field(s.Field)
+		v, err := parseValue(f, payload.Keys[i])
+		if err != nil {
+			return nil, invalid
+		}
+		after[i] = v
+	}
+	return after, nil
+}
+
+// sign 计算游标签名
+func (c *Codec) sign(body string) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(body))
+	return mac.Sum(nil)[:signatureSize]
+}
+
+// digest 计算排序与过滤条件的摘要，只取决于解析后的条件，与参数中的空格等写法无关
+func digest(req *Request) string {
+	var b strings.Builder
+	for _, s := range req.Sort {
+		if s.Desc {
+			b.WriteByte('-')
+		}
+		b.WriteString(s.Field)
+		b.WriteByte(',')
+	}
+	b.WriteByte(';')
+	for _, f := range req.Filters {
+		b.WriteString(f.Field)
+		b.WriteString(string(f.Op))
+		for i, v := range f.Values {
+			if i > 0 {
+				b.WriteByte('|')
+			}
+			b.WriteString(formatValue(v))
+		}
+		b.WriteByte(',')
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return base64.RawURLEncoding.EncodeToString(sum[:8])
+}
@@ -0,0 +1,154 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/internal/conf"
+)
+
+func newTestCodec(t *testing.T, secret string) *Codec {
+	t.Helper()
+	codec, err := NewCodec(&conf.Config{JWT: conf.JWTConfig{Secret: conf.Secret(secret)}})
+	if err != nil {
+		t.Fatalf("NewCodec: %v", err)
+	}
+	return codec
+}
+
+func TestNewCodecRequiresSecret(t *testing.T) {
+	if _, err := NewCodec(&conf.Config{}); err == nil {
+		t.Fatal("NewCodec without jwt.secret succeeded, want error")
+	}
+}
+
+// testOrder 测试用的记录
+type testOrder struct {
+	id        int64
+	amount    int64
+	status    string
+	createdAt time.Time
+}
+
+func orderValue(o testOrder, field string) any {
+	switch field {
+	case "id":
+		return o.id
+	case "amount":
+		return o.amount
+	case "status":
+		return o.status
+	case "created_at":
+		return o.createdAt
+	}
+	return nil
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	codec := newTestCodec(t, "secret")
+	start := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+	var orders []testOrder
+	for i := range 7 {
+		// amount 有重复，需要 id 决定顺序
+		orders = append(orders, testOrder{id: int64(i + 1), amount: int64(i%3) * 100, status: "paid",
+			createdAt: start.Add(time.Duration(i) * time.Minute)})
+	}
+	orders = append(orders, testOrder{id: 8, amount: 0, status: "pending", createdAt: start})
+
+	params := Params{Limit: 3, Sort: "-amount,created_at", Filter: "status=paid"}
+	var got []int64
+	for range 10 {
+		req, err := codec.Parse(testSpec, params)
+		if err != nil {
+			t.Fatalf("Parse(%+v): %v", params, err)
+		}
+		page := Paginate(orders, req, orderValue)
+		if page.Total != 7 {
+			t.Errorf("total = %d, want 7", page.Total)
+		}
+		for _, o := range page.Items {
+			got = append(got, o.id)
+		}
+		params.Cursor = codec.Encode(req, page.Next)
+		if params.Cursor == "" {
+			break
+		}
+	}
+	want := []int64{3, 6, 2, 5, 1, 4, 7}
+	if len(got) != len(want) {
+		t.Fatalf("walked ids %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("walked ids %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCursorRejected(t *testing.T) {
+	codec := newTestCodec(t, "secret")
+	params := Params{Limit: 2, Sort: "created_at", Filter: "status=paid"}
+	req, err := codec.Parse(testSpec, params)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	cursor := codec.Encode(req, []any{time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC), int64(42)})
+	body, sig, _ := strings.Cut(cursor, ".")
+
+	// 修改内容后重新编码：签名不再匹配
+	tampered := func() string {
+		data, _ := base64.RawURLEncoding.DecodeString(body)
+		data = []byte(strings.Replace(string(data), "42", "43", 1))
+		return base64.RawURLEncoding.EncodeToString(data) + "." + sig
+	}()
+
+	tests := []struct {
+		name   string
+		codec  *Codec
+		params Params
+	}{
+		{name: "tampered keys", codec: codec, params: Params{Limit: 2, Sort: "created_at", Filter: "status=paid", Cursor: tampered}},
+		{name: "truncated signature", codec: codec, params: Params{Limit: 2, Sort: "created_at", Filter: "status=paid", Cursor: cursor[:len(cursor)-2]}},
+		{name: "missing signature", codec: codec, params: Params{Limit: 2, Sort: "created_at", Filter: "status=paid", Cursor: body}},
+		{name: "not base64", codec: codec, params: Params{Limit: 2, Sort: "created_at", Filter: "status=paid", Cursor: "!!!." + sig}},
+		{name: "signed with another secret", codec: newTestCodec(t, "other"), params: Params{Limit: 2, Sort: "created_at", Filter: "status=paid", Cursor: cursor}},
+		// 游标与签发时的排序、过滤条件绑定
+		{name: "different sort", codec: codec, params: Params{Limit: 2, Sort: "-created_at", Filter: "status=paid", Cursor: cursor}},
+		{name: "different filter", codec: codec, params: Params{Limit: 2, Sort: "created_at", Filter: "status=shipped", Cursor: cursor}},
+		{name: "filter removed", codec: codec, params: Params{Limit: 2, Sort: "created_at", Cursor: cursor}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.codec.Parse(testSpec, tt.params); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Parse err = %v, want ErrInvalid", err)
+			}
+		})
+	}
+
+	// 不影响条件的写法差异与每页条数变化不会使游标失效
+	same := Params{Limit: 5, Sort: " created_at ,id", Filter: " status = paid ", Cursor: cursor}
+	req, err = codec.Parse(testSpec, same)
+	if err != nil {
+		t.Fatalf("Parse equivalent params: %v", err)
+	}
+	if len(req.After) != 2 || req.After[1] != int64(42) {
+		t.Errorf("after = %v, want [2026-10-18T08:00:00Z 42]", req.After)
+	}
+	if !req.After[0].(time.Time).Equal(time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("after[0] = %v, want 2026-10-18T08:00:00Z", req.After[0])
+	}
+}
+
+func TestEncodeLastPage(t *testing.T) {
+	codec := newTestCodec(t, "secret")
+	req, err := codec.Parse(testSpec, Params{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if cursor := codec.Encode(req, nil); cursor != "" {
+		t.Errorf("Encode(nil) = %q, want empty", cursor)
+	}
+}
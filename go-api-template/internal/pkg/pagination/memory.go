@@ -0,0 +1,88 @@
+package pagination
+
+import (
+	"slices"
+)
+
+// Paginate 在内存中对 items 过滤、排序并取出一页，供内存实现的 Repo 使用
+// value 返回记录中字段的值，类型与 Filter.Values 相同；items 不会被修改
+func Paginate[T any](items []T, req *Request, value func(item T, field string) any) *Page[T] {
+	matched := make([]T, 0, len(items))
+	for _, item := range items {
+		if matchAll(item, req.Filters, value) {
+			matched = append(matched, item)
+		}
+	}
+	slices.SortStableFunc(matched, func(a, b T) int {
+		for _, s := range req.Sort {
+			if c := compareBy(s, value(a, s.Field), value(b, s.Field)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	total := int64(len(matched))
+
+	start := min(req.Offset, len(matched))
+	if req.After != nil {
+		start = len(matched)
+		for i, item := range matched {
+			if afterCursor(item, req, value) {
+				start = i
+				break
+			}
+		}
+	}
+	end := min(start+req.Limit+1, len(matched))
+	return NewPage(matched[start:end], total, req, value)
+}
+
+// matchAll 判断记录是否满足所有过滤条件
+func matchAll[T any](item T, filters []Filter, value func(T, string) any) bool {
+	for _, f := range filters {
+		if !match(value(item, f.Field), f) {
+			return false
+		}
+	}
+	return true
+}
+
+// match 判断字段值是否满足过滤条件
+func match(v any, f Filter) bool {
+	switch f.Op {
+	case Eq:
+		return slices.ContainsFunc(f.Values, func(x any) bool { return compare(v, x) == 0 })
+	case Ne:
+		return !slices.ContainsFunc(f.Values, func(x any) bool { return compare(v, x) == 0 })
+	}
+	c := compare(v, f.Values[0])
+	switch f.Op {
+	case Gt:
+		return c > 0
+	case Gte:
+		return c >= 0
+	case Lt:
+		return c < 0
+	case Lte:
+		return c <= 0
+	}
+	return false
+}
+
+// compareBy 按排序方向比较
+func compareBy(s SortField, a, b any) int {
+	if s.Desc {
+		return compare(b, a)
+	}
+	return compare(a, b)
+}
+
+// afterCursor 判断记录在排序中是否位于游标之后
+func afterCursor[T any](item T, req *Request, value func(T, string) any) bool {
+	for i, s := range req.Sort {
+		if c := compareBy(s, value(item, s.Field), req.After[i]); c != 0 {
+			return c > 0
+		}
+	}
+	return false
+}
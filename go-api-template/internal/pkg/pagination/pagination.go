@@ -0,0 +1,344 @@
+// Package pagination 为列表接口提供分页、排序与过滤
+// 列表接口用 Spec 声明允许排序、过滤的字段，请求中的原始参数（HTTP 查询参数或 gRPC 请求消息的同名字段）
+// 由 Codec.Parse 校验后得到 Request；data 层按 Request 查询（内存实现用 Paginate，SQL 实现用 Request.SQL），
+// 返回 Page，最后由 Codec.Encode 把下一页的位置签名为不透明的游标。
+//
+// 支持两种分页方式：
+//   - 游标：默认方式，cursor 为上一页响应的 next_cursor。游标记录上一页最后一条记录的排序键，
+//     翻页期间插入或删除记录不会导致重复或遗漏；游标带有签名并与排序、过滤条件绑定，客户端不能伪造或挪用。
+//   - 偏移：offset 大于 0 时使用，便于跳页，但深分页代价高，偏移量有上限。
+//
+// 列表请求消息约定包含 limit、offset、cursor、sort、filter 字段，响应消息包含 next_cursor 与 total 字段，
+// 响应体仍是统一的 response.Response，分页信息位于 data 中。
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalid 分页、排序或过滤参数不合法，server 层映射为 400 / InvalidArgument
+var ErrInvalid = errors.New("invalid pagination")
+
+// FieldType 字段的值类型，决定参数的解析方式与可用的比较运算
+type FieldType int
+
+const (
+	// String 字符串，只支持 = 与 !=
+	String FieldType = iota
+	// Int 64 位整数
+	Int
+	// Time 时间，参数为 RFC 3339 格式
+	Time
+	// Enum 取值限定在 Field.Values 中的字符串，只支持 = 与 !=
+	Enum
+)
+
+// Field 列表接口中可排序或过滤的字段
+type Field struct {
+	Name       string
+	Type       FieldType
+	Sortable   bool
+	Filterable bool
+	// Values Enum 字段允许的取值
+	Values []string
+}
+
+// Spec 列表接口的分页、排序与过滤规则，由 biz 层按业务声明
+type Spec struct {
+	// Fields 字段白名单，不在其中的字段不能用于排序和过滤
+	Fields []Field
+	// Key 唯一的整数字段（通常是 id），总是作为最后一个排序字段，保证顺序稳定、游标能准确定位
+	Key string
+	// DefaultSort 未指定 sort 时的排序，格式与 sort 参数相同，如 "-id"
+	DefaultSort string
+	// DefaultLimit 未指定 limit 时的每页条数，默认 20
+	DefaultLimit int
+	// MaxLimit 每页条数上限，默认 100
+	MaxLimit int
+	// MaxOffset 偏移分页允许的最大偏移量，默认 10000，更深的翻页应使用游标
+	MaxOffset int
+}
+
+// field 按名称查找字段
+func (s *Spec) field(name string) (*Field, bool) {
+	for i := range s.Fields {
+		if s.Fields[i].Name == name {
+			return &s.Fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// getDefaultLimit 获取默认每页条数
+func (s *Spec) getDefaultLimit() int {
+	if s.DefaultLimit <= 0 {
+		return 20
+	}
+	return s.DefaultLimit
+}
+
+// getMaxLimit 获取每页条数上限
+func (s *Spec) getMaxLimit() int {
+	if s.MaxLimit <= 0 {
+		return 100
+	}
+	return s.MaxLimit
+}
+
+// getMaxOffset 获取最大偏移量
+func (s *Spec) getMaxOffset() int {
+	if s.MaxOffset <= 0 {
+		return 10000
+	}
+	return s.MaxOffset
+}
+
+// Params 分页参数的原始取值，列表请求消息中的同名字段通过 FromProto 取出
+// HTTP 接口的查询参数先绑定到请求消息，两种协议的参数与游标完全一致
+type Params struct {
+	// Limit 每页条数，0 表示使用默认值
+	Limit int32
+	// Offset 偏移量，大于 0 时使用偏移分页
+	Offset int32
+	// Cursor 上一页响应中的 next_cursor
+	Cursor string
+	// Sort 排序字段，逗号分隔，- 前缀表示倒序
+	Sort string
+	// Filter 过滤条件，逗号分隔
+	Filter string
+}
+
+// ProtoParams 带有分页字段的请求消息，protoc-gen-go 生成的 Getter 即满足该接口
+type ProtoParams interface {
+	GetLimit() int32
+	GetOffset() int32
+	GetCursor() string
+	GetSort() string
+	GetFilter() string
+}
+
+// FromProto 从请求消息中取出分页参数
+func FromProto(m ProtoParams) Params {
+	return Params{
+		Limit:  m.GetLimit(),
+		Offset: m.GetOffset(),
+		Cursor: m.GetCursor(),
+		Sort:   m.GetSort(),
+		Filter: m.GetFilter(),
+	}
+}
+
+// SortField 一个排序字段
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Op 过滤条件的比较运算
+type Op string
+
+const (
+	// Eq 等于，有多个取值时为 IN
+	Eq Op = "="
+	// Ne 不等于，有多个取值时为 NOT IN
+	Ne  Op = "!="
+	Gt  Op = ">"
+	Gte Op = ">="
+	Lt  Op = "<"
+	Lte Op = "<="
+)
+
+// Filter 一个过滤条件，Values 的类型由字段类型决定：Int 为 int64，Time 为 time.Time，其余为 string
+type Filter struct {
+	Field  string
+	Op     Op
+	Values []any
+}
+
+// Request 校验后的分页请求
+type Request struct {
+	// Limit 每页条数
+	Limit int
+	// Offset 偏移分页的偏移量，游标分页时为 0
+	Offset int
+	// After 游标分页时上一页最后一条记录的排序键，与 Sort 一一对应；第一页为 nil
+	After []any
+	// Sort 排序字段，最后一个总是 Spec.Key
+	Sort []SortField
+	// Filters 过滤条件，之间为 AND 关系
+	Filters []Filter
+
+	// digest 排序与过滤条件的摘要，签发的游标与之绑定
+	digest string
+}
+
+// Page 一页结果
+type Page[T any] struct {
+	Items []T
+	// Total 满足过滤条件的总条数
+	Total int64
+	// Next 下一页的起点（本页最后一条记录的排序键），没有下一页时为 nil
+	Next []any
+}
+
+// NewPage 由按 Request 查询到的记录构造 Page
+// items 应按 Request 的排序最多取 Limit+1 条，多出的一条只用于判断是否还有下一页；
+// value 返回记录中字段的值，类型与 Filter.Values 相同
+func NewPage[T any](items []T, total int64, req *Request, value func(item T, field string) any) *Page[T] {
+	page := &Page[T]{Items: items, Total: total}
+	if len(items) > req.Limit {
+		page.Items = items[:req.Limit]
+		last := page.Items[len(page.Items)-1]
+		page.Next = make([]any, len(req.Sort))
+		for i, s := range req.Sort {
+			page.Next[i] = value(last, s.Field)
+		}
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	return page
+}
+
+// parseSort 解析 sort 参数，并在末尾补上 Key
+func parseSort(spec *Spec, raw string) ([]SortField, error) {
+	if strings.TrimSpace(raw) == "" {
+		raw = spec.DefaultSort
+	}
+	var out []SortField
+	for part := range strings.SplitSeq(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		s := SortField{Field: part}
+		if name, ok := strings.CutPrefix(part, "-"); ok {
+			s = SortField{Field: name, Desc: true}
+		}
+		f, ok := spec.field(s.Field)
+		if !ok || !f.Sortable {
+			return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalid, s.Field)
+		}
+		if slices.ContainsFunc(out, func(o SortField) bool { return o.Field == s.Field }) {
+			return nil, fmt.Errorf("%w: duplicate sort field %q", ErrInvalid, s.Field)
+		}
+		out = append(out, s)
+	}
+	if !slices.ContainsFunc(out, func(o SortField) bool { return o.Field == spec.Key }) {
+		desc := len(out) > 0 && out[len(out)-1].Desc
+		out = append(out, SortField{Field: spec.Key, Desc: desc})
+	}
+	return out, nil
+}
+
+// filterOps 按长度从长到短排列，先匹配 >= 再匹配 >
+var filterOps = []Op{Gte, Lte, Ne, Eq, Gt, Lt}
+
+// parseFilter 解析 filter 参数
+// 语法为逗号分隔的 "字段 运算符 值"，如 status=paid|shipped,amount>=1000；
+// = 与 != 可以用 | 分隔多个取值，表示 IN 与 NOT IN
+func parseFilter(spec *Spec, raw string) ([]Filter, error) {
+	var out []Filter
+	for part := range strings.SplitSeq(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		i := strings.IndexAny(part, "=!<>")
+		if i <= 0 {
+			return nil, fmt.Errorf("%w: filter %q must be field<op>value", ErrInvalid, part)
+		}
+		name, rest := strings.TrimSpace(part[:i]), part[i:]
+		var op Op
+		for _, candidate := range filterOps {
+			if strings.HasPrefix(rest, string(candidate)) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return nil, fmt.Errorf("%w: filter %q has an unknown operator", ErrInvalid, part)
+		}
+		f, ok := spec.field(name)
+		if !ok || !f.Filterable {
+			return nil, fmt.Errorf("%w: cannot filter by %q", ErrInvalid, name)
+		}
+		if (f.Type == String || f.Type == Enum) && op != Eq && op != Ne {
+			return nil, fmt.Errorf("%w: %q only supports = and !=", ErrInvalid, name)
+		}
+
+		rawValues := strings.Split(strings.TrimSpace(rest[len(op):]), "|")
+		if len(rawValues) > 1 && op != Eq && op != Ne {
+			return nil, fmt.Errorf("%w: %s does not accept multiple values", ErrInvalid, op)
+		}
+		filter := Filter{Field: name, Op: op}
+		for _, rv := range rawValues {
+			v, err := parseValue(f, rv)
+			if err != nil {
+				return nil, err
+			}
+			filter.Values = append(filter.Values, v)
+		}
+		out = append(out, filter)
+	}
+	return out, nil
+}
+
+// parseValue 按字段类型解析参数中的值
+func parseValue(f *Field, raw string) (any, error) {
+	switch f.Type {
+	case Int:
+		var v int64
+		if _, err := fmt.Sscan(raw, &v); err != nil || fmt.Sprint(v) != raw {
+			return nil, fmt.Errorf("%w: %q must be an integer, got %q", ErrInvalid, f.Name, raw)
+		}
+		return v, nil
+	case Time:
+		v, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q must be an RFC 3339 time, got %q", ErrInvalid, f.Name, raw)
+		}
+		return v.UTC(), nil
+	case Enum:
+		if !slices.Contains(f.Values, raw) {
+			return nil, fmt.Errorf("%w: %q must be one of %s, got %q", ErrInvalid, f.Name, strings.Join(f.Values, ", "), raw)
+		}
+		return raw, nil
+	default:
+		return raw, nil
+	}
+}
+
+// formatValue parseValue 的逆过程，用于游标与条件摘要
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// compare 比较两个同类型的值
+func compare(a, b any) int {
+	switch a := a.(type) {
+	case int64:
+		b, _ := b.(int64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	case time.Time:
+		b, _ := b.(time.Time)
+		return a.Compare(b)
+	default:
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+}
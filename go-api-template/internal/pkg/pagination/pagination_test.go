@@ -0,0 +1,153 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testSpec 与订单列表类似的规则
+var testSpec = &Spec{
+	Fields: []Field{
+		{Name: "id", Type: Int, Sortable: true, Filterable: true},
+		{Name: "amount", Type: Int, Sortable: true, Filterable: true},
+		{Name: "created_at", Type: Time, Sortable: true, Filterable: true},
+		{Name: "status", Type: Enum, Filterable: true, Values: []string{"pending", "paid", "shipped"}},
+		{Name: "note", Type: String, Filterable: true},
+		{Name: "owner", Type: String},
+	},
+	Key:         "id",
+	DefaultSort: "-id",
+	MaxLimit:    50,
+	MaxOffset:   100,
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    []SortField
+		wantErr bool
+	}{
+		{raw: "", want: []SortField{{Field: "id", Desc: true}}},
+		{raw: "  ", want: []SortField{{Field: "id", Desc: true}}},
+		// 末尾补上 Key，方向与最后一个排序字段相同
+		{raw: "amount", want: []SortField{{Field: "amount"}, {Field: "id"}}},
+		{raw: "-created_at", want: []SortField{{Field: "created_at", Desc: true}, {Field: "id", Desc: true}}},
+		{raw: " -amount , created_at ", want: []SortField{{Field: "amount", Desc: true}, {Field: "created_at"}, {Field: "id"}}},
+		{raw: "id,amount", want: []SortField{{Field: "id"}, {Field: "amount"}}},
+		{raw: "amount,,", want: []SortField{{Field: "amount"}, {Field: "id"}}},
+		{raw: "status", wantErr: true},
+		{raw: "unknown", wantErr: true},
+		{raw: "amount,-amount", wantErr: true},
+		{raw: "--amount", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseSort(testSpec, tt.raw)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("parseSort(%q) err = %v, want ErrInvalid", tt.raw, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSort(%q): %v", tt.raw, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSort(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		raw     string
+		want    []Filter
+		wantErr bool
+	}{
+		{raw: "", want: nil},
+		{raw: "status=paid", want: []Filter{{Field: "status", Op: Eq, Values: []any{"paid"}}}},
+		{raw: "status=paid|shipped", want: []Filter{{Field: "status", Op: Eq, Values: []any{"paid", "shipped"}}}},
+		{raw: "status!=pending", want: []Filter{{Field: "status", Op: Ne, Values: []any{"pending"}}}},
+		{raw: "amount>=1000, amount<5000", want: []Filter{
+			{Field: "amount", Op: Gte, Values: []any{int64(1000)}},
+			{Field: "amount", Op: Lt, Values: []any{int64(5000)}},
+		}},
+		{raw: "amount > 10", want: []Filter{{Field: "amount", Op: Gt, Values: []any{int64(10)}}}},
+		{raw: "amount<=-1", want: []Filter{{Field: "amount", Op: Lte, Values: []any{int64(-1)}}}},
+		// 时间参数统一转换为 UTC
+		{raw: "created_at>2026-10-18T08:00:00+08:00", want: []Filter{{Field: "created_at", Op: Gt, Values: []any{day}}}},
+		{raw: "note=hello world", want: []Filter{{Field: "note", Op: Eq, Values: []any{"hello world"}}}},
+		{raw: "id=1|2|3", want: []Filter{{Field: "id", Op: Eq, Values: []any{int64(1), int64(2), int64(3)}}}},
+		{raw: "status", wantErr: true},
+		{raw: "=paid", wantErr: true},
+		{raw: "status~paid", wantErr: true},
+		{raw: "owner=alice", wantErr: true},
+		{raw: "unknown=1", wantErr: true},
+		{raw: "status=refunded", wantErr: true},
+		{raw: "status>paid", wantErr: true},
+		{raw: "note<z", wantErr: true},
+		{raw: "amount>1|2", wantErr: true},
+		{raw: "amount=1.5", wantErr: true},
+		{raw: "amount=01", wantErr: true},
+		{raw: "amount=", wantErr: true},
+		{raw: "created_at>2026-10-18", wantErr: true},
+		{raw: "amount=>1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseFilter(testSpec, tt.raw)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("parseFilter(%q) err = %v, want ErrInvalid", tt.raw, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFilter(%q): %v", tt.raw, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFilter(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	codec := newTestCodec(t, "secret")
+	tests := []struct {
+		name       string
+		params     Params
+		wantLimit  int
+		wantOffset int
+		wantErr    bool
+	}{
+		{name: "defaults", params: Params{}, wantLimit: 20},
+		{name: "explicit", params: Params{Limit: 50, Offset: 100}, wantLimit: 50, wantOffset: 100},
+		{name: "limit over max", params: Params{Limit: 51}, wantErr: true},
+		{name: "negative limit", params: Params{Limit: -1}, wantErr: true},
+		{name: "offset over max", params: Params{Offset: 101}, wantErr: true},
+		{name: "negative offset", params: Params{Offset: -1}, wantErr: true},
+		{name: "offset with cursor", params: Params{Offset: 1, Cursor: "abc.def"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := codec.Parse(testSpec, tt.params)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("Parse err = %v, want ErrInvalid", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if req.Limit != tt.wantLimit || req.Offset != tt.wantOffset {
+				t.Errorf("limit/offset = %d/%d, want %d/%d", req.Limit, req.Offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package pagination
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SQLClauses 由 Request 生成的 SQL 片段，占位符为 ?，由调用方按方言转换
+// 条件片段为空时表示没有对应的条件；Filter 同时用于统计总数与查询本页
+type SQLClauses struct {
+	// Filter 过滤条件，如 "status IN (?, ?) AND amount >= ?"
+	Filter     string
+	FilterArgs []any
+	// Cursor 位于游标之后的条件
+	Cursor     string
+	CursorArgs []any
+	// OrderBy 排序，如 "created_at DESC, id DESC"
+	OrderBy string
+	// Limit 查询条数，比每页条数多一条，用于判断是否还有下一页
+	Limit int
+	// Offset 偏移量
+	Offset int
+}
+
+// Where 组合 base 条件、过滤条件与游标条件，返回不含 WHERE 关键字的条件与对应参数，用于查询本页
+func (c SQLClauses) Where(base string, baseArgs ...any) (string, []any) {
+	where, args := c.FilterWhere(base, baseArgs...)
+	if c.Cursor != "" {
+		where += " AND " + c.Cursor
+		args = append(args, c.CursorArgs...)
+	}
+	return where, args
+}
+
+// FilterWhere 组合 base 条件与过滤条件，不含游标条件，用于统计总数
+func (c SQLClauses) FilterWhere(base string, baseArgs ...any) (string, []any) {
+	where, args := base, append([]any(nil), baseArgs...)
+	if c.Filter != "" {
+		where += " AND " + c.Filter
+		args = append(args, c.FilterArgs...)
+	}
+	return where, args
+}
+
+// SQL 生成查询的 SQL 片段
+// columns 为字段名到列名的映射，必须覆盖 Spec 中所有可排序、可过滤的字段；
+// 列名只来自 columns，参数值全部通过占位符传递，不会拼接到 SQL 中
+func (r *Request) SQL(columns map[string]string) SQLClauses {
+	c := SQLClauses{Limit: r.Limit + 1, Offset: r.Offset}
+
+	var conds []string
+	for _, f := range r.Filters {
+		col := columns[f.Field]
+		switch {
+		case len(f.Values) > 1 && f.Op == Eq:
+			conds = append(conds, fmt.Sprintf("%s IN (%s)", col, placeholders(len(f.Values))))
+		case len(f.Values) > 1 && f.Op == Ne:
+			conds = append(conds, fmt.Sprintf("%s NOT IN (%s)", col, placeholders(len(f.Values))))
+		case f.Op == Ne:
+			conds = append(conds, col+" <> ?")
+		default:
+			conds = append(conds, fmt.Sprintf("%s %s ?", col, f.Op))
+		}
+		for _, v := range f.Values {
+			c.FilterArgs = append(c.FilterArgs, sqlValue(v))
+		}
+	}
+	c.Filter = strings.Join(conds, " AND ")
+
+	order := make([]string, len(r.Sort))
+	for i, s := range r.Sort {
+		order[i] = columns[s.Field]
+		if s.Desc {
+			order[i] += " DESC"
+		}
+	}
+	c.OrderBy = strings.Join(order, ", ")
+
+	// 排序方向可能不一致，不能用行值比较 (a, b) > (?, ?)，展开为
+	// a > ? OR (a = ? AND b > ?) OR ...
+	if r.After != nil {
+		var ors []string
+		for i, s := range r.Sort {
+			var ands []string
+			for j := range i {
+				ands = append(ands, columns[r.Sort[j].Field]+" = ?")
+				c.CursorArgs = append(c.CursorArgs, sqlValue(r.After[j]))
+			}
+			op := " > ?"
+			if s.Desc {
+				op = " < ?"
+			}
+			ands = append(ands, columns[s.Field]+op)
+			c.CursorArgs = append(c.CursorArgs, sqlValue(r.After[i]))
+			ors = append(ors, strings.Join(ands, " AND "))
+		}
+		c.Cursor = "(" + strings.Join(ors, " OR ") + ")"
+	}
+	return c
+}
+
+// placeholders 生成 n 个逗号分隔的占位符
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// sqlValue 转换为与写入时一致的参数，时间统一使用 UTC
+func sqlValue(v any) any {
+	if t, ok := v.(time.Time); ok {
+		return t.UTC()
+	}
+	return v
+}
@@ -32,6 +32,30 @@ const (
 	// 用于请求的资源未找到的场景
 	NotFound Reason = "NOT_FOUND"
 
+	// Conflict 与资源当前状态冲突
+	// 用于状态机不允许的流转（如对已取消的订单发货）、唯一性冲突等场景
+	Conflict Reason = "CONFLICT"
+
+	// PreconditionFailed 前置条件不满足
+	// 用于 If-Match 携带的版本已过期（资源已被其他请求修改）的场景
+	PreconditionFailed Reason = "PRECONDITION_FAILED"
+
+	// PayloadTooLarge 请求体过大
+	// 用于请求体超过 server.max_body_bytes 或路由单独配置的上限的场景
+	PayloadTooLarge Reason = "PAYLOAD_TOO_LARGE"
+
+	// UnsupportedMediaType 不支持的请求体格式
+	// 用于请求的 Content-Type 不在 server.allowed_content_types 中的场景
+	UnsupportedMediaType Reason = "UNSUPPORTED_MEDIA_TYPE"
+
+	// UnprocessableEntity 请求格式正确但语义上无法处理
+	// 用于同一个 Idempotency-Key 被用于不同请求内容的场景
+	UnprocessableEntity Reason = "UNPROCESSABLE_ENTITY"
+
+	// TooManyRequests 请求过于频繁
+	// 用于触发限流的场景
+	TooManyRequests Reason = "TOO_MANY_REQUESTS"
+
 	// ==================== 服务端错误 (5xx) ====================
 
 	// InternalError 内部错误
@@ -41,17 +65,28 @@ const (
 	// ServiceUnavailable 服务不可用
 	// 用于依赖服务故障、维护等场景
 	ServiceUnavailable Reason = "SERVICE_UNAVAILABLE"
+
+	// Timeout 处理超时
+	// 用于请求超过服务端或客户端设定的处理时限的场景
+	Timeout Reason = "TIMEOUT"
 )
 
 // codeHTTPStatus Reason 到 HTTP 状态码的映射
 var codeHTTPStatus = map[Reason]int{
-	Success:            200,
-	InvalidParams:      400,
-	Unauthorized:       401,
-	Forbidden:          403,
-	NotFound:           404,
-	InternalError:      500,
-	ServiceUnavailable: 503,
+	Success:              200,
+	InvalidParams:        400,
+	Unauthorized:         401,
+	Forbidden:            403,
+	NotFound:             404,
+	Conflict:             409,
+	PreconditionFailed:   412,
+	PayloadTooLarge:      413,
+	UnsupportedMediaType: 415,
+	UnprocessableEntity:  422,
+	TooManyRequests:      429,
+	InternalError:        500,
+	ServiceUnavailable:   503,
+	Timeout:              504,
 }
 
 // HTTPStatus 返回 Reason 对应的 HTTP 状态码
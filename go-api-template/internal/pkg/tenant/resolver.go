@@ -0,0 +1,104 @@
+package tenant
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/google/wire"
+
+	"go-api-template/internal/conf"
+)
+
+// ProviderSet 租户组件的依赖提供者集合
+var ProviderSet = wire.NewSet(NewResolver)
+
+// TokenParser 取出访问令牌所属的租户，由 auth.TokenManager 实现
+type TokenParser interface {
+	TenantOf(authorization string) (string, error)
+}
+
+// Source 请求中可能携带租户的位置，由 HTTP 中间件与 gRPC 拦截器分别从请求中取出
+type Source struct {
+	// Host 请求的主机名，HTTP 为 Host 头，gRPC 为 :authority
+	Host string
+	// Header tenancy.header 请求头（gRPC 为同名 metadata）的值
+	Header string
+	// Authorization Authorization 请求头的值
+	Authorization string
+}
+
+// Resolver 按 tenancy 配置解析请求所属的租户
+// 每次解析都读取最新配置，开通或停用租户热加载后立即生效
+type Resolver struct {
+	watcher *conf.Watcher
+	tokens  TokenParser
+}
+
+// NewResolver 创建 Resolver
+func NewResolver(watcher *conf.Watcher, tokens TokenParser) *Resolver {
+	return &Resolver{watcher: watcher, tokens: tokens}
+}
+
+// Header 携带租户的请求头名称
+func (r *Resolver) Header() string {
+	return r.watcher.Current().Tenancy.GetHeader()
+}
+
+// Resolve 按 tenancy.sources 的顺序解析租户
+// 未启用多租户时总是返回 Default；第一个带有租户的来源决定结果，之后的来源不再参与，
+// 访问令牌与请求租户是否一致由认证环节通过 Authorize 校验
+func (r *Resolver) Resolve(src Source) (string, error) {
+	cfg := r.watcher.Current().Tenancy
+	if !cfg.Enabled {
+		return Default, nil
+	}
+
+	var id string
+	for _, source := range cfg.GetSources() {
+		switch source {
+		case "header":
+			id = src.Header
+		case "subdomain":
+			id = subdomain(src.Host, cfg.BaseDomain)
+		case "jwt":
+			// 令牌无效时跳过该来源，需要登录的路由会在认证环节拒绝
+			if src.Authorization != "" {
+				id, _ = r.tokens.TenantOf(src.Authorization)
+			}
+		}
+		if id = strings.ToLower(strings.TrimSpace(id)); id != "" {
+			break
+		}
+	}
+	if id == "" {
+		id = cfg.Default
+	}
+
+	switch {
+	case id == "":
+		return "", ErrRequired
+	case !conf.ValidTenantID(id):
+		return "", fmt.Errorf("%w: %q", ErrInvalid, id)
+	case !cfg.Allows(id):
+		return "", fmt.Errorf("%w: %q", ErrUnknown, id)
+	}
+	return id, nil
+}
+
+// subdomain 取出 host 在 baseDomain 之下的子域名，如 acme.api.example.com 在 api.example.com 之下为 acme
+// host 不在 baseDomain 之下时返回空字符串；多级子域名原样返回，由格式校验拒绝
+func subdomain(host, baseDomain string) string {
+	if baseDomain == "" || host == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	sub, ok := strings.CutSuffix(host, "."+strings.ToLower(baseDomain))
+	if !ok {
+		return ""
+	}
+	return sub
+}
@@ -0,0 +1,108 @@
+package tenant
+
+import (
+	"errors"
+	"testing"
+
+	"go-api-template/internal/conf"
+)
+
+// fakeTokens 按 Authorization 取出租户，令牌不在表中时视为无效
+type fakeTokens map[string]string
+
+func (f fakeTokens) TenantOf(authorization string) (string, error) {
+	id, ok := f[authorization]
+	if !ok {
+		return "", errors.New("invalid token")
+	}
+	return id, nil
+}
+
+func TestResolve(t *testing.T) {
+	tokens := fakeTokens{"Bearer acme": "acme", "Bearer globex": "globex", "Bearer legacy": ""}
+	enabled := conf.TenancyConfig{
+		Enabled:    true,
+		BaseDomain: "api.example.com",
+		Tenants: map[string]conf.TenantConfig{
+			"acme":      {Enabled: true},
+			"globex":    {Enabled: true},
+			"suspended": {Enabled: false},
+		},
+	}
+	withDefault := enabled
+	withDefault.Default = conf.DefaultTenant
+	jwtFirst := enabled
+	jwtFirst.Sources = []string{"jwt", "header"}
+	customHeader := enabled
+	customHeader.Header = "X-Org"
+	open := conf.TenancyConfig{Enabled: true, BaseDomain: "api.example.com"}
+
+	tests := []struct {
+		name    string
+		cfg     conf.TenancyConfig
+		src     Source
+		want    string
+		wantErr error
+	}{
+		{name: "disabled", cfg: conf.TenancyConfig{}, src: Source{Header: "acme"}, want: Default},
+		{name: "header", cfg: enabled, src: Source{Header: "acme"}, want: "acme"},
+		{name: "header normalized", cfg: enabled, src: Source{Header: "  ACME "}, want: "acme"},
+		{name: "subdomain", cfg: enabled, src: Source{Host: "globex.api.example.com"}, want: "globex"},
+		{name: "subdomain with port", cfg: enabled, src: Source{Host: "globex.api.example.com:8080"}, want: "globex"},
+		{name: "subdomain case and trailing dot", cfg: enabled, src: Source{Host: "Globex.API.example.com."}, want: "globex"},
+		{name: "jwt", cfg: enabled, src: Source{Authorization: "Bearer acme"}, want: "acme"},
+		// 第一个带有租户的来源决定结果，令牌与请求租户是否一致由 Authorize 校验
+		{name: "header before jwt", cfg: enabled, src: Source{Header: "acme", Authorization: "Bearer globex"}, want: "acme"},
+		{name: "header before subdomain", cfg: enabled, src: Source{Header: "acme", Host: "globex.api.example.com"}, want: "acme"},
+		{name: "configured order", cfg: jwtFirst, src: Source{Header: "acme", Authorization: "Bearer globex"}, want: "globex"},
+		// 无效令牌跳过该来源
+		{name: "invalid token skipped", cfg: jwtFirst, src: Source{Header: "acme", Authorization: "Bearer forged"}, want: "acme"},
+		{name: "token without tenant skipped", cfg: jwtFirst, src: Source{Header: "acme", Authorization: "Bearer legacy"}, want: "acme"},
+		{name: "subdomain outside base domain", cfg: enabled, src: Source{Host: "acme.example.org"}, wantErr: ErrRequired},
+		{name: "base domain itself", cfg: enabled, src: Source{Host: "api.example.com"}, wantErr: ErrRequired},
+		{name: "subdomain without base domain", cfg: conf.TenancyConfig{Enabled: true}, src: Source{Host: "acme.api.example.com"}, wantErr: ErrRequired},
+		{name: "nested subdomain", cfg: enabled, src: Source{Host: "a.b.api.example.com"}, wantErr: ErrInvalid},
+		{name: "missing", cfg: enabled, src: Source{}, wantErr: ErrRequired},
+		{name: "default tenant", cfg: withDefault, src: Source{}, want: conf.DefaultTenant},
+		{name: "invalid id", cfg: enabled, src: Source{Header: "acme_corp"}, wantErr: ErrInvalid},
+		{name: "unknown tenant", cfg: enabled, src: Source{Header: "initech"}, wantErr: ErrUnknown},
+		{name: "suspended tenant", cfg: enabled, src: Source{Header: "suspended"}, wantErr: ErrUnknown},
+		// 没有配置 tenants 时接受任意合法的租户
+		{name: "open tenancy", cfg: open, src: Source{Header: "initech"}, want: "initech"},
+		{name: "custom header", cfg: customHeader, src: Source{Header: "acme"}, want: "acme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewResolver(conf.NewWatcher(&conf.Config{Tenancy: tt.cfg}), tokens)
+			got, err := r.Resolve(tt.src)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Resolve = %q, %v; want %v", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolverHeader(t *testing.T) {
+	tests := []struct {
+		cfg  conf.TenancyConfig
+		want string
+	}{
+		{conf.TenancyConfig{}, "X-Tenant-ID"},
+		{conf.TenancyConfig{Header: "X-Org"}, "X-Org"},
+	}
+	for _, tt := range tests {
+		r := NewResolver(conf.NewWatcher(&conf.Config{Tenancy: tt.cfg}), fakeTokens{})
+		if got := r.Header(); got != tt.want {
+			t.Errorf("Header() = %q, want %q", got, tt.want)
+		}
+	}
+}
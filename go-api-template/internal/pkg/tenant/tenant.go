@@ -0,0 +1,70 @@
+// Package tenant 解析请求所属的租户，并在 context 中传递
+// HTTP 中间件与 gRPC 拦截器用 Resolver 解析出租户后放入 request context，biz、data 层从中取出租户，
+// 所有业务数据按租户隔离。context 中没有租户时 data 层直接拒绝访问，而不是退回到某个默认租户：
+// 漏传租户是编程错误，宁可失败也不能读写其他租户的数据。
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-api-template/internal/conf"
+)
+
+// Default 未启用多租户时所有请求所属的租户
+const Default = conf.DefaultTenant
+
+var (
+	// ErrMissing context 中没有租户，说明调用链漏传了租户，server 层映射为内部错误
+	ErrMissing = errors.New("tenant is missing from context")
+	// ErrRequired 请求没有携带租户且未配置默认租户，server 层映射为 400 / InvalidArgument
+	ErrRequired = errors.New("tenant is required")
+	// ErrInvalid 租户 ID 格式不合法，server 层映射为 400 / InvalidArgument
+	ErrInvalid = errors.New("invalid tenant id")
+	// ErrUnknown 租户未在 tenancy.tenants 中开通，server 层映射为 404 / NotFound
+	ErrUnknown = errors.New("unknown tenant")
+	// ErrMismatch 访问令牌属于其他租户，server 层映射为 403 / PermissionDenied
+	ErrMismatch = errors.New("access token belongs to another tenant")
+)
+
+// tenantKey context 中存放租户的键
+type tenantKey struct{}
+
+// NewContext 返回携带租户的 context
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext 取出 context 中的租户
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok && id != ""
+}
+
+// Require 取出 context 中的租户，没有租户时返回 ErrMissing
+// 读写业务数据前使用，保证漏传租户时失败而不是越过隔离
+func Require(ctx context.Context) (string, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return "", ErrMissing
+	}
+	return id, nil
+}
+
+// Authorize 校验访问令牌所属的租户与请求的租户一致
+// 同一个签名密钥为所有租户签发令牌，不校验时租户 A 的令牌可以用来访问租户 B；
+// claimTenant 为空的令牌签发于启用多租户之前，属于 Default
+func Authorize(ctx context.Context, claimTenant string) error {
+	id, err := Require(ctx)
+	if err != nil {
+		return err
+	}
+	if claimTenant == "" {
+		claimTenant = Default
+	}
+	if claimTenant != id {
+		return fmt.Errorf("%w: token tenant %q, request tenant %q", ErrMismatch, claimTenant, id)
+	}
+	return nil
+}
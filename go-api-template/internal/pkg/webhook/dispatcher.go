@@ -0,0 +1,271 @@
+// Package webhook 把 webhook 订阅的待投递记录发送给接收方
+// 订阅管理与"事件 -> 投递记录"的分发在 biz 层完成，这里只负责：
+// 领取到期的投递、签名后 POST、记录每次尝试的结果，并按指数退避安排重试。
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/wire"
+
+	"go-api-template/internal/conf"
+)
+
+// ProviderSet 投递组件的依赖提供者集合
+var ProviderSet = wire.NewSet(NewDispatcher, NewGuard)
+
+const (
+	// maxErrorLen 记录的失败原因最大长度
+	maxErrorLen = 500
+	// maxResponseRead 读取（并丢弃）响应体的上限，读完响应体才能复用连接
+	maxResponseRead = 64 << 10
+)
+
+// Delivery 一条待发送的投递，由 Source 从投递记录与订阅中组装
+type Delivery struct {
+	ID        int64
+	EventID   int64
+	EventType string
+	Payload   []byte
+	// Attempts 此前已尝试的次数
+	Attempts int
+	URL      string
+	Secret   string
+}
+
+// Attempt 一次投递尝试的结果
+type Attempt struct {
+	DeliveryID int64
+	// Number 第几次尝试，从 1 开始
+	Number int
+	// StatusCode 响应状态码，未收到响应时为 0
+	StatusCode int
+	// Error 失败原因，成功时为空
+	Error     string
+	Duration  time.Duration
+	At        time.Time
+	Succeeded bool
+	// NextAttempt 失败后的下次投递时间，零值表示不再重试
+	NextAttempt time.Time
+}
+
+// Source 待投递记录的来源，由 data 层实现
+type Source interface {
+	// Claim 领取最多 limit 条已到投递时间的记录，并在 lease 时长内不再交给其他 Claim 调用
+	Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]Delivery, error)
+	// Record 保存一次尝试的结果并更新投递状态
+	Record(ctx context.Context, a Attempt) error
+}
+
+// Dispatcher 以固定数量的 worker 并发投递
+// 轮询循环领取记录后交给 worker，worker 全忙时轮询循环阻塞，不会领取超出处理能力的记录
+type Dispatcher struct {
+	source Source
+	client *http.Client
+	cfg    conf.WebhooksConfig
+
+	tasks chan Delivery
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// NewDispatcher 创建 Dispatcher
+func NewDispatcher(cfg *conf.Config, source Source, guard *Guard) *Dispatcher {
+	return &Dispatcher{
+		source: source,
+		client: newClient(cfg.Webhooks.GetTimeout(), guard),
+		cfg:    cfg.Webhooks,
+		tasks:  make(chan Delivery),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// newClient 创建投递使用的 HTTP 客户端
+//   - 不走环境变量中的代理：经代理连接时 guard 只能校验代理地址
+//   - 每次建立连接前由 guard 校验实际连接的地址，订阅创建后 DNS 改为指向内网同样会被拒绝
+//   - 不跟随重定向：3xx 响应按失败处理，避免借公网地址跳转到内网
+func newClient(timeout time.Duration, guard *Guard) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   guard.control,
+	}).DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Start 启动轮询循环与 worker，webhooks.enabled 为 false 时什么也不做
+func (d *Dispatcher) Start() {
+	if !d.cfg.Enabled {
+		close(d.done)
+		return
+	}
+	slog.Info("Webhook dispatcher started", "workers", d.cfg.GetWorkers())
+
+	// 投递过程中收到 Stop 时取消进行中的请求，被取消的投递租约到期后重新投递
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for range d.cfg.GetWorkers() {
+		wg.Add(1)
+		go d.worker(ctx, &wg)
+	}
+	go func() {
+		<-d.stop
+		cancel()
+	}()
+	go func() {
+		d.poll(ctx)
+		// 轮询循环退出后关闭任务通道，worker 处理完手头的投递后退出
+		close(d.tasks)
+		wg.Wait()
+		close(d.done)
+	}()
+}
+
+// Stop 停止领取新的投递并等待 worker 退出（或直到 ctx 超时）
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	select {
+	case <-d.stop:
+	default:
+		close(d.stop)
+	}
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// poll 轮询循环：每次最多领取 worker 数量的记录，领满时立即继续，否则等待一个轮询间隔
+func (d *Dispatcher) poll(ctx context.Context) {
+	workers := d.cfg.GetWorkers()
+	// 记录在通道中最多等待一轮投递的时间，租约需要覆盖它和本次投递
+	lease := 2*d.cfg.GetTimeout() + time.Minute
+	ticker := time.NewTicker(d.cfg.GetPollInterval())
+	defer ticker.Stop()
+
+	for {
+		deliveries, err := d.source.Claim(ctx, time.Now(), workers, lease)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("Failed to claim webhook deliveries", "error", err)
+		}
+		for _, delivery := range deliveries {
+			select {
+			case d.tasks <- delivery:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if len(deliveries) >= workers {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// worker 从任务通道取出投递并发送，直到通道关闭
+func (d *Dispatcher) worker(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for delivery := range d.tasks {
+		if ctx.Err() != nil {
+			continue
+		}
+		attempt := d.deliver(ctx, delivery)
+		if ctx.Err() != nil && !attempt.Succeeded {
+			// 因关闭而中断的尝试不计入次数，租约到期后重新投递
+			continue
+		}
+		// 记录结果使用独立的 context：即使正在关闭，也要尽量把结果写回，减少重复投递
+		if err := d.source.Record(context.WithoutCancel(ctx), attempt); err != nil {
+			slog.Error("Failed to record webhook attempt", "delivery_id", delivery.ID, "error", err)
+		}
+	}
+}
+
+// deliver 签名并发送一次投递，返回本次尝试的结果
+func (d *Dispatcher) deliver(ctx context.Context, delivery Delivery) Attempt {
+	start := time.Now()
+	attempt := Attempt{DeliveryID: delivery.ID, Number: delivery.Attempts + 1, At: start}
+
+	status, err := d.send(ctx, delivery, start)
+	attempt.Duration = time.Since(start)
+	attempt.StatusCode = status
+	if err == nil {
+		attempt.Succeeded = true
+		return attempt
+	}
+
+	attempt.Error = err.Error()
+	if len(attempt.Error) > maxErrorLen {
+		attempt.Error = attempt.Error[:maxErrorLen]
+	}
+	if attempt.Number < d.cfg.GetMaxAttempts() {
+		attempt.NextAttempt = time.Now().Add(d.backoff(attempt.Number))
+		slog.Warn("Webhook delivery failed, will retry", "delivery_id", delivery.ID, "url", delivery.URL,
+			"attempt", attempt.Number, "status", status, "retry_at", attempt.NextAttempt, "error", err)
+	} else {
+		slog.Error("Webhook delivery failed, giving up", "delivery_id", delivery.ID, "url", delivery.URL,
+			"attempt", attempt.Number, "status", status, "error", err)
+	}
+	return attempt
+}
+
+// send 发送请求，2xx 响应视为成功；返回响应状态码（未收到响应时为 0）
+func (d *Dispatcher) send(ctx context.Context, delivery Delivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, now, delivery.Payload))
+	req.Header.Set(HeaderDeliveryID, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderEventID, strconv.FormatInt(delivery.EventID, 10))
+	req.Header.Set(HeaderEventType, delivery.EventType)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseRead))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff 第 attempts 次失败后的等待时间：指数增长、有上限，并加入 ±20% 的抖动
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.cfg.GetBackoff()
+	for i := 1; i < attempts && wait < d.cfg.GetMaxBackoff(); i++ {
+		wait *= 2
+	}
+	wait = min(wait, d.cfg.GetMaxBackoff())
+	jitter := 0.8 + 0.4*rand.Float64()
+	return time.Duration(float64(wait) * jitter)
+}
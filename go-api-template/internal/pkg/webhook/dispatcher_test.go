@@ -0,0 +1,351 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api-template/internal/conf"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// fakeSource 内存中的 Source：领取后移出待投递队列，失败且需要重试时按 NextAttempt 放回
+type fakeSource struct {
+	mu      sync.Mutex
+	pending []Delivery
+	dueAt   map[int64]time.Time
+	// claimed 已领取、等待结果的投递
+	claimed  map[int64]Delivery
+	recorded chan Attempt
+}
+
+func newFakeSource(deliveries ...Delivery) *fakeSource {
+	return &fakeSource{
+		pending:  deliveries,
+		dueAt:    make(map[int64]time.Time),
+		claimed:  make(map[int64]Delivery),
+		recorded: make(chan Attempt, 16),
+	}
+}
+
+func (s *fakeSource) Claim(_ context.Context, now time.Time, limit int, _ time.Duration) ([]Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var claimed, rest []Delivery
+	for _, d := range s.pending {
+		if len(claimed) < limit && !s.dueAt[d.ID].After(now) {
+			claimed = append(claimed, d)
+			s.claimed[d.ID] = d
+		} else {
+			rest = append(rest, d)
+		}
+	}
+	s.pending = rest
+	return claimed, nil
+}
+
+func (s *fakeSource) Record(_ context.Context, a Attempt) error {
+	s.mu.Lock()
+	d := s.claimed[a.DeliveryID]
+	delete(s.claimed, a.DeliveryID)
+	if !a.Succeeded && !a.NextAttempt.IsZero() {
+		d.Attempts = a.Number
+		s.pending = append(s.pending, d)
+		s.dueAt[d.ID] = a.NextAttempt
+	}
+	s.mu.Unlock()
+	s.recorded <- a
+	return nil
+}
+
+// newTestDispatcher 创建允许回环地址的 Dispatcher，httptest 服务器监听在 127.0.0.1
+func newTestDispatcher(source Source, cfg conf.WebhooksConfig) *Dispatcher {
+	cfg.Enabled = true
+	cfg.AllowPrivateNetworks = true
+	return NewDispatcher(&conf.Config{Webhooks: cfg}, source, NewGuard(&conf.Config{Webhooks: cfg}))
+}
+
+func testDelivery(url string) Delivery {
+	return Delivery{
+		ID:        7,
+		EventID:   100,
+		EventType: "greeting.created",
+		Payload:   []byte(`{"id":100}`),
+		URL:       url,
+		Secret:    testSecret,
+	}
+}
+
+func TestGuardCheckAddr(t *testing.T) {
+	tests := []struct {
+		addr         string
+		allowPrivate bool
+		allowed      bool
+	}{
+		{"93.184.216.34", false, true},
+		{"2606:2800:220:1::", false, true},
+		{"127.0.0.1", false, false},
+		{"::1", false, false},
+		{"10.0.0.1", false, false},
+		{"172.16.5.4", false, false},
+		{"192.168.1.1", false, false},
+		{"fd00:ec2::254", false, false},
+		{"::ffff:127.0.0.1", false, false},
+		{"0.0.0.0", false, false},
+		{"100.100.100.200", false, false},
+		{"224.0.0.1", false, false},
+		{"64:ff9b::7f00:1", false, false},
+		{"169.254.169.254", false, false},
+		{"fe80::1", false, false},
+		// 本地开发允许回环与内网，但链路本地的元数据地址始终拒绝
+		{"127.0.0.1", true, true},
+		{"10.0.0.1", true, true},
+		{"169.254.169.254", true, false},
+		{"::ffff:169.254.169.254", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			g := &Guard{allowPrivate: tt.allowPrivate}
+			err := g.CheckAddr(netip.MustParseAddr(tt.addr))
+			if (err == nil) != tt.allowed {
+				t.Errorf("CheckAddr(%s, allowPrivate=%v) = %v, want allowed=%v", tt.addr, tt.allowPrivate, err, tt.allowed)
+			}
+			if err != nil && !errors.Is(err, ErrForbiddenTarget) {
+				t.Errorf("error %v does not wrap ErrForbiddenTarget", err)
+			}
+		})
+	}
+}
+
+func TestGuardCheckTarget(t *testing.T) {
+	g := NewGuard(&conf.Config{})
+	for _, host := range []string{"localhost", "127.0.0.1", "::1", "169.254.169.254", "no-such-host.invalid"} {
+		if err := g.CheckTarget(context.Background(), host); !errors.Is(err, ErrForbiddenTarget) {
+			t.Errorf("CheckTarget(%q) = %v, want ErrForbiddenTarget", host, err)
+		}
+	}
+}
+
+func TestDispatcherSend(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantStatus int
+		wantOK     bool
+	}{
+		{"ok", http.StatusOK, http.StatusOK, true},
+		{"no content", http.StatusNoContent, http.StatusNoContent, true},
+		{"server error", http.StatusInternalServerError, http.StatusInternalServerError, false},
+		{"client error", http.StatusGone, http.StatusGone, false},
+		// 重定向不跟随，按失败记录 3xx 状态码
+		{"redirect", http.StatusFound, http.StatusFound, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				header     http.Header
+				body       []byte
+				redirected atomic.Int32
+			)
+			mux := http.NewServeMux()
+			mux.HandleFunc("/hook", func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header.Clone()
+				body, _ = io.ReadAll(r.Body)
+				if tt.status == http.StatusFound {
+					http.Redirect(w, r, "/other", http.StatusFound)
+					return
+				}
+				w.WriteHeader(tt.status)
+			})
+			mux.HandleFunc("/other", func(w http.ResponseWriter, r *http.Request) {
+				redirected.Add(1)
+			})
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			d := newTestDispatcher(newFakeSource(), conf.WebhooksConfig{})
+			delivery := testDelivery(srv.URL + "/hook")
+			attempt := d.deliver(context.Background(), delivery)
+
+			if attempt.Succeeded != tt.wantOK || attempt.StatusCode != tt.wantStatus {
+				t.Fatalf("attempt = {ok:%v status:%d err:%q}, want {ok:%v status:%d}",
+					attempt.Succeeded, attempt.StatusCode, attempt.Error, tt.wantOK, tt.wantStatus)
+			}
+			if attempt.Number != 1 || attempt.DeliveryID != delivery.ID {
+				t.Errorf("attempt number/delivery = %d/%d, want 1/%d", attempt.Number, attempt.DeliveryID, delivery.ID)
+			}
+			if !tt.wantOK && attempt.Error == "" {
+				t.Error("failed attempt has no error")
+			}
+			if redirected.Load() != 0 {
+				t.Error("redirect was followed")
+			}
+
+			if string(body) != string(delivery.Payload) {
+				t.Errorf("body = %s, want %s", body, delivery.Payload)
+			}
+			if err := Verify(testSecret, header.Get(HeaderTimestamp), header.Get(HeaderSignature), body, time.Now(), time.Minute); err != nil {
+				t.Errorf("signature does not verify: %v", err)
+			}
+			ts, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+			if err != nil || time.Since(time.Unix(ts, 0)) > time.Minute {
+				t.Errorf("timestamp header = %q, want current Unix seconds", header.Get(HeaderTimestamp))
+			}
+			for name, want := range map[string]string{
+				"Content-Type":   "application/json",
+				HeaderDeliveryID: "7",
+				HeaderEventID:    "100",
+				HeaderEventType:  "greeting.created",
+			} {
+				if got := header.Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDispatcherRejectsPrivateTarget(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer srv.Close()
+
+	// 订阅创建时地址可能解析到公网，投递时 DNS 已改为指向内网：由连接前的校验拒绝
+	cfg := &conf.Config{Webhooks: conf.WebhooksConfig{Enabled: true}}
+	d := NewDispatcher(cfg, newFakeSource(), NewGuard(cfg))
+	attempt := d.deliver(context.Background(), testDelivery(srv.URL))
+
+	if attempt.Succeeded || attempt.StatusCode != 0 {
+		t.Fatalf("attempt = {ok:%v status:%d}, want failure without response", attempt.Succeeded, attempt.StatusCode)
+	}
+	if !strings.Contains(attempt.Error, "not allowed") {
+		t.Errorf("error = %q, want target-not-allowed error", attempt.Error)
+	}
+	if hits.Load() != 0 {
+		t.Errorf("receiver got %d requests, want 0", hits.Load())
+	}
+}
+
+func TestDispatcherRetrySchedule(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	d := newTestDispatcher(newFakeSource(), conf.WebhooksConfig{
+		MaxAttempts: 4,
+		Backoff:     time.Second,
+		MaxBackoff:  3 * time.Second,
+	})
+	tests := []struct {
+		prevAttempts int
+		wantWait     time.Duration // 零值表示不再重试
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{2, 3 * time.Second}, // 4s 被上限截断
+		{3, 0},               // 第 4 次即最后一次
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.prevAttempts+1), func(t *testing.T) {
+			delivery := testDelivery(srv.URL)
+			delivery.Attempts = tt.prevAttempts
+			before := time.Now()
+			attempt := d.deliver(context.Background(), delivery)
+			after := time.Now()
+
+			if attempt.Number != tt.prevAttempts+1 {
+				t.Errorf("Number = %d, want %d", attempt.Number, tt.prevAttempts+1)
+			}
+			if tt.wantWait == 0 {
+				if !attempt.NextAttempt.IsZero() {
+					t.Errorf("NextAttempt = %v, want zero after last attempt", attempt.NextAttempt)
+				}
+				return
+			}
+			// 抖动 ±20%
+			low := before.Add(tt.wantWait * 8 / 10)
+			high := after.Add(tt.wantWait * 12 / 10)
+			if attempt.NextAttempt.Before(low) || attempt.NextAttempt.After(high) {
+				t.Errorf("NextAttempt in %v, want within [%v, %v]",
+					attempt.NextAttempt.Sub(before), low.Sub(before), high.Sub(before))
+			}
+		})
+	}
+}
+
+func TestDispatcherRun(t *testing.T) {
+	var (
+		calls       atomic.Int32
+		mu          sync.Mutex
+		deliveryIDs []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		deliveryIDs = append(deliveryIDs, r.Header.Get(HeaderDeliveryID))
+		mu.Unlock()
+		// 前两次失败，第三次成功
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	source := newFakeSource(testDelivery(srv.URL))
+	d := newTestDispatcher(source, conf.WebhooksConfig{
+		Workers:      2,
+		PollInterval: 10 * time.Millisecond,
+		MaxAttempts:  5,
+		Backoff:      10 * time.Millisecond,
+	})
+	d.Start()
+	defer d.Stop(context.Background())
+
+	var got []Attempt
+	for len(got) < 3 {
+		select {
+		case a := <-source.recorded:
+			got = append(got, a)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out after %d attempts", len(got))
+		}
+	}
+
+	want := []struct {
+		status int
+		ok     bool
+	}{{500, false}, {500, false}, {200, true}}
+	for i, w := range want {
+		a := got[i]
+		if a.Number != i+1 || a.StatusCode != w.status || a.Succeeded != w.ok {
+			t.Errorf("attempt %d = {number:%d status:%d ok:%v}, want {number:%d status:%d ok:%v}",
+				i, a.Number, a.StatusCode, a.Succeeded, i+1, w.status, w.ok)
+		}
+	}
+	if !got[2].NextAttempt.IsZero() {
+		t.Error("successful attempt scheduled a retry")
+	}
+	// 重试沿用同一个投递 ID，接收方据此识别重复请求
+	mu.Lock()
+	defer mu.Unlock()
+	for _, id := range deliveryIDs {
+		if id != "7" {
+			t.Errorf("delivery header = %q, want 7", id)
+		}
+	}
+}
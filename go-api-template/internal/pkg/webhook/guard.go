@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+
+	"go-api-template/internal/conf"
+)
+
+// ErrForbiddenTarget 接收地址解析到了不允许访问的网络地址
+var ErrForbiddenTarget = errors.New("webhook: target address not allowed")
+
+// reservedPrefixes 标准库判断函数未覆盖、但同样不应从服务端访问的地址段
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "本网络"
+	netip.MustParsePrefix("100.64.0.0/10"),   // 运营商级 NAT，部分云厂商的元数据服务在此段
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF 协议分配
+	netip.MustParsePrefix("192.0.2.0/24"),    // 文档示例
+	netip.MustParsePrefix("198.18.0.0/15"),   // 基准测试
+	netip.MustParsePrefix("198.51.100.0/24"), // 文档示例
+	netip.MustParsePrefix("203.0.113.0/24"),  // 文档示例
+	netip.MustParsePrefix("240.0.0.0/4"),     // 保留，含广播地址
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64，可映射到任意 IPv4 地址
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // 本地 NAT64
+	netip.MustParsePrefix("100::/64"),        // 丢弃
+	netip.MustParsePrefix("2001::/32"),       // Teredo
+	netip.MustParsePrefix("2001:db8::/32"),   // 文档示例
+	netip.MustParsePrefix("2002::/16"),       // 6to4，可嵌入任意 IPv4 地址
+	netip.MustParsePrefix("fec0::/10"),       // 已废弃的站点本地地址
+}
+
+// Guard 限制 webhook 只能发送到公网地址，防止用户借订阅访问内网服务或云元数据接口（SSRF）
+// 创建订阅时校验一次；DNS 记录随时可能变化，投递时在建立连接前对实际连接的地址再校验一次
+type Guard struct {
+	allowPrivate bool
+	resolver     *net.Resolver
+}
+
+// NewGuard 创建 Guard
+func NewGuard(cfg *conf.Config) *Guard {
+	return &Guard{allowPrivate: cfg.Webhooks.AllowPrivateNetworks, resolver: net.DefaultResolver}
+}
+
+// CheckTarget 解析主机名并校验全部地址，任一地址不允许访问即拒绝
+func (g *Guard) CheckTarget(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return g.CheckAddr(addr)
+	}
+	addrs, err := g.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("%w: cannot resolve %s", ErrForbiddenTarget, host)
+	}
+	for _, addr := range addrs {
+		if err := g.CheckAddr(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckAddr 校验单个地址
+// 链路本地地址（含 169.254.169.254 等云元数据地址）始终拒绝；
+// 回环与私有网络地址只有在 webhooks.allow_private_networks 开启时允许
+func (g *Guard) CheckAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+	switch {
+	case addr.IsLinkLocalUnicast(), addr.IsLinkLocalMulticast(), addr.IsInterfaceLocalMulticast(),
+		addr.IsMulticast(), addr.IsUnspecified():
+		return fmt.Errorf("%w: %s is a link-local, multicast or unspecified address", ErrForbiddenTarget, addr)
+	case addr.IsLoopback(), addr.IsPrivate():
+		if g.allowPrivate {
+			return nil
+		}
+		return fmt.Errorf("%w: %s is a loopback or private address", ErrForbiddenTarget, addr)
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return fmt.Errorf("%w: %s is a reserved address", ErrForbiddenTarget, addr)
+		}
+	}
+	return nil
+}
+
+// control 作为 net.Dialer.Control，在 DNS 解析之后、建立连接之前校验实际连接的地址
+func (g *Guard) control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenTarget, address)
+	}
+	return g.CheckAddr(addrPort.Addr())
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 签名相关请求头
+const (
+	// HeaderTimestamp 签名时间（Unix 秒），参与签名，接收方据此拒绝过旧的请求防止重放
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	// HeaderSignature 请求签名，格式为 sha256=<hex>
+	HeaderSignature = "X-Webhook-Signature"
+	// HeaderDeliveryID 投递 ID，重新投递时保持不变
+	HeaderDeliveryID = "X-Webhook-Delivery"
+	// HeaderEventID 领域事件 ID，接收方据此去重
+	HeaderEventID = "X-Webhook-Event-ID"
+	// HeaderEventType 领域事件类型
+	HeaderEventType = "X-Webhook-Event"
+
+	signaturePrefix = "sha256="
+)
+
+// 签名校验失败的原因
+var (
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	ErrExpiredTimestamp = errors.New("webhook: timestamp outside tolerance")
+)
+
+// Sign 计算请求签名：HMAC-SHA256(secret, "<timestamp>.<body>")
+// 时间戳参与签名，截获的请求无法修改时间戳后重放
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验签名与时间戳，供接收方（以及测试中的接收端）使用
+// tolerance 为允许的时钟偏差，超出时返回 ErrExpiredTimestamp
+func Verify(secret, timestampHeader, signatureHeader string, body []byte, now time.Time, tolerance time.Duration) error {
+	unix, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	ts := time.Unix(unix, 0)
+	if now.Sub(ts) > tolerance || ts.Sub(now) > tolerance {
+		return ErrExpiredTimestamp
+	}
+	if !strings.HasPrefix(signatureHeader, signaturePrefix) ||
+		!hmac.Equal([]byte(signatureHeader), []byte(Sign(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
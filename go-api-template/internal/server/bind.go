@@ -0,0 +1,42 @@
+package server
+
+import (
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/protobuf/proto"
+
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/reason"
+)
+
+// protoBody 可以从 protobuf 请求体填充的 DTO，M 为对应的 proto 请求消息
+type protoBody[M proto.Message] interface {
+	FromProto(M)
+}
+
+// bindBody 按 Content-Type 解析请求体到 dst，并按 DTO 的 binding tag 验证
+//   - application/x-protobuf：解码为 msg 后由 DTO.FromProto 填充，与 gRPC 使用同一个消息定义
+//   - application/msgpack、application/x-msgpack：字段名与 JSON 相同
+//   - 其余（application/json）：与原先的 ShouldBindJSON 相同
+//
+// 返回的错误交给 apperrors.FromValidationError 转换
+func bindBody[M proto.Message](c *gin.Context, dst protoBody[M], msg M) error {
+	switch c.ContentType() {
+	case binding.MIMEPROTOBUF:
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return err
+		}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return apperrors.Wrap(reason.InvalidParams, "请求体不是合法的 protobuf 消息", err)
+		}
+		dst.FromProto(msg)
+		return binding.Validator.ValidateStruct(dst)
+	case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+		return c.ShouldBindWith(dst, binding.MsgPack)
+	default:
+		return c.ShouldBindJSON(dst)
+	}
+}
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	v1 "go-api-template/api/featureflags/v1"
+)
+
+// ListFeatureFlagsQuery 是 GET /api/v1/admin/feature-flags 的查询参数
+// 求值对象默认为调用者自己，非空的参数替换对应的部分
+type ListFeatureFlagsQuery struct {
+	// Tenant 求值使用的租户
+	Tenant string `form:"tenant" binding:"omitempty,max=63" example:"acme"`
+	// User 求值使用的用户名
+	User string `form:"user" binding:"omitempty,max=50" example:"alice"`
+	// Environment 求值使用的运行环境
+	Environment string `form:"environment" binding:"omitempty,max=50" example:"production"`
+}
+
+// ToProto 将 DTO 转换为 Proto 类型
+func (q *ListFeatureFlagsQuery) ToProto() *v1.ListFeatureFlagsRequest {
+	return &v1.ListFeatureFlagsRequest{
+		Tenant:      q.Tenant,
+		User:        q.User,
+		Environment: q.Environment,
+	}
+}
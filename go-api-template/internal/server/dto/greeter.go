@@ -23,3 +23,50 @@ func (r *SayHelloRequest) ToProto() *v1.SayHelloRequest {
 		Name: r.Name,
 	}
 }
+
+// FromProto 从 protobuf 请求体填充 DTO，之后同样按 binding tag 验证
+func (r *SayHelloRequest) FromProto(m *v1.SayHelloRequest) {
+	r.Name = m.GetName()
+}
+
+// ListGreetingsQuery 是 GET /api/v1/greeter/greetings 的查询参数
+// 这里只做格式校验，字段白名单、偏移量上限与游标由 Service 层按 biz.GreeterListSpec 校验
+type ListGreetingsQuery struct {
+	// Limit 每页条数，默认 20
+	Limit int32 `form:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+	// Offset 偏移量
+	Offset int32 `form:"offset" binding:"omitempty,min=0" example:"0"`
+	// Cursor 上一页响应中的 next_cursor
+	Cursor string `form:"cursor" binding:"max=1024"`
+	// Sort 排序字段
+	Sort string `form:"sort" binding:"max=200" example:"-created_at"`
+	// Filter 过滤条件
+	Filter string `form:"filter" binding:"max=1000" example:"name=World"`
+}
+
+// ToProto 将 DTO 转换为 Proto 类型
+func (q *ListGreetingsQuery) ToProto() *v1.ListGreetingsRequest {
+	return &v1.ListGreetingsRequest{
+		Limit:  q.Limit,
+		Offset: q.Offset,
+		Cursor: q.Cursor,
+		Sort:   q.Sort,
+		Filter: q.Filter,
+	}
+}
+
+// WatchGreetingsQuery 是 GET /api/v1/greeter/stream 的查询参数
+type WatchGreetingsQuery struct {
+	// Name 只接收该名称的问候，为空时接收全部
+	Name string `form:"name" binding:"omitempty,max=100" example:"World"`
+	// LastEventID 从该 ID 之后开始推送；浏览器 EventSource 重连时改用 Last-Event-ID 请求头，两者同时存在时以请求头为准
+	LastEventID int64 `form:"last_event_id" binding:"omitempty,min=0" example:"42"`
+}
+
+// ToProto 将 DTO 转换为 Proto 类型
+func (q *WatchGreetingsQuery) ToProto() *v1.WatchGreetingsRequest {
+	return &v1.WatchGreetingsRequest{
+		Name:    q.Name,
+		AfterId: q.LastEventID,
+	}
+}
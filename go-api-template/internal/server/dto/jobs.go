@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"strings"
+
+	v1 "go-api-template/api/jobs/v1"
+)
+
+// ListJobsQuery 是 GET /api/v1/admin/jobs 的查询参数，为空的条件不参与筛选
+type ListJobsQuery struct {
+	// Status 任务状态
+	Status string `form:"status" binding:"omitempty,oneof=queued running succeeded dead cancelled" example:"dead"`
+	// Queue 队列名
+	Queue string `form:"queue" binding:"omitempty,max=100" example:"default"`
+	// Type 任务类型
+	Type string `form:"type" binding:"omitempty,max=100" example:"webhook.prune_deliveries"`
+	// Limit 最多返回的条数，默认 50
+	Limit int32 `form:"limit" binding:"omitempty,min=1,max=200" example:"50"`
+}
+
+// ToProto 将 DTO 转换为 Proto 类型，状态名映射为对应的枚举值
+func (q *ListJobsQuery) ToProto() *v1.ListJobsRequest {
+	return &v1.ListJobsRequest{
+		Status: v1.JobStatus(v1.JobStatus_value["JOB_STATUS_"+strings.ToUpper(q.Status)]),
+		Queue:  q.Queue,
+		Type:   q.Type,
+		Limit:  q.Limit,
+	}
+}
@@ -0,0 +1,77 @@
+package dto
+
+import (
+	v1 "go-api-template/api/order/v1"
+)
+
+// CreateOrderRequest 是 POST /api/v1/orders 的请求体
+type CreateOrderRequest struct {
+	// Product 商品名称
+	Product string `json:"product" binding:"required,min=1,max=200" example:"Go 语言圣经"`
+	// Quantity 数量
+	Quantity int32 `json:"quantity" binding:"required,gt=0" example:"1"`
+	// Amount 订单总金额（分）
+	Amount int64 `json:"amount" binding:"required,gt=0" example:"8900"`
+}
+
+// ToProto 将 DTO 转换为 Proto 类型
+func (r *CreateOrderRequest) ToProto() *v1.CreateOrderRequest {
+	return &v1.CreateOrderRequest{
+		Product:  r.Product,
+		Quantity: r.Quantity,
+		Amount:   r.Amount,
+	}
+}
+
+// FromProto 从 protobuf 请求体填充 DTO
+func (r *CreateOrderRequest) FromProto(m *v1.CreateOrderRequest) {
+	r.Product = m.GetProduct()
+	r.Quantity = m.GetQuantity()
+	r.Amount = m.GetAmount()
+}
+
+// ListOrdersQuery 是 GET /api/v1/orders 的查询参数
+// 这里只做格式校验，字段白名单、偏移量上限与游标由 Service 层按 biz.OrderListSpec 校验
+type ListOrdersQuery struct {
+	// Limit 每页条数，默认 20
+	Limit int32 `form:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+	// Offset 偏移量
+	Offset int32 `form:"offset" binding:"omitempty,min=0" example:"0"`
+	// Cursor 上一页响应中的 next_cursor
+	Cursor string `form:"cursor" binding:"max=1024"`
+	// Sort 排序字段
+	Sort string `form:"sort" binding:"max=200" example:"-created_at"`
+	// Filter 过滤条件
+	Filter string `form:"filter" binding:"max=1000" example:"status=paid|shipped,amount>=1000"`
+}
+
+// ToProto 将 DTO 转换为 Proto 类型
+func (q *ListOrdersQuery) ToProto() *v1.ListOrdersRequest {
+	return &v1.ListOrdersRequest{
+		Limit:  q.Limit,
+		Offset: q.Offset,
+		Cursor: q.Cursor,
+		Sort:   q.Sort,
+		Filter: q.Filter,
+	}
+}
+
+// CancelOrderRequest 是 POST /api/v1/orders/:id/cancel 的请求体（可选）
+type CancelOrderRequest struct {
+	// Reason 取消原因
+	Reason string `json:"reason" binding:"max=500" example:"不想要了"`
+}
+
+// ToProto 将 DTO 转换为 Proto 类型，id 来自 URL 路径，version 来自 If-Match 头
+func (r *CancelOrderRequest) ToProto(id, version int64) *v1.CancelOrderRequest {
+	return &v1.CancelOrderRequest{
+		Id:      id,
+		Reason:  r.Reason,
+		Version: version,
+	}
+}
+
+// FromProto 从 protobuf 请求体填充 DTO，请求体中的 id 与 version 被忽略
+func (r *CancelOrderRequest) FromProto(m *v1.CancelOrderRequest) {
+	r.Reason = m.GetReason()
+}
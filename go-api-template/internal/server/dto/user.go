@@ -0,0 +1,100 @@
+package dto
+
+import (
+	v1 "go-api-template/api/user/v1"
+)
+
+// RegisterRequest 是 POST /api/v1/users/register 的请求体
+// 这里的规则与 biz 层一致，用于在 HTTP 入口给出字段级错误；gRPC 调用由 biz 层兜底校验
+type RegisterRequest struct {
+	// Username 登录名：3-32 位字母、数字或下划线
+	Username string `json:"username" binding:"required,min=3,max=32" example:"alice"`
+	// Password 密码：8-128 个字符
+	Password string `json:"password" binding:"required,min=8,max=128" example:"s3cret-passw0rd"`
+	// Email 邮箱，可选
+	Email string `json:"email" binding:"omitempty,email,max=254" example:"alice@example.com"`
+	// Nickname 昵称，可选
+	Nickname string `json:"nickname" binding:"max=50" example:"Alice"`
+}
+
+// ToProto 将 DTO 转换为 Proto 类型
+func (r *RegisterRequest) ToProto() *v1.RegisterRequest {
+	return &v1.RegisterRequest{
+		Username: r.Username,
+		Password: r.Password,
+		Email:    r.Email,
+		Nickname: r.Nickname,
+	}
+}
+
+// FromProto 从 protobuf 请求体填充 DTO
+func (r *RegisterRequest) FromProto(m *v1.RegisterRequest) {
+	r.Username = m.GetUsername()
+	r.Password = m.GetPassword()
+	r.Email = m.GetEmail()
+	r.Nickname = m.GetNickname()
+}
+
+// LoginRequest 是 POST /api/v1/users/login 的请求体
+type LoginRequest struct {
+	Username string `json:"username" binding:"required" example:"alice"`
+	Password string `json:"password" binding:"required" example:"s3cret-passw0rd"`
+}
+
+// ToProto 将 DTO 转换为 Proto 类型
+func (r *LoginRequest) ToProto() *v1.LoginRequest {
+	return &v1.LoginRequest{
+		Username: r.Username,
+		Password: r.Password,
+	}
+}
+
+// FromProto 从 protobuf 请求体填充 DTO
+func (r *LoginRequest) FromProto(m *v1.LoginRequest) {
+	r.Username = m.GetUsername()
+	r.Password = m.GetPassword()
+}
+
+// UpdateProfileRequest 是 PUT /api/v1/users/me 的请求体
+type UpdateProfileRequest struct {
+	// Email 邮箱，为空表示清除
+	Email string `json:"email" binding:"omitempty,email,max=254" example:"alice@example.com"`
+	// Nickname 昵称，为空表示保持不变
+	Nickname string `json:"nickname" binding:"max=50" example:"Alice"`
+}
+
+// ToProto 将 DTO 转换为 Proto 类型，version 来自 If-Match 头
+func (r *UpdateProfileRequest) ToProto(version int64) *v1.UpdateProfileRequest {
+	return &v1.UpdateProfileRequest{
+		Email:    r.Email,
+		Nickname: r.Nickname,
+		Version:  version,
+	}
+}
+
+// FromProto 从 protobuf 请求体填充 DTO，请求体中的 version 被忽略
+func (r *UpdateProfileRequest) FromProto(m *v1.UpdateProfileRequest) {
+	r.Email = m.GetEmail()
+	r.Nickname = m.GetNickname()
+}
+
+// ChangePasswordRequest 是 PUT /api/v1/users/me/password 的请求体
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required" example:"s3cret-passw0rd"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=128" example:"n3w-s3cret-passw0rd"`
+}
+
+// ToProto 将 DTO 转换为 Proto 类型，version 来自 If-Match 头
+func (r *ChangePasswordRequest) ToProto(version int64) *v1.ChangePasswordRequest {
+	return &v1.ChangePasswordRequest{
+		OldPassword: r.OldPassword,
+		NewPassword: r.NewPassword,
+		Version:     version,
+	}
+}
+
+// FromProto 从 protobuf 请求体填充 DTO，请求体中的 version 被忽略
+func (r *ChangePasswordRequest) FromProto(m *v1.ChangePasswordRequest) {
+	r.OldPassword = m.GetOldPassword()
+	r.NewPassword = m.GetNewPassword()
+}
@@ -0,0 +1,90 @@
+package dto
+
+import (
+	v1 "go-api-template/api/webhook/v1"
+)
+
+// CreateWebhookRequest 是 POST /api/v1/webhooks 的请求体
+type CreateWebhookRequest struct {
+	// URL 接收事件的地址，必须是 http 或 https，且主机解析到公网地址
+	URL string `json:"url" binding:"required,url,max=2000" example:"https://partner.example.com/hooks/greetings"`
+	// EventTypes 订阅的事件类型，"*" 表示全部
+	EventTypes []string `json:"event_types" binding:"required,min=1,max=50,dive,required" example:"greeter.greeting_created"`
+	// Secret 签名密钥（16-200 个字符），为空时自动生成
+	Secret string `json:"secret" binding:"omitempty,min=16,max=200" example:""`
+}
+
+// ToProto 将 DTO 转换为 Proto 类型
+func (r *CreateWebhookRequest) ToProto() *v1.CreateSubscriptionRequest {
+	return &v1.CreateSubscriptionRequest{
+		Url:        r.URL,
+		EventTypes: r.EventTypes,
+		Secret:     r.Secret,
+	}
+}
+
+// FromProto 从 protobuf 请求体填充 DTO
+func (r *CreateWebhookRequest) FromProto(m *v1.CreateSubscriptionRequest) {
+	r.URL = m.GetUrl()
+	r.EventTypes = m.GetEventTypes()
+	r.Secret = m.GetSecret()
+}
+
+// UpdateWebhookRequest 是 PATCH /api/v1/webhooks/:id 的请求体，省略的字段保持不变
+type UpdateWebhookRequest struct {
+	// URL 接收事件的地址
+	URL *string `json:"url" binding:"omitempty,url,max=2000" example:"https://partner.example.com/hooks/greetings"`
+	// EventTypes 订阅的事件类型
+	EventTypes []string `json:"event_types" binding:"omitempty,min=1,max=50,dive,required" example:"*"`
+	// Secret 轮换签名密钥，传空字符串时自动生成；新密钥在响应中返回
+	Secret *string `json:"secret" binding:"omitempty,max=200"`
+	// Active 是否启用
+	Active *bool `json:"active" example:"false"`
+}
+
+// ToProto 将 DTO 转换为 Proto 类型，id 来自 URL 路径
+func (r *UpdateWebhookRequest) ToProto(id int64) *v1.UpdateSubscriptionRequest {
+	return &v1.UpdateSubscriptionRequest{
+		Id:         id,
+		Url:        r.URL,
+		EventTypes: r.EventTypes,
+		Secret:     r.Secret,
+		Active:     r.Active,
+	}
+}
+
+// FromProto 从 protobuf 请求体填充 DTO，请求体中的 id 被忽略
+// optional 字段未设置时保持为 nil，与 JSON 请求体省略字段的语义一致
+func (r *UpdateWebhookRequest) FromProto(m *v1.UpdateSubscriptionRequest) {
+	r.URL = m.Url
+	r.EventTypes = m.GetEventTypes()
+	r.Secret = m.Secret
+	r.Active = m.Active
+}
+
+// ListWebhookDeliveriesQuery 是 GET /api/v1/webhooks/:id/deliveries 的查询参数
+// 这里只做格式校验，字段白名单、偏移量上限与游标由 Service 层按 biz.WebhookDeliveryListSpec 校验
+type ListWebhookDeliveriesQuery struct {
+	// Limit 每页条数，默认 50
+	Limit int32 `form:"limit" binding:"omitempty,min=1,max=100" example:"50"`
+	// Offset 偏移量
+	Offset int32 `form:"offset" binding:"omitempty,min=0" example:"0"`
+	// Cursor 上一页响应中的 next_cursor
+	Cursor string `form:"cursor" binding:"max=1024"`
+	// Sort 排序字段
+	Sort string `form:"sort" binding:"max=200" example:"-created_at"`
+	// Filter 过滤条件
+	Filter string `form:"filter" binding:"max=1000" example:"status=failed"`
+}
+
+// ToProto 将 DTO 转换为 Proto 类型，订阅 ID 来自 URL 路径
+func (q *ListWebhookDeliveriesQuery) ToProto(subscriptionID int64) *v1.ListDeliveriesRequest {
+	return &v1.ListDeliveriesRequest{
+		SubscriptionId: subscriptionID,
+		Limit:          q.Limit,
+		Offset:         q.Offset,
+		Cursor:         q.Cursor,
+		Sort:           q.Sort,
+		Filter:         q.Filter,
+	}
+}
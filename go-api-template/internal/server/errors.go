@@ -0,0 +1,231 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/pkg/featureflags"
+	"go-api-template/internal/pkg/feed"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/reason"
+	"go-api-template/internal/pkg/tenant"
+)
+
+// toAppError 将 Service 返回的错误映射为 AppError
+// 已经是 AppError 的直接返回；可识别的领域错误映射为对应的业务错误码；
+// 其余错误视为内部错误，原始错误只进入日志，不暴露给客户端
+func toAppError(err error) *apperrors.AppError {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	switch {
+	case errors.Is(err, biz.ErrNotFound):
+		return apperrors.NotFound(err.Error())
+	case errors.Is(err, biz.ErrAlreadyExists), errors.Is(err, biz.ErrConflict):
+		return apperrors.New(reason.Conflict, err.Error())
+	case errors.Is(err, biz.ErrVersionMismatch):
+		return apperrors.New(reason.PreconditionFailed, err.Error())
+	case errors.Is(err, biz.ErrInvalidArgument), errors.Is(err, pagination.ErrInvalid):
+		return apperrors.InvalidParams(err.Error())
+	case errors.Is(err, biz.ErrUnauthorized):
+		return apperrors.Unauthorized(err.Error())
+	case errors.Is(err, biz.ErrForbidden):
+		return apperrors.Forbidden(err.Error())
+	case errors.Is(err, tenant.ErrRequired), errors.Is(err, tenant.ErrInvalid):
+		return apperrors.InvalidParams(err.Error())
+	case errors.Is(err, tenant.ErrUnknown):
+		return apperrors.NotFound(err.Error())
+	case errors.Is(err, tenant.ErrMismatch):
+		return apperrors.Forbidden("访问令牌不属于当前租户")
+	case errors.Is(err, context.DeadlineExceeded):
+		return apperrors.Wrap(reason.Timeout, "请求处理超时", err)
+	case errors.Is(err, feed.ErrSlowConsumer):
+		return apperrors.New(reason.TooManyRequests, "消费过慢，推送已断开，请重新订阅")
+	case errors.Is(err, feed.ErrClosed):
+		return apperrors.New(reason.ServiceUnavailable, "服务正在停止，请稍后重新订阅")
+	default:
+		return apperrors.Internal("服务处理失败", err)
+	}
+}
+
+// grpcCodes 领域错误到 gRPC 状态码的映射，与 toAppError 保持一致
+// 版本冲突按 gRPC 约定使用 Aborted，提示客户端重新读取后重试整个"读取-修改-写入"；
+// 推送流被断开时，消费过慢使用 ResourceExhausted，服务停止使用 Unavailable（客户端可立即重连其他实例）
+var grpcCodes = map[error]codes.Code{
+	biz.ErrNotFound:        codes.NotFound,
+	biz.ErrAlreadyExists:   codes.AlreadyExists,
+	biz.ErrConflict:        codes.FailedPrecondition,
+	biz.ErrVersionMismatch: codes.Aborted,
+	biz.ErrInvalidArgument: codes.InvalidArgument,
+	pagination.ErrInvalid:  codes.InvalidArgument,
+	biz.ErrUnauthorized:    codes.Unauthenticated,
+	biz.ErrForbidden:       codes.PermissionDenied,
+	feed.ErrSlowConsumer:   codes.ResourceExhausted,
+	feed.ErrClosed:         codes.Unavailable,
+	tenant.ErrRequired:     codes.InvalidArgument,
+	tenant.ErrInvalid:      codes.InvalidArgument,
+	tenant.ErrUnknown:      codes.NotFound,
+	tenant.ErrMismatch:     codes.PermissionDenied,
+
+	context.DeadlineExceeded: codes.DeadlineExceeded,
+	context.Canceled:         codes.Canceled,
+}
+
+// unaryErrorInterceptor 将 Service 返回的领域错误转换为 gRPC status
+// 未识别的错误交给 gRPC 默认处理（codes.Unknown）
+func unaryErrorInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	return resp, toGRPCError(err)
+}
+
+// streamErrorInterceptor 流式 RPC 版本的 unaryErrorInterceptor
+func streamErrorInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return toGRPCError(handler(srv, ss))
+}
+
+// toGRPCError 按 grpcCodes 把错误转换为 gRPC status，已经是 status 的错误原样返回
+func toGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	for target, code := range grpcCodes {
+		if errors.Is(err, target) {
+			return status.Error(code, err.Error())
+		}
+	}
+	return err
+}
+
+// unaryTimeoutInterceptor 按 server.request_timeout / route_timeouts 为请求附加处理时限
+// 客户端通过 grpc-timeout 要求的截止时间已由 gRPC 放入 context，WithTimeout 保留两者中较早的一个；
+// 流式 RPC 是长连接，不附加时限
+func unaryTimeoutInterceptor(cfg conf.ServerConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		timeout := cfg.GetRouteTimeout(info.FullMethod)
+		if timeout == 0 {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// unaryAuthInterceptor 解析 authorization 元数据中的访问令牌
+// 携带了令牌但校验失败时直接拒绝；未携带令牌的请求照常放行，
+// 由需要登录的方法自行检查 context 中是否有用户身份（Register、Login 等方法无需登录）；
+// 令牌必须属于租户拦截器解析出的租户
+func unaryAuthInterceptor(tokens *auth.TokenManager) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return handler(ctx, req)
+		}
+		claims, err := tokens.ParseAuthorization(values[0])
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid or expired access token")
+		}
+		if err := tenant.Authorize(ctx, claims.Tenant); err != nil {
+			return nil, toGRPCError(err)
+		}
+		return handler(auth.NewContext(ctx, claims), req)
+	}
+}
+
+// streamAuthInterceptor 流式 RPC 版本的 unaryAuthInterceptor
+func streamAuthInterceptor(tokens *auth.TokenManager) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return handler(srv, ss)
+		}
+		claims, err := tokens.ParseAuthorization(values[0])
+		if err != nil {
+			return status.Error(codes.Unauthenticated, "invalid or expired access token")
+		}
+		if err := tenant.Authorize(ss.Context(), claims.Tenant); err != nil {
+			return toGRPCError(err)
+		}
+		return handler(srv, &authServerStream{ServerStream: ss, ctx: auth.NewContext(ss.Context(), claims)})
+	}
+}
+
+// unaryTenantInterceptor 从元数据解析请求所属的租户并放入 context，位于认证拦截器之前
+// 来源与 HTTP 的 Tenant 中间件一致：租户元数据、:authority 的子域名、访问令牌
+func unaryTenantInterceptor(resolver *tenant.Resolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id, err := resolveTenant(ctx, resolver)
+		if err != nil {
+			return nil, toGRPCError(err)
+		}
+		return handler(tenant.NewContext(ctx, id), req)
+	}
+}
+
+// streamTenantInterceptor 流式 RPC 版本的 unaryTenantInterceptor
+func streamTenantInterceptor(resolver *tenant.Resolver) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id, err := resolveTenant(ss.Context(), resolver)
+		if err != nil {
+			return toGRPCError(err)
+		}
+		return handler(srv, &authServerStream{ServerStream: ss, ctx: tenant.NewContext(ss.Context(), id)})
+	}
+}
+
+// resolveTenant 按元数据解析租户
+func resolveTenant(ctx context.Context, resolver *tenant.Resolver) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	return resolver.Resolve(tenant.Source{
+		Host:          first(":authority"),
+		Header:        first(strings.ToLower(resolver.Header())),
+		Authorization: first("authorization"),
+	})
+}
+
+// unaryFeatureFlagsInterceptor 把功能开关放入 context，供 biz 层判断，与 HTTP 的 FeatureFlags 中间件一致
+func unaryFeatureFlagsInterceptor(flags *featureflags.Manager) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(featureflags.NewContext(ctx, flags), req)
+	}
+}
+
+// streamFeatureFlagsInterceptor 流式 RPC 版本的 unaryFeatureFlagsInterceptor
+func streamFeatureFlagsInterceptor(flags *featureflags.Manager) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &authServerStream{ServerStream: ss, ctx: featureflags.NewContext(ss.Context(), flags)})
+	}
+}
+
+// authServerStream 替换 ServerStream 的 context，使 Service 能从中取得租户、用户身份
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context 返回替换后的 context
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
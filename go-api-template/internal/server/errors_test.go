@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/reason"
+)
+
+func TestToAppError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantReason reason.Reason
+		wantStatus int
+	}{
+		{"forbidden order transition", fmt.Errorf("order 1: %w: cannot ship an order in status pending", biz.ErrConflict),
+			reason.Conflict, http.StatusConflict},
+		{"already exists", fmt.Errorf("user alice: %w", biz.ErrAlreadyExists), reason.Conflict, http.StatusConflict},
+		{"version mismatch", fmt.Errorf("order 1: %w", biz.ErrVersionMismatch), reason.PreconditionFailed, http.StatusPreconditionFailed},
+		{"not found", fmt.Errorf("order 1: %w", biz.ErrNotFound), reason.NotFound, http.StatusNotFound},
+		{"invalid argument", fmt.Errorf("%w: quantity must be positive", biz.ErrInvalidArgument), reason.InvalidParams, http.StatusBadRequest},
+		{"deadline", context.DeadlineExceeded, reason.Timeout, http.StatusGatewayTimeout},
+		{"app error is kept", apperrors.Forbidden("no"), reason.Forbidden, http.StatusForbidden},
+		{"unknown error", errors.New("boom"), reason.InternalError, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toAppError(tt.err)
+			if got.Code != tt.wantReason || got.HTTPCode != tt.wantStatus {
+				t.Errorf("toAppError = %s/%d, want %s/%d", got.Code, got.HTTPCode, tt.wantReason, tt.wantStatus)
+			}
+		})
+	}
+}
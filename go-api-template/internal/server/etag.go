@@ -0,0 +1,72 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/reason"
+	"go-api-template/internal/server/response"
+)
+
+// HTTP 条件请求
+//
+// 可修改的资源在响应中以 ETag 头返回版本号：
+//   - 修改请求携带 If-Match 时，版本比较交给 biz 层与仓储的条件更新完成，
+//     这样"比较 + 写入"是原子的，不会在 HTTP 层检查通过后又被并发请求抢先修改
+//   - 读取请求携带 If-None-Match 且版本未变化时返回 304，不再传输响应体
+
+// etag 资源版本对应的实体标签
+// 同一 URL 下版本号唯一确定资源状态，直接以版本号作为强校验标签
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// setETag 在响应头中写出资源版本，版本为 0（资源没有版本）时不写
+func setETag(c *gin.Context, version int64) {
+	if version > 0 {
+		c.Header("ETag", etag(version))
+	}
+}
+
+// ifMatchVersion 解析 If-Match 头，返回客户端期望的版本，0 表示不校验
+// "*" 只要求资源存在，而资源不存在时本来就会返回 404，因此同样不校验版本；
+// 弱标签和无法识别的标签不可能与任何版本强匹配，直接返回 412。
+// 解析失败时已写出错误响应，调用方直接 return 即可
+func ifMatchVersion(c *gin.Context) (int64, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	if strings.Contains(header, ",") {
+		response.ErrorJSON(c, apperrors.InvalidParams("If-Match 只支持单个实体标签"))
+		return 0, false
+	}
+	version, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(header, `"`), `"`), 10, 64)
+	if err != nil || version <= 0 || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		response.ErrorJSON(c, apperrors.New(reason.PreconditionFailed, "If-Match 与资源当前版本不匹配"))
+		return 0, false
+	}
+	return version, true
+}
+
+// notModified 判断 If-None-Match 是否命中当前版本，命中时写出 304 响应
+// If-None-Match 使用弱比较，W/ 前缀被忽略；调用方需在此之前通过 setETag 写出 ETag
+func notModified(c *gin.Context, version int64) bool {
+	header := c.GetHeader("If-None-Match")
+	if header == "" || version <= 0 {
+		return false
+	}
+	current := etag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/pkg/reason"
+)
+
+// conditionalResponse 条件请求的响应
+type conditionalResponse struct {
+	status int
+	etag   string
+	code   reason.Reason
+	body   string
+}
+
+// doConditional 以 acme 租户发送请求，headers 为成对的请求头名称与取值
+func doConditional(t *testing.T, method, url, body string, headers ...string) conditionalResponse {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	req.Header.Set(testTenantHeader, "acme")
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	var decoded struct {
+		Code reason.Reason `json:"code"`
+	}
+	_ = json.Unmarshal(data, &decoded)
+	return conditionalResponse{status: resp.StatusCode, etag: resp.Header.Get("ETag"), code: decoded.Code, body: string(data)}
+}
+
+func TestSetETag(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int64
+		want    string
+	}{
+		{name: "versioned resource", version: 3, want: `"3"`},
+		{name: "no version", version: 0, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			setETag(c, tt.version)
+			if got := c.Writer.Header().Get("ETag"); got != tt.want {
+				t.Errorf("ETag = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		want       int64
+		wantStatus int // 0 表示解析成功，没有写出响应
+		wantReason reason.Reason
+	}{
+		{name: "no header", want: 0},
+		{name: "any version", header: "*", want: 0},
+		{name: "strong tag", header: `"3"`, want: 3},
+		{name: "surrounding spaces", header: ` "3" `, want: 3},
+		{name: "weak tag", header: `W/"3"`, wantStatus: http.StatusPreconditionFailed, wantReason: reason.PreconditionFailed},
+		{name: "unquoted", header: "3", wantStatus: http.StatusPreconditionFailed, wantReason: reason.PreconditionFailed},
+		{name: "zero version", header: `"0"`, wantStatus: http.StatusPreconditionFailed, wantReason: reason.PreconditionFailed},
+		{name: "not a version", header: `"abc"`, wantStatus: http.StatusPreconditionFailed, wantReason: reason.PreconditionFailed},
+		{name: "several tags", header: `"1", "2"`, wantStatus: http.StatusBadRequest, wantReason: reason.InvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+			got, ok := ifMatchVersion(c)
+			if ok != (tt.wantStatus == 0) || got != tt.want {
+				t.Fatalf("ifMatchVersion = %d, %v; want %d, %v", got, ok, tt.want, tt.wantStatus == 0)
+			}
+			if ok {
+				if c.Writer.Written() {
+					t.Errorf("response written for an accepted header: %d %s", w.Code, w.Body)
+				}
+				return
+			}
+			var body struct {
+				Code reason.Reason `json:"code"`
+			}
+			if w.Code != tt.wantStatus || json.Unmarshal(w.Body.Bytes(), &body) != nil || body.Code != tt.wantReason {
+				t.Errorf("response = %d %s, want %d %s", w.Code, w.Body, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		version int64
+		want    bool
+	}{
+		{name: "no header", version: 3, want: false},
+		{name: "current version", header: `"3"`, version: 3, want: true},
+		{name: "weak tag of current version", header: `W/"3"`, version: 3, want: true},
+		{name: "list containing current version", header: `"1", W/"3"`, version: 3, want: true},
+		{name: "any version", header: "*", version: 3, want: true},
+		{name: "changed version", header: `"2"`, version: 3, want: false},
+		{name: "unversioned resource", header: "*", version: 0, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-None-Match", tt.header)
+			}
+			if got := notModified(c, tt.version); got != tt.want {
+				t.Fatalf("notModified = %v, want %v", got, tt.want)
+			}
+			c.Writer.WriteHeaderNow()
+			if tt.want && w.Code != http.StatusNotModified {
+				t.Errorf("status = %d, want 304", w.Code)
+			}
+			if !tt.want && w.Code != http.StatusOK {
+				t.Errorf("status = %d, want the response left to the handler", w.Code)
+			}
+		})
+	}
+}
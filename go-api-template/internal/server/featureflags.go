@@ -0,0 +1,59 @@
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+
+	v1 "go-api-template/api/featureflags/v1"
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/server/dto"
+	"go-api-template/internal/server/middleware"
+	"go-api-template/internal/server/response"
+	"go-api-template/internal/service"
+)
+
+// registerFeatureFlagRoutes 注册功能开关管理的 HTTP 路由
+// 路由要求登录，是否为管理员由 Service 层按 admin.users 判断
+func registerFeatureFlagRoutes(group *gin.RouterGroup, svc *service.FeatureFlagService, tokens *auth.TokenManager) {
+	flags := group.Group("/admin/feature-flags", middleware.RequireAuth(tokens))
+	flags.GET("", handleListFeatureFlags(svc))
+}
+
+// registerFeatureFlagGRPC 注册功能开关管理的 gRPC 实现
+func registerFeatureFlagGRPC(srv *grpc.Server, svc *service.FeatureFlagService) {
+	v1.RegisterFeatureFlagServiceServer(srv, svc)
+}
+
+// handleListFeatureFlags 列出功能开关
+//
+// @Summary      功能开关列表
+// @Description  按名称返回当前生效的功能开关、对求值对象的结果及本实例的求值次数；仅管理员可用。
+// @Description  求值对象默认为调用者自己（当前租户、当前用户与当前环境），查询参数可以替换其中的部分
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        tenant      query    string false "求值使用的租户"
+// @Param        user        query    string false "求值使用的用户名"
+// @Param        environment query    string false "求值使用的运行环境"
+// @Success      200         {object} response.Response{data=v1.ListFeatureFlagsResponse} "成功"
+// @Failure      400         {object} response.Response "请求参数错误"
+// @Failure      401         {object} response.Response "未登录或令牌无效"
+// @Failure      403         {object} response.Response "不是管理员"
+// @Router       /admin/feature-flags [get]
+func handleListFeatureFlags(svc *service.FeatureFlagService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query dto.ListFeatureFlagsQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			return
+		}
+
+		resp, err := svc.ListFeatureFlags(c.Request.Context(), query.ToProto())
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
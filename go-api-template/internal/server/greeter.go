@@ -0,0 +1,303 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc"
+
+	v1 "go-api-template/api/helloworld/v1"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/pkg/tenant"
+	"go-api-template/internal/server/dto"
+	"go-api-template/internal/server/middleware"
+	"go-api-template/internal/server/response"
+	"go-api-template/internal/service"
+)
+
+// registerGreeterRoutes 注册 Greeter 服务的 HTTP 路由
+// 将 gRPC 风格的服务暴露为 RESTful HTTP 端点
+func registerGreeterRoutes(group *gin.RouterGroup, svc *service.GreeterService, cfg conf.GreeterConfig,
+	tokens *auth.TokenManager, sockets *wsHub) {
+	// 问候无需登录；携带令牌时按用户求值功能开关
+	optionalAuth := middleware.OptionalAuth(tokens)
+
+	// POST /api/v1/greeter/say-hello
+	// 请求体: {"name": "World"}
+	// 响应体: {"message": "Hello, World! You are visitor #1."}
+	group.POST("/greeter/say-hello", optionalAuth, handleSayHello(svc))
+
+	// GET /api/v1/greeter/say-hello/:name
+	// 便捷的 GET 端点，name 作为 URL 参数
+	group.GET("/greeter/say-hello/:name", optionalAuth, handleSayHelloByPath(svc))
+
+	// GET /api/v1/greeter/greetings
+	// 分页列出当前租户的问候记录
+	group.GET("/greeter/greetings", handleListGreetings(svc))
+
+	// GET /api/v1/greeter/greetings/:id
+	// 按 ID 获取问候记录，支持 If-None-Match 条件请求
+	group.GET("/greeter/greetings/:id", handleGetGreeting(svc))
+
+	// GET /api/v1/greeter/stream
+	// 以 SSE 推送新保存的问候，对应 gRPC 的 WatchGreetings
+	group.GET("/greeter/stream", handleWatchGreetings(svc, cfg.Stream))
+
+	// GET /api/v1/greeter/session
+	// WebSocket 交互式问候会话，对应 gRPC 的 GreetSession
+	group.GET("/greeter/session", handleGreetSession(svc, cfg.Session, tokens, sockets))
+}
+
+// registerGreeterGRPC 注册 Greeter 服务的 gRPC 实现
+func registerGreeterGRPC(srv *grpc.Server, svc *service.GreeterService) {
+	v1.RegisterGreeterServiceServer(srv, svc)
+}
+
+// handleSayHello 处理 POST 请求
+// 使用 DTO 接收请求，Validator 自动验证，然后转换为 Proto 类型调用 Service
+//
+// @Summary      发送问候
+// @Description  向指定用户发送问候消息，返回问候语和访问计数
+// @Tags         greeter
+// @Accept       json
+// @Produce      json
+// @Param        request body     dto.SayHelloRequest true "问候请求参数"
+// @Success      200     {object} response.Response{data=v1.SayHelloResponse} "成功"
+// @Header       200     {string} ETag "问候记录版本"
+// @Failure      400     {object} response.Response "请求参数错误"
+// @Failure      401     {object} response.Response "携带的令牌无效"
+// @Failure      413     {object} response.Response "请求体过大"
+// @Failure      415     {object} response.Response "请求体格式不支持"
+// @Failure      500     {object} response.Response "服务内部错误"
+// @Router       /greeter/say-hello [post]
+func handleSayHello(svc *service.GreeterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 使用 DTO 接收请求（DTO 有 binding tag，会自动验证）
+		var req dto.SayHelloRequest
+
+		// bindBody 会：
+		// 1. 按 Content-Type 把 JSON、protobuf 或 MessagePack 请求体填充到 req
+		// 2. 根据 binding tag 验证（required, min=1, max=100）
+		// 3. 验证失败返回错误,err!=nil 表示验证失败
+		// 请求体的格式与大小已由 BodyLimit 中间件约束
+		if err := bindBody(c, &req, &v1.SayHelloRequest{}); err != nil {
+			// 使用统一响应：将 validator 错误转换为 AppError，再输出
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			return
+		}
+
+		// DTO 转 Proto，调用 Service
+		resp, err := svc.SayHello(c.Request.Context(), req.ToProto())
+		if err != nil {
+			// 使用统一响应：领域错误与超时映射为对应的错误码，其余为内部错误
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+
+		// 使用统一响应：成功响应
+		// 最佳实践：直接传递结构体（DTO 或 Proto），避免手动构造 map
+		setETag(c, resp.GetGreeting().GetVersion())
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleSayHelloByPath 处理 GET 请求，name 从 URL 路径获取
+//
+// @Summary      发送问候（URL参数）
+// @Description  通过 URL 路径参数向指定用户发送问候消息
+// @Tags         greeter
+// @Accept       json
+// @Produce      json
+// @Param        name path     string true "用户名称" minlength(1) maxlength(100)
+// @Success      200  {object} response.Response{data=v1.SayHelloResponse} "成功"
+// @Header       200  {string} ETag "问候记录版本"
+// @Failure      400  {object} response.Response "请求参数错误"
+// @Failure      401  {object} response.Response "携带的令牌无效"
+// @Failure      500  {object} response.Response "服务内部错误"
+// @Router       /greeter/say-hello/{name} [get]
+func handleSayHelloByPath(svc *service.GreeterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		if name == "" {
+			// 使用统一响应
+			response.ErrorJSON(c, apperrors.InvalidParams("name 参数是必填的"))
+			return
+		}
+
+		// 构造 proto 请求
+		req := &v1.SayHelloRequest{Name: name}
+
+		// 调用服务
+		resp, err := svc.SayHello(c.Request.Context(), req)
+		if err != nil {
+			// 使用统一响应：领域错误与超时映射为对应的错误码，其余为内部错误
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+
+		// 使用统一响应：成功响应
+		// 灵活用法：使用 response.Body (map[string]any) 构造临时数据
+		setETag(c, resp.GetGreeting().GetVersion())
+		response.SuccessJSON(c, response.Body{
+			"message": resp.GetMessage(),
+		})
+	}
+}
+
+// handleListGreetings 分页列出当前租户的问候记录
+//
+// @Summary      问候记录列表
+// @Description  默认按 ID 倒序使用游标分页：把响应中的 next_cursor 作为下一次请求的 cursor，为空表示没有下一页。
+// @Description  游标与 sort、filter 绑定，修改条件后需从第一页开始；offset 大于 0 时改用偏移分页，不能与 cursor 同时使用。
+// @Description  可排序字段：id、created_at；可过滤字段：created_at（支持 = != > >= < <=）、name（支持 = !=，| 分隔多个取值）。
+// @Description  时间使用 RFC 3339 格式。
+// @Tags         greeter
+// @Produce      json
+// @Param        limit  query    int    false "每页条数（1-100），默认 20"
+// @Param        offset query    int    false "偏移量（0-10000）"
+// @Param        cursor query    string false "上一页响应中的 next_cursor"
+// @Param        sort   query    string false "排序字段，逗号分隔，- 前缀表示倒序，默认 -id" example(-created_at)
+// @Param        filter query    string false "过滤条件，逗号分隔，之间为 AND 关系" example(name=World,created_at>=2024-01-01T00:00:00Z)
+// @Success      200    {object} response.Response{data=v1.ListGreetingsResponse} "成功"
+// @Failure      400    {object} response.Response "分页、排序或过滤参数错误"
+// @Router       /greeter/greetings [get]
+func handleListGreetings(svc *service.GreeterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query dto.ListGreetingsQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			return
+		}
+
+		resp, err := svc.ListGreetings(c.Request.Context(), query.ToProto())
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleGetGreeting 按 ID 获取问候记录
+//
+// @Summary      问候记录详情
+// @Description  响应头 ETag 为问候记录版本；携带 If-None-Match 且版本未变化时返回 304
+// @Tags         greeter
+// @Produce      json
+// @Param        id            path     int    true  "问候记录 ID"
+// @Param        If-None-Match header   string false "上次获取的 ETag"
+// @Success      200 {object} response.Response{data=v1.GetGreetingResponse} "成功"
+// @Header       200 {string} ETag "问候记录版本"
+// @Success      304 "问候记录未变化"
+// @Failure      400 {object} response.Response "请求参数错误"
+// @Failure      404 {object} response.Response "问候记录不存在"
+// @Router       /greeter/greetings/{id} [get]
+func handleGetGreeting(svc *service.GreeterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c)
+		if !ok {
+			return
+		}
+
+		resp, err := svc.GetGreeting(c.Request.Context(), &v1.GetGreetingRequest{Id: id})
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		setETag(c, resp.GetGreeting().GetVersion())
+		if notModified(c, resp.GetGreeting().GetVersion()) {
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleWatchGreetings 以 Server-Sent Events 推送新问候
+// 每条问候是一个 greeting 事件，事件 ID 为问候记录 ID；浏览器 EventSource 断线重连时会自动携带
+// Last-Event-ID 请求头，服务端从该 ID 之后补发，重连期间的问候不会丢失
+//
+// @Summary      订阅新问候（SSE）
+// @Description  text/event-stream 长连接，每条新问候推送一个 greeting 事件（id 为问候 ID，data 为 v1.Greeting）；
+// @Description  消费过慢或服务停止时推送 error 事件后断开，data 与 JSON 接口的错误响应结构相同
+// @Tags         greeter
+// @Produce      text/event-stream
+// @Param        name          query    string false "只接收该名称的问候" maxlength(100)
+// @Param        last_event_id query    int    false "从该 ID 之后开始推送"
+// @Param        Last-Event-ID header   int    false "同 last_event_id，EventSource 重连时自动携带，优先于查询参数"
+// @Success      200           {object} v1.Greeting "greeting 事件的 data"
+// @Failure      400           {object} response.Response "请求参数错误"
+// @Router       /greeter/stream [get]
+func handleWatchGreetings(svc *service.GreeterService, cfg conf.GreeterStreamConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query dto.WatchGreetingsQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			return
+		}
+		if header := c.GetHeader("Last-Event-ID"); header != "" {
+			id, err := strconv.ParseInt(header, 10, 64)
+			if err != nil || id < 0 {
+				response.ErrorJSON(c, apperrors.InvalidParams("Last-Event-ID 必须是非负整数"))
+				return
+			}
+			query.LastEventID = id
+		}
+
+		req := query.ToProto()
+		serveSSE(c, "greeting", cfg.GetHeartbeat(),
+			func(msg *v1.WatchGreetingsResponse) (int64, any) {
+				return msg.GetGreeting().GetId(), msg.GetGreeting()
+			},
+			func(stream grpc.ServerStreamingServer[v1.WatchGreetingsResponse]) error {
+				return svc.WatchGreetings(req, stream)
+			})
+	}
+}
+
+// handleGreetSession 把请求升级为 WebSocket，进行交互式问候会话
+// 握手时校验访问令牌：浏览器的 WebSocket API 无法设置请求头，因此除 Authorization 外也接受 access_token 查询参数
+//
+// @Summary      交互式问候会话（WebSocket）
+// @Description  握手成功后，客户端每发送一条 {"name": "..."} 文本消息，服务端回复 {"greeting": {...}}；
+// @Description  会话期间同时推送 {"presence": {...}} 在线状态变化，单条消息出错时回复 {"error": {"code", "message"}}。
+// @Description  服务端每隔 greeter.session.ping_interval 发送 ping；服务停止时以 1001 关闭，发送过慢以 1013 关闭
+// @Tags         greeter
+// @Security     BearerAuth
+// @Param        access_token query    string false "访问令牌，无法设置 Authorization 请求头时使用"
+// @Success      101          {object} v1.GreetSessionResponse "切换为 WebSocket 协议，之后每条服务端消息的结构"
+// @Failure      400          {object} response.Response "不是 WebSocket 握手请求"
+// @Failure      401          {object} response.Response "未登录或令牌无效"
+// @Failure      403          {object} response.Response "令牌不属于当前租户"
+// @Router       /greeter/session [get]
+func handleGreetSession(svc *service.GreeterService, cfg conf.GreeterSessionConfig,
+	tokens *auth.TokenManager, sockets *wsHub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" && c.Query("access_token") != "" {
+			header = "Bearer " + c.Query("access_token")
+		}
+		if header == "" {
+			response.ErrorJSON(c, apperrors.Unauthorized("缺少访问令牌"))
+			return
+		}
+		claims, err := tokens.ParseAuthorization(header)
+		if err != nil {
+			response.ErrorJSON(c, apperrors.Unauthorized("访问令牌无效或已过期"))
+			return
+		}
+		if err := tenant.Authorize(c.Request.Context(), claims.Tenant); err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		if !websocket.IsWebSocketUpgrade(c.Request) {
+			response.ErrorJSON(c, apperrors.InvalidParams("需要 WebSocket 握手请求"))
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), claims))
+		serveWebSocket(c, sockets, cfg, svc.GreetSession)
+	}
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/reason"
+	"go-api-template/internal/service"
+)
+
+// newGreeterServer 启动只注册了问候记录列表与详情接口的测试服务器
+func newGreeterServer(t *testing.T, svc *service.GreeterService) string {
+	t.Helper()
+	engine := gin.New()
+	engine.Use(withTestTenant)
+	engine.GET("/greeter/greetings", handleListGreetings(svc))
+	engine.GET("/greeter/greetings/:id", handleGetGreeting(svc))
+	srv := httptest.NewServer(engine)
+	t.Cleanup(srv.Close)
+	return srv.URL + "/greeter/greetings"
+}
+
+// listGreetingsBody GET /greeter/greetings 的响应体，protojson 把 int64 编码为字符串
+type listGreetingsBody struct {
+	Code reason.Reason `json:"code"`
+	Data struct {
+		Greetings []struct {
+			Name string `json:"name"`
+		} `json:"greetings"`
+		NextCursor string `json:"next_cursor"`
+		Total      string `json:"total"`
+	} `json:"data"`
+}
+
+// listGreetings 以 acme 租户请求问候记录列表
+func listGreetings(t *testing.T, base string, query url.Values) (int, *listGreetingsBody) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, base+"?"+query.Encode(), nil)
+	req.Header.Set(testTenantHeader, "acme")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	var body listGreetingsBody
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return resp.StatusCode, &body
+}
+
+func TestListGreetingsHTTP(t *testing.T) {
+	svc := newTestGreeterService(t, &conf.Config{})
+	base := newGreeterServer(t, svc)
+	for _, name := range []string{"alice", "bob", "carol", "bob"} {
+		sayHello(t, svc, "acme", name)
+	}
+	sayHello(t, svc, "globex", "dave")
+
+	// 沿着 next_cursor 翻页，直到游标为空
+	var names []string
+	query := url.Values{"limit": {"2"}, "filter": {"name!=carol"}}
+	for page := 1; ; page++ {
+		status, body := listGreetings(t, base, query)
+		if status != http.StatusOK || body.Data.Total != "3" {
+			t.Fatalf("page %d = %d %+v, want 200 with total 3", page, status, body)
+		}
+		for _, g := range body.Data.Greetings {
+			names = append(names, g.Name)
+		}
+		if body.Data.NextCursor == "" {
+			break
+		}
+		if page == 3 {
+			t.Fatal("next_cursor never became empty")
+		}
+		query.Set("cursor", body.Data.NextCursor)
+	}
+	if want := []string{"bob", "bob", "alice"}; !slices.Equal(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+
+	// 第一页的游标
+	_, first := listGreetings(t, base, url.Values{"limit": {"1"}})
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{name: "limit too large", query: url.Values{"limit": {"101"}}},
+		{name: "unknown sort field", query: url.Values{"sort": {"message"}}},
+		{name: "unknown filter field", query: url.Values{"filter": {"message=hi"}}},
+		{name: "malformed filter time", query: url.Values{"filter": {"created_at>yesterday"}}},
+		{name: "forged cursor", query: url.Values{"cursor": {"e30.AAAA"}}},
+		{name: "cursor with another filter", query: url.Values{"limit": {"1"}, "filter": {"name=bob"}, "cursor": {first.Data.NextCursor}}},
+		{name: "cursor with offset", query: url.Values{"offset": {"1"}, "cursor": {first.Data.NextCursor}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := listGreetings(t, base, tt.query)
+			if status != http.StatusBadRequest || body.Code != reason.InvalidParams {
+				t.Errorf("response = %d %s, want 400 %s", status, body.Code, reason.InvalidParams)
+			}
+		})
+	}
+}
+
+func TestGetGreetingConditional(t *testing.T) {
+	svc := newTestGreeterService(t, &conf.Config{})
+	base := newGreeterServer(t, svc)
+	url := base + "/" + strconv.FormatInt(sayHello(t, svc, "acme", "alice"), 10)
+
+	tests := []struct {
+		name       string
+		url        string
+		headers    []string
+		wantStatus int
+		wantETag   string
+	}{
+		{name: "plain GET", url: url, wantStatus: http.StatusOK, wantETag: `"1"`},
+		{name: "unchanged", url: url, headers: []string{"If-None-Match", `"1"`}, wantStatus: http.StatusNotModified, wantETag: `"1"`},
+		{name: "weak tag", url: url, headers: []string{"If-None-Match", `W/"1"`}, wantStatus: http.StatusNotModified, wantETag: `"1"`},
+		{name: "stale tag", url: url, headers: []string{"If-None-Match", `"7"`}, wantStatus: http.StatusOK, wantETag: `"1"`},
+		{name: "missing greeting", url: base + "/999999", headers: []string{"If-None-Match", "*"}, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := doConditional(t, http.MethodGet, tt.url, "", tt.headers...)
+			if resp.status != tt.wantStatus || resp.etag != tt.wantETag {
+				t.Errorf("response = %d ETag %q, want %d ETag %q", resp.status, resp.etag, tt.wantStatus, tt.wantETag)
+			}
+			// 304 不带响应体，客户端继续使用缓存
+			if tt.wantStatus == http.StatusNotModified && resp.body != "" {
+				t.Errorf("304 body = %q, want empty", resp.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+
+	"google.golang.org/grpc"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/pkg/crash"
+	"go-api-template/internal/pkg/featureflags"
+	"go-api-template/internal/pkg/tenant"
+)
+
+// GRPCServer 封装 gRPC 服务器
+// 与 HTTPServer 共享同一组 Service 实现，proto 定义的每个 RPC 同时通过两种协议暴露
+type GRPCServer struct {
+	server *grpc.Server
+	addr   string
+	svcs   *Services
+}
+
+// NewGRPCServer 创建 gRPC 服务器并注册所有服务
+// 恢复拦截器位于错误转换之后，其余拦截器与方法中的 panic 都会被捕获并上报给 reporter；
+// 租户拦截器位于认证之前，认证时校验令牌所属的租户
+func NewGRPCServer(cfg *conf.Config, tokens *auth.TokenManager, resolver *tenant.Resolver, flags *featureflags.Manager,
+	reporter crash.PanicReporter, svcs *Services) *GRPCServer {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			unaryErrorInterceptor,
+			unaryRecoveryInterceptor(reporter),
+			unaryTimeoutInterceptor(cfg.Server),
+			unaryTenantInterceptor(resolver),
+			unaryFeatureFlagsInterceptor(flags),
+			unaryAuthInterceptor(tokens),
+		),
+		grpc.ChainStreamInterceptor(
+			streamErrorInterceptor,
+			streamRecoveryInterceptor(reporter),
+			streamTenantInterceptor(resolver),
+			streamFeatureFlagsInterceptor(flags),
+			streamAuthInterceptor(tokens),
+		),
+	)
+
+	// 注册各模块的 gRPC 服务
+	registerGreeterGRPC(srv, svcs.Greeter)
+	registerUserGRPC(srv, svcs.User)
+	registerOrderGRPC(srv, svcs.Order)
+	registerWebhookGRPC(srv, svcs.Webhook)
+	registerJobGRPC(srv, svcs.Job)
+	registerFeatureFlagGRPC(srv, svcs.FeatureFlag)
+	// gen:grpc - cmd/gen 在此处插入新模块的 gRPC 注册
+
+	return &GRPCServer{
+		server: srv,
+		addr:   fmt.Sprintf(":%d", cfg.Server.GetGRPCPort()),
+		svcs:   svcs,
+	}
+}
+
+// Start 启动 gRPC 服务器（非阻塞）
+// 返回的 channel 在监听失败或服务异常退出时收到错误
+func (s *GRPCServer) Start() <-chan error {
+	errChan := make(chan error, 1)
+	go func() {
+		defer close(errChan)
+		lis, err := net.Listen("tcp", s.addr)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		if err := s.server.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			errChan <- err
+		}
+	}()
+	return errChan
+}
+
+// Stop 优雅关闭 gRPC 服务器
+// GracefulStop 会等待所有进行中的 RPC 完成，ctx 超时后强制关闭；
+// 推送流不会自行结束，先断开它们再等待
+func (s *GRPCServer) Stop(ctx context.Context) error {
+	s.svcs.closeStreams()
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
+// Addr 返回服务器监听地址
+func (s *GRPCServer) Addr() string {
+	return s.addr
+}
+
+// Methods 返回已注册的所有 gRPC 方法全名，如 /helloworld.v1.GreeterService/SayHello
+func (s *GRPCServer) Methods() []string {
+	var methods []string
+	for name, info := range s.server.GetServiceInfo() {
+		for _, m := range info.Methods {
+			methods = append(methods, "/"+name+"/"+m.Name)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"io"
+
+	"google.golang.org/grpc/encoding"
+
+	"go-api-template/internal/pkg/compress"
+)
+
+// 注册与 HTTP 响应压缩相同的编码器
+// gRPC 服务端按请求的 grpc-encoding 解压，并以同一编码压缩响应；客户端未压缩时响应也不压缩。
+// encoding.RegisterCompressor 不是并发安全的，只能在 init 中调用
+func init() {
+	for _, name := range compress.Names() {
+		encoding.RegisterCompressor(grpcCompressor{codec: compress.Lookup(name)})
+	}
+}
+
+// grpcCompressor 把 compress.Codec 适配为 encoding.Compressor
+type grpcCompressor struct {
+	codec *compress.Codec
+}
+
+// Compress 实现 encoding.Compressor
+func (c grpcCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return c.codec.Writer(w), nil
+}
+
+// Decompress 实现 encoding.Compressor
+func (c grpcCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return c.codec.Reader(r)
+}
+
+// Name 实现 encoding.Compressor
+func (c grpcCompressor) Name() string {
+	return c.codec.Name()
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go-api-template/internal/pkg/crash"
+	"go-api-template/internal/server/middleware"
+)
+
+// grpcRequestIDKey 请求 ID 的元数据键，与 HTTP 的 X-Request-ID 对应
+var grpcRequestIDKey = strings.ToLower(middleware.HeaderXRequestID)
+
+// unaryRecoveryInterceptor 捕获 gRPC 方法中的 panic，与 HTTP 的 middleware.Recovery 相同：
+// 记录日志、交给 reporter 上报，并返回 codes.Internal，不向客户端暴露 panic 的内容
+// 没有 panic 恢复时，一次 panic 会让整个进程退出
+func unaryRecoveryInterceptor(reporter crash.PanicReporter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = recoverPanic(ctx, reporter, info.FullMethod, v)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// streamRecoveryInterceptor 流式 RPC 版本的 unaryRecoveryInterceptor
+// 只能捕获方法所在 goroutine 中的 panic，方法自行启动的 goroutine 需要自己恢复
+func streamRecoveryInterceptor(reporter crash.PanicReporter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = recoverPanic(ss.Context(), reporter, info.FullMethod, v)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// recoverPanic 记录并上报 panic，返回给客户端的错误
+// 请求 ID 取自客户端传入的 x-request-id 元数据，没有时生成一个，并通过响应头返回，便于客户端反馈问题时关联报告
+func recoverPanic(ctx context.Context, reporter crash.PanicReporter, method string, v any) error {
+	stack := debug.Stack()
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID := ""
+	if values := md.Get(grpcRequestIDKey); len(values) > 0 {
+		requestID = values[0]
+	} else {
+		requestID = uuid.New().String()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(grpcRequestIDKey, requestID))
+
+	slog.Error("Panic recovered", "request_id", requestID, "route", method,
+		"error", fmt.Sprint(v), "stack", string(stack))
+	report := crash.New(crash.ProtocolGRPC, requestID, method, md, v, stack)
+	if err := reporter.Report(ctx, report); err != nil {
+		slog.Error("Failed to report panic", "request_id", requestID, "error", err)
+	}
+	return status.Error(codes.Internal, "internal server error")
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go-api-template/internal/pkg/crash"
+)
+
+// recordingReporter 记录收到的 panic 报告
+type recordingReporter struct {
+	mu      sync.Mutex
+	reports []*crash.Report
+}
+
+func (r *recordingReporter) Report(_ context.Context, report *crash.Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, report)
+	return nil
+}
+
+// headerStream 记录 grpc.SetHeader 设置的响应头
+type headerStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *headerStream) Method() string { return "" }
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// contextStream 只提供 Context 的 grpc.ServerStream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context { return s.ctx }
+
+func TestRecoveryInterceptors(t *testing.T) {
+	const method = "/helloworld.v1.GreeterService/SayHello"
+	tests := []struct {
+		name string
+		md   metadata.MD
+		// panicValue 为 nil 时方法正常返回
+		panicValue    any
+		wantRequestID string
+	}{
+		{name: "no panic"},
+		{
+			name:          "panic with request id",
+			md:            metadata.Pairs("x-request-id", "req-1", "authorization", "Bearer abc"),
+			panicValue:    "nil map",
+			wantRequestID: "req-1",
+		},
+		{name: "panic without request id", md: metadata.Pairs("user-agent", "grpc-go"), panicValue: "index out of range"},
+	}
+	for _, tt := range tests {
+		for _, kind := range []string{"unary", "stream"} {
+			t.Run(tt.name+"/"+kind, func(t *testing.T) {
+				reporter := &recordingReporter{}
+				stream := &headerStream{}
+				ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+				if tt.md != nil {
+					ctx = metadata.NewIncomingContext(ctx, tt.md)
+				}
+				call := func() {
+					if tt.panicValue != nil {
+						panic(tt.panicValue)
+					}
+				}
+
+				var err error
+				if kind == "unary" {
+					var resp any
+					resp, err = unaryRecoveryInterceptor(reporter)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+						func(context.Context, any) (any, error) {
+							call()
+							return "ok", nil
+						})
+					if tt.panicValue == nil && resp != "ok" {
+						t.Errorf("resp = %v, want ok", resp)
+					}
+				} else {
+					err = streamRecoveryInterceptor(reporter)(nil, &contextStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: method},
+						func(any, grpc.ServerStream) error {
+							call()
+							return nil
+						})
+				}
+
+				if tt.panicValue == nil {
+					if err != nil || len(reporter.reports) != 0 {
+						t.Fatalf("err = %v, reports = %d; want nil and none", err, len(reporter.reports))
+					}
+					return
+				}
+				// 返回 Internal，不向客户端暴露 panic 的内容
+				if st, _ := status.FromError(err); st.Code() != codes.Internal || st.Message() != "internal server error" {
+					t.Fatalf("err = %v, want Internal with a generic message", err)
+				}
+				if len(reporter.reports) != 1 {
+					t.Fatalf("reports = %d, want 1", len(reporter.reports))
+				}
+				r := reporter.reports[0]
+				if r.Protocol != crash.ProtocolGRPC || r.Route != method || r.Panic != tt.panicValue || r.Fingerprint == "" {
+					t.Errorf("report = {protocol:%s route:%s panic:%s fingerprint:%q}", r.Protocol, r.Route, r.Panic, r.Fingerprint)
+				}
+				if values := r.Headers["authorization"]; len(values) > 0 && values[0] == "Bearer abc" {
+					t.Error("report contains the raw authorization metadata")
+				}
+
+				// 请求 ID 通过响应头返回，与报告中的一致；客户端没有传入时生成一个
+				header := stream.header.Get("x-request-id")
+				if len(header) != 1 || header[0] != r.RequestID {
+					t.Fatalf("x-request-id header = %v, report request id = %q", header, r.RequestID)
+				}
+				if tt.wantRequestID != "" && r.RequestID != tt.wantRequestID {
+					t.Errorf("request id = %q, want %q", r.RequestID, tt.wantRequestID)
+				}
+				if r.RequestID == "" {
+					t.Error("request id is empty")
+				}
+			})
+		}
+	}
+}
@@ -1,17 +1,20 @@
 package server
 
 import (
+	"expvar"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/encoding/protojson"
 
-	v1 "go-api-template/api/helloworld/v1"
 	"go-api-template/internal/conf"
-	"go-api-template/internal/pkg/apperrors"
-	"go-api-template/internal/server/dto"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/pkg/crash"
+	"go-api-template/internal/pkg/featureflags"
+	"go-api-template/internal/pkg/idempotency"
+	"go-api-template/internal/pkg/tenant"
 	"go-api-template/internal/server/middleware"
 	"go-api-template/internal/server/response"
-	"go-api-template/internal/service"
 
 	// 导入生成的 Swagger 文档包（空导入，执行 init 函数注册规范）
 	_ "go-api-template/internal/swagger"
@@ -19,20 +22,68 @@ import (
 
 // NewHTTPServer 创建并配置 HTTP 服务器
 // cfg 提供服务器配置（端口、环境等）
-// greeterSvc 是通过依赖注入传入的服务实例
-func NewHTTPServer(cfg *conf.Config, greeterSvc *service.GreeterService) *HTTPServer {
+// watcher 提供可热加载的配置（限流策略等）
+// tokens 校验需要登录的路由携带的访问令牌
+// resolver 解析请求所属的租户
+// flags 放入 request context，供 biz 层判断功能开关
+// idempotencyStore 保存携带 Idempotency-Key 请求的首次响应
+// reporter 上报 Recovery 捕获的 panic
+// svcs 聚合了通过依赖注入传入的所有服务实例
+func NewHTTPServer(cfg *conf.Config, watcher *conf.Watcher, tokens *auth.TokenManager, resolver *tenant.Resolver,
+	flags *featureflags.Manager, idempotencyStore idempotency.Store, reporter crash.PanicReporter, svcs *Services) *HTTPServer {
 	// 根据环境设置 Gin 模式
 	setGinMode(cfg)
 
+	// proto 消息编码为 JSON、MessagePack 时的字段命名
+	response.Configure(response.Options{ProtoJSON: protojson.MarshalOptions{
+		UseProtoNames:   cfg.Response.UseProtoNames(),
+		EmitUnpopulated: cfg.Response.EmitUnpopulated,
+		UseEnumNumbers:  cfg.Response.UseEnumNumbers,
+	}})
+
 	// 使用 gin.New() 创建空白引擎，手动控制中间件
 	// 不使用 gin.Default()，因为它内置的 Recovery 返回非 JSON 格式
 	engine := gin.New()
 
+	// 跨域与限流策略支持热加载，订阅配置变更后原地替换
+	cors := middleware.NewCORS(cfg.CORS, cfg.Tenancy)
+	rateLimiter := middleware.NewRateLimiter(cfg.RateLimit, cfg.Tenancy)
+	watcher.Subscribe(func(e conf.ChangeEvent) {
+		if e.Changed("cors") {
+			cors.SetPolicy(e.New.CORS)
+		}
+		if e.Changed("rate_limit") {
+			rateLimiter.SetPolicy(e.New.RateLimit)
+		}
+		if e.Changed("tenancy") {
+			cors.SetTenancy(e.New.Tenancy)
+			rateLimiter.SetTenancy(e.New.Tenancy)
+		}
+	})
+
 	// 注册中间件（顺序重要）
 	// 1. RequestID - 请求追踪
-	// 2. Recovery - Panic 恢复，返回统一 JSON 格式
+	// 2. Recovery - Panic 恢复，返回统一 JSON 格式并上报
 	// 3. Logger - 请求日志
-	middleware.Register(engine)
+	// 4. CORS - 跨域，位于限流之前：预检请求不消耗令牌，被限流的响应也带有 CORS 头，浏览器能读到 429
+	// 5. Tenant - 解析请求所属的租户，解析失败由业务路由组上的 RequireTenant 拒绝
+	// 6. RateLimit - 按租户与客户端 IP 限流，租户可以覆盖限流策略
+	// 7. FeatureFlags - 把功能开关放入 request context
+	// 8. Compress - 响应压缩，位于幂等之外：幂等中间件在压缩前记录响应体与响应头（不含 Content-Encoding 与压缩中间件的 Vary），
+	//    重放时按新请求的 Accept-Encoding 重新压缩
+	// 9. BodyLimit - 校验请求体格式与大小，位于幂等之前，幂等中间件读取请求体时同样受上限约束
+	// 10. Timeout - 为请求 context 附加处理时限，之后的中间件与处理函数都受其约束
+	// 11. Idempotency - 幂等键，位于限流之后，被限流拒绝的请求不会占用幂等键
+	extra := []gin.HandlerFunc{cors.Middleware(), middleware.Tenant(resolver), rateLimiter.Middleware(),
+		middleware.FeatureFlags(flags)}
+	if cfg.Compression.Enabled {
+		extra = append(extra, middleware.Compress(cfg.Compression))
+	}
+	extra = append(extra, middleware.BodyLimit(cfg.Server), middleware.Timeout(cfg.Server))
+	if cfg.Idempotency.Enabled {
+		extra = append(extra, middleware.Idempotency(idempotencyStore, cfg.Idempotency))
+	}
+	middleware.Register(engine, reporter, extra...)
 
 	// 注册路由级别的错误处理（404、405）
 	middleware.RegisterRouteHandlers(engine)
@@ -44,6 +95,12 @@ func NewHTTPServer(cfg *conf.Config, greeterSvc *service.GreeterService) *HTTPSe
 		})
 	})
 
+	// 运行指标（expvar，含功能开关的求值次数），非生产环境开放
+	// expvar 会输出启动参数，其中可能有 -set 传入的密钥；生产环境通过管理接口查看开关的求值次数
+	if !cfg.IsProduction() {
+		engine.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+	}
+
 	// 服务信息端点
 	engine.GET("/", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -54,8 +111,9 @@ func NewHTTPServer(cfg *conf.Config, greeterSvc *service.GreeterService) *HTTPSe
 		})
 	})
 
-	// 注册 Greeter 服务的 HTTP 路由
-	registerGreeterRoutes(engine, greeterSvc)
+	// 注册各模块的 HTTP 路由
+	sockets := newWSHub()
+	registerRoutes(engine, cfg, svcs, tokens, sockets)
 
 	// 注册 Swagger UI（非生产环境）
 	registerSwagger(engine, cfg.App.Env)
@@ -64,106 +122,23 @@ func NewHTTPServer(cfg *conf.Config, greeterSvc *service.GreeterService) *HTTPSe
 	httpServer := buildHTTPServer(cfg, engine)
 
 	return &HTTPServer{
-		server: httpServer,
-		engine: engine,
+		server:  httpServer,
+		engine:  engine,
+		svcs:    svcs,
+		sockets: sockets,
 	}
 }
 
-// registerGreeterRoutes 注册 Greeter 服务的 HTTP 路由
-// 将 gRPC 风格的服务暴露为 RESTful HTTP 端点
-func registerGreeterRoutes(engine *gin.Engine, svc *service.GreeterService) {
-	// API v1 路由组
-	v1Group := engine.Group("/api/v1")
-	{
-		// POST /api/v1/greeter/say-hello
-		// 请求体: {"name": "World"}
-		// 响应体: {"message": "Hello, World! You are visitor #1."}
-		v1Group.POST("/greeter/say-hello", handleSayHello(svc))
-
-		// GET /api/v1/greeter/say-hello/:name
-		// 便捷的 GET 端点，name 作为 URL 参数
-		v1Group.GET("/greeter/say-hello/:name", handleSayHelloByPath(svc))
-	}
-}
-
-// handleSayHello 处理 POST 请求
-// 使用 DTO 接收请求，Validator 自动验证，然后转换为 Proto 类型调用 Service
-//
-// @Summary      发送问候
-// @Description  向指定用户发送问候消息，返回问候语和访问计数
-// @Tags         greeter
-// @Accept       json
-// @Produce      json
-// @Param        request body     dto.SayHelloRequest true "问候请求参数"
-// @Success      200     {object} response.Response{data=v1.SayHelloResponse} "成功"
-// @Failure      400     {object} response.Response "请求参数错误"
-// @Failure      500     {object} response.Response "服务内部错误"
-// @Router       /greeter/say-hello [post]
-func handleSayHello(svc *service.GreeterService) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// 使用 DTO 接收请求（DTO 有 binding tag，会自动验证）
-		var req dto.SayHelloRequest
-
-		// ShouldBindJSON 会：
-		// 1. 把 JSON 填充到 req
-		// 2. 根据 binding tag 验证（required, min=1, max=100）
-		// 3. 验证失败返回错误,err!=nil 表示验证失败
-		if err := c.ShouldBindJSON(&req); err != nil {
-			// 使用统一响应：将 validator 错误转换为 AppError，再输出
-			response.ErrorJSON(c, apperrors.FromValidationError(err))
-			return
-		}
-
-		// DTO 转 Proto，调用 Service
-		resp, err := svc.SayHello(c.Request.Context(), req.ToProto())
-		if err != nil {
-			// 使用统一响应：包装内部错误
-			response.ErrorJSON(c, apperrors.Internal("服务处理失败", err))
-			return
-		}
-
-		// 使用统一响应：成功响应
-		// 最佳实践：直接传递结构体（DTO 或 Proto），避免手动构造 map
-		response.SuccessJSON(c, resp)
-	}
-}
-
-// handleSayHelloByPath 处理 GET 请求，name 从 URL 路径获取
-//
-// @Summary      发送问候（URL参数）
-// @Description  通过 URL 路径参数向指定用户发送问候消息
-// @Tags         greeter
-// @Accept       json
-// @Produce      json
-// @Param        name path     string true "用户名称" minlength(1) maxlength(100)
-// @Success      200  {object} response.Response{data=v1.SayHelloResponse} "成功"
-// @Failure      400  {object} response.Response "请求参数错误"
-// @Failure      500  {object} response.Response "服务内部错误"
-// @Router       /greeter/say-hello/{name} [get]
-func handleSayHelloByPath(svc *service.GreeterService) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		name := c.Param("name")
-		if name == "" {
-			// 使用统一响应
-			response.ErrorJSON(c, apperrors.InvalidParams("name 参数是必填的"))
-			return
-		}
-
-		// 构造 proto 请求
-		req := &v1.SayHelloRequest{Name: name}
-
-		// 调用服务
-		resp, err := svc.SayHello(c.Request.Context(), req)
-		if err != nil {
-			// 使用统一响应：包装内部错误
-			response.ErrorJSON(c, apperrors.Internal("服务处理失败", err))
-			return
-		}
-
-		// 使用统一响应：成功响应
-		// 灵活用法：使用 response.Body (map[string]any) 构造临时数据
-		response.SuccessJSON(c, response.Body{
-			"message": resp.GetMessage(),
-		})
-	}
+// registerRoutes 注册所有业务模块的 HTTP 路由
+// 所有业务 API 挂载在 /api/v1 路由组下，要求请求能解析出租户
+func registerRoutes(engine *gin.Engine, cfg *conf.Config, svcs *Services, tokens *auth.TokenManager, sockets *wsHub) {
+	v1Group := engine.Group("/api/v1", middleware.RequireTenant())
+
+	registerGreeterRoutes(v1Group, svcs.Greeter, cfg.Greeter, tokens, sockets)
+	registerUserRoutes(v1Group, svcs.User, tokens)
+	registerOrderRoutes(v1Group, svcs.Order, tokens)
+	registerWebhookRoutes(v1Group, svcs.Webhook, tokens)
+	registerJobRoutes(v1Group, svcs.Job, tokens)
+	registerFeatureFlagRoutes(v1Group, svcs.FeatureFlag, tokens)
+	// gen:routes - cmd/gen 在此处插入新模块的路由注册
 }
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+
+	v1 "go-api-template/api/jobs/v1"
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/server/dto"
+	"go-api-template/internal/server/middleware"
+	"go-api-template/internal/server/response"
+	"go-api-template/internal/service"
+)
+
+// registerJobRoutes 注册后台任务管理的 HTTP 路由
+// 路由要求登录，是否为管理员由 Service 层按 admin.users 判断
+func registerJobRoutes(group *gin.RouterGroup, svc *service.JobService, tokens *auth.TokenManager) {
+	jobs := group.Group("/admin/jobs", middleware.RequireAuth(tokens))
+	jobs.GET("", handleListJobs(svc))
+	jobs.GET("/:id", handleGetJob(svc))
+	jobs.POST("/:id/retry", handleRetryJob(svc))
+	jobs.POST("/:id/cancel", handleCancelJob(svc))
+}
+
+// registerJobGRPC 注册后台任务管理的 gRPC 实现
+func registerJobGRPC(srv *grpc.Server, svc *service.JobService) {
+	v1.RegisterJobServiceServer(srv, svc)
+}
+
+// handleListJobs 列出任务
+//
+// @Summary      后台任务列表
+// @Description  按 ID 倒序返回任务，可按状态、队列、类型筛选；仅管理员可用
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status query    string false "任务状态" Enums(queued, running, succeeded, dead, cancelled)
+// @Param        queue  query    string false "队列名"
+// @Param        type   query    string false "任务类型"
+// @Param        limit  query    int    false "最多返回的条数（1-200），默认 50"
+// @Success      200    {object} response.Response{data=v1.ListJobsResponse} "成功"
+// @Failure      400    {object} response.Response "请求参数错误"
+// @Failure      401    {object} response.Response "未登录或令牌无效"
+// @Failure      403    {object} response.Response "不是管理员"
+// @Router       /admin/jobs [get]
+func handleListJobs(svc *service.JobService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query dto.ListJobsQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			return
+		}
+
+		resp, err := svc.ListJobs(c.Request.Context(), query.ToProto())
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleGetJob 获取任务
+//
+// @Summary      后台任务详情
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path     int true "任务 ID"
+// @Success      200 {object} response.Response{data=v1.GetJobResponse} "成功"
+// @Failure      403 {object} response.Response "不是管理员"
+// @Failure      404 {object} response.Response "任务不存在"
+// @Router       /admin/jobs/{id} [get]
+func handleGetJob(svc *service.JobService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c)
+		if !ok {
+			return
+		}
+
+		resp, err := svc.GetJob(c.Request.Context(), &v1.GetJobRequest{Id: id})
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleRetryJob 重试任务
+//
+// @Summary      重试后台任务
+// @Description  把死信、已取消或已成功的任务重新排队并立即执行，执行次数清零；排队中或执行中的任务返回 409
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path     int true "任务 ID"
+// @Success      200 {object} response.Response{data=v1.RetryJobResponse} "成功"
+// @Failure      403 {object} response.Response "不是管理员"
+// @Failure      404 {object} response.Response "任务不存在"
+// @Failure      409 {object} response.Response "任务状态不允许重试"
+// @Router       /admin/jobs/{id}/retry [post]
+func handleRetryJob(svc *service.JobService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c)
+		if !ok {
+			return
+		}
+
+		resp, err := svc.RetryJob(c.Request.Context(), &v1.RetryJobRequest{Id: id})
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleCancelJob 取消任务
+//
+// @Summary      取消后台任务
+// @Description  只能取消排队中（含延迟执行、等待重试）的任务；执行中或已结束的任务返回 409
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path     int true "任务 ID"
+// @Success      200 {object} response.Response{data=v1.CancelJobResponse} "成功"
+// @Failure      403 {object} response.Response "不是管理员"
+// @Failure      404 {object} response.Response "任务不存在"
+// @Failure      409 {object} response.Response "任务状态不允许取消"
+// @Router       /admin/jobs/{id}/cancel [post]
+func handleCancelJob(svc *service.JobService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c)
+		if !ok {
+			return
+		}
+
+		resp, err := svc.CancelJob(c.Request.Context(), &v1.CancelJobRequest{Id: id})
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
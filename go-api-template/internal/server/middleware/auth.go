@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/pkg/tenant"
+	"go-api-template/internal/server/response"
+)
+
+// RequireAuth 返回认证中间件，只挂载在需要登录的路由组上
+// 校验 Authorization: Bearer <token>，通过后把用户身份放入 request context，
+// Service 层通过 auth.FromContext 读取，与 gRPC 拦截器的行为一致；
+// 令牌必须属于 Tenant 中间件解析出的租户
+func RequireAuth(tokens *auth.TokenManager) gin.HandlerFunc {
+	return authenticate(tokens, true)
+}
+
+// OptionalAuth 返回可选认证中间件，挂载在无需登录、但结果可能因用户而异的路由上（如按用户放量的功能开关）
+// 未携带令牌时照常放行；携带了令牌但校验失败时拒绝，与 gRPC 认证拦截器的行为一致
+func OptionalAuth(tokens *auth.TokenManager) gin.HandlerFunc {
+	return authenticate(tokens, false)
+}
+
+// authenticate 校验 Authorization 头中的访问令牌，required 为 false 时允许不携带令牌
+func authenticate(tokens *auth.TokenManager, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" && !required {
+			c.Next()
+			return
+		}
+		if header == "" {
+			response.ErrorJSON(c, apperrors.Unauthorized("缺少访问令牌"))
+			c.Abort()
+			return
+		}
+		claims, err := tokens.ParseAuthorization(header)
+		if err != nil {
+			response.ErrorJSON(c, apperrors.Unauthorized("访问令牌无效或已过期"))
+			c.Abort()
+			return
+		}
+
+		if err := tenant.Authorize(c.Request.Context(), claims.Tenant); err != nil {
+			response.ErrorJSON(c, tenantError(err))
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), claims))
+		c.Next()
+	}
+}
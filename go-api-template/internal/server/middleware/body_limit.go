@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/reason"
+	"go-api-template/internal/server/response"
+)
+
+// BodyLimit 返回请求体校验中间件
+// 只检查带请求体的请求：Content-Type 不在 server.allowed_content_types 中返回 415；
+// 大小上限取路由配置（server.route_body_limits，未配置时为 server.max_body_bytes），
+// 声明的 Content-Length 已超出时直接返回 413，否则用 http.MaxBytesReader 包装请求体，
+// 读取超出上限时得到 *http.MaxBytesError，由 apperrors.FromValidationError 映射为 413。
+// 位于 Idempotency 之前，幂等中间件读取请求体时同样受上限约束
+func BodyLimit(cfg conf.ServerConfig) gin.HandlerFunc {
+	allowed := cfg.GetAllowedContentTypes()
+	return func(c *gin.Context) {
+		if !hasBody(c.Request) {
+			c.Next()
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err != nil || !slices.Contains(allowed, mediaType) {
+			response.ErrorJSON(c, apperrors.New(reason.UnsupportedMediaType,
+				"不支持的请求体格式，Content-Type 应为 "+strings.Join(allowed, "、")))
+			c.Abort()
+			return
+		}
+
+		limit := cfg.GetRouteBodyLimit(c.Request.Method + " " + c.FullPath())
+		if limit == 0 {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > limit {
+			response.ErrorJSON(c, apperrors.PayloadTooLarge(limit))
+			c.Abort()
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// hasBody 判断请求是否带有请求体
+// 服务端收到的请求没有请求体时 ContentLength 为 0 且没有 Transfer-Encoding（分块传输的 ContentLength 为 -1）
+func hasBody(r *http.Request) bool {
+	return r.ContentLength != 0 || len(r.TransferEncoding) > 0
+}
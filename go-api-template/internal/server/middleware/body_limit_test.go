@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/reason"
+	"go-api-template/internal/server/response"
+)
+
+// readBodyHandler 读取整个请求体，读取失败时按业务错误响应（超出上限为 413）
+func readBodyHandler(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		response.ErrorJSON(c, apperrors.FromValidationError(err))
+		return
+	}
+	c.String(http.StatusOK, "read %d", len(body))
+}
+
+func TestBodyLimit(t *testing.T) {
+	cfg := conf.ServerConfig{
+		MaxBodyBytes: 16,
+		RouteBodyLimits: []conf.RouteBodyLimitConfig{
+			{Route: "POST /uploads", MaxBytes: 64},
+			{Route: "POST /unlimited", MaxBytes: 0},
+		},
+	}
+	engine := gin.New()
+	engine.Use(BodyLimit(cfg))
+	for _, path := range []string{"/greetings", "/uploads", "/unlimited"} {
+		engine.POST(path, readBodyHandler)
+	}
+	engine.GET("/greetings", readBodyHandler)
+
+	jsonOf := func(n int) string { return `"` + strings.Repeat("a", n-2) + `"` }
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		contentType string
+		// chunked 不声明 Content-Length，只能在读取时发现超出上限
+		chunked    bool
+		wantStatus int
+		wantReason reason.Reason
+	}{
+		{name: "within default limit", method: http.MethodPost, path: "/greetings", body: jsonOf(16), contentType: "application/json", wantStatus: http.StatusOK},
+		{name: "declared length over default limit", method: http.MethodPost, path: "/greetings", body: jsonOf(17), contentType: "application/json", wantStatus: http.StatusRequestEntityTooLarge, wantReason: reason.PayloadTooLarge},
+		{name: "chunked body over default limit", method: http.MethodPost, path: "/greetings", body: jsonOf(17), contentType: "application/json", chunked: true, wantStatus: http.StatusRequestEntityTooLarge, wantReason: reason.PayloadTooLarge},
+		{name: "chunked body within limit", method: http.MethodPost, path: "/greetings", body: jsonOf(10), contentType: "application/json", chunked: true, wantStatus: http.StatusOK},
+		{name: "route limit raises the cap", method: http.MethodPost, path: "/uploads", body: jsonOf(64), contentType: "application/json", wantStatus: http.StatusOK},
+		{name: "route limit still enforced", method: http.MethodPost, path: "/uploads", body: jsonOf(65), contentType: "application/json", wantStatus: http.StatusRequestEntityTooLarge, wantReason: reason.PayloadTooLarge},
+		{name: "route limit zero disables the cap", method: http.MethodPost, path: "/unlimited", body: jsonOf(4096), contentType: "application/json", wantStatus: http.StatusOK},
+		{name: "content type with parameters", method: http.MethodPost, path: "/greetings", body: jsonOf(8), contentType: "application/json; charset=utf-8", wantStatus: http.StatusOK},
+		{name: "protobuf allowed", method: http.MethodPost, path: "/greetings", body: "\x0a\x01a", contentType: "application/x-protobuf", wantStatus: http.StatusOK},
+		{name: "unsupported content type", method: http.MethodPost, path: "/greetings", body: "a=1", contentType: "application/x-www-form-urlencoded", wantStatus: http.StatusUnsupportedMediaType, wantReason: reason.UnsupportedMediaType},
+		{name: "missing content type", method: http.MethodPost, path: "/greetings", body: jsonOf(8), wantStatus: http.StatusUnsupportedMediaType, wantReason: reason.UnsupportedMediaType},
+		{name: "malformed content type", method: http.MethodPost, path: "/greetings", body: jsonOf(8), contentType: "application/", wantStatus: http.StatusUnsupportedMediaType, wantReason: reason.UnsupportedMediaType},
+		// 415 优先于 413：格式不支持时不关心大小
+		{name: "unsupported and too large", method: http.MethodPost, path: "/greetings", body: strings.Repeat("x", 100), contentType: "text/plain", wantStatus: http.StatusUnsupportedMediaType, wantReason: reason.UnsupportedMediaType},
+		// 没有请求体的请求不检查 Content-Type
+		{name: "no body", method: http.MethodGet, path: "/greetings", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.path, body)
+			if tt.chunked {
+				req.ContentLength = -1
+				req.TransferEncoding = []string{"chunked"}
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantReason != "" && !strings.Contains(w.Body.String(), string(tt.wantReason)) {
+				t.Errorf("body = %s, want reason %s", w.Body.String(), tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/compress"
+)
+
+// Compress 返回响应压缩中间件
+// 按 Accept-Encoding 从 compression.encodings 中选择编码；响应体先缓冲到 compression.min_size，
+// 达到后再根据响应头决定是否压缩，因此处理函数无需关心压缩：
+//   - 响应格式不在 compression.content_types 中、已经带有 Content-Encoding 的响应原样输出；
+//   - 缓冲未满就调用 Flush 的响应（SSE 等流式推送）原样输出，压缩会让消息滞留在编码器中；
+//   - WebSocket 握手请求直接放行，升级后的连接不经过 HTTP 响应。
+//
+// ETag 保持不变：订单等资源的 ETag 用于 If-Match 强比较，压缩与否不影响资源版本
+func Compress(cfg conf.CompressionConfig) gin.HandlerFunc {
+	offered := cfg.GetEncodings()
+	policy := &compressPolicy{
+		minSize:      cfg.GetMinSize(),
+		contentTypes: cfg.GetContentTypes(),
+	}
+	return func(c *gin.Context) {
+		if c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		codec := compress.Lookup(negotiateEncoding(c.GetHeader("Accept-Encoding"), offered))
+		if codec == nil || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		w := &compressWriter{ResponseWriter: original, codec: codec, policy: policy}
+		c.Writer = w
+		defer func() {
+			_ = w.close()
+			// 恢复原始 Writer，外层的 Logger 记录的是实际写出的状态码与字节数
+			c.Writer = original
+		}()
+		c.Next()
+	}
+}
+
+// compressPolicy 由 conf.CompressionConfig 预先计算出的压缩条件
+type compressPolicy struct {
+	minSize      int
+	contentTypes []string
+}
+
+// negotiateEncoding 按 Accept-Encoding 的 q 值选择编码，q 值相同时取 offered 中靠前的一个
+// 没有可接受的编码时返回空字符串
+func negotiateEncoding(header string, offered []string) string {
+	if header == "" {
+		return ""
+	}
+	weights := make(map[string]float64)
+	wildcard := -1.0
+	for part := range strings.SplitSeq(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		weight := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err := strconv.ParseFloat(v, 64); err == nil {
+				weight = q
+			}
+		}
+		if name == "*" {
+			wildcard = weight
+		} else {
+			weights[name] = weight
+		}
+	}
+
+	best, bestWeight := "", 0.0
+	for _, enc := range offered {
+		weight, ok := weights[enc]
+		if !ok {
+			weight = wildcard
+		}
+		if weight > bestWeight {
+			best, bestWeight = enc, weight
+		}
+	}
+	return best
+}
+
+// compressState compressWriter 的输出状态
+type compressState int
+
+const (
+	// statePending 响应体仍在缓冲，尚未决定是否压缩，响应头也尚未写出
+	statePending compressState = iota
+	// stateCompress 经编码器写出
+	stateCompress
+	// statePassthrough 原样写出
+	statePassthrough
+)
+
+// compressWriter 缓冲响应体，决定压缩后经编码器写出
+type compressWriter struct {
+	gin.ResponseWriter
+	codec  *compress.Codec
+	policy *compressPolicy
+
+	state  compressState
+	status int
+	buf    []byte
+	enc    *compress.Writer
+}
+
+// WriteHeader 缓冲期间只记录状态码，决定是否压缩后再与响应头一起写出
+func (w *compressWriter) WriteHeader(code int) {
+	if w.state == statePending {
+		w.status = code
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// WriteHeaderNow 用于没有响应体的响应（如 204），不压缩
+func (w *compressWriter) WriteHeaderNow() {
+	if w.state == statePending {
+		_ = w.commit(statePassthrough)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write 缓冲或写出响应体
+func (w *compressWriter) Write(p []byte) (int, error) {
+	switch w.state {
+	case stateCompress:
+		return w.enc.Write(p)
+	case statePassthrough:
+		return w.ResponseWriter.Write(p)
+	}
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.policy.minSize {
+		if err := w.commit(w.decide()); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// WriteString 同 Write
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush 立即送达已写入的数据；缓冲期间调用说明是流式响应，改为原样输出
+func (w *compressWriter) Flush() {
+	switch w.state {
+	case statePending:
+		_ = w.commit(statePassthrough)
+	case stateCompress:
+		_ = w.enc.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Written 缓冲中的响应体也视为已写出，与 gin 的语义保持一致
+func (w *compressWriter) Written() bool {
+	return w.state != statePending || len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+// Status 返回缓冲期间记录的状态码
+func (w *compressWriter) Status() int {
+	if w.state == statePending && w.status != 0 {
+		return w.status
+	}
+	return w.ResponseWriter.Status()
+}
+
+// Unwrap 供 http.ResponseController 访问底层连接（如 SSE 取消写超时）
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide 根据已写入的响应头判断是否压缩
+func (w *compressWriter) decide() compressState {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" || len(w.buf) < w.policy.minSize {
+		return statePassthrough
+	}
+	switch w.status {
+	case http.StatusNoContent, http.StatusNotModified:
+		return statePassthrough
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || mediaType == sse.ContentType || !slices.Contains(w.policy.contentTypes, mediaType) {
+		return statePassthrough
+	}
+	return stateCompress
+}
+
+// commit 写出响应头与缓冲的响应体，之后的写入不再缓冲
+func (w *compressWriter) commit(state compressState) error {
+	w.state = state
+	if state == stateCompress {
+		header := w.Header()
+		header.Set("Content-Encoding", w.codec.Name())
+		header.Del("Content-Length")
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if state == stateCompress {
+		w.enc = w.codec.Writer(w.ResponseWriter)
+		_, err := w.enc.Write(buf)
+		return err
+	}
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// close 请求结束时写出缓冲中的响应体，并归还编码器
+func (w *compressWriter) close() error {
+	switch w.state {
+	case statePending:
+		if len(w.buf) == 0 {
+			// 没有响应体，也没有写出响应头，交给 gin 按记录的状态码处理
+			if w.status != 0 {
+				w.ResponseWriter.WriteHeader(w.status)
+			}
+			return nil
+		}
+		return w.commit(w.decide())
+	case stateCompress:
+		return w.enc.Close()
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/compress"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	offered := []string{"zstd", "br", "gzip"}
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"gzip, br", "br"},
+		// q 值相同时按服务端偏好
+		{"gzip, br, zstd", "zstd"},
+		{"GZIP", "gzip"},
+		{"gzip;q=1.0, br;q=0.5", "gzip"},
+		{"gzip; q=0.2, zstd;q=0.8", "zstd"},
+		{"zstd;q=0, gzip", "gzip"},
+		{"*", "zstd"},
+		{"*;q=0.5, br", "br"},
+		{"*, zstd;q=0", "br"},
+		{"gzip;q=0", ""},
+		{"deflate, compress", ""},
+		// 无法解析的 q 值按 1 处理
+		{"gzip;q=abc", "gzip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := negotiateEncoding(tt.header, offered); got != tt.want {
+				t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat(`{"message":"hello"}`, 100)
+	tests := []struct {
+		name           string
+		method         string
+		acceptEncoding string
+		headers        []string
+		handler        gin.HandlerFunc
+		wantStatus     int
+		wantEncoding   string
+		wantBody       string
+	}{
+		{
+			name: "large json compressed", acceptEncoding: "gzip",
+			handler:    func(c *gin.Context) { c.Data(http.StatusOK, "application/json", []byte(large)) },
+			wantStatus: http.StatusOK, wantEncoding: "gzip", wantBody: large,
+		},
+		{
+			name: "preferred encoding", acceptEncoding: "gzip, br, zstd",
+			handler:    func(c *gin.Context) { c.Data(http.StatusCreated, "application/json; charset=utf-8", []byte(large)) },
+			wantStatus: http.StatusCreated, wantEncoding: "zstd", wantBody: large,
+		},
+		{
+			name: "written in small chunks", acceptEncoding: "br",
+			handler: func(c *gin.Context) {
+				c.Header("Content-Type", "text/plain")
+				for range 100 {
+					_, _ = c.Writer.WriteString(`{"message":"hello"}`)
+				}
+			},
+			wantStatus: http.StatusOK, wantEncoding: "br", wantBody: large,
+		},
+		{
+			name: "small body not compressed", acceptEncoding: "gzip",
+			handler:    func(c *gin.Context) { c.Data(http.StatusOK, "application/json", []byte(`{"ok":true}`)) },
+			wantStatus: http.StatusOK, wantBody: `{"ok":true}`,
+		},
+		{
+			name: "no accept-encoding", acceptEncoding: "",
+			handler:    func(c *gin.Context) { c.Data(http.StatusOK, "application/json", []byte(large)) },
+			wantStatus: http.StatusOK, wantBody: large,
+		},
+		{
+			name: "unsupported encoding", acceptEncoding: "deflate",
+			handler:    func(c *gin.Context) { c.Data(http.StatusOK, "application/json", []byte(large)) },
+			wantStatus: http.StatusOK, wantBody: large,
+		},
+		{
+			name: "content type not compressible", acceptEncoding: "gzip",
+			handler:    func(c *gin.Context) { c.Data(http.StatusOK, "image/png", []byte(large)) },
+			wantStatus: http.StatusOK, wantBody: large,
+		},
+		{
+			name: "already encoded", acceptEncoding: "gzip",
+			handler: func(c *gin.Context) {
+				c.Header("Content-Encoding", "identity")
+				c.Data(http.StatusOK, "application/json", []byte(large))
+			},
+			wantStatus: http.StatusOK, wantEncoding: "identity", wantBody: large,
+		},
+		{
+			name: "flushed stream not compressed", acceptEncoding: "gzip",
+			handler: func(c *gin.Context) {
+				c.Header("Content-Type", "text/plain")
+				_, _ = c.Writer.WriteString("first event\n")
+				c.Writer.Flush()
+				_, _ = c.Writer.WriteString(large)
+			},
+			wantStatus: http.StatusOK, wantBody: "first event\n" + large,
+		},
+		{
+			name: "not modified", acceptEncoding: "gzip",
+			handler:    func(c *gin.Context) { c.Status(http.StatusNotModified) },
+			wantStatus: http.StatusNotModified,
+		},
+		{
+			name: "head request", method: http.MethodHead, acceptEncoding: "gzip",
+			handler:    func(c *gin.Context) { c.Status(http.StatusOK) },
+			wantStatus: http.StatusOK,
+		},
+	}
+	cfg := conf.CompressionConfig{Enabled: true, MinSize: 256}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			engine := newTestEngine(tt.handler, Compress(cfg))
+			w := serve(engine, method, "/greetings", "", "Accept-Encoding", tt.acceptEncoding)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			encoding := w.Header().Get("Content-Encoding")
+			if encoding != tt.wantEncoding {
+				t.Fatalf("Content-Encoding = %q, want %q", encoding, tt.wantEncoding)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+
+			body := w.Body.String()
+			if codec := compress.Lookup(encoding); codec != nil {
+				if w.Header().Get("Content-Length") != "" {
+					t.Error("compressed response kept Content-Length")
+				}
+				r, err := codec.Reader(w.Body)
+				if err != nil {
+					t.Fatalf("open %s reader: %v", encoding, err)
+				}
+				decoded, err := io.ReadAll(r)
+				if err != nil {
+					t.Fatalf("decode %s body: %v", encoding, err)
+				}
+				body = string(decoded)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %.60q..., want %.60q...", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestCompressSkipsUpgrade(t *testing.T) {
+	engine := newTestEngine(func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", []byte(strings.Repeat("a", 2048)))
+	}, Compress(conf.CompressionConfig{Enabled: true}))
+	w := serve(engine, http.MethodGet, "/ws", "", "Accept-Encoding", "gzip", "Upgrade", "websocket")
+
+	if w.Header().Get("Content-Encoding") != "" || w.Header().Get("Vary") != "" {
+		t.Errorf("upgrade request got Content-Encoding %q, Vary %q; want neither",
+			w.Header().Get("Content-Encoding"), w.Header().Get("Vary"))
+	}
+}
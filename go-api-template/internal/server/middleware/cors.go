@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/server/response"
+)
+
+// CORS 跨域资源共享中间件
+// 策略可以在运行期通过 SetPolicy 替换（配置热加载），因此中间件始终注册，关闭时直接放行
+type CORS struct {
+	mu      sync.RWMutex
+	cfg     conf.CORSConfig
+	tenancy conf.TenancyConfig
+	policy  corsPolicy
+}
+
+// corsPolicy 由 conf.CORSConfig 预先计算出的响应头
+type corsPolicy struct {
+	cfg           conf.CORSConfig
+	anyOrigin     bool
+	allowMethods  []string
+	allowHeaders  []string
+	methods       string
+	headers       string
+	exposeHeaders string
+	maxAge        string
+}
+
+// NewCORS 创建 CORS 中间件
+// 多租户从请求头解析租户时，允许的请求头自动包含租户请求头
+func NewCORS(cfg conf.CORSConfig, tenancy conf.TenancyConfig) *CORS {
+	c := &CORS{cfg: cfg, tenancy: tenancy}
+	c.policy = newCORSPolicy(cfg, tenancy)
+	return c
+}
+
+// SetPolicy 替换跨域策略
+func (c *CORS) SetPolicy(cfg conf.CORSConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cfg = cfg
+	c.policy = newCORSPolicy(c.cfg, c.tenancy)
+}
+
+// SetTenancy 替换多租户配置，租户请求头变化后允许的请求头随之更新
+func (c *CORS) SetTenancy(tenancy conf.TenancyConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tenancy = tenancy
+	c.policy = newCORSPolicy(c.cfg, c.tenancy)
+}
+
+// newCORSPolicy 预先计算响应头
+func newCORSPolicy(cfg conf.CORSConfig, tenancy conf.TenancyConfig) corsPolicy {
+	headers := cfg.GetAllowHeaders()
+	if tenancy.Enabled && slices.Contains(tenancy.GetSources(), "header") &&
+		!slices.ContainsFunc(headers, func(h string) bool { return strings.EqualFold(h, tenancy.GetHeader()) }) {
+		headers = append(slices.Clip(headers), tenancy.GetHeader())
+	}
+	policy := corsPolicy{
+		cfg:           cfg,
+		anyOrigin:     cfg.AllowsAnyOrigin(),
+		allowMethods:  upperAll(cfg.GetAllowMethods()),
+		allowHeaders:  lowerAll(headers),
+		exposeHeaders: strings.Join(cfg.GetExposeHeaders(), ", "),
+		maxAge:        strconv.Itoa(int(cfg.GetMaxAge().Seconds())),
+	}
+	policy.methods = strings.Join(policy.allowMethods, ", ")
+	policy.headers = strings.Join(headers, ", ")
+	return policy
+}
+
+// Middleware 返回 Gin 中间件
+// 预检请求（带 Access-Control-Request-Method 的 OPTIONS）在这里直接以 204 结束，
+// 不会进入路由，也就不会因为路由没有注册 OPTIONS 而落入 HandleNoMethod 的 405
+func (c *CORS) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		c.mu.RLock()
+		p := c.policy
+		c.mu.RUnlock()
+
+		origin := ctx.GetHeader("Origin")
+		if !p.cfg.Enabled || origin == "" {
+			ctx.Next()
+			return
+		}
+
+		header := ctx.Writer.Header()
+		header.Add("Vary", "Origin")
+		preflight := ctx.Request.Method == http.MethodOptions && ctx.GetHeader("Access-Control-Request-Method") != ""
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if !p.allowOrigin(origin) {
+			if preflight {
+				response.ErrorJSON(ctx, apperrors.Forbidden("不允许的跨域来源"))
+				ctx.Abort()
+				return
+			}
+			// 非预检请求照常处理，浏览器因缺少 CORS 响应头而拒绝脚本读取响应
+			ctx.Next()
+			return
+		}
+
+		if p.anyOrigin {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if p.cfg.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			header.Set("Access-Control-Expose-Headers", p.exposeHeaders)
+			ctx.Next()
+			return
+		}
+
+		if !p.allowPreflight(ctx.GetHeader("Access-Control-Request-Method"), ctx.GetHeader("Access-Control-Request-Headers")) {
+			response.ErrorJSON(ctx, apperrors.Forbidden("不允许的跨域请求方法或请求头"))
+			ctx.Abort()
+			return
+		}
+		header.Set("Access-Control-Allow-Methods", p.methods)
+		header.Set("Access-Control-Allow-Headers", p.headers)
+		header.Set("Access-Control-Max-Age", p.maxAge)
+		ctx.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// allowOrigin 判断源是否被允许
+func (p *corsPolicy) allowOrigin(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	for _, allowed := range p.cfg.AllowOrigins {
+		if matchOrigin(strings.ToLower(allowed), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowPreflight 判断预检请求声明的方法与请求头是否都被允许
+func (p *corsPolicy) allowPreflight(method, requestHeaders string) bool {
+	if !slices.Contains(p.allowMethods, strings.ToUpper(method)) {
+		return false
+	}
+	for name := range strings.SplitSeq(requestHeaders, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" && !slices.Contains(p.allowHeaders, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchOrigin 按 conf.CORSConfig.AllowOrigins 的写法匹配源
+// https://*.example.com 要求 * 至少对应一级子域名，且不能跨越端口或路径
+func matchOrigin(pattern, origin string) bool {
+	prefix, suffix, wildcard := strings.Cut(pattern, "*")
+	if !wildcard {
+		return pattern == origin
+	}
+	if len(origin) <= len(prefix)+len(suffix) || !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+		return false
+	}
+	sub := origin[len(prefix) : len(origin)-len(suffix)]
+	return !strings.ContainsAny(sub, "/:")
+}
+
+// upperAll 把每个元素转为大写
+func upperAll(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strings.ToUpper(v)
+	}
+	return out
+}
+
+// lowerAll 把每个元素转为小写
+func lowerAll(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strings.ToLower(v)
+	}
+	return out
+}
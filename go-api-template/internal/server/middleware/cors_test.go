@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/conf"
+)
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		pattern string
+		origin  string
+		want    bool
+	}{
+		{"https://app.example.com", "https://app.example.com", true},
+		{"https://app.example.com", "http://app.example.com", false},
+		{"https://*.example.com", "https://app.example.com", true},
+		{"https://*.example.com", "https://a.b.example.com", true},
+		// * 至少对应一级子域名
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "https://.example.com", false},
+		// 不能借 * 匹配其他域名、端口或路径
+		{"https://*.example.com", "https://evil-example.com", false},
+		{"https://*.example.com", "https://example.com.evil.io", false},
+		{"https://*.example.com", "https://app.example.com:8443", false},
+		{"https://*.example.com", "https://evil.io/.example.com", false},
+		{"https://*.example.com", "https://evil.io:1.example.com", false},
+		{"https://*.example.com", "http://app.example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.origin, func(t *testing.T) {
+			if got := matchOrigin(tt.pattern, tt.origin); got != tt.want {
+				t.Errorf("matchOrigin(%q, %q) = %v, want %v", tt.pattern, tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	allowList := conf.CORSConfig{
+		Enabled:          true,
+		AllowOrigins:     []string{"https://app.example.com", "https://*.preview.example.com"},
+		AllowCredentials: true,
+	}
+	anyOrigin := conf.CORSConfig{Enabled: true, AllowOrigins: []string{"*"}}
+
+	tests := []struct {
+		name    string
+		cfg     conf.CORSConfig
+		method  string
+		headers []string
+		// wantStatus 为 http.StatusOK 时表示请求进入了 handler
+		wantStatus      int
+		wantAllowOrigin string
+		wantCredentials string
+		wantAllowHeader bool
+	}{
+		{
+			name:       "disabled",
+			cfg:        conf.CORSConfig{AllowOrigins: []string{"*"}},
+			method:     http.MethodGet,
+			headers:    []string{"Origin", "https://app.example.com"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "same origin request without Origin",
+			cfg:        allowList,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:            "exact origin",
+			cfg:             allowList,
+			method:          http.MethodGet,
+			headers:         []string{"Origin", "https://app.example.com"},
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "https://app.example.com",
+			wantCredentials: "true",
+		},
+		{
+			name:            "wildcard subdomain",
+			cfg:             allowList,
+			method:          http.MethodGet,
+			headers:         []string{"Origin", "https://PR-42.preview.example.com"},
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "https://PR-42.preview.example.com",
+			wantCredentials: "true",
+		},
+		{
+			name:       "wildcard does not match apex",
+			cfg:        allowList,
+			method:     http.MethodGet,
+			headers:    []string{"Origin", "https://preview.example.com"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "disallowed origin preflight",
+			cfg:        allowList,
+			method:     http.MethodOptions,
+			headers:    []string{"Origin", "https://evil.io", "Access-Control-Request-Method", "POST"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "wildcard preflight",
+			cfg:    allowList,
+			method: http.MethodOptions,
+			headers: []string{"Origin", "https://pr-7.preview.example.com", "Access-Control-Request-Method", "patch",
+				"Access-Control-Request-Headers", "content-type, if-match, x-request-timeout"},
+			wantStatus:      http.StatusNoContent,
+			wantAllowOrigin: "https://pr-7.preview.example.com",
+			wantCredentials: "true",
+			wantAllowHeader: true,
+		},
+		{
+			name:   "preflight with disallowed header",
+			cfg:    allowList,
+			method: http.MethodOptions,
+			headers: []string{"Origin", "https://app.example.com", "Access-Control-Request-Method", "POST",
+				"Access-Control-Request-Headers", "X-Custom"},
+			wantStatus: http.StatusForbidden,
+			// 源被允许，但方法或请求头不在允许列表中
+			wantAllowOrigin: "https://app.example.com",
+			wantCredentials: "true",
+		},
+		{
+			name:       "preflight with disallowed method",
+			cfg:        allowList,
+			method:     http.MethodOptions,
+			headers:    []string{"Origin", "https://app.example.com", "Access-Control-Request-Method", "TRACE"},
+			wantStatus: http.StatusForbidden,
+			// 源被允许，但方法或请求头不在允许列表中
+			wantAllowOrigin: "https://app.example.com",
+			wantCredentials: "true",
+		},
+		{
+			name:            "any origin",
+			cfg:             anyOrigin,
+			method:          http.MethodGet,
+			headers:         []string{"Origin", "https://anything.io"},
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "*",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine(func(c *gin.Context) { c.Status(http.StatusOK) }, NewCORS(tt.cfg, conf.TenancyConfig{}).Middleware())
+			w := serve(engine, tt.method, "/api/v1/greetings", "", tt.headers...)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllowOrigin {
+				t.Errorf("Allow-Origin = %q, want %q", got, tt.wantAllowOrigin)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != tt.wantCredentials {
+				t.Errorf("Allow-Credentials = %q, want %q", got, tt.wantCredentials)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Headers") != ""; got != tt.wantAllowHeader {
+				t.Errorf("Allow-Headers present = %v, want %v", got, tt.wantAllowHeader)
+			}
+		})
+	}
+}
+
+func TestCORSSetPolicy(t *testing.T) {
+	cors := NewCORS(conf.CORSConfig{Enabled: true, AllowOrigins: []string{"https://old.example.com"}}, conf.TenancyConfig{})
+	engine := newTestEngine(func(c *gin.Context) { c.Status(http.StatusOK) }, cors.Middleware())
+
+	// 配置热加载替换策略后立即对新请求生效
+	cors.SetPolicy(conf.CORSConfig{Enabled: true, AllowOrigins: []string{"https://*.example.com"}})
+	for origin, want := range map[string]string{
+		"https://old.example.com": "https://old.example.com",
+		"https://new.example.com": "https://new.example.com",
+		"https://example.org":     "",
+	} {
+		w := serve(engine, http.MethodGet, "/", "", "Origin", origin)
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != want {
+			t.Errorf("origin %s: Allow-Origin = %q, want %q", origin, got, want)
+		}
+	}
+}
+
+func TestCORSTenancyHeader(t *testing.T) {
+	cors := conf.CORSConfig{Enabled: true, AllowOrigins: []string{"https://app.example.com"}}
+	custom := conf.CORSConfig{Enabled: true, AllowOrigins: []string{"https://app.example.com"}, AllowHeaders: []string{"Content-Type"}}
+
+	tests := []struct {
+		name      string
+		cors      conf.CORSConfig
+		tenancy   conf.TenancyConfig
+		requested string
+		wantOK    bool
+	}{
+		{name: "tenancy disabled", cors: cors, requested: "X-Tenant-ID", wantOK: false},
+		{name: "default tenant header", cors: cors, tenancy: conf.TenancyConfig{Enabled: true}, requested: "x-tenant-id", wantOK: true},
+		{name: "custom tenant header", cors: cors, tenancy: conf.TenancyConfig{Enabled: true, Header: "X-Org"}, requested: "content-type, x-org", wantOK: true},
+		{name: "custom allow headers", cors: custom, tenancy: conf.TenancyConfig{Enabled: true}, requested: "Content-Type, X-Tenant-ID", wantOK: true},
+		// 只从子域名或令牌解析租户时不需要额外的请求头
+		{name: "header source not used", cors: cors, tenancy: conf.TenancyConfig{Enabled: true, Sources: []string{"jwt"}}, requested: "X-Tenant-ID", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine(func(c *gin.Context) { c.Status(http.StatusOK) }, NewCORS(tt.cors, tt.tenancy).Middleware())
+			w := serve(engine, http.MethodOptions, "/api/v1/greetings", "", "Origin", "https://app.example.com",
+				"Access-Control-Request-Method", "GET", "Access-Control-Request-Headers", tt.requested)
+
+			if got := w.Code == http.StatusNoContent; got != tt.wantOK {
+				t.Errorf("preflight status = %d, want allowed %v", w.Code, tt.wantOK)
+			}
+		})
+	}
+	// 用户配置的 allow_headers 不被修改
+	if len(custom.AllowHeaders) != 1 {
+		t.Errorf("AllowHeaders = %v, want it unchanged", custom.AllowHeaders)
+	}
+}
+
+func TestCORSSetTenancy(t *testing.T) {
+	cors := NewCORS(conf.CORSConfig{Enabled: true, AllowOrigins: []string{"*"}}, conf.TenancyConfig{})
+	engine := newTestEngine(func(c *gin.Context) { c.Status(http.StatusOK) }, cors.Middleware())
+	preflight := func() int {
+		return serve(engine, http.MethodOptions, "/", "", "Origin", "https://app.example.com",
+			"Access-Control-Request-Method", "GET", "Access-Control-Request-Headers", "X-Tenant-ID").Code
+	}
+
+	if code := preflight(); code != http.StatusForbidden {
+		t.Fatalf("before enabling tenancy: status = %d, want 403", code)
+	}
+	// 配置热加载启用多租户后，预检立即允许租户请求头
+	cors.SetTenancy(conf.TenancyConfig{Enabled: true})
+	if code := preflight(); code != http.StatusNoContent {
+		t.Fatalf("after enabling tenancy: status = %d, want 204", code)
+	}
+	// 替换跨域策略时保留多租户配置
+	cors.SetPolicy(conf.CORSConfig{Enabled: true, AllowOrigins: []string{"*"}, MaxAge: time.Minute})
+	if code := preflight(); code != http.StatusNoContent {
+		t.Fatalf("after SetPolicy: status = %d, want 204", code)
+	}
+}
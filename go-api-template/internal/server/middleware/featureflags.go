@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/pkg/featureflags"
+)
+
+// FeatureFlags 返回把功能开关放入 request context 的中间件
+// 开关在 biz 层判断时才求值，届时 context 中已有 Tenant、RequireAuth 放入的租户与用户，
+// 因此本中间件在链中的位置不影响求值结果
+func FeatureFlags(flags *featureflags.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(featureflags.NewContext(c.Request.Context(), flags))
+		c.Next()
+	}
+}
@@ -0,0 +1,249 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/idempotency"
+	"go-api-template/internal/pkg/reason"
+	"go-api-template/internal/pkg/tenant"
+	"go-api-template/internal/server/response"
+)
+
+// 幂等键相关常量
+const (
+	// HeaderIdempotencyKey 客户端为一次逻辑操作生成的唯一键（通常是 UUID），重试时保持不变
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed 响应来自首次请求的重放时写入该头
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLen 幂等键的最大长度
+	maxIdempotencyKeyLen = 255
+	// maxIdempotencyPoll 等待首次请求完成时的最大轮询间隔
+	maxIdempotencyPoll = 200 * time.Millisecond
+)
+
+// unreplayedHeaders 不随响应重放的头：它们描述的是单次请求而不是操作结果
+var unreplayedHeaders = []string{HeaderXRequestID, "Date", "Content-Length"}
+
+// Idempotency 返回幂等键中间件，只作用于携带 Idempotency-Key 头的 POST 请求
+//   - 首次请求正常执行，响应（状态码、响应头、响应体）保存 TTL 时长；5xx 响应不保存，客户端可以重试
+//   - 相同键、相同内容的重复请求直接重放首次响应，并带上 Idempotent-Replayed: true
+//   - 相同键、不同内容（方法、路径或请求体不同）的请求返回 422
+//   - 首次请求仍在处理中时，重复请求等待其完成后重放，而不是再执行一次；等待超时返回 409
+//
+// 键按租户与 Authorization 头隔离，不同租户、不同用户使用相同的键互不影响
+func Idempotency(store idempotency.Store, cfg conf.IdempotencyConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if key == "" || c.Request.Method != http.MethodPost {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			response.ErrorJSON(c, apperrors.InvalidParams("Idempotency-Key 过长"))
+			c.Abort()
+			return
+		}
+
+		// 请求体参与指纹计算，读取后放回供 Handler 绑定
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			// 超出 BodyLimit 设定的上限时返回 413
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		tenantID, _ := tenant.FromContext(c.Request.Context())
+		storeKey := digest(tenantID, c.GetHeader("Authorization"), key)
+		fingerprint := digest(c.Request.Method, c.Request.URL.RequestURI(), string(body))
+
+		rec, appErr := awaitIdempotencyKey(c, store, cfg, storeKey, fingerprint)
+		switch {
+		case appErr != nil:
+			response.ErrorJSON(c, appErr)
+			c.Abort()
+		case rec == nil:
+			executeIdempotent(c, store, cfg, storeKey, fingerprint)
+		default:
+			replay(c, rec.Response)
+		}
+	}
+}
+
+// awaitIdempotencyKey 占用幂等键，键已被占用且首次请求未完成时轮询等待
+// 返回 (nil, nil) 表示当前请求获得了执行权；返回记录表示应当重放其中的响应
+func awaitIdempotencyKey(c *gin.Context, store idempotency.Store, cfg conf.IdempotencyConfig,
+	key, fingerprint string) (*idempotency.Record, *apperrors.AppError) {
+	ctx := c.Request.Context()
+	deadline := time.Now().Add(cfg.GetWaitTimeout())
+	poll := 10 * time.Millisecond
+
+	for {
+		rec, started, err := store.Begin(ctx, key, fingerprint, cfg.GetLockTimeout())
+		if err != nil {
+			// 无法确认是否已执行过时拒绝执行，避免重复执行
+			return nil, apperrors.Wrap(reason.ServiceUnavailable, "幂等键存储暂不可用", err)
+		}
+		if started {
+			return nil, nil
+		}
+		if rec.Fingerprint != fingerprint {
+			return nil, apperrors.New(reason.UnprocessableEntity, "Idempotency-Key 已被用于内容不同的请求")
+		}
+		if rec.Completed() {
+			return rec, nil
+		}
+
+		if time.Now().Add(poll).After(deadline) {
+			return nil, apperrors.New(reason.Conflict, "相同 Idempotency-Key 的请求仍在处理中，请稍后重试")
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, apperrors.Wrap(reason.Timeout, "请求处理超时", ctx.Err())
+			}
+			return nil, apperrors.Wrap(reason.ServiceUnavailable, "请求已取消", ctx.Err())
+		case <-time.After(poll):
+		}
+		poll = min(poll*2, maxIdempotencyPoll)
+	}
+}
+
+// executeIdempotent 执行首次请求并保存响应
+// Handler panic 时同样释放幂等键（panic 由外层的 Recovery 转换为 500 响应）
+func executeIdempotent(c *gin.Context, store idempotency.Store, cfg conf.IdempotencyConfig, key, fingerprint string) {
+	// 客户端断开不应影响结果的保存，否则它重试时会再执行一次
+	ctx := context.WithoutCancel(c.Request.Context())
+	// 外层中间件（CORS、Compress 的 Vary 等）此时已写入的响应头在重放时会由它们再次写入，不保存
+	inherited := c.Writer.Header().Clone()
+	recorder := &recordingWriter{ResponseWriter: c.Writer}
+	c.Writer = recorder
+
+	saved := false
+	defer func() {
+		if !saved {
+			if err := store.Release(ctx, key); err != nil {
+				slog.Error("Failed to release idempotency key", "error", err)
+			}
+		}
+	}()
+
+	c.Next()
+
+	status := recorder.Status()
+	if status >= http.StatusInternalServerError {
+		return
+	}
+	header := recorder.handlerHeader()
+	for _, name := range unreplayedHeaders {
+		header.Del(name)
+	}
+	removeHeaderValues(header, inherited)
+	rec := &idempotency.Record{
+		Fingerprint: fingerprint,
+		Response:    &idempotency.Response{Status: status, Header: header, Body: recorder.body.Bytes()},
+	}
+	if err := store.Complete(ctx, key, rec, cfg.GetTTL()); err != nil {
+		slog.Error("Failed to save idempotent response", "error", err)
+		return
+	}
+	saved = true
+}
+
+// replay 写出首次请求保存的响应
+func replay(c *gin.Context, resp *idempotency.Response) {
+	for name, values := range resp.Header {
+		for _, v := range values {
+			c.Writer.Header().Add(name, v)
+		}
+	}
+	c.Header(HeaderIdempotentReplayed, "true")
+	c.Writer.WriteHeader(resp.Status)
+	_, _ = c.Writer.Write(resp.Body)
+	c.Abort()
+}
+
+// digest 计算若干字段的 SHA-256，字段之间以 0 字节分隔避免拼接歧义
+func digest(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// removeHeaderValues 从 header 中删除 inherited 里出现过的值
+func removeHeaderValues(header, inherited http.Header) {
+	for name, values := range inherited {
+		kept := slices.DeleteFunc(header.Values(name), func(v string) bool {
+			return slices.Contains(values, v)
+		})
+		if len(kept) == 0 {
+			header.Del(name)
+		} else {
+			header[name] = kept
+		}
+	}
+}
+
+// recordingWriter 在写出响应的同时保留一份响应体，以及 Handler 写出响应体时的响应头
+// 响应头在写出前记录：内层的 compressWriter 会在写出时加上 Content-Encoding，
+// 而保存的响应体是未压缩的，重放时由 Compress 按新请求的 Accept-Encoding 重新压缩
+type recordingWriter struct {
+	gin.ResponseWriter
+	body   bytes.Buffer
+	header http.Header
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.snapshot()
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.snapshot()
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *recordingWriter) WriteHeaderNow() {
+	w.snapshot()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *recordingWriter) Flush() {
+	w.snapshot()
+	w.ResponseWriter.Flush()
+}
+
+// snapshot 记录首次写出前的响应头，之后的改动来自下游的 Writer 而不是 Handler
+func (w *recordingWriter) snapshot() {
+	if w.header == nil {
+		w.header = w.ResponseWriter.Header().Clone()
+	}
+}
+
+// handlerHeader 返回需要保存的响应头；没有响应体的响应取当前的响应头
+func (w *recordingWriter) handlerHeader() http.Header {
+	if w.header == nil {
+		return w.ResponseWriter.Header().Clone()
+	}
+	return w.header.Clone()
+}
@@ -0,0 +1,253 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/compress"
+	"go-api-template/internal/pkg/idempotency"
+)
+
+// countingHandler 每次执行计数加一，并把计数写入响应体，便于区分重放与重新执行
+func countingHandler(calls *atomic.Int32, status int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		n := calls.Add(1)
+		c.Header("X-Custom", "kept")
+		c.Header(HeaderXRequestID, fmt.Sprintf("req-%d", n))
+		c.String(status, "call %d", n)
+	}
+}
+
+func TestIdempotency(t *testing.T) {
+	type request struct {
+		method, path, body, key, auth string
+	}
+	post := func(body string) request { return request{http.MethodPost, "/orders", body, "key-1", ""} }
+
+	tests := []struct {
+		name       string
+		status     int
+		first      request
+		second     request
+		wantStatus int
+		wantBody   string
+		replayed   bool
+		wantCalls  int32
+	}{
+		{
+			name: "same request is replayed", status: http.StatusCreated,
+			first: post(`{"a":1}`), second: post(`{"a":1}`),
+			wantStatus: http.StatusCreated, wantBody: "call 1", replayed: true, wantCalls: 1,
+		},
+		{
+			name: "different body is rejected", status: http.StatusCreated,
+			first: post(`{"a":1}`), second: post(`{"a":2}`),
+			wantStatus: http.StatusUnprocessableEntity, wantCalls: 1,
+		},
+		{
+			name: "different path is rejected", status: http.StatusCreated,
+			first: post(`{"a":1}`), second: request{http.MethodPost, "/orders/1/pay", `{"a":1}`, "key-1", ""},
+			wantStatus: http.StatusUnprocessableEntity, wantCalls: 1,
+		},
+		{
+			name: "keys are scoped by authorization", status: http.StatusCreated,
+			first: post(`{"a":1}`), second: request{http.MethodPost, "/orders", `{"a":1}`, "key-1", "Bearer other"},
+			wantStatus: http.StatusCreated, wantBody: "call 2", wantCalls: 2,
+		},
+		{
+			name: "server errors are not saved", status: http.StatusInternalServerError,
+			first: post(`{"a":1}`), second: post(`{"a":1}`),
+			wantStatus: http.StatusInternalServerError, wantBody: "call 2", wantCalls: 2,
+		},
+		{
+			name: "requests without key run every time", status: http.StatusCreated,
+			first:      request{http.MethodPost, "/orders", `{"a":1}`, "", ""},
+			second:     request{http.MethodPost, "/orders", `{"a":1}`, "", ""},
+			wantStatus: http.StatusCreated, wantBody: "call 2", wantCalls: 2,
+		},
+		{
+			name: "non-POST requests ignore the key", status: http.StatusOK,
+			first:      request{http.MethodPut, "/orders/1", `{"a":1}`, "key-1", ""},
+			second:     request{http.MethodPut, "/orders/1", `{"a":1}`, "key-1", ""},
+			wantStatus: http.StatusOK, wantBody: "call 2", wantCalls: 2,
+		},
+		{
+			name: "overlong key is rejected", status: http.StatusCreated,
+			first:      request{http.MethodPost, "/orders", "", "", ""},
+			second:     request{http.MethodPost, "/orders", "", strings.Repeat("k", maxIdempotencyKeyLen+1), ""},
+			wantStatus: http.StatusBadRequest, wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			engine := newTestEngine(countingHandler(&calls, tt.status),
+				Idempotency(idempotency.NewMemoryStore(), conf.IdempotencyConfig{}))
+			send := func(r request) *httptest.ResponseRecorder {
+				return serve(engine, r.method, r.path, r.body, HeaderIdempotencyKey, r.key, "Authorization", r.auth)
+			}
+
+			send(tt.first)
+			w := send(tt.second)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", w.Body, tt.wantBody)
+			}
+			if got := w.Header().Get(HeaderIdempotentReplayed) == "true"; got != tt.replayed {
+				t.Errorf("replayed = %v, want %v", got, tt.replayed)
+			}
+			if tt.replayed {
+				if w.Header().Get("X-Custom") != "kept" {
+					t.Error("replayed response lost the handler's headers")
+				}
+				if w.Header().Get(HeaderXRequestID) != "" {
+					t.Error("replayed response repeated the first request's X-Request-ID")
+				}
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("handler calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyConcurrentRequests(t *testing.T) {
+	tests := []struct {
+		name        string
+		waitTimeout time.Duration
+		wantStatus  int
+		wantCalls   int32
+	}{
+		{"duplicate waits and replays", 5 * time.Second, http.StatusCreated, 1},
+		{"duplicate gives up after wait timeout", 50 * time.Millisecond, http.StatusConflict, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			started, release := make(chan struct{}), make(chan struct{})
+			handler := func(c *gin.Context) {
+				n := calls.Add(1)
+				if n == 1 {
+					close(started)
+					<-release
+				}
+				c.String(http.StatusCreated, "call %d", n)
+			}
+			engine := newTestEngine(handler, Idempotency(idempotency.NewMemoryStore(),
+				conf.IdempotencyConfig{WaitTimeout: tt.waitTimeout}))
+
+			var wg sync.WaitGroup
+			var first, second *httptest.ResponseRecorder
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				first = serve(engine, http.MethodPost, "/orders", "{}", HeaderIdempotencyKey, "key-1")
+			}()
+			<-started
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				second = serve(engine, http.MethodPost, "/orders", "{}", HeaderIdempotencyKey, "key-1")
+			}()
+
+			if tt.wantStatus == http.StatusConflict {
+				<-done
+				close(release)
+			} else {
+				select {
+				case <-done:
+					t.Fatal("duplicate request finished while the first one was still running")
+				case <-time.After(100 * time.Millisecond):
+				}
+				close(release)
+				<-done
+			}
+			wg.Wait()
+
+			if first.Code != http.StatusCreated || first.Body.String() != "call 1" {
+				t.Errorf("first = %d %q", first.Code, first.Body)
+			}
+			if second.Code != tt.wantStatus {
+				t.Errorf("second status = %d, want %d (body %s)", second.Code, tt.wantStatus, second.Body)
+			}
+			if tt.wantStatus == http.StatusCreated && second.Body.String() != "call 1" {
+				t.Errorf("second body = %q, want replay of the first response", second.Body)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("handler calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyWithCompression(t *testing.T) {
+	payload := strings.Repeat("compressible ", 100)
+	tests := []struct {
+		name         string
+		replayAccept string
+		wantEncoding string
+	}{
+		{"replay is compressed again", "gzip", "gzip"},
+		{"replay without Accept-Encoding is plain", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			handler := func(c *gin.Context) {
+				calls.Add(1)
+				c.String(http.StatusCreated, payload)
+			}
+			engine := newTestEngine(handler, Compress(conf.CompressionConfig{Enabled: true, MinSize: 256}),
+				Idempotency(idempotency.NewMemoryStore(), conf.IdempotencyConfig{}))
+
+			first := serve(engine, http.MethodPost, "/orders", "{}", HeaderIdempotencyKey, "key-1", "Accept-Encoding", "gzip")
+			if got := first.Header().Get("Content-Encoding"); got != "gzip" {
+				t.Fatalf("first Content-Encoding = %q, want gzip", got)
+			}
+			w := serve(engine, http.MethodPost, "/orders", "{}", HeaderIdempotencyKey, "key-1", "Accept-Encoding", tt.replayAccept)
+
+			if w.Code != http.StatusCreated || w.Header().Get(HeaderIdempotentReplayed) != "true" {
+				t.Fatalf("replay = %d, replayed %q", w.Code, w.Header().Get(HeaderIdempotentReplayed))
+			}
+			if got := w.Header().Values("Content-Encoding"); len(got) > 1 || w.Header().Get("Content-Encoding") != tt.wantEncoding {
+				t.Fatalf("replay Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if got := w.Header().Values("Vary"); len(got) != 1 || got[0] != "Accept-Encoding" {
+				t.Errorf("replay Vary = %q, want a single Accept-Encoding", got)
+			}
+
+			body := w.Body.String()
+			if codec := compress.Lookup(tt.wantEncoding); codec != nil {
+				r, err := codec.Reader(w.Body)
+				if err != nil {
+					t.Fatalf("open %s reader: %v", tt.wantEncoding, err)
+				}
+				decoded, err := io.ReadAll(r)
+				if err != nil {
+					t.Fatalf("decode replay: %v", err)
+				}
+				body = string(decoded)
+			}
+			if body != payload {
+				t.Errorf("replay body = %.40q..., want %.40q...", body, payload)
+			}
+			if got := calls.Load(); got != 1 {
+				t.Errorf("handler calls = %d, want 1", got)
+			}
+		})
+	}
+}
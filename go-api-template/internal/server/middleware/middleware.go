@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/pkg/crash"
 )
 
 // middlewareChain 定义中间件链
@@ -14,15 +16,19 @@ import (
 //  1. 顺序一目了然，修改只需调整数组
 //  2. 符合声明式编程风格
 //  3. 避免多次调用 engine.Use() 的冗余
-var middlewareChain = []gin.HandlerFunc{
-	RequestID(),  // [0] 最先执行，确保后续中间件都能获取请求 ID
-	Recovery(),   // [1] 捕获后续所有代码的 panic
-	gin.Logger(), // [2] 记录请求日志
+func middlewareChain(reporter crash.PanicReporter) []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		RequestID(),        // [0] 最先执行，确保后续中间件都能获取请求 ID
+		Recovery(reporter), // [1] 捕获后续所有代码的 panic
+		Logger(),           // [2] 记录请求日志，隐藏 URL 中的访问令牌
+	}
 }
 
 // Register 注册所有中间件到 Gin 引擎
-func Register(engine *gin.Engine) {
-	engine.Use(middlewareChain...)
+// reporter 上报 Recovery 捕获的 panic；extra 是依赖运行期配置的中间件（如限流），追加在固定链之后执行
+func Register(engine *gin.Engine, reporter crash.PanicReporter, extra ...gin.HandlerFunc) {
+	engine.Use(middlewareChain(reporter)...)
+	engine.Use(extra...)
 }
 
 // RegisterRouteHandlers 注册路由级别的错误处理
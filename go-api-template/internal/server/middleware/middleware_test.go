@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestEngine 创建只注册了 mws 与一个 handler 的引擎，handler 挂在 "/*path" 上响应所有方法
+func newTestEngine(handler gin.HandlerFunc, mws ...gin.HandlerFunc) *gin.Engine {
+	engine := gin.New()
+	engine.Use(mws...)
+	engine.Any("/*path", handler)
+	return engine
+}
+
+// serve 发送一个请求并返回响应，headers 按 "名称, 值" 成对给出
+func serve(engine http.Handler, method, target, body string, headers ...string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
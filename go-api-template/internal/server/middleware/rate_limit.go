@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/reason"
+	"go-api-template/internal/pkg/tenant"
+	"go-api-template/internal/server/response"
+)
+
+// bucketIdleTTL 令牌桶闲置超过此时间后被清理，避免按 IP 建桶导致内存无限增长
+const bucketIdleTTL = 10 * time.Minute
+
+// RateLimiter 按租户与客户端 IP 的令牌桶限流器
+// 每个租户的令牌桶相互独立，租户可以覆盖速率与容量；
+// 策略可以在运行期通过 SetPolicy、SetTenancy 替换（配置热加载），已有的桶会按新策略继续计算
+type RateLimiter struct {
+	mu        sync.Mutex
+	policy    conf.RateLimitConfig
+	tenancy   conf.TenancyConfig
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// tokenBucket 单个客户端的令牌桶状态
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// NewRateLimiter 创建限流器
+func NewRateLimiter(policy conf.RateLimitConfig, tenancy conf.TenancyConfig) *RateLimiter {
+	return &RateLimiter{
+		policy:    policy,
+		tenancy:   tenancy,
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// SetPolicy 替换限流策略
+func (l *RateLimiter) SetPolicy(policy conf.RateLimitConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.policy = policy
+}
+
+// SetTenancy 替换租户的限流覆盖
+func (l *RateLimiter) SetTenancy(tenancy conf.TenancyConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tenancy = tenancy
+}
+
+// Allow 判断租户中 client 对应的客户端是否还有可用令牌
+// tenantID 为空（请求未能解析出租户）时使用全局策略
+func (l *RateLimiter) Allow(tenantID, client string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.policy.Enabled {
+		return true
+	}
+	policy := l.tenancy.RateLimitFor(tenantID, l.policy)
+
+	now := time.Now()
+	l.sweep(now)
+
+	key := tenantID + "/" + client
+	b, ok := l.buckets[key]
+	if !ok {
+		// 新客户端以满桶开始，允许一次突发
+		b = &tokenBucket{tokens: float64(policy.Burst), lastSeen: now}
+		l.buckets[key] = b
+	}
+
+	// 按流逝时间补充令牌，不超过桶容量
+	elapsed := now.Sub(b.lastSeen).Seconds()
+	b.tokens = min(float64(policy.Burst), b.tokens+elapsed*policy.RPS)
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sweep 定期清理闲置的令牌桶，调用方需持有锁
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < bucketIdleTTL {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > bucketIdleTTL {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// Middleware 返回限流中间件，需位于 Tenant 之后
+// 超出限额时返回 429 统一错误响应
+func (l *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, _ := tenant.FromContext(c.Request.Context())
+		if !l.Allow(tenantID, c.ClientIP()) {
+			response.ErrorJSON(c, apperrors.New(reason.TooManyRequests, "请求过于频繁，请稍后再试"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -2,25 +2,26 @@ package middleware
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"runtime/debug"
 
 	"github.com/gin-gonic/gin"
 
 	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/crash"
 	"go-api-template/internal/server/response"
 )
 
 // Recovery 返回 Panic 恢复中间件
 // 职责：
 //   - 捕获 Handler 中发生的 panic，防止服务崩溃
-//   - 记录错误堆栈到日志（用于调试和告警）
+//   - 记录错误堆栈到日志，并交给 reporter 上报（崩溃转储、告警 webhook 等，见 panic_report 配置）
 //   - 返回统一格式的 500 错误响应（不暴露内部细节）
 //
 // 为什么需要自定义 Recovery？
 // Gin 内置的 gin.Recovery() 返回的是纯文本或 HTML 格式，
 // 将返回内容统一为 JSON 响应格式和一致的结构体。
-func Recovery() gin.HandlerFunc {
+func Recovery(reporter crash.PanicReporter) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
@@ -30,10 +31,14 @@ func Recovery() gin.HandlerFunc {
 				// 获取堆栈信息
 				stack := debug.Stack()
 
-				// 记录到日志（生产环境应接入日志系统/告警）
-				// 这里使用标准库 log，未来可替换为 zap/zerolog
-				log.Printf("[PANIC RECOVERED] request_id=%s error=%v\n%s",
-					requestID, err, string(stack))
+				// 路由模板而非实际路径：同一接口的报告可以归并，路径参数与查询参数中的令牌也不会进入报告
+				route := c.Request.Method + " " + c.FullPath()
+				slog.Error("Panic recovered", "request_id", requestID, "route", route,
+					"error", fmt.Sprint(err), "stack", string(stack))
+				report := crash.New(crash.ProtocolHTTP, requestID, route, c.Request.Header, err, stack)
+				if reportErr := reporter.Report(c.Request.Context(), report); reportErr != nil {
+					slog.Error("Failed to report panic", "request_id", requestID, "error", reportErr)
+				}
 
 				// 返回统一格式的错误响应
 				// 注意：不暴露 panic 的具体信息给客户端（安全性）
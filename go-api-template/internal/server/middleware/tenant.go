@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/tenant"
+	"go-api-template/internal/server/response"
+)
+
+// tenantErrKey gin context 中保存租户解析错误的键
+const tenantErrKey = "tenant_error"
+
+// Tenant 返回租户解析中间件，注册在引擎级别
+// 解析成功时把租户放入 request context；解析失败时只记录错误并放行，
+// 由挂载在业务路由组上的 RequireTenant 拒绝请求，健康检查、Swagger 等路由不受租户影响
+// WebSocket 客户端无法设置请求头，未携带 Authorization 时从 access_token 查询参数读取令牌
+func Tenant(resolver *tenant.Resolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authorization := c.GetHeader("Authorization")
+		if authorization == "" {
+			if token := c.Query("access_token"); token != "" {
+				authorization = "Bearer " + token
+			}
+		}
+		id, err := resolver.Resolve(tenant.Source{
+			Host:          c.Request.Host,
+			Header:        c.GetHeader(resolver.Header()),
+			Authorization: authorization,
+		})
+		if err != nil {
+			c.Set(tenantErrKey, err)
+			c.Next()
+			return
+		}
+		c.Request = c.Request.WithContext(tenant.NewContext(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// RequireTenant 返回要求请求已解析出租户的中间件，挂载在业务路由组上
+func RequireTenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if v, ok := c.Get(tenantErrKey); ok {
+			response.ErrorJSON(c, tenantError(v.(error)))
+			c.Abort()
+			return
+		}
+		if _, err := tenant.Require(c.Request.Context()); err != nil {
+			response.ErrorJSON(c, tenantError(err))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// tenantError 将租户错误映射为 AppError，与 server 层的 toAppError 保持一致
+func tenantError(err error) *apperrors.AppError {
+	switch {
+	case errors.Is(err, tenant.ErrRequired), errors.Is(err, tenant.ErrInvalid):
+		return apperrors.InvalidParams(err.Error())
+	case errors.Is(err, tenant.ErrUnknown):
+		return apperrors.NotFound(err.Error())
+	case errors.Is(err, tenant.ErrMismatch):
+		return apperrors.Forbidden("访问令牌不属于当前租户")
+	default:
+		return apperrors.Internal("租户解析失败", err)
+	}
+}
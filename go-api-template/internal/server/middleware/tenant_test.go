@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/reason"
+	"go-api-template/internal/pkg/tenant"
+)
+
+// fakeTokens 按 Authorization 取出租户，令牌不在表中时视为无效
+type fakeTokens map[string]string
+
+func (f fakeTokens) TenantOf(authorization string) (string, error) {
+	id, ok := f[authorization]
+	if !ok {
+		return "", errors.New("invalid token")
+	}
+	return id, nil
+}
+
+// newTenantEngine 注册租户解析中间件：/api 下的路由要求租户，/health 不要求
+// handler 以请求 context 中的租户作为响应体
+func newTenantEngine(cfg conf.TenancyConfig) *gin.Engine {
+	resolver := tenant.NewResolver(conf.NewWatcher(&conf.Config{Tenancy: cfg}), fakeTokens{"Bearer globex": "globex"})
+	echo := func(c *gin.Context) {
+		id, _ := tenant.FromContext(c.Request.Context())
+		c.String(http.StatusOK, id)
+	}
+	engine := gin.New()
+	engine.Use(Tenant(resolver))
+	engine.GET("/health", echo)
+	engine.Group("/api", RequireTenant()).GET("/greetings", echo)
+	return engine
+}
+
+func TestRequireTenant(t *testing.T) {
+	enabled := conf.TenancyConfig{
+		Enabled: true,
+		Tenants: map[string]conf.TenantConfig{"acme": {Enabled: true}, "globex": {Enabled: true}},
+	}
+	tests := []struct {
+		name       string
+		cfg        conf.TenancyConfig
+		target     string
+		headers    []string
+		wantStatus int
+		wantTenant string
+		wantReason reason.Reason
+	}{
+		{name: "disabled", cfg: conf.TenancyConfig{}, target: "/api/greetings", wantStatus: http.StatusOK, wantTenant: tenant.Default},
+		{name: "header", cfg: enabled, target: "/api/greetings", headers: []string{"X-Tenant-ID", "acme"}, wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "authorization", cfg: enabled, target: "/api/greetings", headers: []string{"Authorization", "Bearer globex"}, wantStatus: http.StatusOK, wantTenant: "globex"},
+		// WebSocket 客户端无法设置请求头，从 access_token 查询参数读取令牌
+		{name: "access token query", cfg: enabled, target: "/api/greetings?access_token=globex", wantStatus: http.StatusOK, wantTenant: "globex"},
+		{name: "missing", cfg: enabled, target: "/api/greetings", wantStatus: http.StatusBadRequest, wantReason: reason.InvalidParams},
+		{name: "invalid", cfg: enabled, target: "/api/greetings", headers: []string{"X-Tenant-ID", "acme_corp"}, wantStatus: http.StatusBadRequest, wantReason: reason.InvalidParams},
+		{name: "unknown", cfg: enabled, target: "/api/greetings", headers: []string{"X-Tenant-ID", "initech"}, wantStatus: http.StatusNotFound, wantReason: reason.NotFound},
+		// 不要求租户的路由不受解析失败影响
+		{name: "health without tenant", cfg: enabled, target: "/health", wantStatus: http.StatusOK},
+		{name: "health with unknown tenant", cfg: enabled, target: "/health", headers: []string{"X-Tenant-ID", "initech"}, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(newTenantEngine(tt.cfg), http.MethodGet, tt.target, "", tt.headers...)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantReason != "" {
+				if !strings.Contains(w.Body.String(), string(tt.wantReason)) {
+					t.Errorf("body = %s, want reason %s", w.Body.String(), tt.wantReason)
+				}
+				return
+			}
+			if got := w.Body.String(); got != tt.wantTenant {
+				t.Errorf("tenant = %q, want %q", got, tt.wantTenant)
+			}
+		})
+	}
+}
+
+func TestRequireTenantWithoutResolver(t *testing.T) {
+	// 漏注册 Tenant 中间件是编程错误，按内部错误拒绝，而不是退回到默认租户
+	engine := newTestEngine(func(c *gin.Context) { c.Status(http.StatusOK) }, RequireTenant())
+	w := serve(engine, http.MethodGet, "/api/greetings", "")
+	if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), string(reason.InternalError)) {
+		t.Errorf("status = %d, body = %s; want 500 %s", w.Code, w.Body.String(), reason.InternalError)
+	}
+}
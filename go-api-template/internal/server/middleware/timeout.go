@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/reason"
+	"go-api-template/internal/server/response"
+)
+
+const (
+	// HeaderRequestTimeout 客户端要求的处理时限，如 "2s"、"500ms"，纯数字按秒计
+	HeaderRequestTimeout = "X-Request-Timeout"
+	// HeaderGRPCTimeout gRPC 的时限格式，如 "500m"（毫秒）、"2S"（秒），便于 gRPC 网关直接转发
+	HeaderGRPCTimeout = "Grpc-Timeout"
+
+	// maxRequestTimeout 客户端时限的上限，更长的时限按上限处理，避免换算为 time.Duration 时溢出
+	// 服务端时限总是更短，上限只影响没有配置时限的路由
+	maxRequestTimeout = 24 * time.Hour
+)
+
+// timeoutParentKey gin.Context 中保存附加时限之前的请求 context 的键
+const timeoutParentKey = "timeout.parent"
+
+// Timeout 返回请求时限中间件
+// 时限取路由配置（server.route_timeouts，未配置时为 server.request_timeout）与客户端要求中较短的一个，
+// 附加到请求 context 上：Repo 等下游调用通过同一个 context 感知截止时间，到期后一并取消。
+// 处理函数在到期后通常会返回 context.DeadlineExceeded，由 toAppError 映射为 504；
+// 处理函数没有写出任何响应时，由本中间件补写 504
+func Timeout(cfg conf.ServerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := cfg.GetRouteTimeout(c.Request.Method + " " + c.FullPath())
+		requested, err := requestedTimeout(c)
+		if err != nil {
+			response.ErrorJSON(c, apperrors.InvalidParams(err.Error()))
+			c.Abort()
+			return
+		}
+		if requested > 0 && (timeout == 0 || requested < timeout) {
+			timeout = requested
+		}
+		if timeout == 0 {
+			c.Next()
+			return
+		}
+
+		parent := c.Request.Context()
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+		c.Set(timeoutParentKey, parent)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			response.ErrorJSON(c, apperrors.New(reason.Timeout, "请求处理超时"))
+		}
+	}
+}
+
+// DetachTimeout 移除 Timeout 附加的时限，用于 SSE、WebSocket 等长连接
+// 保留请求 context 中的其他值，客户端断开时 context 仍会被取消
+func DetachTimeout(c *gin.Context) {
+	value, _ := c.Get(timeoutParentKey)
+	parent, ok := value.(context.Context)
+	if !ok {
+		return
+	}
+	c.Request = c.Request.WithContext(detachedContext{Context: c.Request.Context(), parent: parent})
+}
+
+// detachedContext 取值时使用当前 context，取消信号与截止时间来自附加时限之前的 context
+type detachedContext struct {
+	context.Context
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool) { return c.parent.Deadline() }
+func (c detachedContext) Done() <-chan struct{}       { return c.parent.Done() }
+func (c detachedContext) Err() error                  { return c.parent.Err() }
+
+// requestedTimeout 解析客户端要求的时限，两个请求头都没有时返回 0
+// 超过 maxRequestTimeout 的时限按上限处理，不足 1ns 的时限视为格式错误，而不是"不限时"
+func requestedTimeout(c *gin.Context) (time.Duration, error) {
+	if v := c.GetHeader(HeaderRequestTimeout); v != "" {
+		if seconds, err := strconv.ParseFloat(v, 64); err == nil && seconds > 0 {
+			if seconds >= maxRequestTimeout.Seconds() {
+				return maxRequestTimeout, nil
+			}
+			if d := time.Duration(seconds * float64(time.Second)); d > 0 {
+				return d, nil
+			}
+		} else if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return min(d, maxRequestTimeout), nil
+		}
+		return 0, errors.New(HeaderRequestTimeout + " 必须是不短于 1ns 的时长，如 2s、500ms")
+	}
+	if v := c.GetHeader(HeaderGRPCTimeout); v != "" {
+		d, ok := parseGRPCTimeout(v)
+		if !ok {
+			return 0, errors.New("grpc-timeout 格式错误，应为 1 到 8 位数字加单位 H/M/S/m/u/n")
+		}
+		return d, nil
+	}
+	return 0, nil
+}
+
+// grpcTimeoutUnits grpc-timeout 的单位
+var grpcTimeoutUnits = map[byte]time.Duration{
+	'H': time.Hour,
+	'M': time.Minute,
+	'S': time.Second,
+	'm': time.Millisecond,
+	'u': time.Microsecond,
+	'n': time.Nanosecond,
+}
+
+// parseGRPCTimeout 按 gRPC over HTTP/2 规范解析 grpc-timeout，超过 maxRequestTimeout 时按上限处理
+func parseGRPCTimeout(v string) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if len(v) < 2 || len(v) > 9 {
+		return 0, false
+	}
+	unit, ok := grpcTimeoutUnits[v[len(v)-1]]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	if n > int64(maxRequestTimeout/unit) {
+		return maxRequestTimeout, true
+	}
+	return time.Duration(n) * unit, true
+}
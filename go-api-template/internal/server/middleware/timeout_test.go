@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/reason"
+)
+
+func TestRequestedTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "no header", want: 0},
+		{name: "seconds", header: HeaderRequestTimeout, value: "2", want: 2 * time.Second},
+		{name: "fractional seconds", header: HeaderRequestTimeout, value: "0.25", want: 250 * time.Millisecond},
+		{name: "duration", header: HeaderRequestTimeout, value: "500ms", want: 500 * time.Millisecond},
+		{name: "huge seconds are capped", header: HeaderRequestTimeout, value: "1e300", want: maxRequestTimeout},
+		{name: "infinite seconds are capped", header: HeaderRequestTimeout, value: "+Inf", want: maxRequestTimeout},
+		{name: "long duration is capped", header: HeaderRequestTimeout, value: "100h", want: maxRequestTimeout},
+		{name: "below 1ns", header: HeaderRequestTimeout, value: "1e-12", wantErr: true},
+		{name: "zero", header: HeaderRequestTimeout, value: "0", wantErr: true},
+		{name: "negative", header: HeaderRequestTimeout, value: "-1s", wantErr: true},
+		{name: "not a number", header: HeaderRequestTimeout, value: "NaN", wantErr: true},
+		{name: "garbage", header: HeaderRequestTimeout, value: "soon", wantErr: true},
+		{name: "grpc milliseconds", header: HeaderGRPCTimeout, value: "500m", want: 500 * time.Millisecond},
+		{name: "grpc seconds", header: HeaderGRPCTimeout, value: "2S", want: 2 * time.Second},
+		{name: "grpc nanoseconds", header: HeaderGRPCTimeout, value: "1n", want: time.Nanosecond},
+		{name: "grpc hours are capped", header: HeaderGRPCTimeout, value: "99999999H", want: maxRequestTimeout},
+		{name: "grpc too many digits", header: HeaderGRPCTimeout, value: "123456789S", wantErr: true},
+		{name: "grpc unknown unit", header: HeaderGRPCTimeout, value: "5d", wantErr: true},
+		{name: "grpc zero", header: HeaderGRPCTimeout, value: "0S", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set(tt.header, tt.value)
+			}
+			got, err := requestedTimeout(c)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("requestedTimeout = %v, %v; want %v, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTimeoutDeadline(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        conf.ServerConfig
+		headers    []string
+		want       time.Duration // 0 表示没有截止时间
+		wantStatus int
+	}{
+		{name: "server timeout", cfg: conf.ServerConfig{RequestTimeout: time.Minute}, want: time.Minute, wantStatus: http.StatusOK},
+		{name: "shorter client timeout wins", cfg: conf.ServerConfig{RequestTimeout: time.Minute},
+			headers: []string{HeaderRequestTimeout, "2s"}, want: 2 * time.Second, wantStatus: http.StatusOK},
+		{name: "shorter server timeout wins", cfg: conf.ServerConfig{RequestTimeout: 2 * time.Second},
+			headers: []string{HeaderGRPCTimeout, "1M"}, want: 2 * time.Second, wantStatus: http.StatusOK},
+		{name: "route timeout", cfg: conf.ServerConfig{RequestTimeout: time.Minute,
+			RouteTimeouts: []conf.RouteTimeoutConfig{{Route: "GET /*path", Timeout: 5 * time.Second}}},
+			want: 5 * time.Second, wantStatus: http.StatusOK},
+		{name: "unlimited route", cfg: conf.ServerConfig{RouteTimeouts: []conf.RouteTimeoutConfig{{Route: "GET /*path"}}},
+			want: 0, wantStatus: http.StatusOK},
+		{name: "client timeout on unlimited route", cfg: conf.ServerConfig{RouteTimeouts: []conf.RouteTimeoutConfig{{Route: "GET /*path"}}},
+			headers: []string{HeaderRequestTimeout, "3s"}, want: 3 * time.Second, wantStatus: http.StatusOK},
+		{name: "invalid client timeout", cfg: conf.ServerConfig{}, headers: []string{HeaderRequestTimeout, "1e-12"},
+			wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got time.Duration
+			handler := func(c *gin.Context) {
+				if deadline, ok := c.Request.Context().Deadline(); ok {
+					got = time.Until(deadline)
+				}
+				c.Status(http.StatusOK)
+			}
+			w := serve(newTestEngine(handler, Timeout(tt.cfg)), http.MethodGet, "/orders", "", tt.headers...)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			// 截止时间在处理函数中读取，允许少量误差
+			if got > tt.want || got < tt.want-time.Second {
+				t.Errorf("remaining time = %v, want about %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeoutExpired(t *testing.T) {
+	cfg := conf.ServerConfig{RequestTimeout: 20 * time.Millisecond}
+	tests := []struct {
+		name       string
+		handler    gin.HandlerFunc
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "silent handler gets 504",
+			handler:    func(c *gin.Context) { <-c.Request.Context().Done() },
+			wantStatus: http.StatusGatewayTimeout,
+			wantBody:   string(reason.Timeout),
+		},
+		{
+			name: "handler's own response is kept",
+			handler: func(c *gin.Context) {
+				<-c.Request.Context().Done()
+				c.String(http.StatusServiceUnavailable, "gave up")
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "gave up",
+		},
+		{
+			name: "detached handler outlives the timeout",
+			handler: func(c *gin.Context) {
+				DetachTimeout(c)
+				if _, ok := c.Request.Context().Deadline(); ok {
+					c.String(http.StatusInternalServerError, "still has a deadline")
+					return
+				}
+				time.Sleep(50 * time.Millisecond)
+				if err := c.Request.Context().Err(); err != nil {
+					c.String(http.StatusInternalServerError, err.Error())
+					return
+				}
+				c.String(http.StatusOK, "streamed")
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "streamed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(newTestEngine(tt.handler, Timeout(cfg)), http.MethodGet, "/orders", "")
+			if w.Code != tt.wantStatus || !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("response = %d %s, want %d containing %q", w.Code, w.Body, tt.wantStatus, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestDetachTimeoutKeepsValues(t *testing.T) {
+	type key struct{}
+	var got any
+	handler := func(c *gin.Context) {
+		DetachTimeout(c)
+		got = c.Request.Context().Value(key{})
+	}
+	withValue := func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), key{}, "tenant"))
+	}
+	serve(newTestEngine(handler, Timeout(conf.ServerConfig{}), withValue), http.MethodGet, "/stream", "")
+	if got != "tenant" {
+		t.Errorf("value = %v, want the value set after Timeout", got)
+	}
+}
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+
+	v1 "go-api-template/api/order/v1"
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/server/dto"
+	"go-api-template/internal/server/middleware"
+	"go-api-template/internal/server/response"
+	"go-api-template/internal/service"
+)
+
+// registerOrderRoutes 注册 Order 服务的 HTTP 路由，全部需要访问令牌
+// 状态流转使用 POST /orders/:id/<动作>，而不是 PUT status 字段：
+// 每个动作对应状态机中的一个事件，非法流转返回 409
+func registerOrderRoutes(group *gin.RouterGroup, svc *service.OrderService, tokens *auth.TokenManager) {
+	orders := group.Group("/orders", middleware.RequireAuth(tokens))
+	orders.POST("", handleCreateOrder(svc))
+	orders.GET("", handleListOrders(svc))
+	orders.GET("/:id", handleGetOrder(svc))
+	orders.POST("/:id/pay", handleOrderTransition(func(ctx context.Context, id, version int64) (orderResult, error) {
+		return svc.PayOrder(ctx, &v1.PayOrderRequest{Id: id, Version: version})
+	}))
+	orders.POST("/:id/ship", handleOrderTransition(func(ctx context.Context, id, version int64) (orderResult, error) {
+		return svc.ShipOrder(ctx, &v1.ShipOrderRequest{Id: id, Version: version})
+	}))
+	orders.POST("/:id/complete", handleOrderTransition(func(ctx context.Context, id, version int64) (orderResult, error) {
+		return svc.CompleteOrder(ctx, &v1.CompleteOrderRequest{Id: id, Version: version})
+	}))
+	orders.POST("/:id/cancel", handleCancelOrder(svc))
+}
+
+// orderResult 是各个流转响应的公共部分，用于写出 ETag
+type orderResult interface {
+	GetOrder() *v1.Order
+}
+
+// registerOrderGRPC 注册 Order 服务的 gRPC 实现
+func registerOrderGRPC(srv *grpc.Server, svc *service.OrderService) {
+	v1.RegisterOrderServiceServer(srv, svc)
+}
+
+// handleCreateOrder 创建订单
+//
+// @Summary      创建订单
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body     dto.CreateOrderRequest true "订单参数"
+// @Success      200     {object} response.Response{data=v1.CreateOrderResponse} "成功"
+// @Failure      400     {object} response.Response "请求参数错误"
+// @Failure      401     {object} response.Response "未登录或令牌无效"
+// @Router       /orders [post]
+func handleCreateOrder(svc *service.OrderService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.CreateOrderRequest
+		if err := bindBody(c, &req, &v1.CreateOrderRequest{}); err != nil {
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			return
+		}
+
+		resp, err := svc.CreateOrder(c.Request.Context(), req.ToProto())
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleListOrders 列出当前用户的订单
+//
+// @Summary      订单列表
+// @Description  默认按 ID 倒序使用游标分页：把响应中的 next_cursor 作为下一次请求的 cursor，为空表示没有下一页。
+// @Description  游标与 sort、filter 绑定，修改条件后需从第一页开始；offset 大于 0 时改用偏移分页，不能与 cursor 同时使用。
+// @Description  可排序字段：id、created_at、updated_at、amount；可过滤字段：created_at、amount、quantity（支持 = != > >= < <=）、
+// @Description  status、product（支持 = !=，| 分隔多个取值）。时间使用 RFC 3339 格式。
+// @Tags         order
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit  query    int    false "每页条数（1-100），默认 20"
+// @Param        offset query    int    false "偏移量（0-10000）"
+// @Param        cursor query    string false "上一页响应中的 next_cursor"
+// @Param        sort   query    string false "排序字段，逗号分隔，- 前缀表示倒序，默认 -id" example(-created_at,amount)
+// @Param        filter query    string false "过滤条件，逗号分隔，之间为 AND 关系" example(status=paid|shipped,amount>=1000)
+// @Success      200    {object} response.Response{data=v1.ListOrdersResponse} "成功"
+// @Failure      400    {object} response.Response "分页、排序或过滤参数错误"
+// @Failure      401    {object} response.Response "未登录或令牌无效"
+// @Router       /orders [get]
+func handleListOrders(svc *service.OrderService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query dto.ListOrdersQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			return
+		}
+
+		resp, err := svc.ListOrders(c.Request.Context(), query.ToProto())
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleGetOrder 获取订单及状态流转记录
+//
+// @Summary      订单详情
+// @Description  响应头 ETag 为订单版本；携带 If-None-Match 且版本未变化时返回 304
+// @Tags         order
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id            path     int    true  "订单 ID"
+// @Param        If-None-Match header   string false "上次获取的 ETag"
+// @Success      200 {object} response.Response{data=v1.GetOrderResponse} "成功"
+// @Header       200 {string} ETag "订单版本"
+// @Success      304 "订单未变化"
+// @Failure      401 {object} response.Response "未登录或令牌无效"
+// @Failure      404 {object} response.Response "订单不存在"
+// @Router       /orders/{id} [get]
+func handleGetOrder(svc *service.OrderService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c)
+		if !ok {
+			return
+		}
+
+		resp, err := svc.GetOrder(c.Request.Context(), &v1.GetOrderRequest{Id: id})
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		setETag(c, resp.GetOrder().GetVersion())
+		if notModified(c, resp.GetOrder().GetVersion()) {
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleOrderTransition 处理不需要请求体的状态流转（支付、发货、确认收货）
+//
+// @Summary      订单状态流转
+// @Description  action 为 pay（待支付→已支付）、ship（已支付→已发货）或 complete（已发货→已完成）；
+// @Description  携带 If-Match 时仅在订单版本未变化时流转，否则返回 412
+// @Tags         order
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path     int    true  "订单 ID"
+// @Param        action   path     string true  "流转动作" Enums(pay, ship, complete)
+// @Param        If-Match header   string false "上次获取的 ETag"
+// @Success      200      {object} response.Response{data=v1.PayOrderResponse} "成功"
+// @Header       200      {string} ETag "流转后的订单版本"
+// @Failure      404      {object} response.Response "订单不存在"
+// @Failure      409      {object} response.Response "当前状态不允许该流转"
+// @Failure      412      {object} response.Response "订单已被修改"
+// @Router       /orders/{id}/{action} [post]
+func handleOrderTransition(fire func(ctx context.Context, id, version int64) (orderResult, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c)
+		if !ok {
+			return
+		}
+		version, ok := ifMatchVersion(c)
+		if !ok {
+			return
+		}
+
+		resp, err := fire(c.Request.Context(), id, version)
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		setETag(c, resp.GetOrder().GetVersion())
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleCancelOrder 取消订单
+//
+// @Summary      取消订单
+// @Description  待支付或已支付的订单可以取消，取消原因记录在流转记录中；If-Match 的含义同其他流转
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path     int                    true  "订单 ID"
+// @Param        If-Match header   string                 false "上次获取的 ETag"
+// @Param        request  body     dto.CancelOrderRequest false "取消原因"
+// @Success      200      {object} response.Response{data=v1.CancelOrderResponse} "成功"
+// @Header       200      {string} ETag "流转后的订单版本"
+// @Failure      404      {object} response.Response "订单不存在"
+// @Failure      409      {object} response.Response "当前状态不允许取消"
+// @Failure      412      {object} response.Response "订单已被修改"
+// @Router       /orders/{id}/cancel [post]
+func handleCancelOrder(svc *service.OrderService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c)
+		if !ok {
+			return
+		}
+		version, ok := ifMatchVersion(c)
+		if !ok {
+			return
+		}
+		// 请求体可选：没有取消原因时允许空请求体
+		var req dto.CancelOrderRequest
+		if c.Request.ContentLength != 0 {
+			if err := bindBody(c, &req, &v1.CancelOrderRequest{}); err != nil {
+				response.ErrorJSON(c, apperrors.FromValidationError(err))
+				return
+			}
+		}
+
+		resp, err := svc.CancelOrder(c.Request.Context(), req.ToProto(id, version))
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		setETag(c, resp.GetOrder().GetVersion())
+		response.SuccessJSON(c, resp)
+	}
+}
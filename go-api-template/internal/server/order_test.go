@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "go-api-template/api/order/v1"
+	"go-api-template/internal/biz"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/data"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/reason"
+	"go-api-template/internal/pkg/tenant"
+	"go-api-template/internal/service"
+)
+
+// testOrderUser 订单测试中登录的用户
+var testOrderUser = &auth.Claims{UserID: 7, Username: "alice", Tenant: "acme"}
+
+// orderServer 只注册了订单详情、支付与取消接口的测试服务器
+type orderServer struct {
+	url string
+	svc *service.OrderService
+}
+
+func newOrderServer(t *testing.T) *orderServer {
+	t.Helper()
+	cfg := &conf.Config{JWT: conf.JWTConfig{Secret: "test-secret"}}
+	pages, err := pagination.NewCodec(cfg)
+	if err != nil {
+		t.Fatalf("NewCodec: %v", err)
+	}
+	s := &orderServer{svc: service.NewOrderService(biz.NewOrderUsecase(data.NewOrderRepo(data.NewMemoryData(cfg))), pages)}
+
+	engine := gin.New()
+	// 代替认证中间件：所有请求都以 testOrderUser 的身份访问
+	engine.Use(withTestTenant, func(c *gin.Context) {
+		c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), testOrderUser))
+	})
+	engine.GET("/orders/:id", handleGetOrder(s.svc))
+	engine.POST("/orders/:id/pay", handleOrderTransition(func(ctx context.Context, id, version int64) (orderResult, error) {
+		return s.svc.PayOrder(ctx, &v1.PayOrderRequest{Id: id, Version: version})
+	}))
+	engine.POST("/orders/:id/cancel", handleCancelOrder(s.svc))
+	srv := httptest.NewServer(engine)
+	t.Cleanup(srv.Close)
+	s.url = srv.URL + "/orders/"
+	return s
+}
+
+// ctx 返回 testOrderUser 的请求 context
+func (s *orderServer) ctx() context.Context {
+	return auth.NewContext(tenant.NewContext(context.Background(), testOrderUser.Tenant), testOrderUser)
+}
+
+// create 创建一个待支付订单，返回其 URL 与 ID
+func (s *orderServer) create(t *testing.T) (string, int64) {
+	t.Helper()
+	resp, err := s.svc.CreateOrder(s.ctx(), &v1.CreateOrderRequest{Product: "book", Quantity: 1, Amount: 1000})
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	return s.url + strconv.FormatInt(resp.GetOrder().GetId(), 10), resp.GetOrder().GetId()
+}
+
+// status 返回订单当前的状态与版本
+func (s *orderServer) status(t *testing.T, id int64) (v1.OrderStatus, int64) {
+	t.Helper()
+	resp, err := s.svc.GetOrder(s.ctx(), &v1.GetOrderRequest{Id: id})
+	if err != nil {
+		t.Fatalf("GetOrder: %v", err)
+	}
+	return resp.GetOrder().GetStatus(), resp.GetOrder().GetVersion()
+}
+
+func TestGetOrderConditional(t *testing.T) {
+	s := newOrderServer(t)
+	url, _ := s.create(t)
+
+	tests := []struct {
+		name       string
+		headers    []string
+		wantStatus int
+	}{
+		{name: "plain GET", wantStatus: http.StatusOK},
+		{name: "unchanged", headers: []string{"If-None-Match", `"1"`}, wantStatus: http.StatusNotModified},
+		{name: "changed", headers: []string{"If-None-Match", `"2"`}, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := doConditional(t, http.MethodGet, url, "", tt.headers...)
+			if resp.status != tt.wantStatus || resp.etag != `"1"` {
+				t.Errorf("response = %d ETag %q, want %d ETag \"1\"", resp.status, resp.etag, tt.wantStatus)
+			}
+		})
+	}
+
+	// 订单流转后版本变化，旧的 ETag 不再命中
+	if resp := doConditional(t, http.MethodPost, url+"/pay", ""); resp.status != http.StatusOK {
+		t.Fatalf("pay = %d %s", resp.status, resp.body)
+	}
+	if resp := doConditional(t, http.MethodGet, url, "", "If-None-Match", `"1"`); resp.status != http.StatusOK || resp.etag != `"2"` {
+		t.Errorf("GET after pay = %d ETag %q, want 200 ETag \"2\"", resp.status, resp.etag)
+	}
+}
+
+func TestOrderTransitionIfMatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     string
+		body       string
+		ifMatch    string
+		wantStatus int
+		wantReason reason.Reason
+	}{
+		{name: "pay without If-Match", action: "pay", wantStatus: http.StatusOK},
+		{name: "pay current version", action: "pay", ifMatch: `"1"`, wantStatus: http.StatusOK},
+		{name: "pay any version", action: "pay", ifMatch: "*", wantStatus: http.StatusOK},
+		{name: "pay stale version", action: "pay", ifMatch: `"2"`,
+			wantStatus: http.StatusPreconditionFailed, wantReason: reason.PreconditionFailed},
+		{name: "pay weak tag", action: "pay", ifMatch: `W/"1"`,
+			wantStatus: http.StatusPreconditionFailed, wantReason: reason.PreconditionFailed},
+		{name: "pay several tags", action: "pay", ifMatch: `"1", "2"`,
+			wantStatus: http.StatusBadRequest, wantReason: reason.InvalidParams},
+		{name: "cancel current version", action: "cancel", body: `{"reason":"changed my mind"}`, ifMatch: `"1"`,
+			wantStatus: http.StatusOK},
+		{name: "cancel stale version", action: "cancel", body: `{"reason":"changed my mind"}`, ifMatch: `"3"`,
+			wantStatus: http.StatusPreconditionFailed, wantReason: reason.PreconditionFailed},
+	}
+	s := newOrderServer(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, id := s.create(t)
+			var headers []string
+			if tt.ifMatch != "" {
+				headers = []string{"If-Match", tt.ifMatch}
+			}
+			resp := doConditional(t, http.MethodPost, url+"/"+tt.action, tt.body, headers...)
+			if resp.status != tt.wantStatus || (tt.wantReason != "" && resp.code != tt.wantReason) {
+				t.Fatalf("response = %d %s, want %d %s", resp.status, resp.body, tt.wantStatus, tt.wantReason)
+			}
+
+			status, version := s.status(t, id)
+			if tt.wantStatus == http.StatusOK {
+				// 成功的流转返回新版本的 ETag
+				if version != 2 || resp.etag != `"2"` {
+					t.Errorf("version = %d, ETag %q; want 2 and \"2\"", version, resp.etag)
+				}
+				return
+			}
+			// 被拒绝的请求不修改订单，也不返回 ETag
+			if status != v1.OrderStatus_ORDER_STATUS_PENDING || version != 1 || resp.etag != "" {
+				t.Errorf("order = %s v%d, ETag %q; want it untouched", status, version, resp.etag)
+			}
+		})
+	}
+}
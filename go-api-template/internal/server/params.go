@@ -0,0 +1,27 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/server/response"
+)
+
+// pathID 解析 URL 路径中的 :id 参数
+// 解析失败时已写出 400 响应，调用方直接 return 即可
+func pathID(c *gin.Context) (int64, bool) {
+	return pathParamID(c, "id")
+}
+
+// pathParamID 解析 URL 路径中名为 name 的 ID 参数，用于嵌套资源（如 :deliveryId）
+// 解析失败时已写出 400 响应，调用方直接 return 即可
+func pathParamID(c *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id <= 0 {
+		response.ErrorJSON(c, apperrors.InvalidParams(name+" 必须是正整数"))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,293 @@
+package response
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"go-api-template/internal/pkg/reason"
+)
+
+// 可协商的响应体格式，第一个为客户端没有 Accept 或不接受其中任何一种时使用的格式
+var offeredFormats = []string{binding.MIMEJSON, binding.MIMEPROTOBUF, binding.MIMEMSGPACK2, binding.MIMEMSGPACK}
+
+// Options 响应编码选项
+type Options struct {
+	// ProtoJSON proto 消息编码为 JSON 时的选项；MessagePack 沿用其中的字段命名、枚举写法与是否输出零值
+	ProtoJSON protojson.MarshalOptions
+}
+
+// options 当前的编码选项，由 Configure 在启动时设置
+var options atomic.Pointer[Options]
+
+func init() {
+	options.Store(&Options{ProtoJSON: protojson.MarshalOptions{UseProtoNames: true}})
+}
+
+// Configure 设置响应编码选项，应在开始处理请求前调用
+func Configure(opts Options) {
+	options.Store(&opts)
+}
+
+// MarshalProtoJSON 按当前选项把 proto 消息编码为 JSON，供 SSE、WebSocket 等不经过统一响应的输出使用
+func MarshalProtoJSON(m proto.Message) ([]byte, error) {
+	return options.Load().ProtoJSON.Marshal(m)
+}
+
+// write 按 Accept 协商格式后输出响应
+//   - JSON：统一响应结构，data 中的 proto 消息按 protojson 编码
+//   - protobuf：成功时响应体就是 data 本身（必须是 proto 消息，否则退回 JSON），
+//     失败时为 google.rpc.Status，details 中包含 ErrorInfo（reason 为业务错误码）与 BadRequest（字段错误）
+//   - MessagePack：成功时为 data 本身，失败时为与 JSON 相同的统一响应结构
+func write(c *gin.Context, r *Response) {
+	c.Writer.Header().Add("Vary", "Accept")
+	opts := options.Load()
+	switch c.NegotiateFormat(offeredFormats...) {
+	case binding.MIMEPROTOBUF:
+		if writeProtobuf(c, r) {
+			return
+		}
+	case binding.MIMEMSGPACK2, binding.MIMEMSGPACK:
+		writeMsgPack(c, r, opts)
+		return
+	}
+	writeJSON(c, r, opts)
+}
+
+// writeJSON 输出 JSON 格式的统一响应
+// proto 生成的结构体带有 state、sizeCache 等内部字段，json 标签也不体现 proto 的 JSON 约定
+// （int64 写成字符串、枚举写成名称、oneof 展开为成员字段），因此 data 中的 proto 消息先用 protojson 编码，
+// 再以 json.RawMessage 嵌入统一响应结构
+func writeJSON(c *gin.Context, r *Response, opts *Options) {
+	data, err := protoJSONValue(r.Data, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, encodeFailed(err))
+		return
+	}
+	out := *r
+	out.Data = data
+	c.JSON(out.HTTPCode, &out)
+}
+
+// protoJSONValue 把 v 中的 proto 消息替换为 protojson 编码结果
+func protoJSONValue(v any, opts *Options) (any, error) {
+	return protoValue(v, func(m proto.Message) (any, error) {
+		data, err := opts.ProtoJSON.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+		return json.RawMessage(data), nil
+	})
+}
+
+// protoMsgPackValue 把 v 中的 proto 消息替换为 protoToMap 的转换结果
+func protoMsgPackValue(v any, opts *Options) any {
+	out, _ := protoValue(v, func(m proto.Message) (any, error) {
+		return protoToMap(m.ProtoReflect(), opts), nil
+	})
+	return out
+}
+
+// protoValue 把 v 中的 proto 消息替换为 encode 的结果
+// 除 data 本身外，也处理 response.Body 等 map 与切片中的 proto 消息，其余值原样返回
+func protoValue(v any, encode func(proto.Message) (any, error)) (any, error) {
+	switch v := v.(type) {
+	case proto.Message:
+		return encode(v)
+	case Body:
+		return protoMap(v, encode)
+	case map[string]any:
+		return protoMap(v, encode)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			encoded, err := protoValue(item, encode)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = encoded
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// protoMap protoValue 的 map 版本，返回新的 map，不修改调用方的数据
+func protoMap(m map[string]any, encode func(proto.Message) (any, error)) (map[string]any, error) {
+	out := make(map[string]any, len(m))
+	for k, item := range m {
+		encoded, err := protoValue(item, encode)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = encoded
+	}
+	return out, nil
+}
+
+// writeProtobuf 输出 protobuf 格式的响应，成功响应的 data 不是 proto 消息时返回 false
+func writeProtobuf(c *gin.Context, r *Response) bool {
+	var msg proto.Message
+	if r.Code == reason.Success {
+		m, ok := r.Data.(proto.Message)
+		if !ok {
+			return false
+		}
+		msg = m
+	} else {
+		msg = errorStatus(r)
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, encodeFailed(err))
+		return true
+	}
+	c.Data(r.HTTPCode, binding.MIMEPROTOBUF, data)
+	return true
+}
+
+// writeMsgPack 输出 MessagePack 格式的响应，data 中的 proto 消息（包括 map 与切片中的）先转换为 map
+func writeMsgPack(c *gin.Context, r *Response, opts *Options) {
+	var body any = r
+	if r.Code == reason.Success {
+		body = protoMsgPackValue(r.Data, opts)
+	}
+	c.Render(r.HTTPCode, render.MsgPack{Data: body})
+}
+
+// encodeFailed 响应体编码失败时的统一响应，原因只进入日志
+func encodeFailed(err error) *Response {
+	slog.Error("Failed to encode response", "error", err)
+	return &Response{
+		Code:     reason.InternalError,
+		Message:  "响应编码失败",
+		HTTPCode: http.StatusInternalServerError,
+	}
+}
+
+// reasonGRPCCodes 业务错误码到 google.rpc.Status.code 的映射，与 gRPC 接口返回的状态码保持一致
+var reasonGRPCCodes = map[reason.Reason]codes.Code{
+	reason.InvalidParams:        codes.InvalidArgument,
+	reason.Unauthorized:         codes.Unauthenticated,
+	reason.Forbidden:            codes.PermissionDenied,
+	reason.NotFound:             codes.NotFound,
+	reason.Conflict:             codes.FailedPrecondition,
+	reason.PreconditionFailed:   codes.Aborted,
+	reason.PayloadTooLarge:      codes.ResourceExhausted,
+	reason.UnsupportedMediaType: codes.InvalidArgument,
+	reason.UnprocessableEntity:  codes.InvalidArgument,
+	reason.TooManyRequests:      codes.ResourceExhausted,
+	reason.InternalError:        codes.Internal,
+	reason.ServiceUnavailable:   codes.Unavailable,
+	reason.Timeout:              codes.DeadlineExceeded,
+}
+
+// errorStatus 把错误响应转换为 google.rpc.Status
+func errorStatus(r *Response) *spb.Status {
+	code, ok := reasonGRPCCodes[r.Code]
+	if !ok {
+		code = codes.Unknown
+	}
+	st := &spb.Status{Code: int32(code), Message: r.Message}
+	if info, err := anypb.New(&errdetails.ErrorInfo{Reason: string(r.Code)}); err == nil {
+		st.Details = append(st.Details, info)
+	}
+	if len(r.Details) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(r.Details))
+		for _, d := range r.Details {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: d.Field, Description: d.Message})
+		}
+		if badRequest, err := anypb.New(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+			st.Details = append(st.Details, badRequest)
+		}
+	}
+	return st
+}
+
+// protoToMap 把 proto 消息转换为 map，供 MessagePack 编码
+// 字段名、枚举写法与是否输出零值与 protojson 相同，但 int64 保持为整数（MessagePack 没有 JavaScript 的精度问题）；
+// Timestamp 等 well-known 类型按 protojson 的写法转换
+func protoToMap(m protoreflect.Message, opts *Options) any {
+	if m.Descriptor().FullName().Parent() == "google.protobuf" {
+		data, err := opts.ProtoJSON.Marshal(m.Interface())
+		if err != nil {
+			return nil
+		}
+		var v any
+		_ = json.Unmarshal(data, &v)
+		return v
+	}
+	out := make(map[string]any)
+	fields := m.Descriptor().Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		if !m.Has(fd) {
+			// 与 protojson 相同：oneof 成员与 optional 字段未设置时始终省略
+			if !opts.ProtoJSON.EmitUnpopulated || fd.ContainingOneof() != nil {
+				continue
+			}
+		}
+		name := fd.JSONName()
+		if opts.ProtoJSON.UseProtoNames {
+			name = fd.TextName()
+		}
+		out[name] = protoFieldValue(fd, m.Get(fd), opts)
+	}
+	return out
+}
+
+// protoFieldValue 转换一个字段的值
+func protoFieldValue(fd protoreflect.FieldDescriptor, v protoreflect.Value, opts *Options) any {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		items := make([]any, list.Len())
+		for i := range items {
+			items[i] = protoScalar(fd, list.Get(i), opts)
+		}
+		return items
+	case fd.IsMap():
+		entries := make(map[string]any, v.Map().Len())
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			entries[k.String()] = protoScalar(fd.MapValue(), v, opts)
+			return true
+		})
+		return entries
+	default:
+		return protoScalar(fd, v, opts)
+	}
+}
+
+// protoScalar 转换单个值
+func protoScalar(fd protoreflect.FieldDescriptor, v protoreflect.Value, opts *Options) any {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if !v.Message().IsValid() {
+			return nil
+		}
+		return protoToMap(v.Message(), opts)
+	case protoreflect.EnumKind:
+		if opts.ProtoJSON.UseEnumNumbers {
+			return int32(v.Enum())
+		}
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,245 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	v1 "go-api-template/api/helloworld/v1"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// respond 以指定的 Accept 输出 r，返回响应记录
+func respond(r *Response, accept string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if accept != "" {
+		c.Request.Header.Set("Accept", accept)
+	}
+	JSON(c, r)
+	return w
+}
+
+type presenceBody struct {
+	Action   string `json:"action"`
+	Username string `json:"username"`
+	Online   int32  `json:"online"`
+}
+
+type sessionBody struct {
+	Presence presenceBody   `json:"presence"`
+	History  []presenceBody `json:"history"`
+	Total    int            `json:"total"`
+}
+
+func TestWriteNestedProto(t *testing.T) {
+	joined := &v1.Presence{Action: v1.PresenceAction_PRESENCE_ACTION_JOINED, Username: "alice", Online: 2}
+	left := &v1.Presence{Action: v1.PresenceAction_PRESENCE_ACTION_LEFT, Username: "bob", Online: 1}
+	data := Body{
+		"presence": joined,
+		"history":  []any{joined, map[string]any{"action": "PRESENCE_ACTION_LEFT", "username": "bob", "online": 1}, left},
+		"total":    3,
+	}
+
+	tests := []struct {
+		name   string
+		accept string
+		decode func(t *testing.T, body []byte) sessionBody
+	}{
+		{
+			name:   "json",
+			accept: binding.MIMEJSON,
+			decode: func(t *testing.T, body []byte) sessionBody {
+				var out struct {
+					Data sessionBody `json:"data"`
+				}
+				if err := json.Unmarshal(body, &out); err != nil {
+					t.Fatalf("decode json: %v", err)
+				}
+				return out.Data
+			},
+		},
+		{
+			name:   "msgpack",
+			accept: binding.MIMEMSGPACK2,
+			decode: func(t *testing.T, body []byte) sessionBody {
+				var out sessionBody
+				if err := binding.MsgPack.BindBody(body, &out); err != nil {
+					t.Fatalf("decode msgpack: %v", err)
+				}
+				return out
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := respond(Success(data), tt.accept)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", w.Code)
+			}
+			got := tt.decode(t, w.Body.Bytes())
+
+			// 嵌套在 Body 与切片中的 proto 消息也按 proto 的约定编码：枚举写成名称
+			want := presenceBody{Action: "PRESENCE_ACTION_JOINED", Username: "alice", Online: 2}
+			if got.Presence != want {
+				t.Errorf("presence = %+v, want %+v", got.Presence, want)
+			}
+			if len(got.History) != 3 || got.History[0] != want || got.History[1] != got.History[2] {
+				t.Errorf("history = %+v, want joined then two identical left entries", got.History)
+			}
+			if got.Total != 3 {
+				t.Errorf("total = %d, want 3", got.Total)
+			}
+		})
+	}
+
+	// 编码不修改调用方的数据
+	if _, ok := data["presence"].(*v1.Presence); !ok {
+		t.Errorf("data[presence] = %T, want *v1.Presence", data["presence"])
+	}
+}
+
+// normalize 把解码结果统一为可比较的形式：MessagePack 的字符串解码为 []byte、map 的键为 any、
+// 整数按编码长度解码为不同类型，这里分别转换为 string、map[string]any 与 int64
+func normalize(v any) any {
+	switch v := v.(type) {
+	case []byte:
+		return string(v)
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[fmt.Sprint(normalize(k))] = normalize(item)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = normalize(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalize(item)
+		}
+		return out
+	case uint64:
+		return int64(v)
+	case int:
+		return int64(v)
+	default:
+		return v
+	}
+}
+
+func TestProtoJSONOptions(t *testing.T) {
+	saved := *options.Load()
+	t.Cleanup(func() { Configure(saved) })
+
+	data := Body{
+		"greeting": &v1.Greeting{Id: 9007199254740993, Name: "alice", CreatedAt: "2026-10-18T08:00:00Z"},
+		"presence": &v1.Presence{Action: v1.PresenceAction_PRESENCE_ACTION_JOINED, Username: "alice"},
+	}
+
+	tests := []struct {
+		name string
+		opts protojson.MarshalOptions
+		// wantJSON 为 JSON 响应中的 data
+		wantJSON string
+		// wantMsgPack 为 MessagePack 响应体，int64 保持为整数
+		wantMsgPack map[string]any
+	}{
+		{
+			name: "proto names",
+			opts: protojson.MarshalOptions{UseProtoNames: true},
+			wantJSON: `{"greeting":{"id":"9007199254740993","name":"alice","created_at":"2026-10-18T08:00:00Z"},
+				"presence":{"action":"PRESENCE_ACTION_JOINED","username":"alice"}}`,
+			wantMsgPack: map[string]any{
+				"greeting": map[string]any{"id": int64(9007199254740993), "name": "alice", "created_at": "2026-10-18T08:00:00Z"},
+				"presence": map[string]any{"action": "PRESENCE_ACTION_JOINED", "username": "alice"},
+			},
+		},
+		{
+			name: "camel names",
+			opts: protojson.MarshalOptions{},
+			wantJSON: `{"greeting":{"id":"9007199254740993","name":"alice","createdAt":"2026-10-18T08:00:00Z"},
+				"presence":{"action":"PRESENCE_ACTION_JOINED","username":"alice"}}`,
+			wantMsgPack: map[string]any{
+				"greeting": map[string]any{"id": int64(9007199254740993), "name": "alice", "createdAt": "2026-10-18T08:00:00Z"},
+				"presence": map[string]any{"action": "PRESENCE_ACTION_JOINED", "username": "alice"},
+			},
+		},
+		{
+			name: "emit unpopulated",
+			opts: protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+			wantJSON: `{"greeting":{"id":"9007199254740993","name":"alice","message":"","created_at":"2026-10-18T08:00:00Z","version":"0"},
+				"presence":{"action":"PRESENCE_ACTION_JOINED","username":"alice","online":0}}`,
+			wantMsgPack: map[string]any{
+				"greeting": map[string]any{"id": int64(9007199254740993), "name": "alice", "message": "",
+					"created_at": "2026-10-18T08:00:00Z", "version": int64(0)},
+				"presence": map[string]any{"action": "PRESENCE_ACTION_JOINED", "username": "alice", "online": int64(0)},
+			},
+		},
+		{
+			name: "enum numbers",
+			opts: protojson.MarshalOptions{UseProtoNames: true, UseEnumNumbers: true},
+			wantJSON: `{"greeting":{"id":"9007199254740993","name":"alice","created_at":"2026-10-18T08:00:00Z"},
+				"presence":{"action":1,"username":"alice"}}`,
+			wantMsgPack: map[string]any{
+				"greeting": map[string]any{"id": int64(9007199254740993), "name": "alice", "created_at": "2026-10-18T08:00:00Z"},
+				"presence": map[string]any{"action": int64(1), "username": "alice"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Configure(Options{ProtoJSON: tt.opts})
+
+			var gotJSON struct {
+				Data map[string]any `json:"data"`
+			}
+			if err := json.Unmarshal(respond(Success(data), binding.MIMEJSON).Body.Bytes(), &gotJSON); err != nil {
+				t.Fatalf("decode json: %v", err)
+			}
+			var wantJSON map[string]any
+			if err := json.Unmarshal([]byte(tt.wantJSON), &wantJSON); err != nil {
+				t.Fatalf("decode wantJSON: %v", err)
+			}
+			if !reflect.DeepEqual(gotJSON.Data, wantJSON) {
+				t.Errorf("json data = %v, want %v", gotJSON.Data, wantJSON)
+			}
+
+			var gotMsgPack map[string]any
+			if err := binding.MsgPack.BindBody(respond(Success(data), binding.MIMEMSGPACK).Body.Bytes(), &gotMsgPack); err != nil {
+				t.Fatalf("decode msgpack: %v", err)
+			}
+			if got := normalize(gotMsgPack); !reflect.DeepEqual(got, tt.wantMsgPack) {
+				t.Errorf("msgpack body = %v, want %v", got, tt.wantMsgPack)
+			}
+
+			// SSE、WebSocket 使用的 MarshalProtoJSON 与统一响应的编码一致
+			raw, err := MarshalProtoJSON(data["presence"].(*v1.Presence))
+			if err != nil {
+				t.Fatalf("MarshalProtoJSON: %v", err)
+			}
+			var presence map[string]any
+			if err := json.Unmarshal(raw, &presence); err != nil {
+				t.Fatalf("decode MarshalProtoJSON output: %v", err)
+			}
+			if !reflect.DeepEqual(presence, wantJSON["presence"]) {
+				t.Errorf("MarshalProtoJSON = %s, want %v", raw, wantJSON["presence"])
+			}
+		})
+	}
+}
@@ -1,5 +1,5 @@
 // Package response 提供统一的 HTTP 响应格式
-// 确保所有 API 响应具有一致的结构，方便前端处理
+// 确保所有 API 响应具有一致的结构，方便前端处理；移动端等客户端可以通过 Accept 改用 protobuf 或 MessagePack
 package response
 
 import (
@@ -62,9 +62,10 @@ func Error(err *apperrors.AppError) *Response {
 // ==================== Gin 响应输出 ====================
 
 // JSON 输出统一响应到 gin.Context
-// 使用 Response 的 HTTPCode 作为 HTTP 状态码
+// 使用 Response 的 HTTPCode 作为 HTTP 状态码；响应体格式按 Accept 在 JSON、protobuf、MessagePack 中协商，
+// 没有 Accept 时为 JSON，见 write
 func JSON(c *gin.Context, r *Response) {
-	c.JSON(r.HTTPCode, r)
+	write(c, r)
 }
 
 // SuccessJSON 快捷方法：输出成功响应
@@ -7,25 +7,48 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 
 	"go-api-template/internal/conf"
+	"go-api-template/internal/service"
 )
 
 // ProviderSet 是 server 层的依赖提供者集合
 // server 层按协议划分（HTTP/gRPC），不按业务模块划分
 var ProviderSet = wire.NewSet(
+	wire.Struct(new(Services), "*"),
 	NewHTTPServer,
-	// NewGRPCServer, // 未来：gRPC 服务器
+	NewGRPCServer,
 )
 
+// Services 聚合所有模块的 Service，供 HTTP 与 gRPC 服务器共享
+// 新增模块只需添加字段，Wire 会按字段类型自动注入，服务器构造函数签名保持不变
+type Services struct {
+	Greeter     *service.GreeterService
+	User        *service.UserService
+	Order       *service.OrderService
+	Webhook     *service.WebhookService
+	Job         *service.JobService
+	FeatureFlag *service.FeatureFlagService
+	// gen:services - cmd/gen 在此处插入新模块的 Service 字段
+}
+
+// closeStreams 断开所有进行中的推送流与会话（SSE、WebSocket 与 gRPC 流式 RPC）
+// HTTP 与 gRPC 服务器停止时都会调用，重复调用无副作用
+func (s *Services) closeStreams() {
+	s.Greeter.CloseStreams()
+}
+
 // HTTPServer 封装 HTTP 服务器的配置和底层 http.Server
 // 使用 http.Server 而非 gin.Engine.Run()，以支持优雅关闭
 type HTTPServer struct {
-	server *http.Server
-	engine *gin.Engine
+	server  *http.Server
+	engine  *gin.Engine
+	svcs    *Services
+	sockets *wsHub
 }
 
 // Start 启动 HTTP 服务器（非阻塞）
@@ -48,8 +71,12 @@ func (s *HTTPServer) Start() <-chan error {
 // 1. 停止接受新连接
 // 2. 等待正在处理的请求完成（或直到 context 超时）
 // 3. 关闭所有空闲连接
+// SSE 与 WebSocket 连接上的请求不会自行结束，Shutdown 之前先断开推送流与会话；
+// WebSocket 连接不受 Shutdown 管理，另外等待它们发送完关闭帧
 func (s *HTTPServer) Stop(ctx context.Context) error {
-	return s.server.Shutdown(ctx)
+	s.svcs.closeStreams()
+	err := s.server.Shutdown(ctx)
+	return errors.Join(err, s.sockets.shutdown(ctx))
 }
 
 // Addr 返回服务器监听地址
@@ -57,13 +84,22 @@ func (s *HTTPServer) Addr() string {
 	return s.server.Addr
 }
 
+// Routes 返回已注册的所有 HTTP 路由
+func (s *HTTPServer) Routes() gin.RoutesInfo {
+	return s.engine.Routes()
+}
+
 // Engine 返回底层的 Gin 引擎（用于测试等场景）
 func (s *HTTPServer) Engine() *gin.Engine {
 	return s.engine
 }
 
-// setGinMode 根据环境设置 Gin 模式
+// setGinMode 根据环境设置 Gin 模式，显式设置的 GIN_MODE 环境变量优先
 func setGinMode(cfg *conf.Config) {
+	if mode := os.Getenv(gin.EnvGinMode); mode != "" {
+		gin.SetMode(mode)
+		return
+	}
 	if cfg.IsProduction() {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/server/middleware"
+	"go-api-template/internal/server/response"
+)
+
+// sseStream 把服务端流式 RPC 的输出写成 Server-Sent Events
+// 实现 grpc.ServerStreamingServer，使同一个 Service 方法同时服务 gRPC 流与 SSE
+type sseStream[T any] struct {
+	c     *gin.Context
+	event string
+	// encode 返回消息的事件 ID 与数据，数据按 JSON 编码，proto 消息与统一响应一样按 protojson 编码
+	encode func(*T) (id int64, data any)
+
+	// mu 串行化消息与心跳的写入
+	mu sync.Mutex
+}
+
+// serveSSE 以 SSE 响应当前请求：写出响应头后调用 watch，直到 watch 返回
+// 期间每隔 heartbeat 写一行注释，防止代理因连接空闲而断开；
+// watch 因客户端断开之外的原因结束时，以 error 事件写出与 JSON 接口相同结构的错误
+func serveSSE[T any](c *gin.Context, event string, heartbeat time.Duration,
+	encode func(*T) (int64, any), watch func(grpc.ServerStreamingServer[T]) error) {
+	// 推送连接长期保持，不受 server.write_timeout 与请求处理时限限制
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	middleware.DetachTimeout(c)
+	header := c.Writer.Header()
+	header.Set("Content-Type", sse.ContentType)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	stream := &sseStream[T]{c: c, event: event, encode: encode}
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	var wg sync.WaitGroup
+	wg.Go(func() { stream.heartbeat(ctx, heartbeat) })
+
+	err := watch(stream)
+	cancel()
+	wg.Wait()
+	if err == nil || c.Request.Context().Err() != nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	stream.writeError(toAppError(err))
+}
+
+// heartbeat 定期写入注释行，ctx 结束时返回
+func (s *sseStream[T]) heartbeat(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			_, err := s.c.Writer.WriteString(": ping\n\n")
+			if err == nil {
+				s.c.Writer.Flush()
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// writeError 以 error 事件写出错误
+func (s *sseStream[T]) writeError(appErr *apperrors.AppError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sse.Encode(s.c.Writer, sse.Event{Event: "error", Data: response.Error(appErr)}) == nil {
+		s.c.Writer.Flush()
+	}
+}
+
+// Send 实现 grpc.ServerStreamingServer，写出一条消息并立即刷新
+func (s *sseStream[T]) Send(msg *T) error {
+	id, data := s.encode(msg)
+	if m, ok := data.(proto.Message); ok {
+		raw, err := response.MarshalProtoJSON(m)
+		if err != nil {
+			return err
+		}
+		data = json.RawMessage(raw)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := sse.Encode(s.c.Writer, sse.Event{Id: strconv.FormatInt(id, 10), Event: s.event, Data: data})
+	if err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+// Context 返回请求的 context，客户端断开时结束
+func (s *sseStream[T]) Context() context.Context {
+	return s.c.Request.Context()
+}
+
+// SetHeader SSE 的响应头在开始推送前已写出，忽略 gRPC 元数据
+func (s *sseStream[T]) SetHeader(metadata.MD) error { return nil }
+
+// SendHeader 同 SetHeader
+func (s *sseStream[T]) SendHeader(metadata.MD) error { return nil }
+
+// SetTrailer SSE 没有 trailer，忽略
+func (s *sseStream[T]) SetTrailer(metadata.MD) {}
+
+// SendMsg 只支持 Send 使用的消息类型
+func (s *sseStream[T]) SendMsg(m any) error {
+	msg, ok := m.(*T)
+	if !ok {
+		return errors.New("sse: unexpected message type")
+	}
+	return s.Send(msg)
+}
+
+// RecvMsg 服务端流式 RPC 只在开始时接收一次请求，SSE 的请求已由 handler 解析
+func (s *sseStream[T]) RecvMsg(any) error {
+	return errors.New("sse: receiving is not supported")
+}
@@ -0,0 +1,245 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+
+	v1 "go-api-template/api/helloworld/v1"
+	"go-api-template/internal/biz"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/data"
+	"go-api-template/internal/pkg/feed"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/reason"
+	"go-api-template/internal/pkg/tenant"
+	"go-api-template/internal/service"
+)
+
+// testTenantHeader 测试中指定租户的请求头，代替完整的租户解析中间件
+const testTenantHeader = "X-Tenant-ID"
+
+// fixedTemplate 所有租户使用同一个问候模板
+type fixedTemplate struct{}
+
+func (fixedTemplate) GreetingTemplate(string) string { return "Hello {name}" }
+
+// newTestGreeterService 创建基于内存存储的 GreeterService，测试结束时断开所有流
+func newTestGreeterService(t *testing.T, cfg *conf.Config) *service.GreeterService {
+	t.Helper()
+	d := data.NewMemoryData(cfg)
+	uc := biz.NewGreeterUsecase(data.NewGreeterRepo(d), data.NewTransaction(d), fixedTemplate{}, data.NewOutbox(d))
+	pages, err := pagination.NewCodec(&conf.Config{JWT: conf.JWTConfig{Secret: "test-secret"}})
+	if err != nil {
+		t.Fatalf("NewCodec: %v", err)
+	}
+	svc := service.NewGreeterService(uc, cfg, pages)
+	t.Cleanup(svc.CloseStreams)
+	return svc
+}
+
+// withTestTenant 把 testTenantHeader 中的租户放入请求 context
+func withTestTenant(c *gin.Context) {
+	if id := c.GetHeader(testTenantHeader); id != "" {
+		c.Request = c.Request.WithContext(tenant.NewContext(c.Request.Context(), id))
+	}
+}
+
+// sayHello 在租户中保存一条问候，返回其 ID
+func sayHello(t *testing.T, svc *service.GreeterService, tenantID, name string) int64 {
+	t.Helper()
+	resp, err := svc.SayHello(tenant.NewContext(context.Background(), tenantID), &v1.SayHelloRequest{Name: name})
+	if err != nil {
+		t.Fatalf("SayHello(%s, %s): %v", tenantID, name, err)
+	}
+	return resp.GetGreeting().GetId()
+}
+
+// sseEvent 一条 Server-Sent Event
+type sseEvent struct {
+	id, event, data string
+}
+
+// openSSE 以 tenantID 订阅 url，返回按顺序收到的事件；连接结束时 channel 被关闭
+func openSSE(t *testing.T, url, tenantID string, lastEventID int64) <-chan sseEvent {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req.Header.Set(testTenantHeader, tenantID)
+	req.Header.Set("Last-Event-ID", strconv.FormatInt(lastEventID, 10))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("response = %d %s, want an event stream", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	events := make(chan sseEvent, 16)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+		var ev sseEvent
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				if ev.event != "" {
+					events <- ev
+				}
+				ev = sseEvent{}
+			case strings.HasPrefix(line, "id:"):
+				ev.id = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+			case strings.HasPrefix(line, "event:"):
+				ev.event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			case strings.HasPrefix(line, "data:"):
+				ev.data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			}
+		}
+	}()
+	return events
+}
+
+// nextEvent 等待下一条事件，连接已结束时返回 false
+func nextEvent(t *testing.T, events <-chan sseEvent) (sseEvent, bool) {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		return ev, ok
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+		return sseEvent{}, false
+	}
+}
+
+// greetingName 解析 greeting 事件中的问候名称
+func greetingName(t *testing.T, ev sseEvent) string {
+	t.Helper()
+	var g struct {
+		Name string `json:"name"`
+	}
+	if ev.event != "greeting" {
+		t.Fatalf("event = %+v, want a greeting", ev)
+	}
+	if err := json.Unmarshal([]byte(ev.data), &g); err != nil {
+		t.Fatalf("decode greeting %s: %v", ev.data, err)
+	}
+	return g.Name
+}
+
+// newSSEServer 启动只注册了问候推送接口的测试服务器
+func newSSEServer(t *testing.T, svc *service.GreeterService, cfg conf.GreeterStreamConfig) string {
+	t.Helper()
+	engine := gin.New()
+	engine.Use(withTestTenant)
+	engine.GET("/greeter/stream", handleWatchGreetings(svc, cfg))
+	srv := httptest.NewServer(engine)
+	t.Cleanup(srv.Close)
+	return srv.URL + "/greeter/stream"
+}
+
+func TestWatchGreetingsSSE(t *testing.T) {
+	cfg := &conf.Config{Greeter: conf.GreeterConfig{Stream: conf.GreeterStreamConfig{PollInterval: 20 * time.Millisecond}}}
+	svc := newTestGreeterService(t, cfg)
+	url := newSSEServer(t, svc, cfg.Greeter.Stream)
+
+	// 从已有问候之后开始订阅：订阅建立之前保存的新问候会补发，之后的由推送源推送，不会错过
+	acmeStart, globexStart := sayHello(t, svc, "acme", "seed"), sayHello(t, svc, "globex", "seed")
+	first := openSSE(t, url, "acme", acmeStart)
+	second := openSSE(t, url, "acme", acmeStart)
+	bobOnly := openSSE(t, url+"?name=bob", "acme", acmeStart)
+	globex := openSSE(t, url, "globex", globexStart)
+
+	for _, name := range []string{"alice", "bob", "carol"} {
+		sayHello(t, svc, "acme", name)
+	}
+
+	// 同一租户的订阅者都按顺序收到全部问候
+	for i, events := range []<-chan sseEvent{first, second} {
+		for _, want := range []string{"alice", "bob", "carol"} {
+			ev, ok := nextEvent(t, events)
+			if !ok {
+				t.Fatalf("subscriber %d stream ended early", i)
+			}
+			if got := greetingName(t, ev); got != want {
+				t.Fatalf("subscriber %d got %s, want %s", i, got, want)
+			}
+		}
+	}
+	if ev, _ := nextEvent(t, bobOnly); greetingName(t, ev) != "bob" {
+		t.Errorf("name filter delivered %+v, want bob only", ev)
+	}
+
+	// 其他租户的订阅者收不到 acme 的问候：它收到的第一条是自己租户的问候
+	sayHello(t, svc, "globex", "dave")
+	if ev, _ := nextEvent(t, globex); greetingName(t, ev) != "dave" {
+		t.Errorf("globex got %+v, want its own greeting dave", ev)
+	}
+
+	// 服务停止时推送 error 事件并结束连接
+	svc.CloseStreams()
+	for i, events := range []<-chan sseEvent{first, second, bobOnly, globex} {
+		ev, ok := nextEvent(t, events)
+		if !ok || ev.event != "error" || !strings.Contains(ev.data, string(reason.ServiceUnavailable)) {
+			t.Errorf("subscriber %d got %+v after shutdown, want a SERVICE_UNAVAILABLE error event", i, ev)
+		}
+		if _, ok := nextEvent(t, events); ok {
+			t.Errorf("subscriber %d stream still open after shutdown", i)
+		}
+	}
+}
+
+func TestServeSSEErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantReason reason.Reason
+	}{
+		{"slow consumer", feed.ErrSlowConsumer, reason.TooManyRequests},
+		{"shutdown", feed.ErrClosed, reason.ServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.GET("/stream", func(c *gin.Context) {
+				serveSSE(c, "greeting", time.Minute,
+					func(msg *v1.WatchGreetingsResponse) (int64, any) { return msg.GetGreeting().GetId(), msg.GetGreeting() },
+					func(stream grpc.ServerStreamingServer[v1.WatchGreetingsResponse]) error {
+						if err := stream.Send(&v1.WatchGreetingsResponse{Greeting: &v1.Greeting{Id: 7, Name: "alice"}}); err != nil {
+							return err
+						}
+						return tt.err
+					})
+			})
+			srv := httptest.NewServer(engine)
+			defer srv.Close()
+
+			events := openSSE(t, srv.URL+"/stream", "", 0)
+			ev, _ := nextEvent(t, events)
+			if ev.id != "7" || greetingName(t, ev) != "alice" {
+				t.Errorf("first event = %+v, want greeting 7", ev)
+			}
+			ev, ok := nextEvent(t, events)
+			var body struct {
+				Code reason.Reason `json:"code"`
+			}
+			if !ok || ev.event != "error" || json.Unmarshal([]byte(ev.data), &body) != nil || body.Code != tt.wantReason {
+				t.Errorf("error event = %+v, want code %s", ev, tt.wantReason)
+			}
+			if _, ok := nextEvent(t, events); ok {
+				t.Error("stream still open after the error event")
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+
+	v1 "go-api-template/api/user/v1"
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/server/dto"
+	"go-api-template/internal/server/middleware"
+	"go-api-template/internal/server/response"
+	"go-api-template/internal/service"
+)
+
+// registerUserRoutes 注册 User 服务的 HTTP 路由
+// 注册和登录公开访问，/users/me 下的路由需要访问令牌
+func registerUserRoutes(group *gin.RouterGroup, svc *service.UserService, tokens *auth.TokenManager) {
+	users := group.Group("/users")
+	users.POST("/register", handleRegister(svc))
+	users.POST("/login", handleLogin(svc))
+
+	me := users.Group("/me", middleware.RequireAuth(tokens))
+	me.GET("", handleGetProfile(svc))
+	me.PUT("", handleUpdateProfile(svc))
+	me.PUT("/password", handleChangePassword(svc))
+}
+
+// registerUserGRPC 注册 User 服务的 gRPC 实现
+func registerUserGRPC(srv *grpc.Server, svc *service.UserService) {
+	v1.RegisterUserServiceServer(srv, svc)
+}
+
+// handleRegister 注册新用户
+//
+// @Summary      注册
+// @Description  创建新用户，登录名全局唯一
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Param        request body     dto.RegisterRequest true "注册参数"
+// @Success      200     {object} response.Response{data=v1.RegisterResponse} "成功"
+// @Failure      400     {object} response.Response "请求参数错误"
+// @Failure      409     {object} response.Response "登录名已被占用"
+// @Failure      500     {object} response.Response "服务内部错误"
+// @Router       /users/register [post]
+func handleRegister(svc *service.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.RegisterRequest
+		if err := bindBody(c, &req, &v1.RegisterRequest{}); err != nil {
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			return
+		}
+
+		resp, err := svc.Register(c.Request.Context(), req.ToProto())
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleLogin 使用用户名和密码登录
+//
+// @Summary      登录
+// @Description  校验用户名和密码，返回访问令牌；后续请求在 Authorization 头中携带 "Bearer {token}"
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Param        request body     dto.LoginRequest true "登录参数"
+// @Success      200     {object} response.Response{data=v1.LoginResponse} "成功"
+// @Failure      400     {object} response.Response "请求参数错误"
+// @Failure      401     {object} response.Response "用户名或密码错误"
+// @Router       /users/login [post]
+func handleLogin(svc *service.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.LoginRequest
+		if err := bindBody(c, &req, &v1.LoginRequest{}); err != nil {
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			return
+		}
+
+		resp, err := svc.Login(c.Request.Context(), req.ToProto())
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleGetProfile 获取当前用户资料
+//
+// @Summary      获取个人资料
+// @Description  响应头 ETag 为资料版本；携带 If-None-Match 且版本未变化时返回 304
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        If-None-Match header   string false "上次获取的 ETag"
+// @Success      200 {object} response.Response{data=v1.GetProfileResponse} "成功"
+// @Header       200 {string} ETag "资料版本"
+// @Success      304 "资料未变化"
+// @Failure      401 {object} response.Response "未登录或令牌无效"
+// @Router       /users/me [get]
+func handleGetProfile(svc *service.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := svc.GetProfile(c.Request.Context(), &v1.GetProfileRequest{})
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		setETag(c, resp.GetUser().GetVersion())
+		if notModified(c, resp.GetUser().GetVersion()) {
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleUpdateProfile 修改当前用户资料
+//
+// @Summary      修改个人资料
+// @Description  携带 If-Match 时仅在资料版本未变化时修改，否则返回 412
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        If-Match header   string                   false "上次获取的 ETag"
+// @Param        request  body     dto.UpdateProfileRequest true  "资料参数"
+// @Success      200      {object} response.Response{data=v1.UpdateProfileResponse} "成功"
+// @Header       200      {string} ETag "修改后的资料版本"
+// @Failure      400      {object} response.Response "请求参数错误"
+// @Failure      401      {object} response.Response "未登录或令牌无效"
+// @Failure      412      {object} response.Response "资料已被修改"
+// @Router       /users/me [put]
+func handleUpdateProfile(svc *service.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version, ok := ifMatchVersion(c)
+		if !ok {
+			return
+		}
+		var req dto.UpdateProfileRequest
+		if err := bindBody(c, &req, &v1.UpdateProfileRequest{}); err != nil {
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			return
+		}
+
+		resp, err := svc.UpdateProfile(c.Request.Context(), req.ToProto(version))
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		setETag(c, resp.GetUser().GetVersion())
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleChangePassword 修改当前用户密码
+//
+// @Summary      修改密码
+// @Description  密码属于资料的一部分，修改后资料版本加一；If-Match 的含义同修改个人资料
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        If-Match header   string                    false "上次获取的 ETag"
+// @Param        request  body     dto.ChangePasswordRequest true  "密码参数"
+// @Success      200      {object} response.Response{data=v1.ChangePasswordResponse} "成功"
+// @Header       200      {string} ETag "修改后的资料版本"
+// @Failure      400      {object} response.Response "请求参数错误"
+// @Failure      401      {object} response.Response "未登录、令牌无效或旧密码错误"
+// @Failure      412      {object} response.Response "资料已被修改"
+// @Router       /users/me/password [put]
+func handleChangePassword(svc *service.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version, ok := ifMatchVersion(c)
+		if !ok {
+			return
+		}
+		var req dto.ChangePasswordRequest
+		if err := bindBody(c, &req, &v1.ChangePasswordRequest{}); err != nil {
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			return
+		}
+
+		resp, err := svc.ChangePassword(c.Request.Context(), req.ToProto(version))
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		setETag(c, resp.GetUser().GetVersion())
+		response.SuccessJSON(c, resp)
+	}
+}
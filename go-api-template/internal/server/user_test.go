@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/data"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/pkg/reason"
+	"go-api-template/internal/pkg/tenant"
+	"go-api-template/internal/service"
+)
+
+// newProfileServer 启动只注册了个人资料接口的测试服务器，所有请求都以新注册的用户身份访问
+func newProfileServer(t *testing.T) string {
+	t.Helper()
+	cfg := &conf.Config{JWT: conf.JWTConfig{Secret: "test-secret"}}
+	tokens, err := auth.NewTokenManager(cfg)
+	if err != nil {
+		t.Fatalf("NewTokenManager: %v", err)
+	}
+	uc, err := biz.NewUserUsecase(data.NewUserRepo(data.NewMemoryData(cfg)), tokens)
+	if err != nil {
+		t.Fatalf("NewUserUsecase: %v", err)
+	}
+	user, err := uc.Register(tenant.NewContext(context.Background(), "acme"), "alice", "alice@example.com", "", "correct-horse")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	claims := &auth.Claims{UserID: user.ID, Username: user.Username, Tenant: "acme"}
+	svc := service.NewUserService(uc)
+
+	engine := gin.New()
+	engine.Use(withTestTenant, func(c *gin.Context) {
+		c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), claims))
+	})
+	engine.GET("/users/me", handleGetProfile(svc))
+	engine.PUT("/users/me", handleUpdateProfile(svc))
+	srv := httptest.NewServer(engine)
+	t.Cleanup(srv.Close)
+	return srv.URL + "/users/me"
+}
+
+func TestProfileConditionalRequests(t *testing.T) {
+	url := newProfileServer(t)
+	update := `{"email":"alice@example.com","nickname":"Alice"}`
+
+	// 步骤依次执行，模拟两个客户端基于同一版本修改资料
+	steps := []struct {
+		name       string
+		method     string
+		body       string
+		headers    []string
+		wantStatus int
+		wantETag   string
+		wantReason reason.Reason
+	}{
+		{name: "read", method: http.MethodGet, wantStatus: http.StatusOK, wantETag: `"1"`},
+		{name: "revalidate unchanged", method: http.MethodGet, headers: []string{"If-None-Match", `"1"`},
+			wantStatus: http.StatusNotModified, wantETag: `"1"`},
+		{name: "update with a version never seen", method: http.MethodPut, body: update, headers: []string{"If-Match", `"2"`},
+			wantStatus: http.StatusPreconditionFailed, wantReason: reason.PreconditionFailed},
+		{name: "first writer", method: http.MethodPut, body: update, headers: []string{"If-Match", `"1"`},
+			wantStatus: http.StatusOK, wantETag: `"2"`},
+		{name: "second writer with the old version", method: http.MethodPut, body: update, headers: []string{"If-Match", `"1"`},
+			wantStatus: http.StatusPreconditionFailed, wantReason: reason.PreconditionFailed},
+		{name: "revalidate after update", method: http.MethodGet, headers: []string{"If-None-Match", `"1"`},
+			wantStatus: http.StatusOK, wantETag: `"2"`},
+		{name: "update without If-Match", method: http.MethodPut, body: update, wantStatus: http.StatusOK, wantETag: `"3"`},
+	}
+	for _, step := range steps {
+		resp := doConditional(t, step.method, url, step.body, step.headers...)
+		if resp.status != step.wantStatus || resp.etag != step.wantETag || (step.wantReason != "" && resp.code != step.wantReason) {
+			t.Fatalf("%s: response = %d ETag %q %s, want %d ETag %q %s",
+				step.name, resp.status, resp.etag, resp.body, step.wantStatus, step.wantETag, step.wantReason)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+
+	v1 "go-api-template/api/webhook/v1"
+	"go-api-template/internal/pkg/apperrors"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/server/dto"
+	"go-api-template/internal/server/middleware"
+	"go-api-template/internal/server/response"
+	"go-api-template/internal/service"
+)
+
+// registerWebhookRoutes 注册 Webhook 服务的 HTTP 路由，全部需要访问令牌
+// 投递记录作为订阅的子资源：/webhooks/:id/deliveries/:deliveryId
+func registerWebhookRoutes(group *gin.RouterGroup, svc *service.WebhookService, tokens *auth.TokenManager) {
+	webhooks := group.Group("/webhooks", middleware.RequireAuth(tokens))
+	webhooks.POST("", handleCreateWebhook(svc))
+	webhooks.GET("", handleListWebhooks(svc))
+	webhooks.GET("/:id", handleGetWebhook(svc))
+	webhooks.PATCH("/:id", handleUpdateWebhook(svc))
+	webhooks.DELETE("/:id", handleDeleteWebhook(svc))
+	webhooks.GET("/:id/deliveries", handleListWebhookDeliveries(svc))
+	webhooks.GET("/:id/deliveries/:deliveryId", handleGetWebhookDelivery(svc))
+	webhooks.POST("/:id/deliveries/:deliveryId/redeliver", handleRedeliverWebhook(svc))
+}
+
+// registerWebhookGRPC 注册 Webhook 服务的 gRPC 实现
+func registerWebhookGRPC(srv *grpc.Server, svc *service.WebhookService) {
+	v1.RegisterWebhookServiceServer(srv, svc)
+}
+
+// handleCreateWebhook 创建订阅
+//
+// @Summary      创建 webhook 订阅
+// @Description  响应中的 secret 是签名密钥，之后的查询不再返回，请妥善保存。
+// @Description  投递请求带 X-Webhook-Timestamp 与 X-Webhook-Signature（sha256=hex(HMAC(secret, "<timestamp>.<body>"))）
+// @Description  url 的主机必须解析到公网地址，回环、内网与链路本地地址会被拒绝；投递时不跟随重定向
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body     dto.CreateWebhookRequest true "订阅参数"
+// @Success      200     {object} response.Response{data=v1.CreateSubscriptionResponse} "成功"
+// @Failure      400     {object} response.Response "请求参数错误"
+// @Failure      401     {object} response.Response "未登录或令牌无效"
+// @Router       /webhooks [post]
+func handleCreateWebhook(svc *service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.CreateWebhookRequest
+		if err := bindBody(c, &req, &v1.CreateSubscriptionRequest{}); err != nil {
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			return
+		}
+
+		resp, err := svc.CreateSubscription(c.Request.Context(), req.ToProto())
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleListWebhooks 列出当前用户的订阅
+//
+// @Summary      webhook 订阅列表
+// @Tags         webhook
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} response.Response{data=v1.ListSubscriptionsResponse} "成功"
+// @Failure      401 {object} response.Response "未登录或令牌无效"
+// @Router       /webhooks [get]
+func handleListWebhooks(svc *service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := svc.ListSubscriptions(c.Request.Context(), &v1.ListSubscriptionsRequest{})
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleGetWebhook 获取订阅
+//
+// @Summary      webhook 订阅详情
+// @Tags         webhook
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path     int true "订阅 ID"
+// @Success      200 {object} response.Response{data=v1.GetSubscriptionResponse} "成功"
+// @Failure      401 {object} response.Response "未登录或令牌无效"
+// @Failure      404 {object} response.Response "订阅不存在"
+// @Router       /webhooks/{id} [get]
+func handleGetWebhook(svc *service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c)
+		if !ok {
+			return
+		}
+
+		resp, err := svc.GetSubscription(c.Request.Context(), &v1.GetSubscriptionRequest{Id: id})
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleUpdateWebhook 修改订阅
+//
+// @Summary      修改 webhook 订阅
+// @Description  省略的字段保持不变；设置 secret 会轮换签名密钥，新密钥只在本次响应中返回
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path     int                      true "订阅 ID"
+// @Param        request body     dto.UpdateWebhookRequest true "修改的字段"
+// @Success      200     {object} response.Response{data=v1.UpdateSubscriptionResponse} "成功"
+// @Failure      400     {object} response.Response "请求参数错误"
+// @Failure      404     {object} response.Response "订阅不存在"
+// @Router       /webhooks/{id} [patch]
+func handleUpdateWebhook(svc *service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c)
+		if !ok {
+			return
+		}
+		var req dto.UpdateWebhookRequest
+		if err := bindBody(c, &req, &v1.UpdateSubscriptionRequest{}); err != nil {
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			return
+		}
+
+		resp, err := svc.UpdateSubscription(c.Request.Context(), req.ToProto(id))
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleDeleteWebhook 删除订阅
+//
+// @Summary      删除 webhook 订阅
+// @Description  尚未完成的投递随之取消，投递日志一并删除
+// @Tags         webhook
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path     int true "订阅 ID"
+// @Success      200 {object} response.Response{data=v1.DeleteSubscriptionResponse} "成功"
+// @Failure      404 {object} response.Response "订阅不存在"
+// @Router       /webhooks/{id} [delete]
+func handleDeleteWebhook(svc *service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c)
+		if !ok {
+			return
+		}
+
+		resp, err := svc.DeleteSubscription(c.Request.Context(), &v1.DeleteSubscriptionRequest{Id: id})
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleListWebhookDeliveries 分页列出订阅的投递
+//
+// @Summary      webhook 投递日志
+// @Description  默认按 ID 倒序使用游标分页，每页 50 条：把响应中的 next_cursor 作为下一次请求的 cursor，为空表示没有下一页。
+// @Description  游标与 sort、filter 绑定，修改条件后需从第一页开始；offset 大于 0 时改用偏移分页，不能与 cursor 同时使用。
+// @Description  可排序字段：id、created_at、updated_at；可过滤字段：created_at、last_status_code（支持 = != > >= < <=）、
+// @Description  status、event_type（支持 = !=，| 分隔多个取值）。时间使用 RFC 3339 格式。
+// @Tags         webhook
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path     int    true  "订阅 ID"
+// @Param        limit  query    int    false "每页条数（1-100），默认 50"
+// @Param        offset query    int    false "偏移量（0-10000）"
+// @Param        cursor query    string false "上一页响应中的 next_cursor"
+// @Param        sort   query    string false "排序字段，逗号分隔，- 前缀表示倒序，默认 -id" example(-created_at)
+// @Param        filter query    string false "过滤条件，逗号分隔，之间为 AND 关系" example(status=failed,last_status_code>=500)
+// @Success      200    {object} response.Response{data=v1.ListDeliveriesResponse} "成功"
+// @Failure      400    {object} response.Response "分页、排序或过滤参数错误"
+// @Failure      404    {object} response.Response "订阅不存在"
+// @Router       /webhooks/{id}/deliveries [get]
+func handleListWebhookDeliveries(svc *service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c)
+		if !ok {
+			return
+		}
+		var query dto.ListWebhookDeliveriesQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			response.ErrorJSON(c, apperrors.FromValidationError(err))
+			return
+		}
+
+		resp, err := svc.ListDeliveries(c.Request.Context(), query.ToProto(id))
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleGetWebhookDelivery 获取投递及每次尝试的记录
+//
+// @Summary      webhook 投递详情
+// @Tags         webhook
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path     int true "订阅 ID"
+// @Param        deliveryId path     int true "投递 ID"
+// @Success      200        {object} response.Response{data=v1.GetDeliveryResponse} "成功"
+// @Failure      404        {object} response.Response "订阅或投递不存在"
+// @Router       /webhooks/{id}/deliveries/{deliveryId} [get]
+func handleGetWebhookDelivery(svc *service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c)
+		if !ok {
+			return
+		}
+		deliveryID, ok := pathParamID(c, "deliveryId")
+		if !ok {
+			return
+		}
+
+		resp, err := svc.GetDelivery(c.Request.Context(), &v1.GetDeliveryRequest{SubscriptionId: id, Id: deliveryID})
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
+
+// handleRedeliverWebhook 重新投递
+//
+// @Summary      重新投递 webhook
+// @Description  以相同的请求体重新投递已成功或已失败的投递，尝试次数重新计算；投递仍在进行中时返回 409
+// @Tags         webhook
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path     int true "订阅 ID"
+// @Param        deliveryId path     int true "投递 ID"
+// @Success      200        {object} response.Response{data=v1.RedeliverDeliveryResponse} "成功"
+// @Failure      404        {object} response.Response "订阅或投递不存在"
+// @Failure      409        {object} response.Response "投递仍在进行中"
+// @Router       /webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func handleRedeliverWebhook(svc *service.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c)
+		if !ok {
+			return
+		}
+		deliveryID, ok := pathParamID(c, "deliveryId")
+		if !ok {
+			return
+		}
+
+		resp, err := svc.RedeliverDelivery(c.Request.Context(), &v1.RedeliverDeliveryRequest{SubscriptionId: id, Id: deliveryID})
+		if err != nil {
+			response.ErrorJSON(c, toAppError(err))
+			return
+		}
+		response.SuccessJSON(c, resp)
+	}
+}
@@ -0,0 +1,263 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/reason"
+	"go-api-template/internal/server/middleware"
+	"go-api-template/internal/server/response"
+)
+
+// upgrader 把 HTTP 请求升级为 WebSocket 连接
+// 身份来自访问令牌而非 Cookie，跨站页面拿不到令牌，因此不限制 Origin
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+// wsHub 记录进行中的 WebSocket 连接
+// 升级后的连接已脱离 http.Server 的管理，Shutdown 不会等待它们，由 wsHub 负责等待与强制关闭
+type wsHub struct {
+	mu     sync.Mutex
+	conns  map[*websocket.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// newWSHub 创建 wsHub
+func newWSHub() *wsHub {
+	return &wsHub{conns: make(map[*websocket.Conn]struct{})}
+}
+
+// add 登记连接，服务器正在停止时返回 false
+func (h *wsHub) add(conn *websocket.Conn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.conns[conn] = struct{}{}
+	h.wg.Add(1)
+	return true
+}
+
+// remove 注销连接
+func (h *wsHub) remove(conn *websocket.Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.conns, conn)
+	h.wg.Done()
+}
+
+// shutdown 等待所有连接结束，ctx 超时后直接关闭剩余连接
+// 调用前应先结束 Service 中的会话，连接会随会话结束发送关闭帧后断开
+func (h *wsHub) shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		h.mu.Lock()
+		for conn := range h.conns {
+			_ = conn.Close()
+		}
+		h.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// serveWebSocket 把当前请求升级为 WebSocket，并以双向流式 RPC 的形式交给 session 处理
+// 每条文本或二进制消息是一个 protojson 编码的请求；服务端定期发送 ping，
+// pongTimeout 内没有收到 pong 或任何消息视为连接已断开；session 结束后按其错误发送关闭帧
+func serveWebSocket[Req, Resp any](c *gin.Context, sockets *wsHub, cfg conf.GreeterSessionConfig,
+	session func(grpc.BidiStreamingServer[Req, Resp]) error) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade 已经写出了 HTTP 错误响应
+		return
+	}
+	defer conn.Close()
+	if !sockets.add(conn) {
+		writeClose(conn, websocket.CloseGoingAway, string(reason.ServiceUnavailable), cfg.GetPongTimeout())
+		return
+	}
+	defer sockets.remove(conn)
+
+	// 会话长期保持，不受请求处理时限限制
+	middleware.DetachTimeout(c)
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	stream := &wsStream[Req, Resp]{ctx: ctx, cancel: cancel, conn: conn, cfg: cfg}
+	conn.SetReadLimit(cfg.GetMaxMessageSize())
+	stream.extendReadDeadline()
+	conn.SetPongHandler(func(string) error {
+		stream.extendReadDeadline()
+		return nil
+	})
+	go stream.ping()
+
+	err = session(stream)
+	if ctx.Err() != nil {
+		// 连接已断开，无法再发送关闭帧
+		return
+	}
+	code, text := closeCode(err)
+	writeClose(conn, code, text, cfg.GetPongTimeout())
+}
+
+// closeCode 把会话结束的原因映射为 WebSocket 关闭码，关闭原因使用业务错误码
+func closeCode(err error) (int, string) {
+	if err == nil {
+		return websocket.CloseNormalClosure, ""
+	}
+	appErr := toAppError(err)
+	switch appErr.Code {
+	case reason.InvalidParams:
+		return websocket.CloseInvalidFramePayloadData, string(appErr.Code)
+	case reason.ServiceUnavailable:
+		return websocket.CloseGoingAway, string(appErr.Code)
+	case reason.TooManyRequests:
+		return websocket.CloseTryAgainLater, string(appErr.Code)
+	case reason.InternalError:
+		return websocket.CloseInternalServerErr, string(appErr.Code)
+	default:
+		return websocket.ClosePolicyViolation, string(appErr.Code)
+	}
+}
+
+// writeClose 发送关闭帧，对端已断开时忽略错误
+func writeClose(conn *websocket.Conn, code int, text string, timeout time.Duration) {
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(timeout))
+}
+
+// wsStream 把 WebSocket 连接适配为 grpc.BidiStreamingServer
+// 与 sseStream 相同，使同一个 Service 方法同时服务 gRPC 双向流与 WebSocket
+type wsStream[Req, Resp any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	conn   *websocket.Conn
+	cfg    conf.GreeterSessionConfig
+
+	// mu 串行化数据消息的写入；控制帧（ping、close）允许与之并发
+	mu sync.Mutex
+}
+
+// extendReadDeadline 收到 pong 或消息后延长读超时
+func (s *wsStream[Req, Resp]) extendReadDeadline() {
+	_ = s.conn.SetReadDeadline(time.Now().Add(s.cfg.GetPingInterval() + s.cfg.GetPongTimeout()))
+}
+
+// ping 定期发送 ping，发送失败或会话结束时返回
+func (s *wsStream[Req, Resp]) ping() {
+	ticker := time.NewTicker(s.cfg.GetPingInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(s.cfg.GetPongTimeout())); err != nil {
+				s.cancel()
+				return
+			}
+		}
+	}
+}
+
+// Recv 实现 grpc.BidiStreamingServer，读取并解码下一条消息
+// 客户端正常关闭时返回 io.EOF；连接断开时同时结束 Context
+func (s *wsStream[Req, Resp]) Recv() (*Req, error) {
+	for {
+		typ, data, err := s.conn.ReadMessage()
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			return nil, io.EOF
+		}
+		if err != nil {
+			s.cancel()
+			return nil, err
+		}
+		if typ != websocket.TextMessage && typ != websocket.BinaryMessage {
+			continue
+		}
+		s.extendReadDeadline()
+
+		req := new(Req)
+		if err := protojson.Unmarshal(data, any(req).(proto.Message)); err != nil {
+			return nil, fmt.Errorf("%w: invalid message: %v", biz.ErrInvalidArgument, err)
+		}
+		return req, nil
+	}
+}
+
+// Send 实现 grpc.BidiStreamingServer，以 protojson 编码写出一条文本消息，编码选项与 HTTP 响应相同
+func (s *wsStream[Req, Resp]) Send(msg *Resp) error {
+	data, err := response.MarshalProtoJSON(any(msg).(proto.Message))
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.conn.SetWriteDeadline(time.Now().Add(s.cfg.GetPongTimeout()))
+	return s.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// Context 返回会话的 context，连接断开时结束
+func (s *wsStream[Req, Resp]) Context() context.Context {
+	return s.ctx
+}
+
+// SetHeader WebSocket 握手已完成，忽略 gRPC 元数据
+func (s *wsStream[Req, Resp]) SetHeader(metadata.MD) error { return nil }
+
+// SendHeader 同 SetHeader
+func (s *wsStream[Req, Resp]) SendHeader(metadata.MD) error { return nil }
+
+// SetTrailer WebSocket 没有 trailer，忽略
+func (s *wsStream[Req, Resp]) SetTrailer(metadata.MD) {}
+
+// SendMsg 只支持 Send 使用的消息类型
+func (s *wsStream[Req, Resp]) SendMsg(m any) error {
+	msg, ok := m.(*Resp)
+	if !ok {
+		return errors.New("websocket: unexpected message type")
+	}
+	return s.Send(msg)
+}
+
+// RecvMsg 只支持 Recv 使用的消息类型
+func (s *wsStream[Req, Resp]) RecvMsg(m any) error {
+	dst, ok := m.(*Req)
+	if !ok {
+		return errors.New("websocket: unexpected message type")
+	}
+	req, err := s.Recv()
+	if err != nil {
+		return err
+	}
+	proto.Merge(any(dst).(proto.Message), any(req).(proto.Message))
+	return nil
+}
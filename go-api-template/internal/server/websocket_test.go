@@ -0,0 +1,253 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	v1 "go-api-template/api/helloworld/v1"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/auth"
+	"go-api-template/internal/pkg/reason"
+	"go-api-template/internal/service"
+)
+
+// size 返回进行中的连接数
+func (h *wsHub) size() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.conns)
+}
+
+// sessionServer 只注册了问候会话接口的测试服务器
+type sessionServer struct {
+	url     string
+	svc     *service.GreeterService
+	tokens  *auth.TokenManager
+	sockets *wsHub
+}
+
+func newSessionServer(t *testing.T, session conf.GreeterSessionConfig) *sessionServer {
+	t.Helper()
+	cfg := &conf.Config{
+		JWT:     conf.JWTConfig{Secret: "test-secret"},
+		Greeter: conf.GreeterConfig{Session: session},
+	}
+	tokens, err := auth.NewTokenManager(cfg)
+	if err != nil {
+		t.Fatalf("NewTokenManager: %v", err)
+	}
+	s := &sessionServer{svc: newTestGreeterService(t, cfg), tokens: tokens, sockets: newWSHub()}
+
+	engine := gin.New()
+	engine.Use(withTestTenant)
+	engine.GET("/greeter/session", handleGreetSession(s.svc, session, tokens, s.sockets))
+	srv := httptest.NewServer(engine)
+	t.Cleanup(srv.Close)
+	s.url = "ws" + strings.TrimPrefix(srv.URL, "http") + "/greeter/session"
+	return s
+}
+
+// token 为租户中的用户签发访问令牌
+func (s *sessionServer) token(t *testing.T, tenantID string, userID int64, username string) string {
+	t.Helper()
+	token, _, err := s.tokens.Issue(tenantID, userID, username)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	return token
+}
+
+// dial 以 username 的身份打开会话
+func (s *sessionServer) dial(t *testing.T, tenantID, username string) *websocket.Conn {
+	t.Helper()
+	header := http.Header{testTenantHeader: {tenantID}}
+	conn, resp, err := websocket.DefaultDialer.Dial(s.url+"?access_token="+s.token(t, tenantID, 1, username), header)
+	if err != nil {
+		t.Fatalf("dial: %v (response %v)", err, resp)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// readResponse 读取并解码下一条服务端消息
+func readResponse(t *testing.T, conn *websocket.Conn) *v1.GreetSessionResponse {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	resp := &v1.GreetSessionResponse{}
+	if err := protojson.Unmarshal(data, resp); err != nil {
+		t.Fatalf("decode %s: %v", data, err)
+	}
+	return resp
+}
+
+// expectPresence 断言下一条消息是 username 的在线状态变化
+func expectPresence(t *testing.T, conn *websocket.Conn, action v1.PresenceAction, username string, online int32) {
+	t.Helper()
+	p := readResponse(t, conn).GetPresence()
+	if p.GetAction() != action || p.GetUsername() != username || p.GetOnline() != online {
+		t.Fatalf("presence = %v, want %s %s with %d online", p, action, username, online)
+	}
+}
+
+// waitSessions 等待进行中的连接数变为 n
+func waitSessions(t *testing.T, sockets *wsHub, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for sockets.size() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("sessions = %d, want %d", sockets.size(), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGreetSessionWebSocket(t *testing.T) {
+	s := newSessionServer(t, conf.GreeterSessionConfig{})
+	alice := s.dial(t, "acme", "alice")
+	expectPresence(t, alice, v1.PresenceAction_PRESENCE_ACTION_JOINED, "alice", 1)
+
+	if err := alice.WriteMessage(websocket.TextMessage, []byte(`{"name":"world"}`)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if g := readResponse(t, alice).GetGreeting(); g.GetName() != "world" || g.GetMessage() != "Hello world" {
+		t.Errorf("greeting = %v, want Hello world", g)
+	}
+	// 单条消息的参数错误只回复错误，会话继续
+	if err := alice.WriteMessage(websocket.TextMessage, []byte(`{"name":""}`)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if e := readResponse(t, alice).GetError(); e.GetCode() != string(reason.InvalidParams) {
+		t.Errorf("error = %v, want %s", e, reason.InvalidParams)
+	}
+
+	bob := s.dial(t, "acme", "bob")
+	expectPresence(t, alice, v1.PresenceAction_PRESENCE_ACTION_JOINED, "bob", 2)
+	expectPresence(t, bob, v1.PresenceAction_PRESENCE_ACTION_JOINED, "bob", 2)
+	waitSessions(t, s.sockets, 2)
+
+	// 客户端正常关闭：服务端回复关闭帧，会话从 wsHub 与在线列表中移除
+	if err := bob.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")); err != nil {
+		t.Fatalf("write close: %v", err)
+	}
+	_ = bob.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err := bob.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("bob read after close = %v, want a normal closure", err)
+	}
+	expectPresence(t, alice, v1.PresenceAction_PRESENCE_ACTION_LEFT, "bob", 1)
+	waitSessions(t, s.sockets, 1)
+
+	// 服务停止：会话以 1001 关闭，关闭原因为业务错误码
+	s.svc.CloseStreams()
+	_ = alice.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err := alice.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseGoingAway || closeErr.Text != string(reason.ServiceUnavailable) {
+		t.Errorf("alice read after shutdown = %v, want 1001 %s", err, reason.ServiceUnavailable)
+	}
+	waitSessions(t, s.sockets, 0)
+}
+
+func TestGreetSessionPing(t *testing.T) {
+	cfg := conf.GreeterSessionConfig{PingInterval: 20 * time.Millisecond, PongTimeout: 50 * time.Millisecond}
+
+	t.Run("client answering pings stays connected", func(t *testing.T) {
+		s := newSessionServer(t, cfg)
+		conn := s.dial(t, "acme", "alice")
+		pings := make(chan struct{}, 100)
+		conn.SetPingHandler(func(data string) error {
+			pings <- struct{}{}
+			return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		})
+		expectPresence(t, conn, v1.PresenceAction_PRESENCE_ACTION_JOINED, "alice", 1)
+
+		// 读取循环处理 ping 并回复 pong，远超 ping_interval + pong_timeout 后连接仍然有效
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+		time.Sleep(5 * (cfg.PingInterval + cfg.PongTimeout))
+		if len(pings) < 3 {
+			t.Errorf("received %d pings, want the server to ping every %v", len(pings), cfg.PingInterval)
+		}
+		if s.sockets.size() != 1 {
+			t.Errorf("sessions = %d, want the answering client still connected", s.sockets.size())
+		}
+	})
+
+	t.Run("silent client is disconnected", func(t *testing.T) {
+		s := newSessionServer(t, cfg)
+		conn := s.dial(t, "acme", "alice")
+		expectPresence(t, conn, v1.PresenceAction_PRESENCE_ACTION_JOINED, "alice", 1)
+		if s.sockets.size() != 1 {
+			t.Fatalf("sessions = %d, want 1", s.sockets.size())
+		}
+		// 之后不再读取连接，ping 得不到 pong，服务端在读超时后断开并注销会话
+		waitSessions(t, s.sockets, 0)
+	})
+}
+
+func TestGreetSessionHandshake(t *testing.T) {
+	s := newSessionServer(t, conf.GreeterSessionConfig{})
+	httpURL := "http" + strings.TrimPrefix(s.url, "ws")
+	tests := []struct {
+		name       string
+		tenantID   string
+		token      string
+		upgrade    bool
+		wantStatus int
+	}{
+		{name: "missing token", tenantID: "acme", upgrade: true, wantStatus: http.StatusUnauthorized},
+		{name: "invalid token", tenantID: "acme", token: "garbage", upgrade: true, wantStatus: http.StatusUnauthorized},
+		{name: "token of another tenant", tenantID: "globex", token: s.token(t, "acme", 1, "alice"), upgrade: true,
+			wantStatus: http.StatusForbidden},
+		{name: "not an upgrade", tenantID: "acme", token: s.token(t, "acme", 1, "alice"), wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status int
+			if tt.upgrade {
+				header := http.Header{testTenantHeader: {tt.tenantID}}
+				if tt.token != "" {
+					header.Set("Authorization", "Bearer "+tt.token)
+				}
+				conn, resp, err := websocket.DefaultDialer.Dial(s.url, header)
+				if err == nil {
+					_ = conn.Close()
+					t.Fatal("handshake succeeded, want it rejected")
+				}
+				status = resp.StatusCode
+			} else {
+				req, _ := http.NewRequest(http.MethodGet, httpURL, nil)
+				req.Header.Set(testTenantHeader, tt.tenantID)
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatalf("GET: %v", err)
+				}
+				_ = resp.Body.Close()
+				status = resp.StatusCode
+			}
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+	if s.sockets.size() != 0 {
+		t.Errorf("sessions = %d after rejected handshakes, want 0", s.sockets.size())
+	}
+}
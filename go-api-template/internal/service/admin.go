@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/auth"
+)
+
+// requireAdmin 确认当前用户已登录、属于 default 租户且在 admin.users 中，供管理接口使用
+// 在 service 层检查，HTTP 与 gRPC 两种入口的行为一致
+func requireAdmin(ctx context.Context, cfg *conf.Config) (*auth.Claims, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Admin.IsAdmin(claims.Tenant, claims.Username) {
+		return nil, fmt.Errorf("%w: admin privileges required", biz.ErrForbidden)
+	}
+	return claims, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/wire"
+
+	v1 "go-api-template/api/featureflags/v1"
+	"go-api-template/internal/biz"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/featureflags"
+)
+
+// FeatureFlagProviderSet 是功能开关管理服务的依赖提供者集合
+var FeatureFlagProviderSet = wire.NewSet(NewFeatureFlagService)
+
+// FeatureFlagService 实现 proto 定义的 FeatureFlagServiceServer 接口
+// 所有方法只对管理员开放
+type FeatureFlagService struct {
+	v1.UnimplementedFeatureFlagServiceServer
+
+	flags *featureflags.Manager
+	cfg   *conf.Config
+}
+
+// NewFeatureFlagService 创建 FeatureFlagService 实例
+func NewFeatureFlagService(flags *featureflags.Manager, cfg *conf.Config) *FeatureFlagService {
+	return &FeatureFlagService{flags: flags, cfg: cfg}
+}
+
+// ListFeatureFlags 实现 FeatureFlagServiceServer.ListFeatureFlags
+// 预览求值不计入开关的求值次数，也不输出求值日志
+func (s *FeatureFlagService) ListFeatureFlags(ctx context.Context, req *v1.ListFeatureFlagsRequest) (*v1.ListFeatureFlagsResponse, error) {
+	if _, err := requireAdmin(ctx, s.cfg); err != nil {
+		return nil, err
+	}
+	subject := s.flags.Subject(ctx)
+	if t := req.GetTenant(); t != "" {
+		if !conf.ValidTenantID(t) {
+			return nil, fmt.Errorf("%w: invalid tenant %q", biz.ErrInvalidArgument, t)
+		}
+		subject.Tenant = t
+	}
+	if u := req.GetUser(); u != "" {
+		subject.User = u
+	}
+	if env := req.GetEnvironment(); env != "" {
+		subject.Environment = env
+	}
+
+	flags := s.flags.Flags()
+	resp := &v1.ListFeatureFlagsResponse{
+		Flags:       make([]*v1.FeatureFlag, 0, len(flags)),
+		Tenant:      subject.Tenant,
+		User:        subject.User,
+		Environment: subject.Environment,
+	}
+	for _, flag := range flags {
+		resp.Flags = append(resp.Flags, toFeatureFlagProto(flag, s.flags.Evaluate(flag.Name, subject)))
+	}
+	return resp, nil
+}
+
+// toFeatureFlagProto 将开关定义与求值结果转换为 API 表示
+func toFeatureFlagProto(flag featureflags.Flag, e featureflags.Evaluation) *v1.FeatureFlag {
+	on, off := featureflags.Counts(flag.Name)
+	return &v1.FeatureFlag{
+		Name:           flag.Name,
+		Description:    flag.Description,
+		Source:         string(flag.Source),
+		Enabled:        flag.Enabled,
+		Environments:   flag.Environments,
+		Tenants:        flag.Tenants,
+		Users:          flag.Users,
+		Percentage:     flag.Percentage,
+		Evaluation:     &v1.FeatureFlagEvaluation{Enabled: e.Enabled, Reason: string(e.Reason)},
+		EvaluationsOn:  on,
+		EvaluationsOff: off,
+	}
+}
@@ -2,11 +2,15 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/wire"
+	"google.golang.org/grpc"
 
 	v1 "go-api-template/api/helloworld/v1"
 	"go-api-template/internal/biz"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/pagination"
 )
 
 // GreeterProviderSet 是 Greeter 模块服务层的依赖提供者集合
@@ -19,11 +23,35 @@ type GreeterService struct {
 
 	// 依赖领域层的业务用例，而非数据层
 	uc *biz.GreeterUsecase
+
+	cfg   *conf.Config
+	pages *pagination.Codec
+
+	// feeds 向 WatchGreetings 的订阅者推送新问候，每个租户一个推送源
+	feeds *perTenant[*greetingFeed]
+	// sessions 记录 GreetSession 会话与在线用户，每个租户一份
+	sessions *perTenant[*sessionHub]
 }
 
 // NewGreeterService 创建 GreeterService 实例
-func NewGreeterService(uc *biz.GreeterUsecase) *GreeterService {
-	return &GreeterService{uc: uc}
+func NewGreeterService(uc *biz.GreeterUsecase, cfg *conf.Config, pages *pagination.Codec) *GreeterService {
+	return &GreeterService{
+		uc:    uc,
+		cfg:   cfg,
+		pages: pages,
+		feeds: newPerTenant(func(tenantID string) *greetingFeed {
+			return newGreetingFeed(tenantID, uc, cfg.Greeter.Stream)
+		}, (*greetingFeed).close),
+		sessions: newPerTenant(func(string) *sessionHub { return newSessionHub() }, (*sessionHub).close),
+	}
+}
+
+// wakeFeed 本进程保存了问候，通知租户的推送源立即轮询
+// 租户还没有推送源时说明没有订阅者，无需通知
+func (s *GreeterService) wakeFeed(ctx context.Context) {
+	if f, ok := s.feeds.lookup(ctx); ok {
+		f.wake()
+	}
 }
 
 // SayHello 实现 GreeterServiceServer.SayHello 方法
@@ -34,9 +62,123 @@ func (s *GreeterService) SayHello(ctx context.Context, req *v1.SayHelloRequest)
 	if err != nil {
 		return nil, err
 	}
+	s.wakeFeed(ctx)
 
 	// 将领域对象转换为 API 响应
 	return &v1.SayHelloResponse{
-		Message: greeter.Message,
+		Message:  greeter.Message,
+		Greeting: toGreetingProto(greeter),
 	}, nil
 }
+
+// GetGreeting 实现 GreeterServiceServer.GetGreeting 方法
+func (s *GreeterService) GetGreeting(ctx context.Context, req *v1.GetGreetingRequest) (*v1.GetGreetingResponse, error) {
+	greeter, err := s.uc.GetGreeting(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &v1.GetGreetingResponse{Greeting: toGreetingProto(greeter)}, nil
+}
+
+// ListGreetings 实现 GreeterServiceServer.ListGreetings 方法
+func (s *GreeterService) ListGreetings(ctx context.Context, req *v1.ListGreetingsRequest) (*v1.ListGreetingsResponse, error) {
+	pageReq, err := s.pages.Parse(biz.GreeterListSpec, pagination.FromProto(req))
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.uc.ListGreetings(ctx, pageReq)
+	if err != nil {
+		return nil, err
+	}
+	resp := &v1.ListGreetingsResponse{
+		Greetings:  make([]*v1.Greeting, 0, len(page.Items)),
+		NextCursor: s.pages.Encode(pageReq, page.Next),
+		Total:      page.Total,
+	}
+	for _, g := range page.Items {
+		resp.Greetings = append(resp.Greetings, toGreetingProto(g))
+	}
+	return resp, nil
+}
+
+// WatchGreetings 实现 GreeterServiceServer.WatchGreetings 方法
+// 先订阅再补发 after_id 之后的历史问候，补发期间产生的新问候留在订阅缓冲中，
+// 随后按 ID 去重，保证不重复也不遗漏；订阅者消费过慢或服务停止时以 feed 包的错误结束
+func (s *GreeterService) WatchGreetings(req *v1.WatchGreetingsRequest, stream grpc.ServerStreamingServer[v1.WatchGreetingsResponse]) error {
+	ctx := stream.Context()
+	f, err := s.feeds.get(ctx)
+	if err != nil {
+		return err
+	}
+	sub, err := f.subscribe()
+	if err != nil {
+		return err
+	}
+	defer sub.Cancel()
+
+	last := req.GetAfterId()
+	if last <= 0 {
+		if last, err = s.uc.LatestGreetingID(ctx); err != nil {
+			return err
+		}
+	}
+
+	// 补发历史问候
+	for {
+		greetings, err := s.uc.GreetingsSince(ctx, last, req.GetName(), greetingPageSize)
+		if err != nil {
+			return err
+		}
+		for _, g := range greetings {
+			if err := stream.Send(toGreetingResponse(g)); err != nil {
+				return err
+			}
+			last = g.ID
+		}
+		if len(greetings) < greetingPageSize {
+			break
+		}
+	}
+
+	// 推送新问候
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case g, ok := <-sub.C:
+			if !ok {
+				return sub.Err()
+			}
+			if g.ID <= last || (req.GetName() != "" && g.Name != req.GetName()) {
+				continue
+			}
+			if err := stream.Send(toGreetingResponse(g)); err != nil {
+				return err
+			}
+			last = g.ID
+		}
+	}
+}
+
+// CloseStreams 断开所有 WatchGreetings 订阅者与 GreetSession 会话
+// 流式请求不会自行结束，服务器优雅关闭前必须先调用，否则关闭会一直等到超时
+func (s *GreeterService) CloseStreams() {
+	s.feeds.close()
+	s.sessions.close()
+}
+
+// toGreetingResponse 将领域对象转换为 WatchGreetings 推送的消息
+func toGreetingResponse(g *biz.Greeter) *v1.WatchGreetingsResponse {
+	return &v1.WatchGreetingsResponse{Greeting: toGreetingProto(g)}
+}
+
+// toGreetingProto 将领域对象转换为 Proto 类型
+func toGreetingProto(g *biz.Greeter) *v1.Greeting {
+	return &v1.Greeting{
+		Id:        g.ID,
+		Name:      g.Name,
+		Message:   g.Message,
+		Version:   g.Version,
+		CreatedAt: g.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
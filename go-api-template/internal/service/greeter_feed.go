@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go-api-template/internal/biz"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/feed"
+	"go-api-template/internal/pkg/tenant"
+)
+
+// greetingPageSize 补发历史问候和每次轮询读取的最大条数
+const greetingPageSize = 100
+
+// greetingFeed 一个租户的新问候推送源
+// 由一个后台循环按 ID 递增轮询数据库，把租户的新问候广播给该租户的所有订阅者；
+// 数据来自数据库而非本进程的 SayHello，多实例部署时其他实例保存的问候同样会被推送
+type greetingFeed struct {
+	uc  *biz.GreeterUsecase
+	cfg conf.GreeterStreamConfig
+	hub *feed.Hub[*biz.Greeter]
+	// ctx 携带推送源所属的租户，轮询只读取该租户的问候
+	ctx context.Context
+
+	// mu 保证轮询循环在没有订阅者时重置 last 与新订阅者加入互斥，
+	// 否则重置期间保存的问候可能既不在订阅者的补发范围内，也不会被推送
+	mu   sync.Mutex
+	last int64
+
+	start  sync.Once
+	notify chan struct{}
+	stop   chan struct{}
+	closed sync.Once
+	done   chan struct{}
+}
+
+// newGreetingFeed 创建租户的推送源，轮询循环在第一个订阅者到来时才启动
+func newGreetingFeed(tenantID string, uc *biz.GreeterUsecase, cfg conf.GreeterStreamConfig) *greetingFeed {
+	return &greetingFeed{
+		uc:     uc,
+		cfg:    cfg,
+		hub:    feed.NewHub[*biz.Greeter](),
+		ctx:    tenant.NewContext(context.Background(), tenantID),
+		notify: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// subscribe 加入一个订阅者，推送源已关闭时返回 feed.ErrClosed
+func (f *greetingFeed) subscribe() (*feed.Subscription[*biz.Greeter], error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.start.Do(func() {
+		f.reset(f.ctx)
+		go f.run()
+	})
+	return f.hub.Subscribe(f.cfg.GetBuffer())
+}
+
+// wake 本进程保存了问候，立即轮询一次而不必等到下一个间隔
+func (f *greetingFeed) wake() {
+	select {
+	case f.notify <- struct{}{}:
+	default:
+	}
+}
+
+// close 断开所有订阅者并停止轮询循环，可重复调用
+func (f *greetingFeed) close() {
+	f.closed.Do(func() {
+		close(f.stop)
+		f.hub.Close()
+		f.start.Do(func() { close(f.done) })
+	})
+	<-f.done
+}
+
+// run 轮询循环
+func (f *greetingFeed) run() {
+	defer close(f.done)
+	ticker := time.NewTicker(f.cfg.GetPollInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.stop:
+			return
+		case <-ticker.C:
+		case <-f.notify:
+		}
+		f.poll(f.ctx)
+	}
+}
+
+// poll 把 last 之后的新问候广播给订阅者
+// 没有订阅者时不读取新问候，只把 last 移到最新位置，订阅者重新到来时不会收到积压的旧问候
+func (f *greetingFeed) poll(ctx context.Context) {
+	f.mu.Lock()
+	if f.hub.Len() == 0 {
+		f.reset(ctx)
+		f.mu.Unlock()
+		return
+	}
+	f.mu.Unlock()
+
+	for {
+		greetings, err := f.uc.GreetingsSince(ctx, f.last, "", greetingPageSize)
+		if err != nil {
+			slog.Error("Failed to poll new greetings", "after_id", f.last, "error", err)
+			return
+		}
+		for _, g := range greetings {
+			f.hub.Publish(g)
+			f.last = g.ID
+		}
+		if len(greetings) < greetingPageSize {
+			return
+		}
+	}
+}
+
+// reset 把 last 移到最新一条问候，调用方需持有 f.mu
+func (f *greetingFeed) reset(ctx context.Context) {
+	latest, err := f.uc.LatestGreetingID(ctx)
+	if err != nil {
+		slog.Error("Failed to read latest greeting id", "error", err)
+		return
+	}
+	f.last = latest
+}
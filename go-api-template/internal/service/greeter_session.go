@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/grpc"
+
+	v1 "go-api-template/api/helloworld/v1"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/feed"
+	"go-api-template/internal/pkg/reason"
+)
+
+// presenceBuffer 每个会话缓冲的在线状态通知条数
+const presenceBuffer = 64
+
+// sessionHub 记录本进程中一个租户打开的问候会话，并在用户上线、下线时通知该租户的所有会话
+// 同一用户可以同时打开多个会话，打开第一个时算上线，关闭最后一个时算下线
+type sessionHub struct {
+	mu     sync.Mutex
+	online map[string]int
+	hub    *feed.Hub[*v1.Presence]
+}
+
+// newSessionHub 创建 sessionHub
+func newSessionHub() *sessionHub {
+	return &sessionHub{
+		online: make(map[string]int),
+		hub:    feed.NewHub[*v1.Presence](),
+	}
+}
+
+// join 登记用户的一个会话并订阅在线状态通知，服务停止后返回 feed.ErrClosed
+// 用户因此上线时，通知（包括发给该会话自身的）在返回前已进入缓冲
+func (h *sessionHub) join(username string) (*feed.Subscription[*v1.Presence], error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sub, err := h.hub.Subscribe(presenceBuffer)
+	if err != nil {
+		return nil, err
+	}
+	h.online[username]++
+	if h.online[username] == 1 {
+		h.publish(v1.PresenceAction_PRESENCE_ACTION_JOINED, username)
+	}
+	return sub, nil
+}
+
+// leave 注销用户的一个会话
+func (h *sessionHub) leave(sub *feed.Subscription[*v1.Presence], username string) {
+	sub.Cancel()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.online[username]--
+	if h.online[username] <= 0 {
+		delete(h.online, username)
+		h.publish(v1.PresenceAction_PRESENCE_ACTION_LEFT, username)
+	}
+}
+
+// publish 广播在线状态变化，调用方需持有 h.mu
+func (h *sessionHub) publish(action v1.PresenceAction, username string) {
+	h.hub.Publish(&v1.Presence{Action: action, Username: username, Online: int32(len(h.online))})
+}
+
+// close 以 feed.ErrClosed 结束所有会话
+func (h *sessionHub) close() {
+	h.hub.Close()
+}
+
+// sessionLimiter 单个会话的令牌桶，会话只在一个 goroutine 中处理消息，无需加锁
+type sessionLimiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newSessionLimiter 创建装满令牌的令牌桶
+func newSessionLimiter(cfg conf.GreeterSessionConfig) *sessionLimiter {
+	burst := float64(cfg.GetBurst())
+	return &sessionLimiter{rate: cfg.GetRPS(), burst: burst, tokens: burst, last: time.Now()}
+}
+
+// allow 消耗一个令牌，令牌不足时返回 false
+func (l *sessionLimiter) allow() bool {
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// sessionMessage Recv 的一次结果
+type sessionMessage struct {
+	req *v1.GreetSessionRequest
+	err error
+}
+
+// GreetSession 实现 GreeterServiceServer.GreetSession 方法
+// 接收与发送分别在两个 goroutine 中进行：接收循环只负责读取，所有发送都在当前 goroutine 中完成，
+// 因为 gRPC 流不允许并发 Send；单条消息的参数错误或超出速率只回复错误，不结束会话
+func (s *GreeterService) GreetSession(stream grpc.BidiStreamingServer[v1.GreetSessionRequest, v1.GreetSessionResponse]) error {
+	ctx := stream.Context()
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return err
+	}
+	sessions, err := s.sessions.get(ctx)
+	if err != nil {
+		return err
+	}
+	presence, err := sessions.join(claims.Username)
+	if err != nil {
+		return err
+	}
+	defer sessions.leave(presence, claims.Username)
+
+	messages := make(chan sessionMessage)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			select {
+			case messages <- sessionMessage{req: req, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	limiter := newSessionLimiter(s.cfg.Greeter.Session)
+	for {
+		var resp *v1.GreetSessionResponse
+		select {
+		case <-ctx.Done():
+			return nil
+		case p, ok := <-presence.C:
+			if !ok {
+				return presence.Err()
+			}
+			resp = &v1.GreetSessionResponse{Payload: &v1.GreetSessionResponse_Presence{Presence: p}}
+		case msg := <-messages:
+			if errors.Is(msg.err, io.EOF) {
+				return nil
+			}
+			if msg.err != nil {
+				return msg.err
+			}
+			if resp, err = s.greetInSession(ctx, limiter, msg.req); err != nil {
+				return err
+			}
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// greetInSession 处理会话中的一个名称，返回的错误会结束会话
+func (s *GreeterService) greetInSession(ctx context.Context, limiter *sessionLimiter, req *v1.GreetSessionRequest) (*v1.GreetSessionResponse, error) {
+	if !limiter.allow() {
+		return sessionError(reason.TooManyRequests, "发送过快，请稍后再试"), nil
+	}
+	if n := utf8.RuneCountInString(req.GetName()); n < 1 || n > 100 {
+		return sessionError(reason.InvalidParams, "name 长度必须在 1 到 100 之间"), nil
+	}
+
+	greeter, err := s.uc.SayHello(ctx, req.GetName())
+	if err != nil {
+		return nil, fmt.Errorf("greet in session: %w", err)
+	}
+	s.wakeFeed(ctx)
+	return &v1.GreetSessionResponse{
+		Payload: &v1.GreetSessionResponse_Greeting{Greeting: toGreetingProto(greeter)},
+	}, nil
+}
+
+// sessionError 构造会话中的错误消息
+func sessionError(code reason.Reason, message string) *v1.GreetSessionResponse {
+	return &v1.GreetSessionResponse{
+		Payload: &v1.GreetSessionResponse_Error{Error: &v1.SessionError{Code: string(code), Message: message}},
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/wire"
+
+	v1 "go-api-template/api/jobs/v1"
+	"go-api-template/internal/biz"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/jobs"
+)
+
+// JobProviderSet 是后台任务管理服务的依赖提供者集合
+var JobProviderSet = wire.NewSet(NewJobService)
+
+// JobService 实现 proto 定义的 JobServiceServer 接口
+// 所有方法只对管理员开放
+type JobService struct {
+	v1.UnimplementedJobServiceServer
+
+	manager *jobs.Manager
+	cfg     *conf.Config
+}
+
+// NewJobService 创建 JobService 实例
+func NewJobService(manager *jobs.Manager, cfg *conf.Config) *JobService {
+	return &JobService{manager: manager, cfg: cfg}
+}
+
+const (
+	// defaultJobListLimit 未指定条数时返回的任务数
+	defaultJobListLimit = 50
+	// maxJobListLimit 一次最多返回的任务数
+	maxJobListLimit = 200
+)
+
+// ListJobs 实现 JobServiceServer.ListJobs
+func (s *JobService) ListJobs(ctx context.Context, req *v1.ListJobsRequest) (*v1.ListJobsResponse, error) {
+	if _, err := requireAdmin(ctx, s.cfg); err != nil {
+		return nil, err
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultJobListLimit
+	}
+	list, err := s.manager.List(ctx, jobs.Filter{
+		Status: jobStatusFromProto[req.GetStatus()],
+		Queue:  req.GetQueue(),
+		Type:   req.GetType(),
+		Limit:  min(limit, maxJobListLimit),
+	})
+	if err != nil {
+		return nil, jobError(err)
+	}
+	resp := &v1.ListJobsResponse{Jobs: make([]*v1.Job, 0, len(list))}
+	for _, job := range list {
+		resp.Jobs = append(resp.Jobs, toJobProto(job))
+	}
+	return resp, nil
+}
+
+// GetJob 实现 JobServiceServer.GetJob
+func (s *JobService) GetJob(ctx context.Context, req *v1.GetJobRequest) (*v1.GetJobResponse, error) {
+	if _, err := requireAdmin(ctx, s.cfg); err != nil {
+		return nil, err
+	}
+	job, err := s.manager.Get(ctx, req.GetId())
+	if err != nil {
+		return nil, jobError(err)
+	}
+	return &v1.GetJobResponse{Job: toJobProto(job)}, nil
+}
+
+// RetryJob 实现 JobServiceServer.RetryJob
+func (s *JobService) RetryJob(ctx context.Context, req *v1.RetryJobRequest) (*v1.RetryJobResponse, error) {
+	if _, err := requireAdmin(ctx, s.cfg); err != nil {
+		return nil, err
+	}
+	job, err := s.manager.Retry(ctx, req.GetId())
+	if err != nil {
+		return nil, jobError(err)
+	}
+	return &v1.RetryJobResponse{Job: toJobProto(job)}, nil
+}
+
+// CancelJob 实现 JobServiceServer.CancelJob
+func (s *JobService) CancelJob(ctx context.Context, req *v1.CancelJobRequest) (*v1.CancelJobResponse, error) {
+	if _, err := requireAdmin(ctx, s.cfg); err != nil {
+		return nil, err
+	}
+	job, err := s.manager.Cancel(ctx, req.GetId())
+	if err != nil {
+		return nil, jobError(err)
+	}
+	return &v1.CancelJobResponse{Job: toJobProto(job)}, nil
+}
+
+// jobError 把任务存储的错误映射为领域错误，传输层据此返回对应的状态码
+func jobError(err error) error {
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		return fmt.Errorf("%w: %s", biz.ErrNotFound, err.Error())
+	case errors.Is(err, jobs.ErrInvalidState):
+		return fmt.Errorf("%w: %s", biz.ErrConflict, err.Error())
+	default:
+		return err
+	}
+}
+
+// jobStatusProto 任务状态到 API 枚举的映射
+var jobStatusProto = map[jobs.Status]v1.JobStatus{
+	jobs.StatusQueued:    v1.JobStatus_JOB_STATUS_QUEUED,
+	jobs.StatusRunning:   v1.JobStatus_JOB_STATUS_RUNNING,
+	jobs.StatusSucceeded: v1.JobStatus_JOB_STATUS_SUCCEEDED,
+	jobs.StatusDead:      v1.JobStatus_JOB_STATUS_DEAD,
+	jobs.StatusCancelled: v1.JobStatus_JOB_STATUS_CANCELLED,
+}
+
+// jobStatusFromProto API 枚举到任务状态的映射，UNSPECIFIED 映射为空（不筛选）
+var jobStatusFromProto = map[v1.JobStatus]jobs.Status{
+	v1.JobStatus_JOB_STATUS_QUEUED:    jobs.StatusQueued,
+	v1.JobStatus_JOB_STATUS_RUNNING:   jobs.StatusRunning,
+	v1.JobStatus_JOB_STATUS_SUCCEEDED: jobs.StatusSucceeded,
+	v1.JobStatus_JOB_STATUS_DEAD:      jobs.StatusDead,
+	v1.JobStatus_JOB_STATUS_CANCELLED: jobs.StatusCancelled,
+}
+
+// toJobProto 将任务转换为 API 表示
+func toJobProto(job *jobs.Job) *v1.Job {
+	out := &v1.Job{
+		Id:          job.ID,
+		Queue:       job.Queue,
+		Type:        job.Type,
+		Payload:     string(job.Payload),
+		Status:      jobStatusProto[job.Status],
+		Attempts:    int32(job.Attempts),
+		MaxAttempts: int32(job.MaxAttempts),
+		RunAt:       job.RunAt.UTC().Format(time.RFC3339),
+		LastError:   job.LastError,
+		UniqueKey:   job.UniqueKey,
+		CreatedAt:   job.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:   job.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if job.FinishedAt != nil {
+		out.FinishedAt = job.FinishedAt.UTC().Format(time.RFC3339)
+	}
+	return out
+}
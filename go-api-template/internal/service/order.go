@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/wire"
+
+	v1 "go-api-template/api/order/v1"
+	"go-api-template/internal/biz"
+	"go-api-template/internal/pkg/pagination"
+)
+
+// OrderProviderSet 是 Order 模块服务层的依赖提供者集合
+var OrderProviderSet = wire.NewSet(NewOrderService)
+
+// OrderService 实现 proto 定义的 OrderServiceServer 接口
+// 所有方法都作用于当前登录用户自己的订单
+type OrderService struct {
+	v1.UnimplementedOrderServiceServer
+
+	uc    *biz.OrderUsecase
+	pages *pagination.Codec
+}
+
+// NewOrderService 创建 OrderService 实例
+func NewOrderService(uc *biz.OrderUsecase, pages *pagination.Codec) *OrderService {
+	return &OrderService{uc: uc, pages: pages}
+}
+
+// CreateOrder 实现 OrderServiceServer.CreateOrder
+func (s *OrderService) CreateOrder(ctx context.Context, req *v1.CreateOrderRequest) (*v1.CreateOrderResponse, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.uc.Create(ctx, claims.UserID, req.GetProduct(), req.GetQuantity(), req.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+	return &v1.CreateOrderResponse{Order: toOrderProto(order)}, nil
+}
+
+// GetOrder 实现 OrderServiceServer.GetOrder
+func (s *OrderService) GetOrder(ctx context.Context, req *v1.GetOrderRequest) (*v1.GetOrderResponse, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	order, transitions, err := s.uc.Get(ctx, claims.UserID, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	resp := &v1.GetOrderResponse{
+		Order:       toOrderProto(order),
+		Transitions: make([]*v1.OrderTransition, 0, len(transitions)),
+	}
+	for _, t := range transitions {
+		resp.Transitions = append(resp.Transitions, &v1.OrderTransition{
+			From:      orderStatusProto[t.From],
+			To:        orderStatusProto[t.To],
+			Event:     string(t.Event),
+			Actor:     t.Actor,
+			Note:      t.Note,
+			CreatedAt: t.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
+
+// ListOrders 实现 OrderServiceServer.ListOrders
+// HTTP 与 gRPC 使用相同的分页参数，游标可以在两种协议间通用
+func (s *OrderService) ListOrders(ctx context.Context, req *v1.ListOrdersRequest) (*v1.ListOrdersResponse, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pageReq, err := s.pages.Parse(biz.OrderListSpec, pagination.FromProto(req))
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.uc.List(ctx, claims.UserID, pageReq)
+	if err != nil {
+		return nil, err
+	}
+	resp := &v1.ListOrdersResponse{
+		Orders:     make([]*v1.Order, 0, len(page.Items)),
+		NextCursor: s.pages.Encode(pageReq, page.Next),
+		Total:      page.Total,
+	}
+	for _, order := range page.Items {
+		resp.Orders = append(resp.Orders, toOrderProto(order))
+	}
+	return resp, nil
+}
+
+// PayOrder 实现 OrderServiceServer.PayOrder
+func (s *OrderService) PayOrder(ctx context.Context, req *v1.PayOrderRequest) (*v1.PayOrderResponse, error) {
+	order, err := s.transition(ctx, req.GetId(), req.GetVersion(), s.uc.Pay)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.PayOrderResponse{Order: order}, nil
+}
+
+// ShipOrder 实现 OrderServiceServer.ShipOrder
+func (s *OrderService) ShipOrder(ctx context.Context, req *v1.ShipOrderRequest) (*v1.ShipOrderResponse, error) {
+	order, err := s.transition(ctx, req.GetId(), req.GetVersion(), s.uc.Ship)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ShipOrderResponse{Order: order}, nil
+}
+
+// CompleteOrder 实现 OrderServiceServer.CompleteOrder
+func (s *OrderService) CompleteOrder(ctx context.Context, req *v1.CompleteOrderRequest) (*v1.CompleteOrderResponse, error) {
+	order, err := s.transition(ctx, req.GetId(), req.GetVersion(), s.uc.Complete)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.CompleteOrderResponse{Order: order}, nil
+}
+
+// CancelOrder 实现 OrderServiceServer.CancelOrder
+func (s *OrderService) CancelOrder(ctx context.Context, req *v1.CancelOrderRequest) (*v1.CancelOrderResponse, error) {
+	order, err := s.transition(ctx, req.GetId(), req.GetVersion(), func(ctx context.Context, userID, orderID, version int64) (*biz.Order, error) {
+		return s.uc.Cancel(ctx, userID, orderID, version, req.GetReason())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.CancelOrderResponse{Order: order}, nil
+}
+
+// transition 取出当前用户后执行一次状态流转
+func (s *OrderService) transition(ctx context.Context, orderID, version int64,
+	fire func(ctx context.Context, userID, orderID, version int64) (*biz.Order, error)) (*v1.Order, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	order, err := fire(ctx, claims.UserID, orderID, version)
+	if err != nil {
+		return nil, err
+	}
+	return toOrderProto(order), nil
+}
+
+// orderStatusProto 领域状态到 API 枚举的映射
+// 显式列出而不是直接做整数转换，两边的枚举顺序可以独立演进
+var orderStatusProto = map[biz.OrderStatus]v1.OrderStatus{
+	biz.OrderStatusUnknown:   v1.OrderStatus_ORDER_STATUS_UNSPECIFIED,
+	biz.OrderStatusPending:   v1.OrderStatus_ORDER_STATUS_PENDING,
+	biz.OrderStatusPaid:      v1.OrderStatus_ORDER_STATUS_PAID,
+	biz.OrderStatusShipped:   v1.OrderStatus_ORDER_STATUS_SHIPPED,
+	biz.OrderStatusCompleted: v1.OrderStatus_ORDER_STATUS_COMPLETED,
+	biz.OrderStatusCancelled: v1.OrderStatus_ORDER_STATUS_CANCELLED,
+}
+
+// toOrderProto 将领域实体转换为 API 表示
+func toOrderProto(order *biz.Order) *v1.Order {
+	return &v1.Order{
+		Id:        order.ID,
+		UserId:    order.UserID,
+		Product:   order.Product,
+		Quantity:  order.Quantity,
+		Amount:    order.Amount,
+		Status:    orderStatusProto[order.Status],
+		Version:   order.Version,
+		CreatedAt: order.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: order.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -9,6 +9,9 @@ import "github.com/google/wire"
 // ProviderSet 聚合 service 层所有模块的 ProviderSet
 var ProviderSet = wire.NewSet(
 	GreeterProviderSet,
-	// UserProviderSet,  // 未来：用户模块
-	// OrderProviderSet, // 未来：订单模块
+	UserProviderSet,        // User 模块
+	OrderProviderSet,       // Order 模块
+	WebhookProviderSet,     // Webhook 模块
+	JobProviderSet,         // 后台任务管理
+	FeatureFlagProviderSet, // 功能开关管理
 )
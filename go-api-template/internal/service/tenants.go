@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"go-api-template/internal/pkg/feed"
+	"go-api-template/internal/pkg/tenant"
+)
+
+// perTenant 按租户懒创建的进程内组件，如问候推送源与会话在线状态
+// 这类组件在订阅者之间广播消息，必须每个租户一份，否则一个租户的订阅者会收到其他租户的问候
+type perTenant[T any] struct {
+	mu      sync.Mutex
+	items   map[string]T
+	closed  bool
+	create  func(tenantID string) T
+	destroy func(T)
+}
+
+// newPerTenant 创建 perTenant，create 创建租户的组件，destroy 在关闭时释放组件
+func newPerTenant[T any](create func(tenantID string) T, destroy func(T)) *perTenant[T] {
+	return &perTenant[T]{items: make(map[string]T), create: create, destroy: destroy}
+}
+
+// get 返回 ctx 中租户的组件，第一次访问时创建；关闭之后返回 feed.ErrClosed
+func (p *perTenant[T]) get(ctx context.Context) (T, error) {
+	var zero T
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return zero, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return zero, feed.ErrClosed
+	}
+	item, ok := p.items[tenantID]
+	if !ok {
+		item = p.create(tenantID)
+		p.items[tenantID] = item
+	}
+	return item, nil
+}
+
+// lookup 返回 ctx 中租户已创建的组件，不会创建新组件
+func (p *perTenant[T]) lookup(ctx context.Context) (T, bool) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	item, ok := p.items[tenantID]
+	return item, ok
+}
+
+// close 释放所有租户的组件，之后的 get 返回 feed.ErrClosed，可重复调用
+func (p *perTenant[T]) close() {
+	p.mu.Lock()
+	items := p.items
+	p.items = make(map[string]T)
+	p.closed = true
+	p.mu.Unlock()
+
+	for _, item := range items {
+		p.destroy(item)
+	}
+}
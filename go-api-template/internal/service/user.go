@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/wire"
+
+	v1 "go-api-template/api/user/v1"
+	"go-api-template/internal/biz"
+	"go-api-template/internal/pkg/auth"
+)
+
+// UserProviderSet 是 User 模块服务层的依赖提供者集合
+var UserProviderSet = wire.NewSet(NewUserService)
+
+// UserService 实现 proto 定义的 UserServiceServer 接口
+type UserService struct {
+	v1.UnimplementedUserServiceServer
+
+	uc *biz.UserUsecase
+}
+
+// NewUserService 创建 UserService 实例
+func NewUserService(uc *biz.UserUsecase) *UserService {
+	return &UserService{uc: uc}
+}
+
+// Register 实现 UserServiceServer.Register
+func (s *UserService) Register(ctx context.Context, req *v1.RegisterRequest) (*v1.RegisterResponse, error) {
+	user, err := s.uc.Register(ctx, req.GetUsername(), req.GetEmail(), req.GetNickname(), req.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+	return &v1.RegisterResponse{User: toUserProto(user)}, nil
+}
+
+// Login 实现 UserServiceServer.Login
+func (s *UserService) Login(ctx context.Context, req *v1.LoginRequest) (*v1.LoginResponse, error) {
+	session, err := s.uc.Login(ctx, req.GetUsername(), req.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+	return &v1.LoginResponse{
+		AccessToken: session.Token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(session.ExpiresAt).Seconds()),
+		User:        toUserProto(session.User),
+	}, nil
+}
+
+// GetProfile 实现 UserServiceServer.GetProfile
+func (s *UserService) GetProfile(ctx context.Context, _ *v1.GetProfileRequest) (*v1.GetProfileResponse, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.uc.GetProfile(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.GetProfileResponse{User: toUserProto(user)}, nil
+}
+
+// UpdateProfile 实现 UserServiceServer.UpdateProfile
+func (s *UserService) UpdateProfile(ctx context.Context, req *v1.UpdateProfileRequest) (*v1.UpdateProfileResponse, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.uc.UpdateProfile(ctx, claims.UserID, req.GetVersion(), req.GetEmail(), req.GetNickname())
+	if err != nil {
+		return nil, err
+	}
+	return &v1.UpdateProfileResponse{User: toUserProto(user)}, nil
+}
+
+// ChangePassword 实现 UserServiceServer.ChangePassword
+func (s *UserService) ChangePassword(ctx context.Context, req *v1.ChangePasswordRequest) (*v1.ChangePasswordResponse, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.uc.ChangePassword(ctx, claims.UserID, req.GetVersion(), req.GetOldPassword(), req.GetNewPassword())
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ChangePasswordResponse{User: toUserProto(user)}, nil
+}
+
+// currentUser 取出认证中间件/拦截器放入 context 的用户身份
+// HTTP 路由已由中间件拦截未认证请求，这里的检查主要服务于 gRPC 调用
+func currentUser(ctx context.Context) (*auth.Claims, error) {
+	claims, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("%w: access token required", biz.ErrUnauthorized)
+	}
+	return claims, nil
+}
+
+// toUserProto 将领域实体转换为 API 表示，密码哈希不会出现在响应中
+func toUserProto(user *biz.User) *v1.User {
+	return &v1.User{
+		Id:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Nickname:  user.Nickname,
+		Version:   user.Version,
+		CreatedAt: user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/wire"
+
+	v1 "go-api-template/api/webhook/v1"
+	"go-api-template/internal/biz"
+	"go-api-template/internal/conf"
+	"go-api-template/internal/pkg/event"
+	"go-api-template/internal/pkg/jobs"
+	"go-api-template/internal/pkg/pagination"
+	"go-api-template/internal/pkg/tenant"
+)
+
+// WebhookProviderSet 是 Webhook 模块服务层的依赖提供者集合
+var WebhookProviderSet = wire.NewSet(NewWebhookService)
+
+// WebhookService 实现 proto 定义的 WebhookServiceServer 接口
+// 所有方法都作用于当前登录用户自己的订阅
+type WebhookService struct {
+	v1.UnimplementedWebhookServiceServer
+
+	uc    *biz.WebhookUsecase
+	pages *pagination.Codec
+}
+
+// NewWebhookService 创建 WebhookService 实例
+// webhooks.enabled 时订阅事件总线上的全部事件，为匹配的订阅生成投递记录；
+// 生成失败时返回错误，事件由 relay 稍后重新投递。
+// 同时注册清理投递日志的后台任务，由 jobs.schedules 定时触发
+func NewWebhookService(uc *biz.WebhookUsecase, bus *event.Bus, manager *jobs.Manager, cfg *conf.Config,
+	pages *pagination.Codec) *WebhookService {
+	s := &WebhookService{uc: uc, pages: pages}
+	if cfg.Webhooks.Enabled {
+		bus.Subscribe(event.AllEvents, s.dispatch)
+	}
+	jobs.Handle(manager, PruneWebhookDeliveriesJob, s.pruneDeliveries)
+	return s
+}
+
+// PruneWebhookDeliveriesJob 清理投递日志的任务类型
+const PruneWebhookDeliveriesJob = "webhook.prune_deliveries"
+
+// pruneDeliveriesPayload 清理任务的参数
+type pruneDeliveriesPayload struct {
+	// OlderThanDays 删除结束超过多少天的投递，默认 30
+	OlderThanDays int `json:"older_than_days"`
+}
+
+// pruneDeliveries 删除已结束且超过保留天数的投递日志
+func (s *WebhookService) pruneDeliveries(ctx context.Context, p pruneDeliveriesPayload) error {
+	days := p.OlderThanDays
+	if days <= 0 {
+		days = 30
+	}
+	n, err := s.uc.PruneDeliveries(ctx, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return err
+	}
+	slog.Info("Pruned webhook deliveries", "count", n, "older_than_days", days)
+	return nil
+}
+
+// dispatch 把事件总线上的事件交给 biz 层分发
+// 在产生事件的租户内分发，只有该租户的订阅会收到；启用多租户之前写入的事件没有租户，属于 default
+func (s *WebhookService) dispatch(ctx context.Context, msg event.Message) error {
+	tenantID := msg.Tenant
+	if tenantID == "" {
+		tenantID = tenant.Default
+	}
+	return s.uc.Dispatch(tenant.NewContext(ctx, tenantID), biz.WebhookEvent{
+		ID:         msg.ID,
+		Type:       msg.Type,
+		Data:       msg.Payload,
+		OccurredAt: msg.OccurredAt,
+	})
+}
+
+// CreateSubscription 实现 WebhookServiceServer.CreateSubscription
+func (s *WebhookService) CreateSubscription(ctx context.Context, req *v1.CreateSubscriptionRequest) (*v1.CreateSubscriptionResponse, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.uc.CreateSubscription(ctx, claims.UserID, req.GetUrl(), req.GetEventTypes(), req.GetSecret())
+	if err != nil {
+		return nil, err
+	}
+	return &v1.CreateSubscriptionResponse{Subscription: toSubscriptionProto(sub, true)}, nil
+}
+
+// GetSubscription 实现 WebhookServiceServer.GetSubscription
+func (s *WebhookService) GetSubscription(ctx context.Context, req *v1.GetSubscriptionRequest) (*v1.GetSubscriptionResponse, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.uc.GetSubscription(ctx, claims.UserID, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &v1.GetSubscriptionResponse{Subscription: toSubscriptionProto(sub, false)}, nil
+}
+
+// ListSubscriptions 实现 WebhookServiceServer.ListSubscriptions
+func (s *WebhookService) ListSubscriptions(ctx context.Context, _ *v1.ListSubscriptionsRequest) (*v1.ListSubscriptionsResponse, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subs, err := s.uc.ListSubscriptions(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &v1.ListSubscriptionsResponse{Subscriptions: make([]*v1.Subscription, 0, len(subs))}
+	for _, sub := range subs {
+		resp.Subscriptions = append(resp.Subscriptions, toSubscriptionProto(sub, false))
+	}
+	return resp, nil
+}
+
+// UpdateSubscription 实现 WebhookServiceServer.UpdateSubscription
+func (s *WebhookService) UpdateSubscription(ctx context.Context, req *v1.UpdateSubscriptionRequest) (*v1.UpdateSubscriptionResponse, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	update := biz.WebhookSubscriptionUpdate{
+		URL:    req.Url,
+		Secret: req.Secret,
+		Active: req.Active,
+	}
+	if len(req.GetEventTypes()) > 0 {
+		update.EventTypes = req.GetEventTypes()
+	}
+	sub, err := s.uc.UpdateSubscription(ctx, claims.UserID, req.GetId(), update)
+	if err != nil {
+		return nil, err
+	}
+	// 轮换了密钥时返回新密钥，这是调用方唯一能拿到它的机会
+	return &v1.UpdateSubscriptionResponse{Subscription: toSubscriptionProto(sub, req.Secret != nil)}, nil
+}
+
+// DeleteSubscription 实现 WebhookServiceServer.DeleteSubscription
+func (s *WebhookService) DeleteSubscription(ctx context.Context, req *v1.DeleteSubscriptionRequest) (*v1.DeleteSubscriptionResponse, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.uc.DeleteSubscription(ctx, claims.UserID, req.GetId()); err != nil {
+		return nil, err
+	}
+	return &v1.DeleteSubscriptionResponse{}, nil
+}
+
+// ListDeliveries 实现 WebhookServiceServer.ListDeliveries
+func (s *WebhookService) ListDeliveries(ctx context.Context, req *v1.ListDeliveriesRequest) (*v1.ListDeliveriesResponse, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pageReq, err := s.pages.Parse(biz.WebhookDeliveryListSpec, pagination.FromProto(req))
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.uc.ListDeliveries(ctx, claims.UserID, req.GetSubscriptionId(), pageReq)
+	if err != nil {
+		return nil, err
+	}
+	resp := &v1.ListDeliveriesResponse{
+		Deliveries: make([]*v1.Delivery, 0, len(page.Items)),
+		NextCursor: s.pages.Encode(pageReq, page.Next),
+		Total:      page.Total,
+	}
+	for _, d := range page.Items {
+		resp.Deliveries = append(resp.Deliveries, toDeliveryProto(d))
+	}
+	return resp, nil
+}
+
+// GetDelivery 实现 WebhookServiceServer.GetDelivery
+func (s *WebhookService) GetDelivery(ctx context.Context, req *v1.GetDeliveryRequest) (*v1.GetDeliveryResponse, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	delivery, attempts, err := s.uc.GetDelivery(ctx, claims.UserID, req.GetSubscriptionId(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	resp := &v1.GetDeliveryResponse{
+		Delivery: toDeliveryProto(delivery),
+		Attempts: make([]*v1.DeliveryAttempt, 0, len(attempts)),
+	}
+	for _, a := range attempts {
+		resp.Attempts = append(resp.Attempts, &v1.DeliveryAttempt{
+			Attempt:    int32(a.Attempt),
+			StatusCode: int32(a.StatusCode),
+			Error:      a.Error,
+			DurationMs: a.Duration.Milliseconds(),
+			CreatedAt:  a.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
+
+// RedeliverDelivery 实现 WebhookServiceServer.RedeliverDelivery
+func (s *WebhookService) RedeliverDelivery(ctx context.Context, req *v1.RedeliverDeliveryRequest) (*v1.RedeliverDeliveryResponse, error) {
+	claims, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.uc.Redeliver(ctx, claims.UserID, req.GetSubscriptionId(), req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &v1.RedeliverDeliveryResponse{Delivery: toDeliveryProto(delivery)}, nil
+}
+
+// deliveryStatusProto 领域状态到 API 枚举的映射
+var deliveryStatusProto = map[biz.WebhookDeliveryStatus]v1.DeliveryStatus{
+	biz.WebhookDeliveryPending:   v1.DeliveryStatus_DELIVERY_STATUS_PENDING,
+	biz.WebhookDeliverySucceeded: v1.DeliveryStatus_DELIVERY_STATUS_SUCCEEDED,
+	biz.WebhookDeliveryFailed:    v1.DeliveryStatus_DELIVERY_STATUS_FAILED,
+}
+
+// toSubscriptionProto 将领域实体转换为 API 表示，withSecret 为 false 时不返回签名密钥
+func toSubscriptionProto(sub *biz.WebhookSubscription, withSecret bool) *v1.Subscription {
+	out := &v1.Subscription{
+		Id:         sub.ID,
+		Url:        sub.URL,
+		EventTypes: sub.EventTypes,
+		Active:     sub.Active,
+		CreatedAt:  sub.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  sub.UpdatedAt.Format(time.RFC3339),
+	}
+	if withSecret {
+		out.Secret = sub.Secret
+	}
+	return out
+}
+
+// toDeliveryProto 将领域实体转换为 API 表示
+func toDeliveryProto(d *biz.WebhookDelivery) *v1.Delivery {
+	out := &v1.Delivery{
+		Id:             d.ID,
+		SubscriptionId: d.SubscriptionID,
+		EventId:        d.EventID,
+		EventType:      d.EventType,
+		Status:         deliveryStatusProto[d.Status],
+		Attempts:       int32(d.Attempts),
+		LastStatusCode: int32(d.LastStatusCode),
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+		Payload:        string(d.Payload),
+	}
+	if d.Status == biz.WebhookDeliveryPending {
+		out.NextAttemptAt = d.NextAttemptAt.Format(time.RFC3339)
+	}
+	if d.DeliveredAt != nil {
+		out.DeliveredAt = d.DeliveredAt.Format(time.RFC3339)
+	}
+	return out
+}
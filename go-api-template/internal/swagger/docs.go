@@ -22,28 +22,122 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/greeter/say-hello": {
-            "post": {
-                "description": "向指定用户发送问候消息，返回问候语和访问计数",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/feature-flags": {
+            "get": {
+                "description": "按名称返回当前生效的功能开关、对求值对象的结果及本实例的求值次数；仅管理员可用。\n求值对象默认为调用者自己（当前租户、当前用户与当前环境），查询参数可以替换其中的部分",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "greeter"
+                    "admin"
                 ],
-                "summary": "发送问候",
+                "summary": "功能开关列表",
                 "parameters": [
                     {
-                        "description": "问候请求参数",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "求值使用的租户",
+                        "name": "tenant",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "求值使用的用户名",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "求值使用的运行环境",
+                        "name": "environment",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
                         "schema": {
-                            "$ref": "#/definitions/go-api-template_internal_server_dto.SayHelloRequest"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_featureflags_v1.ListFeatureFlagsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未登录或令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
                         }
+                    },
+                    "403": {
+                        "description": "不是管理员",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/jobs": {
+            "get": {
+                "description": "按 ID 倒序返回任务，可按状态、队列、类型筛选；仅管理员可用",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "后台任务列表",
+                "parameters": [
+                    {
+                        "enum": [
+                            "queued",
+                            "running",
+                            "succeeded",
+                            "dead",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "任务状态",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "队列名",
+                        "name": "queue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "任务类型",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "最多返回的条数（1-200），默认 50",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -58,7 +152,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/go-api-template_api_helloworld_v1.SayHelloResponse"
+                                            "$ref": "#/definitions/go-api-template_api_jobs_v1.ListJobsResponse"
                                         }
                                     }
                                 }
@@ -71,35 +165,98 @@ const docTemplate = `{
                             "$ref": "#/definitions/go-api-template_internal_server_response.Response"
                         }
                     },
-                    "500": {
-                        "description": "服务内部错误",
+                    "401": {
+                        "description": "未登录或令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "不是管理员",
                         "schema": {
                             "$ref": "#/definitions/go-api-template_internal_server_response.Response"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/greeter/say-hello/{name}": {
+        "/admin/jobs/{id}": {
             "get": {
-                "description": "通过 URL 路径参数向指定用户发送问候消息",
-                "consumes": [
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "后台任务详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "任务 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_jobs_v1.GetJobResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "不是管理员",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "任务不存在",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/jobs/{id}/cancel": {
+            "post": {
+                "description": "只能取消排队中（含延迟执行、等待重试）的任务；执行中或已结束的任务返回 409",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "greeter"
+                    "admin"
                 ],
-                "summary": "发送问候（URL参数）",
+                "summary": "取消后台任务",
                 "parameters": [
                     {
-                        "maxLength": 100,
-                        "minLength": 1,
-                        "type": "string",
-                        "description": "用户名称",
-                        "name": "name",
+                        "type": "integer",
+                        "description": "任务 ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -116,85 +273,2763 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/go-api-template_api_helloworld_v1.SayHelloResponse"
+                                            "$ref": "#/definitions/go-api-template_api_jobs_v1.CancelJobResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "请求参数错误",
+                    "403": {
+                        "description": "不是管理员",
                         "schema": {
                             "$ref": "#/definitions/go-api-template_internal_server_response.Response"
                         }
                     },
-                    "500": {
-                        "description": "服务内部错误",
+                    "404": {
+                        "description": "任务不存在",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "任务状态不允许取消",
                         "schema": {
                             "$ref": "#/definitions/go-api-template_internal_server_response.Response"
                         }
                     }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "go-api-template_api_helloworld_v1.SayHelloResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "description": "问候消息",
-                    "type": "string"
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "go-api-template_internal_pkg_apperrors.FieldError": {
-            "type": "object",
-            "properties": {
-                "field": {
-                    "description": "字段名（JSON 格式）",
-                    "type": "string"
+        "/admin/jobs/{id}/retry": {
+            "post": {
+                "description": "把死信、已取消或已成功的任务重新排队并立即执行，执行次数清零；排队中或执行中的任务返回 409",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "重试后台任务",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "任务 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_jobs_v1.RetryJobResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "不是管理员",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "任务不存在",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "任务状态不允许重试",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
                 },
-                "message": {
-                    "description": "错误描述",
-                    "type": "string"
-                }
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "go-api-template_internal_pkg_reason.Reason": {
-            "type": "string",
-            "enum": [
-                "SUCCESS",
-                "INVALID_PARAMS",
-                "UNAUTHORIZED",
-                "FORBIDDEN",
-                "NOT_FOUND",
-                "INTERNAL_ERROR",
-                "SERVICE_UNAVAILABLE"
-            ],
-            "x-enum-varnames": [
-                "Success",
-                "InvalidParams",
-                "Unauthorized",
-                "Forbidden",
-                "NotFound",
-                "InternalError",
-                "ServiceUnavailable"
-            ]
-        },
-        "go-api-template_internal_server_dto.SayHelloRequest": {
-            "type": "object",
-            "required": [
-                "name"
-            ],
-            "properties": {
-                "name": {
-                    "description": "Name 要问候的用户名称\nrequired: 必填\nmin=1: 最小长度 1\nmax=100: 最大长度 100",
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1,
-                    "example": "World"
+        "/greeter/greetings": {
+            "get": {
+                "description": "默认按 ID 倒序使用游标分页：把响应中的 next_cursor 作为下一次请求的 cursor，为空表示没有下一页。\n游标与 sort、filter 绑定，修改条件后需从第一页开始；offset 大于 0 时改用偏移分页，不能与 cursor 同时使用。\n可排序字段：id、created_at；可过滤字段：created_at（支持 = != \u003e \u003e= \u003c \u003c=）、name（支持 = !=，| 分隔多个取值）。\n时间使用 RFC 3339 格式。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "greeter"
+                ],
+                "summary": "问候记录列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "每页条数（1-100），默认 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "偏移量（0-10000）",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "上一页响应中的 next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "-created_at",
+                        "description": "排序字段，逗号分隔，- 前缀表示倒序，默认 -id",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name=World,created_at\u003e=2024-01-01T00:00:00Z",
+                        "description": "过滤条件，逗号分隔，之间为 AND 关系",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_helloworld_v1.ListGreetingsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "分页、排序或过滤参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/greeter/greetings/{id}": {
+            "get": {
+                "description": "响应头 ETag 为问候记录版本；携带 If-None-Match 且版本未变化时返回 304",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "greeter"
+                ],
+                "summary": "问候记录详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "问候记录 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "上次获取的 ETag",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_helloworld_v1.GetGreetingResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "问候记录版本"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "问候记录未变化"
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "问候记录不存在",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/greeter/say-hello": {
+            "post": {
+                "description": "向指定用户发送问候消息，返回问候语和访问计数",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "greeter"
+                ],
+                "summary": "发送问候",
+                "parameters": [
+                    {
+                        "description": "问候请求参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_dto.SayHelloRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_helloworld_v1.SayHelloResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "问候记录版本"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "携带的令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "请求体过大",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "请求体格式不支持",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "服务内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/greeter/say-hello/{name}": {
+            "get": {
+                "description": "通过 URL 路径参数向指定用户发送问候消息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "greeter"
+                ],
+                "summary": "发送问候（URL参数）",
+                "parameters": [
+                    {
+                        "maxLength": 100,
+                        "minLength": 1,
+                        "type": "string",
+                        "description": "用户名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_helloworld_v1.SayHelloResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "问候记录版本"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "携带的令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "服务内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/greeter/session": {
+            "get": {
+                "description": "握手成功后，客户端每发送一条 {\"name\": \"...\"} 文本消息，服务端回复 {\"greeting\": {...}}；\n会话期间同时推送 {\"presence\": {...}} 在线状态变化，单条消息出错时回复 {\"error\": {\"code\", \"message\"}}。\n服务端每隔 greeter.session.ping_interval 发送 ping；服务停止时以 1001 关闭，发送过慢以 1013 关闭",
+                "tags": [
+                    "greeter"
+                ],
+                "summary": "交互式问候会话（WebSocket）",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "访问令牌，无法设置 Authorization 请求头时使用",
+                        "name": "access_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "切换为 WebSocket 协议，之后每条服务端消息的结构",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_api_helloworld_v1.GreetSessionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不是 WebSocket 握手请求",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未登录或令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "令牌不属于当前租户",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/greeter/stream": {
+            "get": {
+                "description": "text/event-stream 长连接，每条新问候推送一个 greeting 事件（id 为问候 ID，data 为 v1.Greeting）；\n消费过慢或服务停止时推送 error 事件后断开，data 与 JSON 接口的错误响应结构相同",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "greeter"
+                ],
+                "summary": "订阅新问候（SSE）",
+                "parameters": [
+                    {
+                        "maxLength": 100,
+                        "type": "string",
+                        "description": "只接收该名称的问候",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "从该 ID 之后开始推送",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "同 last_event_id，EventSource 重连时自动携带，优先于查询参数",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "greeting 事件的 data",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_api_helloworld_v1.Greeting"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "description": "默认按 ID 倒序使用游标分页：把响应中的 next_cursor 作为下一次请求的 cursor，为空表示没有下一页。\n游标与 sort、filter 绑定，修改条件后需从第一页开始；offset 大于 0 时改用偏移分页，不能与 cursor 同时使用。\n可排序字段：id、created_at、updated_at、amount；可过滤字段：created_at、amount、quantity（支持 = != \u003e \u003e= \u003c \u003c=）、\nstatus、product（支持 = !=，| 分隔多个取值）。时间使用 RFC 3339 格式。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "订单列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "每页条数（1-100），默认 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "偏移量（0-10000）",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "上一页响应中的 next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "-created_at,amount",
+                        "description": "排序字段，逗号分隔，- 前缀表示倒序，默认 -id",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "status=paid|shipped,amount\u003e=1000",
+                        "description": "过滤条件，逗号分隔，之间为 AND 关系",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_order_v1.ListOrdersResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "分页、排序或过滤参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未登录或令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "创建订单",
+                "parameters": [
+                    {
+                        "description": "订单参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_dto.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_order_v1.CreateOrderResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未登录或令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "description": "响应头 ETag 为订单版本；携带 If-None-Match 且版本未变化时返回 304",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "订单详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "订单 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "上次获取的 ETag",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_order_v1.GetOrderResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "订单版本"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "订单未变化"
+                    },
+                    "401": {
+                        "description": "未登录或令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "订单不存在",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/orders/{id}/cancel": {
+            "post": {
+                "description": "待支付或已支付的订单可以取消，取消原因记录在流转记录中；If-Match 的含义同其他流转",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "取消订单",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "订单 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "上次获取的 ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "取消原因",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_dto.CancelOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_order_v1.CancelOrderResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "流转后的订单版本"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "订单不存在",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "当前状态不允许取消",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "订单已被修改",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/orders/{id}/{action}": {
+            "post": {
+                "description": "action 为 pay（待支付→已支付）、ship（已支付→已发货）或 complete（已发货→已完成）；\n携带 If-Match 时仅在订单版本未变化时流转，否则返回 412",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "订单状态流转",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "订单 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pay",
+                            "ship",
+                            "complete"
+                        ],
+                        "type": "string",
+                        "description": "流转动作",
+                        "name": "action",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "上次获取的 ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_order_v1.PayOrderResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "流转后的订单版本"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "订单不存在",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "当前状态不允许该流转",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "订单已被修改",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/login": {
+            "post": {
+                "description": "校验用户名和密码，返回访问令牌；后续请求在 Authorization 头中携带 \"Bearer {token}\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "登录",
+                "parameters": [
+                    {
+                        "description": "登录参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_dto.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_user_v1.LoginResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "用户名或密码错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "description": "响应头 ETag 为资料版本；携带 If-None-Match 且版本未变化时返回 304",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "获取个人资料",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "上次获取的 ETag",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_user_v1.GetProfileResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "资料版本"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "资料未变化"
+                    },
+                    "401": {
+                        "description": "未登录或令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "携带 If-Match 时仅在资料版本未变化时修改，否则返回 412",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "修改个人资料",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "上次获取的 ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "资料参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_dto.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_user_v1.UpdateProfileResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "修改后的资料版本"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未登录或令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "资料已被修改",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/me/password": {
+            "put": {
+                "description": "密码属于资料的一部分，修改后资料版本加一；If-Match 的含义同修改个人资料",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "修改密码",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "上次获取的 ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "密码参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_dto.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_user_v1.ChangePasswordResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "修改后的资料版本"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未登录、令牌无效或旧密码错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "412": {
+                        "description": "资料已被修改",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/register": {
+            "post": {
+                "description": "创建新用户，登录名全局唯一",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "注册",
+                "parameters": [
+                    {
+                        "description": "注册参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_dto.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_user_v1.RegisterResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "登录名已被占用",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "服务内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "webhook 订阅列表",
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_webhook_v1.ListSubscriptionsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未登录或令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "响应中的 secret 是签名密钥，之后的查询不再返回，请妥善保存。\n投递请求带 X-Webhook-Timestamp 与 X-Webhook-Signature（sha256=hex(HMAC(secret, \"\u003ctimestamp\u003e.\u003cbody\u003e\"))）\nurl 的主机必须解析到公网地址，回环、内网与链路本地地址会被拒绝；投递时不跟随重定向",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "创建 webhook 订阅",
+                "parameters": [
+                    {
+                        "description": "订阅参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_dto.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_webhook_v1.CreateSubscriptionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未登录或令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "webhook 订阅详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "订阅 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_webhook_v1.GetSubscriptionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未登录或令牌无效",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "订阅不存在",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "尚未完成的投递随之取消，投递日志一并删除",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "删除 webhook 订阅",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "订阅 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_webhook_v1.DeleteSubscriptionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "订阅不存在",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "省略的字段保持不变；设置 secret 会轮换签名密钥，新密钥只在本次响应中返回",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "修改 webhook 订阅",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "订阅 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "修改的字段",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_dto.UpdateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_webhook_v1.UpdateSubscriptionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "订阅不存在",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "默认按 ID 倒序使用游标分页，每页 50 条：把响应中的 next_cursor 作为下一次请求的 cursor，为空表示没有下一页。\n游标与 sort、filter 绑定，修改条件后需从第一页开始；offset 大于 0 时改用偏移分页，不能与 cursor 同时使用。\n可排序字段：id、created_at、updated_at；可过滤字段：created_at、last_status_code（支持 = != \u003e \u003e= \u003c \u003c=）、\nstatus、event_type（支持 = !=，| 分隔多个取值）。时间使用 RFC 3339 格式。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "webhook 投递日志",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "订阅 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页条数（1-100），默认 50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "偏移量（0-10000）",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "上一页响应中的 next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "-created_at",
+                        "description": "排序字段，逗号分隔，- 前缀表示倒序，默认 -id",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "status=failed,last_status_code\u003e=500",
+                        "description": "过滤条件，逗号分隔，之间为 AND 关系",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_webhook_v1.ListDeliveriesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "分页、排序或过滤参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "订阅不存在",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/{id}/deliveries/{deliveryId}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "webhook 投递详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "订阅 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "投递 ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_webhook_v1.GetDeliveryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "订阅或投递不存在",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/{id}/deliveries/{deliveryId}/redeliver": {
+            "post": {
+                "description": "以相同的请求体重新投递已成功或已失败的投递，尝试次数重新计算；投递仍在进行中时返回 409",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "重新投递 webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "订阅 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "投递 ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/go-api-template_api_webhook_v1.RedeliverDeliveryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "订阅或投递不存在",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "投递仍在进行中",
+                        "schema": {
+                            "$ref": "#/definitions/go-api-template_internal_server_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "go-api-template_api_featureflags_v1.FeatureFlag": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "总开关",
+                    "type": "boolean"
+                },
+                "environments": {
+                    "description": "只在这些环境中开启，为空时不限环境",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "evaluation": {
+                    "description": "对求值对象的结果",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-api-template_api_featureflags_v1.FeatureFlagEvaluation"
+                        }
+                    ]
+                },
+                "evaluations_off": {
+                    "type": "string",
+                    "format": "int64"
+                },
+                "evaluations_on": {
+                    "description": "自进程启动以来本实例的求值次数",
+                    "type": "string",
+                    "format": "int64"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percentage": {
+                    "description": "灰度比例（0-100），未设置时为全部开启",
+                    "type": "number"
+                },
+                "source": {
+                    "description": "定义的来源：config（features.flags）或 file（features.file）",
+                    "type": "string"
+                },
+                "tenants": {
+                    "description": "只在这些租户中开启，为空时不限租户",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "users": {
+                    "description": "始终开启的用户名",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "go-api-template_api_featureflags_v1.FeatureFlagEvaluation": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "description": "决定结果的规则：disabled | environment | user | tenant | enabled | rollout",
+                    "type": "string"
+                }
+            }
+        },
+        "go-api-template_api_featureflags_v1.ListFeatureFlagsResponse": {
+            "type": "object",
+            "properties": {
+                "environment": {
+                    "type": "string"
+                },
+                "flags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-api-template_api_featureflags_v1.FeatureFlag"
+                    }
+                },
+                "tenant": {
+                    "description": "实际使用的求值对象",
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-api-template_api_helloworld_v1.GetGreetingResponse": {
+            "type": "object",
+            "properties": {
+                "greeting": {
+                    "$ref": "#/definitions/go-api-template_api_helloworld_v1.Greeting"
+                }
+            }
+        },
+        "go-api-template_api_helloworld_v1.GreetSessionResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "单条消息处理失败（如参数错误、发送过快），会话继续",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-api-template_api_helloworld_v1.SessionError"
+                        }
+                    ]
+                },
+                "greeting": {
+                    "description": "对客户端所发名称的问候",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-api-template_api_helloworld_v1.Greeting"
+                        }
+                    ]
+                },
+                "presence": {
+                    "description": "在线用户变化",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-api-template_api_helloworld_v1.Presence"
+                        }
+                    ]
+                }
+            }
+        },
+        "go-api-template_api_helloworld_v1.Greeting": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "创建时间（RFC 3339）",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "问候记录 ID，按保存顺序递增",
+                    "type": "string",
+                    "format": "int64"
+                },
+                "message": {
+                    "description": "问候消息",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "被问候者名称",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "版本号，HTTP 响应头 ETag 的取值；问候记录保存后不再修改，版本固定为 1",
+                    "type": "string",
+                    "format": "int64"
+                }
+            }
+        },
+        "go-api-template_api_helloworld_v1.ListGreetingsResponse": {
+            "type": "object",
+            "properties": {
+                "greetings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-api-template_api_helloworld_v1.Greeting"
+                    }
+                },
+                "next_cursor": {
+                    "description": "下一页的游标，为空表示没有下一页",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "满足过滤条件的问候总数",
+                    "type": "string",
+                    "format": "int64"
+                }
+            }
+        },
+        "go-api-template_api_helloworld_v1.Presence": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/go-api-template_api_helloworld_v1.PresenceAction"
+                },
+                "online": {
+                    "description": "变化后的在线用户数",
+                    "type": "integer"
+                },
+                "username": {
+                    "description": "状态变化的用户名",
+                    "type": "string"
+                }
+            }
+        },
+        "go-api-template_api_helloworld_v1.PresenceAction": {
+            "description": "PresenceAction 在线状态变化的类型",
+            "type": "string",
+            "enum": [
+                "PRESENCE_ACTION_UNSPECIFIED",
+                "PRESENCE_ACTION_JOINED",
+                "PRESENCE_ACTION_LEFT"
+            ]
+        },
+        "go-api-template_api_helloworld_v1.SayHelloResponse": {
+            "type": "object",
+            "properties": {
+                "greeting": {
+                    "description": "保存的问候记录",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-api-template_api_helloworld_v1.Greeting"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "问候消息",
+                    "type": "string"
+                }
+            }
+        },
+        "go-api-template_api_helloworld_v1.SessionError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "与 HTTP 响应相同的业务错误码，如 INVALID_PARAMS、TOO_MANY_REQUESTS",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "错误描述",
+                    "type": "string"
+                }
+            }
+        },
+        "go-api-template_api_jobs_v1.CancelJobResponse": {
+            "type": "object",
+            "properties": {
+                "job": {
+                    "$ref": "#/definitions/go-api-template_api_jobs_v1.Job"
+                }
+            }
+        },
+        "go-api-template_api_jobs_v1.GetJobResponse": {
+            "type": "object",
+            "properties": {
+                "job": {
+                    "$ref": "#/definitions/go-api-template_api_jobs_v1.Job"
+                }
+            }
+        },
+        "go-api-template_api_jobs_v1.Job": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "已执行次数",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "创建时间（RFC 3339）",
+                    "type": "string"
+                },
+                "finished_at": {
+                    "description": "结束时间（RFC 3339），未结束时为空",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "int64"
+                },
+                "last_error": {
+                    "description": "最近一次失败的原因",
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "description": "JSON 格式的任务参数",
+                    "type": "string"
+                },
+                "queue": {
+                    "type": "string"
+                },
+                "run_at": {
+                    "description": "计划执行时间（RFC 3339）；执行中的任务为租约到期时间",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/go-api-template_api_jobs_v1.JobStatus"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unique_key": {
+                    "description": "去重键，定时任务为 schedule:\u003c名称\u003e:\u003c计划时间\u003e",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "更新时间（RFC 3339）",
+                    "type": "string"
+                }
+            }
+        },
+        "go-api-template_api_jobs_v1.JobStatus": {
+            "description": "JobStatus 任务状态",
+            "type": "string",
+            "enum": [
+                "JOB_STATUS_UNSPECIFIED",
+                "JOB_STATUS_QUEUED",
+                "JOB_STATUS_RUNNING",
+                "JOB_STATUS_SUCCEEDED",
+                "JOB_STATUS_DEAD",
+                "JOB_STATUS_CANCELLED"
+            ]
+        },
+        "go-api-template_api_jobs_v1.ListJobsResponse": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-api-template_api_jobs_v1.Job"
+                    }
+                }
+            }
+        },
+        "go-api-template_api_jobs_v1.RetryJobResponse": {
+            "type": "object",
+            "properties": {
+                "job": {
+                    "$ref": "#/definitions/go-api-template_api_jobs_v1.Job"
+                }
+            }
+        },
+        "go-api-template_api_order_v1.CancelOrderResponse": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "$ref": "#/definitions/go-api-template_api_order_v1.Order"
+                }
+            }
+        },
+        "go-api-template_api_order_v1.CreateOrderResponse": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "$ref": "#/definitions/go-api-template_api_order_v1.Order"
+                }
+            }
+        },
+        "go-api-template_api_order_v1.GetOrderResponse": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "$ref": "#/definitions/go-api-template_api_order_v1.Order"
+                },
+                "transitions": {
+                    "description": "按时间顺序的状态流转记录",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-api-template_api_order_v1.OrderTransition"
+                    }
+                }
+            }
+        },
+        "go-api-template_api_order_v1.ListOrdersResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "下一页的游标，为空表示没有下一页",
+                    "type": "string"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-api-template_api_order_v1.Order"
+                    }
+                },
+                "total": {
+                    "description": "满足过滤条件的订单总数",
+                    "type": "string",
+                    "format": "int64"
+                }
+            }
+        },
+        "go-api-template_api_order_v1.Order": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "订单总金额（分）",
+                    "type": "string",
+                    "format": "int64"
+                },
+                "created_at": {
+                    "description": "创建时间（RFC 3339）",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "int64"
+                },
+                "product": {
+                    "description": "商品名称",
+                    "type": "string"
+                },
+                "quantity": {
+                    "description": "数量",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/go-api-template_api_order_v1.OrderStatus"
+                },
+                "updated_at": {
+                    "description": "更新时间（RFC 3339）",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "下单用户",
+                    "type": "string",
+                    "format": "int64"
+                },
+                "version": {
+                    "description": "版本号，每次流转加一；HTTP 中同时以 ETag 头返回",
+                    "type": "string",
+                    "format": "int64"
+                }
+            }
+        },
+        "go-api-template_api_order_v1.OrderStatus": {
+            "description": "OrderStatus 订单状态",
+            "type": "string",
+            "enum": [
+                "ORDER_STATUS_UNSPECIFIED",
+                "ORDER_STATUS_PENDING",
+                "ORDER_STATUS_PAID",
+                "ORDER_STATUS_SHIPPED",
+                "ORDER_STATUS_COMPLETED",
+                "ORDER_STATUS_CANCELLED"
+            ]
+        },
+        "go-api-template_api_order_v1.OrderTransition": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "操作人，如 user:42",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "发生时间（RFC 3339）",
+                    "type": "string"
+                },
+                "event": {
+                    "description": "触发事件：create / pay / ship / complete / cancel",
+                    "type": "string"
+                },
+                "from": {
+                    "description": "流转前状态，创建记录为 UNSPECIFIED",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-api-template_api_order_v1.OrderStatus"
+                        }
+                    ]
+                },
+                "note": {
+                    "description": "备注（如取消原因）",
+                    "type": "string"
+                },
+                "to": {
+                    "description": "流转后状态",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-api-template_api_order_v1.OrderStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "go-api-template_api_order_v1.PayOrderResponse": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "$ref": "#/definitions/go-api-template_api_order_v1.Order"
+                }
+            }
+        },
+        "go-api-template_api_user_v1.ChangePasswordResponse": {
+            "type": "object",
+            "properties": {
+                "user": {
+                    "description": "修改后的资料，版本号已更新",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-api-template_api_user_v1.User"
+                        }
+                    ]
+                }
+            }
+        },
+        "go-api-template_api_user_v1.GetProfileResponse": {
+            "type": "object",
+            "properties": {
+                "user": {
+                    "$ref": "#/definitions/go-api-template_api_user_v1.User"
+                }
+            }
+        },
+        "go-api-template_api_user_v1.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "description": "访问令牌",
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "令牌剩余有效期（秒）",
+                    "type": "string",
+                    "format": "int64"
+                },
+                "token_type": {
+                    "description": "令牌类型，固定为 Bearer",
+                    "type": "string"
+                },
+                "user": {
+                    "description": "登录用户的资料",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/go-api-template_api_user_v1.User"
+                        }
+                    ]
+                }
+            }
+        },
+        "go-api-template_api_user_v1.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "user": {
+                    "$ref": "#/definitions/go-api-template_api_user_v1.User"
+                }
+            }
+        },
+        "go-api-template_api_user_v1.UpdateProfileResponse": {
+            "type": "object",
+            "properties": {
+                "user": {
+                    "$ref": "#/definitions/go-api-template_api_user_v1.User"
+                }
+            }
+        },
+        "go-api-template_api_user_v1.User": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "创建时间（RFC 3339）",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "邮箱",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "唯一标识",
+                    "type": "string",
+                    "format": "int64"
+                },
+                "nickname": {
+                    "description": "昵称",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "更新时间（RFC 3339）",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "登录名",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "版本号，每次修改加一；HTTP 中同时以 ETag 头返回",
+                    "type": "string",
+                    "format": "int64"
+                }
+            }
+        },
+        "go-api-template_api_webhook_v1.CreateSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "subscription": {
+                    "$ref": "#/definitions/go-api-template_api_webhook_v1.Subscription"
+                }
+            }
+        },
+        "go-api-template_api_webhook_v1.DeleteSubscriptionResponse": {
+            "type": "object"
+        },
+        "go-api-template_api_webhook_v1.Delivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "已尝试次数",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "创建时间（RFC 3339）",
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "description": "投递成功的时间（RFC 3339）",
+                    "type": "string"
+                },
+                "event_id": {
+                    "description": "领域事件 ID，接收方据此去重",
+                    "type": "string",
+                    "format": "int64"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "int64"
+                },
+                "last_error": {
+                    "description": "最近一次失败的原因",
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "description": "最近一次响应的状态码，未收到响应时为 0",
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "description": "下次投递时间（RFC 3339），仅 PENDING 状态有值",
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "发送的请求体",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/go-api-template_api_webhook_v1.DeliveryStatus"
+                },
+                "subscription_id": {
+                    "type": "string",
+                    "format": "int64"
+                }
+            }
+        },
+        "go-api-template_api_webhook_v1.DeliveryAttempt": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "description": "第几次尝试，从 1 开始",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "发生时间（RFC 3339）",
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "description": "耗时（毫秒）",
+                    "type": "string",
+                    "format": "int64"
+                },
+                "error": {
+                    "description": "失败原因，成功时为空",
+                    "type": "string"
+                },
+                "status_code": {
+                    "description": "响应状态码，未收到响应时为 0",
+                    "type": "integer"
+                }
+            }
+        },
+        "go-api-template_api_webhook_v1.DeliveryStatus": {
+            "description": "DeliveryStatus 投递状态",
+            "type": "string",
+            "enum": [
+                "DELIVERY_STATUS_UNSPECIFIED",
+                "DELIVERY_STATUS_PENDING",
+                "DELIVERY_STATUS_SUCCEEDED",
+                "DELIVERY_STATUS_FAILED"
+            ]
+        },
+        "go-api-template_api_webhook_v1.GetDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "按时间顺序的尝试记录",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-api-template_api_webhook_v1.DeliveryAttempt"
+                    }
+                },
+                "delivery": {
+                    "$ref": "#/definitions/go-api-template_api_webhook_v1.Delivery"
+                }
+            }
+        },
+        "go-api-template_api_webhook_v1.GetSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "subscription": {
+                    "$ref": "#/definitions/go-api-template_api_webhook_v1.Subscription"
+                }
+            }
+        },
+        "go-api-template_api_webhook_v1.ListDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-api-template_api_webhook_v1.Delivery"
+                    }
+                },
+                "next_cursor": {
+                    "description": "下一页的游标，为空表示没有下一页",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "满足过滤条件的投递总数",
+                    "type": "string",
+                    "format": "int64"
+                }
+            }
+        },
+        "go-api-template_api_webhook_v1.ListSubscriptionsResponse": {
+            "type": "object",
+            "properties": {
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-api-template_api_webhook_v1.Subscription"
+                    }
+                }
+            }
+        },
+        "go-api-template_api_webhook_v1.RedeliverDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "delivery": {
+                    "$ref": "#/definitions/go-api-template_api_webhook_v1.Delivery"
+                }
+            }
+        },
+        "go-api-template_api_webhook_v1.Subscription": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "停用的订阅不再产生新的投递",
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "description": "创建时间（RFC 3339）",
+                    "type": "string"
+                },
+                "event_types": {
+                    "description": "订阅的事件类型，\"*\" 表示全部",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "format": "int64"
+                },
+                "secret": {
+                    "description": "签名密钥，只在创建和轮换密钥时返回",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "更新时间（RFC 3339）",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "接收事件的地址",
+                    "type": "string"
+                }
+            }
+        },
+        "go-api-template_api_webhook_v1.UpdateSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "subscription": {
+                    "$ref": "#/definitions/go-api-template_api_webhook_v1.Subscription"
+                }
+            }
+        },
+        "go-api-template_internal_pkg_apperrors.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "字段名（JSON 格式），请求体整体不是合法 JSON 时为空",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "错误描述",
+                    "type": "string"
+                },
+                "offset": {
+                    "description": "出错位置在请求体中的字节偏移，仅 JSON 解析错误提供",
+                    "type": "integer"
+                }
+            }
+        },
+        "go-api-template_internal_pkg_reason.Reason": {
+            "type": "string",
+            "enum": [
+                "SUCCESS",
+                "INVALID_PARAMS",
+                "UNAUTHORIZED",
+                "FORBIDDEN",
+                "NOT_FOUND",
+                "CONFLICT",
+                "PRECONDITION_FAILED",
+                "PAYLOAD_TOO_LARGE",
+                "UNSUPPORTED_MEDIA_TYPE",
+                "UNPROCESSABLE_ENTITY",
+                "TOO_MANY_REQUESTS",
+                "INTERNAL_ERROR",
+                "SERVICE_UNAVAILABLE",
+                "TIMEOUT"
+            ],
+            "x-enum-varnames": [
+                "Success",
+                "InvalidParams",
+                "Unauthorized",
+                "Forbidden",
+                "NotFound",
+                "Conflict",
+                "PreconditionFailed",
+                "PayloadTooLarge",
+                "UnsupportedMediaType",
+                "UnprocessableEntity",
+                "TooManyRequests",
+                "InternalError",
+                "ServiceUnavailable",
+                "Timeout"
+            ]
+        },
+        "go-api-template_internal_server_dto.CancelOrderRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "Reason 取消原因",
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "不想要了"
+                }
+            }
+        },
+        "go-api-template_internal_server_dto.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 8,
+                    "example": "n3w-s3cret-passw0rd"
+                },
+                "old_password": {
+                    "type": "string",
+                    "example": "s3cret-passw0rd"
+                }
+            }
+        },
+        "go-api-template_internal_server_dto.CreateOrderRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "product",
+                "quantity"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Amount 订单总金额（分）",
+                    "type": "integer",
+                    "example": 8900
+                },
+                "product": {
+                    "description": "Product 商品名称",
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 1,
+                    "example": "Go 语言圣经"
+                },
+                "quantity": {
+                    "description": "Quantity 数量",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "go-api-template_internal_server_dto.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "event_types",
+                "url"
+            ],
+            "properties": {
+                "event_types": {
+                    "description": "EventTypes 订阅的事件类型，\"*\" 表示全部",
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "greeter.greeting_created"
+                    ]
+                },
+                "secret": {
+                    "description": "Secret 签名密钥（16-200 个字符），为空时自动生成",
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 16,
+                    "example": ""
+                },
+                "url": {
+                    "description": "URL 接收事件的地址，必须是 http 或 https，且主机解析到公网地址",
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "https://partner.example.com/hooks/greetings"
+                }
+            }
+        },
+        "go-api-template_internal_server_dto.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "s3cret-passw0rd"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "alice"
+                }
+            }
+        },
+        "go-api-template_internal_server_dto.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "description": "Email 邮箱，可选",
+                    "type": "string",
+                    "maxLength": 254,
+                    "example": "alice@example.com"
+                },
+                "nickname": {
+                    "description": "Nickname 昵称，可选",
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Alice"
+                },
+                "password": {
+                    "description": "Password 密码：8-128 个字符",
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 8,
+                    "example": "s3cret-passw0rd"
+                },
+                "username": {
+                    "description": "Username 登录名：3-32 位字母、数字或下划线",
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3,
+                    "example": "alice"
+                }
+            }
+        },
+        "go-api-template_internal_server_dto.SayHelloRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Name 要问候的用户名称\nrequired: 必填\nmin=1: 最小长度 1\nmax=100: 最大长度 100",
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1,
+                    "example": "World"
+                }
+            }
+        },
+        "go-api-template_internal_server_dto.UpdateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "Email 邮箱，为空表示清除",
+                    "type": "string",
+                    "maxLength": 254,
+                    "example": "alice@example.com"
+                },
+                "nickname": {
+                    "description": "Nickname 昵称，为空表示保持不变",
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Alice"
+                }
+            }
+        },
+        "go-api-template_internal_server_dto.UpdateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "event_types"
+            ],
+            "properties": {
+                "active": {
+                    "description": "Active 是否启用",
+                    "type": "boolean",
+                    "example": false
+                },
+                "event_types": {
+                    "description": "EventTypes 订阅的事件类型",
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "*"
+                    ]
+                },
+                "secret": {
+                    "description": "Secret 轮换签名密钥，传空字符串时自动生成；新密钥在响应中返回",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "url": {
+                    "description": "URL 接收事件的地址",
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "https://partner.example.com/hooks/greetings"
                 }
             }
         },
@@ -247,7 +3082,7 @@ var SwaggerInfo = &swag.Spec{
 	BasePath:         "/api/v1",
 	Schemes:          []string{},
 	Title:            "Go API Template",
-	Description:      "一个基于整洁架构的 Go API 服务模板，用于学习和实践 Go Web 开发",
+	Description:      "一个基于整洁架构的 Go API 服务模板，用于学习和实践 Go Web 开发\n请求体与响应体支持 application/json、application/x-protobuf、application/msgpack，按 Content-Type 与 Accept 协商；\n以下响应结构为 JSON 格式，protobuf 与 MessagePack 的成功响应只包含 data 部分",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",